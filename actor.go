@@ -0,0 +1,44 @@
+package gormext
+
+import (
+	"context"
+	"slices"
+)
+
+type (
+	// Actor identifies who performs database operations. It is the single identity convention
+	// read by auditing, created_by/updated_by population and access checks.
+	Actor struct {
+		ID    string   // Stable identifier, such as a user ID or service name.
+		Name  string   // Display name.
+		Roles []string // Roles granted to the actor.
+	}
+
+	// actorKey is the context key of the current actor.
+	actorKey struct{}
+)
+
+// WithActor returns a context carrying actor as the identity performing operations.
+func WithActor(ctx context.Context, actor Actor) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+// ContextWithActor returns a context carrying the actor identified by actorID, for callers
+// knowing only the user ID, such as the ones of created_by/updated_by population.
+func ContextWithActor(ctx context.Context, actorID string) context.Context {
+	return WithActor(ctx, Actor{ID: actorID})
+}
+
+// ActorFromContext returns the actor carried by ctx, if any.
+func ActorFromContext(ctx context.Context) (Actor, bool) {
+	if ctx == nil {
+		return Actor{}, false
+	}
+	actor, ok := ctx.Value(actorKey{}).(Actor)
+	return actor, ok
+}
+
+// HasRole reports whether the actor was granted role.
+func (a Actor) HasRole(role string) bool {
+	return slices.Contains(a.Roles, role)
+}
@@ -0,0 +1,21 @@
+package gormext
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestActorContext verifies actors round-trip through contexts.
+func TestActorContext(t *testing.T) {
+	_, ok := ActorFromContext(context.Background())
+	assert.False(t, ok)
+
+	ctx := WithActor(context.Background(), Actor{ID: "42", Name: "Ann", Roles: []string{"admin"}})
+	actor, ok := ActorFromContext(ctx)
+	assert.True(t, ok)
+	assert.Equal(t, "42", actor.ID)
+	assert.True(t, actor.HasRole("admin"))
+	assert.False(t, actor.HasRole("billing"))
+}
@@ -0,0 +1,130 @@
+package gormext
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+// actorStamp is the plugin of Config.StampActor, recording the context Actor in the
+// created_by, updated_by and deleted_by columns of written records.
+type actorStamp struct{}
+
+// Name returns the plugin name.
+func (s *actorStamp) Name() string {
+	return "gormext:actor_stamp"
+}
+
+// Initialize registers the callbacks stamping creates and updates, and wraps gorm:delete to
+// stamp soft deletes, so that the callbacks registered before gorm:delete still shape them.
+func (s *actorStamp) Initialize(db *gorm.DB) error {
+	const name = "gormext:actor_stamp"
+	callbacks := db.Callback()
+	deleteRecords := callbacks.Delete().Get("gorm:delete")
+	if deleteRecords == nil {
+		return errors.New("gorm:delete callback not registered")
+	}
+	return errors.Join(
+		callbacks.Create().Before("gorm:create").Register(name, s.created),
+		callbacks.Update().Before("gorm:update").Register(name, s.updated),
+		callbacks.Delete().Replace("gorm:delete", func(db *gorm.DB) {
+			s.deleted(db)
+			deleteRecords(db)
+		}),
+	)
+}
+
+// created sets the creator of the created entities, unless set, and their updater.
+func (s *actorStamp) created(db *gorm.DB) {
+	actorID, ok := s.actor(db)
+	if !ok {
+		return
+	}
+
+	stmt := db.Statement
+	createdBy, updatedBy := actorField(stmt.Schema, "CreatedBy"), actorField(stmt.Schema, "UpdatedBy")
+	for _, entity := range auditEntities(stmt) {
+		for _, field := range []*schema.Field{createdBy, updatedBy} {
+			if field == nil {
+				continue
+			}
+			if _, zero := field.ValueOf(stmt.Context, entity); !zero {
+				continue
+			}
+			if err := field.Set(stmt.Context, entity, actorID); err != nil {
+				db.AddError(fmt.Errorf("failed to set '%s' of '%s': %w", field.DBName, stmt.Table, err))
+				return
+			}
+		}
+	}
+}
+
+// updated sets the updater of the updated records.
+func (s *actorStamp) updated(db *gorm.DB) {
+	actorID, ok := s.actor(db)
+	if !ok {
+		return
+	}
+	if field := actorField(db.Statement.Schema, "UpdatedBy"); field != nil {
+		db.Statement.SetColumn(field.DBName, actorID, true)
+	}
+}
+
+// deleted sets the deleter of soft-deleted records: it builds the soft delete of gorm, then
+// rebuilds it with the deleted_by column added, leaving gorm:delete to run it. It runs as part
+// of gorm:delete, once the clauses of the callbacks before it were added.
+func (s *actorStamp) deleted(db *gorm.DB) {
+	actorID, ok := s.actor(db)
+	stmt := db.Statement
+	if !ok || stmt.Unscoped || stmt.SQL.Len() > 0 {
+		return
+	}
+	field := actorField(stmt.Schema, "DeletedBy")
+	if field == nil {
+		return
+	}
+
+	for _, c := range stmt.Schema.DeleteClauses {
+		if softDelete, ok := c.(gorm.SoftDeleteDeleteClause); ok {
+			softDelete.ModifyStatement(stmt)
+		}
+	}
+	set, ok := stmt.Clauses["SET"].Expression.(clause.Set)
+	if !ok || stmt.SQL.Len() == 0 {
+		return
+	}
+
+	stmt.AddClause(append(set, clause.Assignment{Column: clause.Column{Name: field.DBName}, Value: actorID}))
+	stmt.SetColumn(field.DBName, actorID, true)
+	stmt.SQL.Reset()
+	stmt.Vars = nil
+	stmt.Build(db.Callback().Update().Clauses...)
+}
+
+// actor returns the ID of the actor of a statement writing a model, false without one.
+func (s *actorStamp) actor(db *gorm.DB) (string, bool) {
+	if db.Error != nil || db.Statement.Schema == nil {
+		return "", false
+	}
+	actor, ok := ActorFromContext(db.Statement.Context)
+	return actor.ID, ok && actor.ID != ""
+}
+
+// actorField returns the field of table tagged with name, such as `gormext:"createdBy"`, or
+// named name.
+func actorField(table *schema.Schema, name string) *schema.Field {
+	var named *schema.Field
+	for _, field := range table.Fields {
+		if _, ok := schema.ParseTagSetting(field.Tag.Get("gormext"), ";")[strings.ToUpper(name)]; ok {
+			return field
+		}
+		if field.Name == name {
+			named = field
+		}
+	}
+	return named
+}
@@ -0,0 +1,60 @@
+package gormext
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type (
+	// stampedTicket is a model with creator and updater columns.
+	stampedTicket struct {
+		ID        uint
+		Title     string
+		CreatedBy string
+		UpdatedBy *string
+	}
+
+	// taggedTicket is a model whose creator column is tagged.
+	taggedTicket struct {
+		ID     uint
+		Author string `gormext:"createdBy"`
+	}
+)
+
+// TestStampActor verifies that creates and updates record the context actor.
+func TestStampActor(t *testing.T) {
+	g, err := NewGorm(newTestDatabaseContext(), nil, nil, nil, Config{StampActor: true})
+	assert.NoError(t, err)
+	assert.NoError(t, g.Migrate(&stampedTicket{}, &taggedTicket{}))
+	alice := g.GetDB().WithContext(ContextWithActor(context.Background(), "alice"))
+	bob := g.GetDB().WithContext(ContextWithActor(context.Background(), "bob"))
+
+	tickets := []stampedTicket{{Title: "first"}, {Title: "imported", CreatedBy: "legacy"}}
+	assert.NoError(t, alice.Create(&tickets))
+	assert.Equal(t, "alice", tickets[0].CreatedBy)
+	assert.Equal(t, "legacy", tickets[1].CreatedBy)
+	assert.Equal(t, "alice", *tickets[1].UpdatedBy)
+
+	tickets[0].Title = "edited"
+	assert.NoError(t, bob.Update(&tickets[0]))
+	var stored stampedTicket
+	assert.NoError(t, g.GetDB().FirstByID(tickets[0].ID, &stored))
+	assert.Equal(t, "alice", stored.CreatedBy)
+	assert.Equal(t, "bob", *stored.UpdatedBy)
+
+	conn := g.connection.WithContext(ContextWithActor(context.Background(), "carol"))
+	assert.NoError(t, conn.Model(&stored).Update("title", "renamed").Error)
+	assert.NoError(t, g.GetDB().FirstByID(tickets[0].ID, &stored))
+	assert.Equal(t, "carol", *stored.UpdatedBy)
+
+	anonymous := stampedTicket{Title: "anonymous"}
+	assert.NoError(t, g.GetDB().Create(&anonymous))
+	assert.Empty(t, anonymous.CreatedBy)
+	assert.Nil(t, anonymous.UpdatedBy)
+
+	tagged := taggedTicket{}
+	assert.NoError(t, alice.Create(&tagged))
+	assert.Equal(t, "alice", tagged.Author)
+}
@@ -0,0 +1,191 @@
+package gormext
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var (
+	// privilegePattern matches SQL privilege names such as SELECT or ALL PRIVILEGES.
+	privilegePattern = regexp.MustCompile(`^[A-Za-z]+( [A-Za-z]+)*$`)
+
+	// grantObjectPattern matches grant targets such as "TABLE users", "ALL TABLES IN SCHEMA public" or "app.*".
+	grantObjectPattern = regexp.MustCompile(`^[A-Za-z0-9_.* ]+$`)
+)
+
+type (
+	// Admin provides idempotent provisioning helpers for deployment tooling. The connection
+	// must use an account allowed to create roles and databases.
+	Admin struct {
+		g *Gorm
+	}
+
+	// Grant describes privileges granted to a role on a database object.
+	Grant struct {
+		Privileges []string // Privileges, e.g. SELECT, INSERT, UPDATE.
+		On         string   // Target object, e.g. "ALL TABLES IN SCHEMA public" (Postgres) or "app.*" (MySQL).
+	}
+)
+
+// Admin returns the provisioning helpers for the connection.
+func (g *Gorm) Admin() *Admin {
+	return &Admin{g: g}
+}
+
+// EnsureRole creates the login role name if it does not exist and applies the grants.
+// On MySQL name may include the host ("app@10.0.%"); it defaults to any host.
+func (a *Admin) EnsureRole(name string, grants ...Grant) error {
+	role, err := a.role(name)
+	if err != nil {
+		return err
+	}
+
+	switch a.g.databaseCtx.driver {
+	case PostgreSQL:
+		var exists int64
+		if err := a.g.connection.Raw("SELECT count(*) FROM pg_roles WHERE rolname = ?", name).Scan(&exists).Error; err != nil {
+			return fmt.Errorf("failed to look up role '%s': %w", name, err)
+		}
+		if exists == 0 {
+			if err := a.g.connection.Exec("CREATE ROLE " + role + " LOGIN").Error; err != nil {
+				return fmt.Errorf("failed to create role '%s': %w", name, err)
+			}
+		}
+	case MySQL:
+		if err := a.g.connection.Exec("CREATE USER IF NOT EXISTS " + role).Error; err != nil {
+			return fmt.Errorf("failed to create role '%s': %w", name, err)
+		}
+	}
+
+	for _, grant := range grants {
+		stmt, err := a.grantSQL(role, grant)
+		if err != nil {
+			return err
+		}
+		if err := a.g.connection.Exec(stmt).Error; err != nil {
+			return fmt.Errorf("failed to grant '%s' to role '%s': %w", strings.Join(grant.Privileges, ", "), name, err)
+		}
+	}
+	return nil
+}
+
+// SetPassword sets the password of role name.
+func (a *Admin) SetPassword(name, password string) error {
+	role, err := a.role(name)
+	if err != nil {
+		return err
+	}
+
+	stmt := fmt.Sprintf("ALTER ROLE %s WITH PASSWORD %s", role, a.literal(password))
+	if a.g.databaseCtx.driver == MySQL {
+		stmt = fmt.Sprintf("ALTER USER %s IDENTIFIED BY %s", role, a.literal(password))
+	}
+
+	if err := a.g.connection.Exec(stmt).Error; err != nil {
+		return fmt.Errorf("failed to set password of role '%s': %w", name, err)
+	}
+	return nil
+}
+
+// EnsureDatabase creates the database name if it does not exist.
+func (a *Admin) EnsureDatabase(name string) error {
+	if !routineNamePattern.MatchString(name) || strings.Contains(name, ".") {
+		return fmt.Errorf("invalid database name '%s'", name)
+	}
+
+	quoted := a.g.connection.Statement.Quote(name)
+	switch a.g.databaseCtx.driver {
+	case PostgreSQL:
+		// CREATE DATABASE cannot run inside a DO block or transaction, so check first.
+		var exists int64
+		if err := a.g.connection.Raw("SELECT count(*) FROM pg_database WHERE datname = ?", name).Scan(&exists).Error; err != nil {
+			return fmt.Errorf("failed to look up database '%s': %w", name, err)
+		}
+		if exists > 0 {
+			return nil
+		}
+		if err := a.g.connection.Exec("CREATE DATABASE " + quoted).Error; err != nil {
+			return fmt.Errorf("failed to create database '%s': %w", name, err)
+		}
+	case MySQL:
+		if err := a.g.connection.Exec("CREATE DATABASE IF NOT EXISTS " + quoted).Error; err != nil {
+			return fmt.Errorf("failed to create database '%s': %w", name, err)
+		}
+	default:
+		return fmt.Errorf("%w: database provisioning on '%s'", ErrUnsupportedDriver, a.g.databaseCtx.GetDriverAlias())
+	}
+	return nil
+}
+
+// EnsureSchema creates the schema name if it does not exist: a Postgres schema, or a MySQL
+// database, MySQL schemas being databases.
+func (a *Admin) EnsureSchema(name string) error {
+	switch a.g.databaseCtx.driver {
+	case PostgreSQL:
+		if !routineNamePattern.MatchString(name) || strings.Contains(name, ".") {
+			return fmt.Errorf("invalid schema name '%s'", name)
+		}
+		if err := a.g.connection.Exec("CREATE SCHEMA IF NOT EXISTS " + a.g.connection.Statement.Quote(name)).Error; err != nil {
+			return fmt.Errorf("failed to create schema '%s': %w", name, err)
+		}
+	case MySQL:
+		return a.EnsureDatabase(name)
+	default:
+		return fmt.Errorf("%w: schema provisioning on '%s'", ErrUnsupportedDriver, a.g.databaseCtx.GetDriverAlias())
+	}
+	return nil
+}
+
+// role validates name and returns it quoted as a role (Postgres) or account (MySQL).
+func (a *Admin) role(name string) (string, error) {
+	switch a.g.databaseCtx.driver {
+	case PostgreSQL:
+		if !routineNamePattern.MatchString(name) || strings.Contains(name, ".") {
+			return "", fmt.Errorf("invalid role name '%s'", name)
+		}
+		return a.g.connection.Statement.Quote(name), nil
+	case MySQL:
+		user, host, found := strings.Cut(name, "@")
+		if !found {
+			host = "%"
+		}
+		if !routineNamePattern.MatchString(user) || strings.Contains(user, ".") || strings.ContainsAny(host, "'\\") {
+			return "", fmt.Errorf("invalid role name '%s'", name)
+		}
+		return fmt.Sprintf("'%s'@'%s'", user, host), nil
+	default:
+		return "", fmt.Errorf("%w: role provisioning on '%s'", ErrUnsupportedDriver, a.g.databaseCtx.GetDriverAlias())
+	}
+}
+
+// grantSQL builds the GRANT statement for an already quoted role.
+func (a *Admin) grantSQL(role string, grant Grant) (string, error) {
+	if len(grant.Privileges) == 0 {
+		return "", fmt.Errorf("grant on '%s' has no privileges", grant.On)
+	}
+
+	privileges := make([]string, len(grant.Privileges))
+	for i, privilege := range grant.Privileges {
+		privilege = strings.ToUpper(strings.TrimSpace(privilege))
+		if !privilegePattern.MatchString(privilege) {
+			return "", fmt.Errorf("invalid privilege '%s'", privilege)
+		}
+		privileges[i] = privilege
+	}
+
+	on := strings.TrimSpace(grant.On)
+	if !grantObjectPattern.MatchString(on) {
+		return "", fmt.Errorf("invalid grant target '%s'", grant.On)
+	}
+
+	return fmt.Sprintf("GRANT %s ON %s TO %s", strings.Join(privileges, ", "), on, role), nil
+}
+
+// literal quotes s as a SQL string literal. MySQL also treats backslashes as escapes.
+func (a *Admin) literal(s string) string {
+	if a.g.databaseCtx.driver == MySQL {
+		s = strings.ReplaceAll(s, `\`, `\\`)
+	}
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
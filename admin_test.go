@@ -0,0 +1,45 @@
+package gormext
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestAdminGrantSQL verifies roles and grants are rendered per driver.
+func TestAdminGrantSQL(t *testing.T) {
+	pg := newDryRunGorm(t, PostgreSQL).Admin()
+	role, err := pg.role("app_rw")
+	assert.NoError(t, err)
+	stmt, err := pg.grantSQL(role, Grant{Privileges: []string{"select", "insert"}, On: "ALL TABLES IN SCHEMA public"})
+	assert.NoError(t, err)
+	assert.Equal(t, `GRANT SELECT, INSERT ON ALL TABLES IN SCHEMA public TO "app_rw"`, stmt)
+	assert.Equal(t, `'it''s\x'`, pg.literal(`it's\x`))
+
+	my := newDryRunGorm(t, MySQL).Admin()
+	role, err = my.role("app_ro@10.0.%")
+	assert.NoError(t, err)
+	stmt, err = my.grantSQL(role, Grant{Privileges: []string{"SELECT"}, On: "app.*"})
+	assert.NoError(t, err)
+	assert.Equal(t, `GRANT SELECT ON app.* TO 'app_ro'@'10.0.%'`, stmt)
+	assert.Equal(t, `'it''s\\x'`, my.literal(`it's\x`))
+}
+
+// TestAdminValidation verifies unsafe names and unsupported drivers are rejected.
+func TestAdminValidation(t *testing.T) {
+	pg := newDryRunGorm(t, PostgreSQL).Admin()
+
+	_, err := pg.role(`app"; DROP ROLE postgres; --`)
+	assert.ErrorContains(t, err, "invalid role name")
+
+	_, err = pg.grantSQL(`"app"`, Grant{Privileges: []string{"SELECT; DROP"}, On: "users"})
+	assert.ErrorContains(t, err, "invalid privilege")
+
+	_, err = pg.grantSQL(`"app"`, Grant{Privileges: []string{"SELECT"}, On: "users; DROP TABLE x"})
+	assert.ErrorContains(t, err, "invalid grant target")
+
+	g, _ := newTestRepository(t)
+	assert.ErrorIs(t, g.Admin().EnsureRole("app"), ErrUnsupportedDriver)
+	assert.ErrorIs(t, g.Admin().EnsureDatabase("app"), ErrUnsupportedDriver)
+	assert.ErrorIs(t, g.Admin().EnsureSchema("app"), ErrUnsupportedDriver)
+}
@@ -0,0 +1,294 @@
+package gormext
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+// auditOldKey is the statement instance setting holding the entities as they were before an
+// update or delete.
+const auditOldKey = "gormext:audit_old"
+
+type (
+	// AuditLog is a row of the audit_log table, recording a write of an audited entity.
+	AuditLog struct {
+		ID        uint64 `gorm:"primaryKey;autoIncrement"`
+		Entity    string `gorm:"size:128;index:idx_audit_log_entity"` // Table of the entity.
+		EntityID  string `gorm:"size:128;index:idx_audit_log_entity"` // Primary key, comma separated when composite.
+		Operation string `gorm:"size:16"`                             // create, update or delete.
+		Diff      string // JSON object of the changed columns, see AuditChange.
+		ActorID   string `gorm:"size:128"` // ID of the context Actor, if any.
+		ActorName string // Name of the context Actor, if any.
+		CreatedAt time.Time
+	}
+
+	// AuditChange is the change of a column in AuditLog.Diff: only New for creates, only Old
+	// for deletes.
+	AuditChange struct {
+		Old any `json:"old,omitempty"`
+		New any `json:"new,omitempty"`
+	}
+
+	// AuditOptions configures the auditing of a model.
+	AuditOptions struct {
+		Omit []string // Fields or columns left out of the diff, such as secrets.
+	}
+
+	// Auditor is a gorm plugin recording the creates, updates and deletes of entities of the
+	// audited models into the audit_log table, in the transaction of the write, along with the
+	// Actor of the statement context. Statements without entities, such as raw SQL or updates
+	// by condition, are not audited.
+	Auditor struct {
+		models map[reflect.Type]AuditOptions
+	}
+)
+
+// TableName returns the audit log table name.
+func (AuditLog) TableName() string {
+	return "audit_log"
+}
+
+// NewAuditor returns an audit plugin, to register with (*Gorm).Use once its models are set.
+func NewAuditor() *Auditor {
+	return &Auditor{models: make(map[reflect.Type]AuditOptions)}
+}
+
+// Model audits the writes of model with options.
+func (a *Auditor) Model(model any, options AuditOptions) *Auditor {
+	modelType := reflect.TypeOf(model)
+	for modelType.Kind() == reflect.Pointer {
+		modelType = modelType.Elem()
+	}
+	a.models[modelType] = options
+	return a
+}
+
+// Name returns the plugin name.
+func (a *Auditor) Name() string {
+	return "gormext:audit"
+}
+
+// Initialize creates the audit_log table and registers the callbacks auditing writes.
+func (a *Auditor) Initialize(db *gorm.DB) error {
+	if err := db.AutoMigrate(&AuditLog{}); err != nil {
+		return fmt.Errorf("failed to create audit log table: %w", err)
+	}
+
+	const (
+		loadName  = "gormext:audit_load"
+		writeName = "gormext:audit"
+	)
+	callbacks := db.Callback()
+	return errors.Join(
+		callbacks.Update().Before("gorm:update").Register(loadName, a.load),
+		callbacks.Delete().Before("gorm:delete").Register(loadName, a.load),
+		callbacks.Create().After("gorm:create").Register(writeName, a.record("create")),
+		callbacks.Update().After("gorm:update").Register(writeName, a.record("update")),
+		callbacks.Delete().After("gorm:delete").Register(writeName, a.record("delete")),
+	)
+}
+
+// load stores the entities about to be updated or deleted, as they are in the database.
+func (a *Auditor) load(db *gorm.DB) {
+	if db.Error != nil || !a.audited(db.Statement) {
+		return
+	}
+
+	stmt := db.Statement
+	old := make([]reflect.Value, 0)
+	for _, entity := range auditEntities(stmt) {
+		conditions, ok := primaryConditions(stmt, entity)
+		if !ok {
+			old = append(old, reflect.Value{})
+			continue
+		}
+
+		value := reflect.New(stmt.Schema.ModelType)
+		err := db.Session(&gorm.Session{NewDB: true, SkipHooks: true}).Set(unmaskedKey, true).
+			Table(stmt.Table).Where(conditions).Take(value.Interface()).Error
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			old = append(old, reflect.Value{})
+		case err != nil:
+			db.AddError(fmt.Errorf("failed to load audited entity: %w", err))
+			return
+		default:
+			old = append(old, value.Elem())
+		}
+	}
+	db.InstanceSet(auditOldKey, old)
+}
+
+// record returns the callback writing the audit log rows of a successful write.
+func (a *Auditor) record(operation string) func(*gorm.DB) {
+	return func(db *gorm.DB) {
+		if db.Error != nil || db.Statement.RowsAffected == 0 || !a.audited(db.Statement) {
+			return
+		}
+
+		stmt := db.Statement
+		options := a.models[stmt.Schema.ModelType]
+		var old []reflect.Value
+		if value, ok := db.InstanceGet(auditOldKey); ok {
+			old = value.([]reflect.Value)
+		}
+
+		actor, _ := ActorFromContext(stmt.Context)
+		var logs []AuditLog
+		for i, entity := range auditEntities(stmt) {
+			var before reflect.Value
+			if i < len(old) {
+				before = old[i]
+			}
+			if operation != "create" && !before.IsValid() {
+				continue
+			}
+
+			after := entity
+			if operation == "delete" {
+				after = reflect.Value{}
+			}
+			diff := auditDiff(stmt, options, before, after)
+			if len(diff) == 0 {
+				continue
+			}
+
+			encoded, err := json.Marshal(diff)
+			if err != nil {
+				db.AddError(fmt.Errorf("failed to encode audit diff of '%s': %w", stmt.Table, err))
+				return
+			}
+			logs = append(logs, AuditLog{
+				Entity:    stmt.Table,
+				EntityID:  primaryKey(stmt, entity),
+				Operation: operation,
+				Diff:      string(encoded),
+				ActorID:   actor.ID,
+				ActorName: actor.Name,
+			})
+		}
+		if len(logs) == 0 {
+			return
+		}
+
+		if err := db.Session(&gorm.Session{NewDB: true, SkipHooks: true}).Create(&logs).Error; err != nil {
+			db.AddError(fmt.Errorf("failed to write audit log of '%s': %w", stmt.Table, err))
+		}
+	}
+}
+
+// audited reports whether the statement writes entities of an audited model.
+func (a *Auditor) audited(stmt *gorm.Statement) bool {
+	if stmt.Schema == nil {
+		return false
+	}
+	_, ok := a.models[stmt.Schema.ModelType]
+	return ok
+}
+
+// auditEntities returns the entities written by the statement.
+func auditEntities(stmt *gorm.Statement) []reflect.Value {
+	value := reflect.Indirect(stmt.ReflectValue)
+	switch value.Kind() {
+	case reflect.Struct:
+		return []reflect.Value{value}
+	case reflect.Slice, reflect.Array:
+		entities := make([]reflect.Value, 0, value.Len())
+		for i := 0; i < value.Len(); i++ {
+			if entity := reflect.Indirect(value.Index(i)); entity.Kind() == reflect.Struct {
+				entities = append(entities, entity)
+			}
+		}
+		return entities
+	}
+	return nil
+}
+
+// primaryConditions returns the primary key conditions of entity, false when a key is zero.
+func primaryConditions(stmt *gorm.Statement, entity reflect.Value) (map[string]any, bool) {
+	if len(stmt.Schema.PrimaryFields) == 0 {
+		return nil, false
+	}
+
+	conditions := make(map[string]any, len(stmt.Schema.PrimaryFields))
+	for _, field := range stmt.Schema.PrimaryFields {
+		value, zero := field.ValueOf(stmt.Context, entity)
+		if zero {
+			return nil, false
+		}
+		conditions[field.DBName] = value
+	}
+	return conditions, true
+}
+
+// primaryKey returns the primary key of entity as text, comma separated when composite.
+func primaryKey(stmt *gorm.Statement, entity reflect.Value) string {
+	keys := make([]string, 0, len(stmt.Schema.PrimaryFields))
+	for _, field := range stmt.Schema.PrimaryFields {
+		value, _ := field.ValueOf(stmt.Context, entity)
+		keys = append(keys, fmt.Sprint(value))
+	}
+	return strings.Join(keys, ",")
+}
+
+// auditDiff returns the columns that differ between the before and after entities, either of
+// which may be invalid for creates and deletes.
+func auditDiff(stmt *gorm.Statement, options AuditOptions, before, after reflect.Value) map[string]AuditChange {
+	diff := make(map[string]AuditChange)
+	for _, field := range stmt.Schema.Fields {
+		if field.DBName == "" || omitted(options, field) {
+			continue
+		}
+
+		var change AuditChange
+		if before.IsValid() {
+			change.Old = auditValue(stmt, field, before)
+		}
+		if after.IsValid() {
+			change.New = auditValue(stmt, field, after)
+		}
+		if before.IsValid() && after.IsValid() && auditEqual(change.Old, change.New) {
+			continue
+		}
+		diff[field.DBName] = change
+	}
+	return diff
+}
+
+// omitted reports whether field is left out of the diff by options.
+func omitted(options AuditOptions, field *schema.Field) bool {
+	for _, name := range options.Omit {
+		if name == field.Name || name == field.DBName {
+			return true
+		}
+	}
+	return false
+}
+
+// auditValue returns the value of field in entity, nil for nil pointers.
+func auditValue(stmt *gorm.Statement, field *schema.Field, entity reflect.Value) any {
+	value, _ := field.ValueOf(stmt.Context, entity)
+	if v := reflect.ValueOf(value); v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return nil
+		}
+		return v.Elem().Interface()
+	}
+	return value
+}
+
+// auditEqual reports whether two column values are equal, comparing times by instant.
+func auditEqual(a, b any) bool {
+	if ta, ok := a.(time.Time); ok {
+		tb, ok := b.(time.Time)
+		return ok && ta.Equal(tb)
+	}
+	return reflect.DeepEqual(a, b)
+}
@@ -0,0 +1,87 @@
+package gormext
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type auditedUser struct {
+	ID       uint
+	Name     string
+	Password string
+}
+
+type unauditedNote struct {
+	ID   uint
+	Text string
+}
+
+// TestAuditor verifies that creates, updates and deletes of audited models are logged with
+// their diff and actor, leaving omitted fields and other models out.
+func TestAuditor(t *testing.T) {
+	g, err := NewGorm(newTestDatabaseContext(), nil, nil, nil)
+	assert.NoError(t, err)
+	assert.NoError(t, g.Migrate(&auditedUser{}, &unauditedNote{}))
+	assert.NoError(t, g.Use(NewAuditor().Model(&auditedUser{}, AuditOptions{Omit: []string{"Password"}})))
+
+	ctx := WithActor(context.Background(), Actor{ID: "42", Name: "Ana"})
+	repo := g.GetDB().WithContext(ctx)
+	user := &auditedUser{Name: "Bia", Password: "secret"}
+	assert.NoError(t, repo.Create(user))
+	user.Name, user.Password = "Carla", "changed"
+	assert.NoError(t, repo.Update(user))
+	user.Password = "changed again"
+	assert.NoError(t, repo.Update(user))
+	assert.NoError(t, repo.Delete(user))
+	assert.NoError(t, repo.Create(&unauditedNote{Text: "hello"}))
+
+	var logs []AuditLog
+	assert.NoError(t, g.connection.Order("id").Find(&logs).Error)
+	if !assert.Len(t, logs, 3, "Unchanged updates, omitted fields and other models should not be logged") {
+		return
+	}
+
+	diffs := make([]map[string]AuditChange, len(logs))
+	for i, log := range logs {
+		assert.Equal(t, "audited_users", log.Entity)
+		assert.Equal(t, "1", log.EntityID)
+		assert.Equal(t, "42", log.ActorID)
+		assert.Equal(t, "Ana", log.ActorName)
+		assert.False(t, log.CreatedAt.IsZero())
+		assert.NoError(t, json.Unmarshal([]byte(log.Diff), &diffs[i]))
+	}
+
+	assert.Equal(t, "create", logs[0].Operation)
+	assert.Equal(t, AuditChange{New: "Bia"}, diffs[0]["name"])
+	assert.NotContains(t, diffs[0], "password")
+
+	assert.Equal(t, "update", logs[1].Operation)
+	assert.Equal(t, map[string]AuditChange{"name": {Old: "Bia", New: "Carla"}}, diffs[1])
+
+	assert.Equal(t, "delete", logs[2].Operation)
+	assert.Equal(t, AuditChange{Old: "Carla"}, diffs[2]["name"])
+}
+
+// TestAuditorMaskedFields verifies that the Auditor compares the stored values of masked
+// fields, not their masked reads.
+func TestAuditorMaskedFields(t *testing.T) {
+	g, err := NewGorm(newTestDatabaseContext(), nil, nil, nil, Config{MaskFields: true})
+	assert.NoError(t, err)
+	assert.NoError(t, g.Migrate(&maskedCustomer{}))
+	assert.NoError(t, g.Use(NewAuditor().Model(&maskedCustomer{}, AuditOptions{})))
+
+	repo := g.GetDB()
+	customer := &maskedCustomer{Name: "Jane Doe", Email: "jane@example.com", City: "Recife"}
+	assert.NoError(t, repo.Create(customer))
+	customer.City = "Olinda"
+	assert.NoError(t, repo.Update(customer))
+
+	var log AuditLog
+	assert.NoError(t, g.connection.Where("operation = ?", "update").Take(&log).Error)
+	var diff map[string]AuditChange
+	assert.NoError(t, json.Unmarshal([]byte(log.Diff), &diff))
+	assert.Equal(t, map[string]AuditChange{"city": {Old: "Recife", New: "Olinda"}}, diff)
+}
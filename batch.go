@@ -0,0 +1,97 @@
+package gormext
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+type (
+	// Validator is implemented by entities that can validate themselves before being created.
+	Validator interface {
+		Validate() error
+	}
+
+	// RowError describes the failure of a single row in a batch operation.
+	RowError struct {
+		Index int
+		Err   error
+	}
+
+	// BatchResult collects the outcome of a batch operation row by row.
+	BatchResult struct {
+		Total   int
+		Created int
+		Errors  []RowError
+	}
+)
+
+// Error implements the error interface.
+func (e RowError) Error() string {
+	return fmt.Sprintf("row %d: %v", e.Index, e.Err)
+}
+
+// Unwrap returns the underlying row error.
+func (e RowError) Unwrap() error {
+	return e.Err
+}
+
+// HasErrors reports whether any row failed.
+func (r *BatchResult) HasErrors() bool {
+	return len(r.Errors) > 0
+}
+
+// Failed returns the number of rows that failed.
+func (r *BatchResult) Failed() int {
+	return len(r.Errors)
+}
+
+// Err returns all row errors joined together, or nil if every row was created.
+func (r *BatchResult) Err() error {
+	errs := make([]error, len(r.Errors))
+	for i, rowErr := range r.Errors {
+		errs[i] = rowErr
+	}
+	return errors.Join(errs...)
+}
+
+// CreateBatch creates every element of the entities slice, continuing past failures and
+// recording them by row index. Entities implementing Validator are validated first. Each
+// row runs in its own transaction (a savepoint when repo is already transactional), so a
+// failed row never aborts the others. Enable Config.TranslateError to get constraint
+// violations as gorm.ErrDuplicatedKey / gorm.ErrForeignKeyViolated.
+func CreateBatch(repo IRepository, entities any) *BatchResult {
+	rv := reflect.Indirect(reflect.ValueOf(entities))
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return &BatchResult{Total: 1, Errors: []RowError{{Index: 0, Err: fmt.Errorf("invalid batch type %T, expected a slice", entities)}}}
+	}
+
+	result := &BatchResult{Total: rv.Len()}
+	for i := 0; i < rv.Len(); i++ {
+		row := rv.Index(i)
+		if row.Kind() != reflect.Ptr && row.CanAddr() {
+			row = row.Addr()
+		}
+		entity := row.Interface()
+
+		if err := createRow(repo, entity); err != nil {
+			result.Errors = append(result.Errors, RowError{Index: i, Err: err})
+			continue
+		}
+		result.Created++
+	}
+	return result
+}
+
+// createRow validates and creates a single entity in its own transaction.
+func createRow(repo IRepository, entity any) error {
+	if validator, ok := entity.(Validator); ok {
+		if err := validator.Validate(); err != nil {
+			return err
+		}
+	}
+
+	return repo.WithTransaction(func(tx IRepository) error {
+		return tx.Create(entity)
+	})
+}
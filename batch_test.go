@@ -0,0 +1,60 @@
+package gormext
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type importedProduct struct {
+	ID    uint   `gorm:"primaryKey"`
+	SKU   string `gorm:"uniqueIndex"`
+	Price int
+}
+
+var errNegativePrice = errors.New("price must not be negative")
+
+func (p *importedProduct) Validate() error {
+	if p.Price < 0 {
+		return errNegativePrice
+	}
+	return nil
+}
+
+// TestCreateBatchCollectsRowErrors verifies failed rows are reported by index without aborting the batch.
+func TestCreateBatchCollectsRowErrors(t *testing.T) {
+	g, repo := newTestRepository(t)
+	assert.NoError(t, g.Migrate(&importedProduct{}))
+
+	rows := []importedProduct{
+		{SKU: "a", Price: 10},
+		{SKU: "b", Price: -1},
+		{SKU: "a", Price: 20},
+		{SKU: "c", Price: 30},
+	}
+
+	result := CreateBatch(repo, rows)
+	assert.Equal(t, 4, result.Total)
+	assert.Equal(t, 2, result.Created)
+	assert.True(t, result.HasErrors())
+	if assert.Len(t, result.Errors, 2) {
+		assert.Equal(t, 1, result.Errors[0].Index)
+		assert.ErrorIs(t, result.Errors[0], errNegativePrice)
+		assert.Equal(t, 2, result.Errors[1].Index)
+	}
+	assert.NotZero(t, rows[3].ID, "Created rows should receive their primary key")
+
+	var count int64
+	assert.NoError(t, repo.Table("imported_products").Count(&count))
+	assert.Equal(t, int64(2), count)
+}
+
+// TestCreateBatchRejectsNonSlice verifies a clear error is reported for non-slice input.
+func TestCreateBatchRejectsNonSlice(t *testing.T) {
+	_, repo := newTestRepository(t)
+
+	result := CreateBatch(repo, &importedProduct{})
+	assert.True(t, result.HasErrors())
+	assert.Contains(t, result.Err().Error(), "expected a slice")
+}
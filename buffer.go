@@ -0,0 +1,317 @@
+package gormext
+
+import (
+	"context"
+	"database/sql/driver"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+	"time"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/logger"
+)
+
+// BufferMode controls how buffered writes are replayed against the primary database.
+//
+// Delivery is at-least-once in every mode: an entry is removed from the local buffer only
+// after the primary accepted it, so a crash between the two steps replays the entry again.
+// Entries enqueued with the same non-empty dedup key are stored once until flushed.
+type BufferMode uint
+
+const (
+	// BufferOrdered replays entries strictly in the order they were buffered and stops at
+	// the first failure, so a later entry is never applied before an earlier one.
+	BufferOrdered BufferMode = iota
+
+	// BufferBestEffort replays every entry it can, keeping failed entries in the buffer for
+	// the next flush. Entries may reach the primary out of order.
+	BufferBestEffort
+)
+
+// writeBufferTable is the name of the table used by the local buffer database.
+const writeBufferTable = "gormext_write_buffer"
+
+// ErrBufferedWrite is returned by WriteBuffer.Write when the primary database is unreachable
+// and the entity was stored in the local buffer instead.
+var ErrBufferedWrite = errors.New("write buffered locally, primary database unavailable")
+
+type (
+	// WriteBuffer stores low-criticality writes (metrics, logs, audit) in a local SQLite
+	// file while the primary database is unreachable and flushes them once it recovers.
+	WriteBuffer struct {
+		primary *gorm.DB
+		local   *gorm.DB
+		mode    BufferMode
+		mu      sync.Mutex
+	}
+
+	// bufferedWrite is a single pending insert kept in the local buffer database.
+	bufferedWrite struct {
+		ID        uint64  `gorm:"primaryKey;autoIncrement"`
+		DedupKey  *string `gorm:"uniqueIndex"`
+		Target    string  `gorm:"not null"`
+		Payload   string  `gorm:"not null"`
+		CreatedAt time.Time
+	}
+
+	// bufferedValue is the JSON representation of a column value, keeping its driver type.
+	bufferedValue struct {
+		Type  string          `json:"t"`
+		Value json.RawMessage `json:"v,omitempty"`
+	}
+)
+
+// TableName returns the table name of the local buffer.
+func (bufferedWrite) TableName() string {
+	return writeBufferTable
+}
+
+// NewWriteBuffer creates a WriteBuffer in front of the Gorm primary database, persisting
+// pending writes to the SQLite file at path (opened in WAL mode).
+func NewWriteBuffer(g *Gorm, path string, mode BufferMode) (*WriteBuffer, error) {
+	if path == "" {
+		return nil, fmt.Errorf("invalid write buffer path")
+	}
+
+	local, err := gorm.Open(sqlite.Open(path+"?_journal_mode=WAL&_busy_timeout=5000"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open write buffer '%s': %w", path, err)
+	}
+
+	if err := local.AutoMigrate(&bufferedWrite{}); err != nil {
+		return nil, fmt.Errorf("failed to prepare write buffer '%s': %w", path, err)
+	}
+
+	return &WriteBuffer{primary: g.connection, local: local, mode: mode}, nil
+}
+
+// Write inserts the entity into the primary database. If the primary is unreachable the
+// entity is stored in the local buffer and ErrBufferedWrite is returned; any other error is
+// returned as is. A non-empty dedupKey makes repeated buffering of the same write a no-op.
+func (b *WriteBuffer) Write(ctx context.Context, entity any, dedupKey string) error {
+	err := b.primary.WithContext(ctx).Create(entity).Error
+	if err == nil || !b.isOutage(ctx, err) {
+		return err
+	}
+
+	if err := b.enqueue(ctx, entity, dedupKey); err != nil {
+		return fmt.Errorf("failed to buffer write: %w", err)
+	}
+	return ErrBufferedWrite
+}
+
+// Pending returns the number of writes waiting in the local buffer.
+func (b *WriteBuffer) Pending(ctx context.Context) (int64, error) {
+	var count int64
+	err := b.local.WithContext(ctx).Model(&bufferedWrite{}).Count(&count).Error
+	return count, err
+}
+
+// Flush replays buffered writes against the primary database following the buffer mode,
+// returning how many entries were applied.
+func (b *WriteBuffer) Flush(ctx context.Context) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	var entries []bufferedWrite
+	if err := b.local.WithContext(ctx).Order("id").Find(&entries).Error; err != nil {
+		return 0, fmt.Errorf("failed to read write buffer: %w", err)
+	}
+
+	var (
+		flushed int
+		errs    []error
+	)
+	for _, entry := range entries {
+		if err := b.replay(ctx, entry); err != nil {
+			err = fmt.Errorf("failed to flush buffered write %d into '%s': %w", entry.ID, entry.Target, err)
+			if b.mode == BufferOrdered {
+				return flushed, err
+			}
+			errs = append(errs, err)
+			continue
+		}
+		flushed++
+	}
+	return flushed, errors.Join(errs...)
+}
+
+// Run flushes the buffer every interval until the context is cancelled.
+func (b *WriteBuffer) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := b.Flush(ctx); err != nil {
+				b.primary.Logger.Warn(ctx, "write buffer flush: %v", err)
+			}
+		}
+	}
+}
+
+// Close closes the local buffer database.
+func (b *WriteBuffer) Close() error {
+	sqlDB, err := b.local.DB()
+	if err != nil {
+		return err
+	}
+	return sqlDB.Close()
+}
+
+// isOutage reports whether err was caused by the primary database being unreachable.
+func (b *WriteBuffer) isOutage(ctx context.Context, err error) bool {
+	if errors.Is(err, driver.ErrBadConn) {
+		return true
+	}
+
+	sqlDB, dbErr := b.primary.DB()
+	if dbErr != nil {
+		return true
+	}
+	return sqlDB.PingContext(ctx) != nil
+}
+
+// enqueue stores the column values of entity in the local buffer.
+func (b *WriteBuffer) enqueue(ctx context.Context, entity any, dedupKey string) error {
+	stmt := &gorm.Statement{DB: b.primary}
+	if err := stmt.Parse(entity); err != nil {
+		return err
+	}
+
+	rv := reflect.Indirect(reflect.ValueOf(entity))
+	values := make(map[string]bufferedValue, len(stmt.Schema.Fields))
+	for _, field := range stmt.Schema.Fields {
+		if field.DBName == "" || !field.Creatable {
+			continue
+		}
+
+		value, zero := field.ValueOf(ctx, rv)
+		if zero && field.AutoIncrement {
+			continue
+		}
+
+		encoded, err := encodeBufferedValue(value)
+		if err != nil {
+			return fmt.Errorf("column '%s': %w", field.DBName, err)
+		}
+		values[field.DBName] = encoded
+	}
+
+	payload, err := json.Marshal(values)
+	if err != nil {
+		return err
+	}
+
+	entry := bufferedWrite{Target: stmt.Schema.Table, Payload: string(payload)}
+	if dedupKey != "" {
+		entry.DedupKey = &dedupKey
+	}
+	return b.local.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(&entry).Error
+}
+
+// replay inserts a buffered entry into the primary and removes it from the buffer.
+func (b *WriteBuffer) replay(ctx context.Context, entry bufferedWrite) error {
+	var encoded map[string]bufferedValue
+	if err := json.Unmarshal([]byte(entry.Payload), &encoded); err != nil {
+		return err
+	}
+
+	values := make(map[string]any, len(encoded))
+	for column, value := range encoded {
+		decoded, err := decodeBufferedValue(value)
+		if err != nil {
+			return fmt.Errorf("column '%s': %w", column, err)
+		}
+		values[column] = decoded
+	}
+
+	if err := b.primary.WithContext(ctx).Table(entry.Target).Create(values).Error; err != nil {
+		return err
+	}
+	return b.local.WithContext(ctx).Delete(&bufferedWrite{}, entry.ID).Error
+}
+
+// encodeBufferedValue converts a field value to its driver representation and tags it with its type.
+func encodeBufferedValue(value any) (bufferedValue, error) {
+	if valuer, ok := value.(driver.Valuer); ok {
+		v, err := valuer.Value()
+		if err != nil {
+			return bufferedValue{}, err
+		}
+		value = v
+	}
+
+	value, err := driver.DefaultParameterConverter.ConvertValue(value)
+	if err != nil {
+		return bufferedValue{}, err
+	}
+
+	var kind string
+	switch v := value.(type) {
+	case nil:
+		return bufferedValue{Type: "null"}, nil
+	case int64:
+		kind = "int"
+	case float64:
+		kind = "float"
+	case bool:
+		kind = "bool"
+	case string:
+		kind = "string"
+	case time.Time:
+		kind, value = "time", v.Format(time.RFC3339Nano)
+	case []byte:
+		kind, value = "bytes", base64.StdEncoding.EncodeToString(v)
+	default:
+		return bufferedValue{}, fmt.Errorf("unsupported value type %T", value)
+	}
+
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return bufferedValue{}, err
+	}
+	return bufferedValue{Type: kind, Value: raw}, nil
+}
+
+// decodeBufferedValue restores the driver value stored by encodeBufferedValue.
+func decodeBufferedValue(value bufferedValue) (any, error) {
+	var target any
+	switch value.Type {
+	case "null":
+		return nil, nil
+	case "int":
+		target = new(int64)
+	case "float":
+		target = new(float64)
+	case "bool":
+		target = new(bool)
+	case "string", "time", "bytes":
+		target = new(string)
+	default:
+		return nil, fmt.Errorf("unknown buffered value type '%s'", value.Type)
+	}
+
+	if err := json.Unmarshal(value.Value, target); err != nil {
+		return nil, err
+	}
+
+	switch value.Type {
+	case "time":
+		return time.Parse(time.RFC3339Nano, *target.(*string))
+	case "bytes":
+		return base64.StdEncoding.DecodeString(*target.(*string))
+	}
+	return reflect.ValueOf(target).Elem().Interface(), nil
+}
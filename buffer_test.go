@@ -0,0 +1,124 @@
+package gormext
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type bufferedMetric struct {
+	ID        uint `gorm:"primaryKey"`
+	Name      string
+	Value     float64
+	CreatedAt time.Time
+}
+
+// newTestWriteBuffer creates a Gorm instance with a migrated metrics table and a WriteBuffer in front of it.
+func newTestWriteBuffer(t *testing.T, mode BufferMode) (*Gorm, *WriteBuffer) {
+	g, err := NewGorm(newTestDatabaseContext(), dummyRepository, []string{}, map[string]string{})
+	assert.NoError(t, err, "Unexpected error from NewGorm")
+	assert.NoError(t, g.Migrate(&bufferedMetric{}), "Migration failed")
+
+	b, err := NewWriteBuffer(g, filepath.Join(t.TempDir(), "buffer.db"), mode)
+	assert.NoError(t, err, "Unexpected error from NewWriteBuffer")
+	t.Cleanup(func() { b.Close() })
+
+	return g, b
+}
+
+// TestWriteBufferWritesThrough verifies writes go straight to a healthy primary.
+func TestWriteBufferWritesThrough(t *testing.T) {
+	g, b := newTestWriteBuffer(t, BufferOrdered)
+	ctx := context.Background()
+
+	err := b.Write(ctx, &bufferedMetric{Name: "requests", Value: 1}, "")
+	assert.NoError(t, err, "Unexpected error writing to healthy primary")
+
+	pending, err := b.Pending(ctx)
+	assert.NoError(t, err)
+	assert.Zero(t, pending, "Nothing should be buffered")
+
+	var count int64
+	assert.NoError(t, g.connection.Model(&bufferedMetric{}).Count(&count).Error)
+	assert.Equal(t, int64(1), count)
+}
+
+// TestWriteBufferFlushInOrderWithDedup verifies buffered entries are deduplicated and replayed in order.
+func TestWriteBufferFlushInOrderWithDedup(t *testing.T) {
+	g, b := newTestWriteBuffer(t, BufferOrdered)
+	ctx := context.Background()
+	at := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	assert.NoError(t, b.enqueue(ctx, &bufferedMetric{Name: "first", Value: 1.5, CreatedAt: at}, "k1"))
+	assert.NoError(t, b.enqueue(ctx, &bufferedMetric{Name: "first-again", Value: 9, CreatedAt: at}, "k1"))
+	assert.NoError(t, b.enqueue(ctx, &bufferedMetric{Name: "second", Value: 2, CreatedAt: at}, ""))
+
+	pending, err := b.Pending(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), pending, "Duplicate dedup key should be stored once")
+
+	flushed, err := b.Flush(ctx)
+	assert.NoError(t, err, "Unexpected error flushing buffer")
+	assert.Equal(t, 2, flushed)
+
+	var metrics []bufferedMetric
+	assert.NoError(t, g.connection.Order("id").Find(&metrics).Error)
+	if assert.Len(t, metrics, 2) {
+		assert.Equal(t, "first", metrics[0].Name)
+		assert.Equal(t, 1.5, metrics[0].Value)
+		assert.True(t, at.Equal(metrics[0].CreatedAt), "Timestamp should round-trip")
+		assert.Equal(t, "second", metrics[1].Name)
+	}
+
+	pending, err = b.Pending(ctx)
+	assert.NoError(t, err)
+	assert.Zero(t, pending, "Flushed entries should be removed")
+}
+
+// TestWriteBufferOrderedStopsOnFailure verifies ordered mode keeps entries after the first failure.
+func TestWriteBufferOrderedStopsOnFailure(t *testing.T) {
+	_, b := newTestWriteBuffer(t, BufferOrdered)
+	ctx := context.Background()
+
+	assert.NoError(t, b.local.Create(&bufferedWrite{Target: "missing_table", Payload: `{}`}).Error)
+	assert.NoError(t, b.enqueue(ctx, &bufferedMetric{Name: "after"}, ""))
+
+	flushed, err := b.Flush(ctx)
+	assert.Error(t, err, "Expected error replaying into a missing table")
+	assert.Zero(t, flushed)
+
+	pending, err := b.Pending(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), pending, "Ordered mode must not skip the failed entry")
+}
+
+// TestWriteBufferBestEffortSkipsFailures verifies best-effort mode replays the entries after a
+// failed one, reporting the failure and keeping the failed entry for the next flush.
+func TestWriteBufferBestEffortSkipsFailures(t *testing.T) {
+	g, b := newTestWriteBuffer(t, BufferBestEffort)
+	ctx := context.Background()
+
+	failed := bufferedWrite{Target: "missing_table", Payload: `{}`}
+	assert.NoError(t, b.local.Create(&failed).Error)
+	assert.NoError(t, b.enqueue(ctx, &bufferedMetric{Name: "after"}, ""))
+
+	flushed, err := b.Flush(ctx)
+	assert.ErrorContains(t, err, fmt.Sprintf("failed to flush buffered write %d into 'missing_table'", failed.ID))
+	assert.Equal(t, 1, flushed)
+
+	var metrics []bufferedMetric
+	assert.NoError(t, g.connection.Find(&metrics).Error)
+	if assert.Len(t, metrics, 1) {
+		assert.Equal(t, "after", metrics[0].Name)
+	}
+
+	var pending []bufferedWrite
+	assert.NoError(t, b.local.Find(&pending).Error)
+	if assert.Len(t, pending, 1, "Best-effort mode keeps the failed entry only") {
+		assert.Equal(t, failed.ID, pending[0].ID)
+	}
+}
@@ -0,0 +1,453 @@
+package gormext
+
+import (
+	"bytes"
+	"container/list"
+	"context"
+	"encoding/gob"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+const (
+	// cachePluginName is the name of the result cache plugin registered by NewGorm.
+	cachePluginName = "gormext:cache"
+
+	// cacheModeKey is the statement setting holding the cacheMode of NoCache and RefreshCache.
+	cacheModeKey = "gormext:cache_mode"
+
+	// cacheTagPrefix prefixes the store keys holding the current version of the cached results
+	// of a table, replaced by writes to the table.
+	cacheTagPrefix = "gormext:table:"
+)
+
+// cacheVersionSeq tells apart the table versions created in the same instant.
+var cacheVersionSeq atomic.Uint64
+
+// cacheMode is how a statement uses the caches.
+type cacheMode int
+
+const (
+	cacheDefault cacheMode = iota // Read and populate the caches.
+	cacheBypass                   // Read the database, leaving the caches untouched.
+	cacheRefresh                  // Read the database, replacing the cached values.
+)
+
+type (
+	// CacheStore stores the encoded results of cached queries. Implementations must be safe for
+	// concurrent use; see NewMemoryCache and the gormextredis package.
+	CacheStore interface {
+		Get(ctx context.Context, key string) (value []byte, ok bool, err error)     // Return the value of key, if any and not expired.
+		Set(ctx context.Context, key string, value []byte, ttl time.Duration) error // Store value under key for ttl, or without expiry when zero.
+		Delete(ctx context.Context, keys ...string) error                           // Remove keys.
+	}
+
+	// MemoryCache is an in-process CacheStore evicting the least recently used entries.
+	MemoryCache struct {
+		mu       sync.Mutex
+		capacity int
+		entries  map[string]*list.Element
+		order    *list.List
+	}
+
+	// memoryEntry is an entry of a MemoryCache.
+	memoryEntry struct {
+		key       string
+		value     []byte
+		expiresAt time.Time
+	}
+
+	// cacheEntry is a cached result, fresh until Expires.
+	cacheEntry struct {
+		Expires  time.Time `json:"expires"`
+		Value    []byte    `json:"value,omitempty"`     // The result, see encodeResult.
+		NotFound bool      `json:"not_found,omitempty"` // The lookup found no record, see Config.NegativeCacheTTL.
+	}
+
+	// resultCache is the gorm plugin holding the store of the result cache. Results are tagged
+	// with the tables they read, and the writes to a table invalidate its results.
+	resultCache struct {
+		store          CacheStore
+		key            CacheKeyFunc
+		entityRead     bool          // Serve primary key queries of cached entities, see Config.SecondLevelCache.
+		negativeTTL    time.Duration // TTL of the lookups that found no record, see Config.NegativeCacheTTL.
+		entities       sync.Map      // TTL of the records cached by FirstByID, by table.
+		refreshing     sync.Map      // Keys of the results refreshed in the background.
+		misses         queryGroup    // Reads of the missing results.
+		resultCounters cacheCounters // Lookups of Cached results.
+		entityCounters cacheCounters // Lookups of cached entities.
+	}
+)
+
+// NewMemoryCache returns an in-process cache store holding up to capacity entries.
+func NewMemoryCache(capacity int) *MemoryCache {
+	return &MemoryCache{capacity: capacity, entries: make(map[string]*list.Element), order: list.New()}
+}
+
+// Get returns the value of key, if any and not expired.
+func (c *MemoryCache) Get(_ context.Context, key string) ([]byte, bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	element, ok := c.entries[key]
+	if !ok {
+		return nil, false, nil
+	}
+	entry := element.Value.(*memoryEntry)
+	if !entry.expiresAt.IsZero() && time.Now().After(entry.expiresAt) {
+		c.remove(element)
+		return nil, false, nil
+	}
+	c.order.MoveToFront(element)
+	return entry.value, true, nil
+}
+
+// Set stores value under key for ttl, or without expiry when ttl is not positive, evicting the
+// least recently used entry when full.
+func (c *MemoryCache) Set(_ context.Context, key string, value []byte, ttl time.Duration) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var expiresAt time.Time
+	if ttl > 0 {
+		expiresAt = time.Now().Add(ttl)
+	}
+
+	if element, ok := c.entries[key]; ok {
+		entry := element.Value.(*memoryEntry)
+		entry.value, entry.expiresAt = value, expiresAt
+		c.order.MoveToFront(element)
+		return nil
+	}
+
+	c.entries[key] = c.order.PushFront(&memoryEntry{key: key, value: value, expiresAt: expiresAt})
+	for c.capacity > 0 && c.order.Len() > c.capacity {
+		c.remove(c.order.Back())
+	}
+	return nil
+}
+
+// Delete removes keys.
+func (c *MemoryCache) Delete(_ context.Context, keys ...string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, key := range keys {
+		if element, ok := c.entries[key]; ok {
+			c.remove(element)
+		}
+	}
+	return nil
+}
+
+// Len returns the number of entries, expired ones included until they are read or evicted.
+func (c *MemoryCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len()
+}
+
+// remove removes an entry.
+func (c *MemoryCache) remove(element *list.Element) {
+	c.order.Remove(element)
+	delete(c.entries, element.Value.(*memoryEntry).key)
+}
+
+// Name returns the plugin name.
+func (c *resultCache) Name() string {
+	return cachePluginName
+}
+
+// Initialize registers the callbacks invalidating the results of the tables written to, and
+// the second-level cache query callback when enabled.
+func (c *resultCache) Initialize(db *gorm.DB) error {
+	const name = "gormext:cache_invalidate"
+	callbacks := db.Callback()
+	err := errors.Join(
+		callbacks.Create().After("gorm:create").Register(name, c.invalidate),
+		callbacks.Update().After("gorm:update").Register(name, c.invalidate),
+		callbacks.Delete().After("gorm:delete").Register(name, c.invalidate),
+	)
+	if c.entityRead {
+		err = errors.Join(err, callbacks.Query().Replace("gorm:query", c.queryEntities))
+	}
+	return err
+}
+
+// invalidate drops the version of the table written by a successful statement, and once more
+// when its transaction commits, so that results read meanwhile by other connections are not
+// kept either.
+func (c *resultCache) invalidate(db *gorm.DB) {
+	table := db.Statement.Table
+	if db.Error != nil || db.DryRun || table == "" {
+		return
+	}
+
+	ctx := db.Statement.Context
+	ids, entities := c.writtenIDs(db.Statement)
+	drop := func() {
+		if err := c.store.Delete(ctx, cacheTagPrefix+table); err != nil {
+			db.Logger.Warn(ctx, "result cache invalidation of '%s': %v", table, err)
+		}
+		if entities {
+			c.invalidateEntities(db, table, ids)
+		}
+	}
+	drop()
+	afterCommit(db, drop)
+}
+
+// version returns the current version held by the tag key, creating it when missing.
+func (c *resultCache) version(ctx context.Context, tag string) (string, error) {
+	version, ok, err := c.store.Get(ctx, tag)
+	if err != nil || ok {
+		return string(version), err
+	}
+
+	version = []byte(fmt.Sprintf("%d.%d", time.Now().UnixNano(), cacheVersionSeq.Add(1)))
+	return string(version), c.store.Set(ctx, tag, version, 0)
+}
+
+// load fills dest with the cached result of query, or runs it on db and caches its result for
+// ttl, served stale for up to stale more. Cache failures are logged and fall back to the
+// database.
+func (c *resultCache) load(db *gorm.DB, dest any, ttl, stale time.Duration, query readQuery) error {
+	stmt, err := dryRun(db, dest, query)
+	if err != nil {
+		return err
+	}
+
+	ctx := db.Statement.Context
+	tables := cacheTables(stmt)
+	versions := make([]string, len(tables))
+	for i, table := range tables {
+		version, err := c.version(ctx, cacheTagPrefix+table)
+		if err != nil {
+			db.Logger.Warn(ctx, "result cache read: %v", err)
+			return query(db, dest).Error
+		}
+		versions[i] = table + "@" + version
+	}
+
+	key, err := c.key(cacheKeyInput(db, stmt, versions))
+	if err != nil {
+		db.Logger.Warn(ctx, "result cache key: %v", err)
+		return query(db, dest).Error
+	}
+	return c.readThrough(db, &c.resultCounters, key, dest, ttl, stale, query)
+}
+
+// readThrough fills dest with the value of key, or runs query on db and stores its result
+// under key, once for concurrent misses, counting the lookup in counters. Values older than
+// ttl are served for up to stale more while refreshed in the background.
+func (c *resultCache) readThrough(db *gorm.DB, counters *cacheCounters, key string, dest any, ttl, stale time.Duration, query readQuery) error {
+	ctx := db.Statement.Context
+	var value []byte
+	var ok bool
+	var err error
+	if cacheModeOf(db) != cacheRefresh {
+		value, ok, err = c.store.Get(ctx, key)
+	}
+	if err != nil {
+		db.Logger.Warn(ctx, "result cache read: %v", err)
+	}
+
+	var entry cacheEntry
+	if ok && json.Unmarshal(value, &entry) == nil && entry.NotFound {
+		counters.hits.Add(1)
+		return gorm.ErrRecordNotFound
+	}
+	if ok && decodeResult(entry.Value, dest) == nil {
+		counters.hits.Add(1)
+		if stale > 0 && time.Now().After(entry.Expires) {
+			counters.stale.Add(1)
+			c.revalidate(db, key, dest, ttl, stale, query)
+		}
+		return nil
+	}
+
+	counters.misses.Add(1)
+	return c.misses.do(key, dest, func() error {
+		if err := query(db, dest).Error; err != nil {
+			if c.negativeTTL > 0 && errors.Is(err, gorm.ErrRecordNotFound) {
+				c.writeEntry(db, key, cacheEntry{Expires: time.Now().Add(c.negativeTTL), NotFound: true}, c.negativeTTL)
+			}
+			return err
+		}
+		c.write(db, key, dest, ttl, stale)
+		return nil
+	})
+}
+
+// revalidate refreshes the value of key in the background, once at a time per key, on a
+// session of db outliving its context.
+func (c *resultCache) revalidate(db *gorm.DB, key string, dest any, ttl, stale time.Duration, query readQuery) {
+	if _, running := c.refreshing.LoadOrStore(key, struct{}{}); running {
+		return
+	}
+
+	db = db.Session(&gorm.Session{Context: context.WithoutCancel(db.Statement.Context)})
+	fresh := reflect.New(reflect.TypeOf(dest).Elem()).Interface()
+	go func() {
+		defer c.refreshing.Delete(key)
+		if err := query(db, fresh).Error; err != nil {
+			db.Logger.Warn(db.Statement.Context, "result cache refresh: %v", err)
+			return
+		}
+		c.write(db, key, fresh, ttl, stale)
+	}()
+}
+
+// write stores the result dest under key, fresh for ttl and kept stale for stale more.
+func (c *resultCache) write(db *gorm.DB, key string, dest any, ttl, stale time.Duration) {
+	value, err := encodeResult(dest)
+	if err != nil {
+		db.Logger.Warn(db.Statement.Context, "result cache write: %v", err)
+		return
+	}
+	c.writeEntry(db, key, cacheEntry{Expires: time.Now().Add(ttl), Value: value}, ttl+stale)
+}
+
+// encodeResult encodes the result dest with gob, which unlike JSON ignores the json tags and
+// MarshalJSON methods meant for APIs, keeping every column gorm loads.
+func encodeResult(dest any) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(dest); err != nil {
+		return nil, fmt.Errorf("failed to encode result: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// decodeResult fills dest with the result of encodeResult. dest is zeroed first, since gob
+// leaves the fields it got no value for untouched.
+func decodeResult(value []byte, dest any) error {
+	target := reflect.ValueOf(dest).Elem()
+	decoded := reflect.New(target.Type())
+	if err := gob.NewDecoder(bytes.NewReader(value)).DecodeValue(decoded); err != nil {
+		return fmt.Errorf("failed to decode result: %w", err)
+	}
+	target.Set(decoded.Elem())
+	return nil
+}
+
+// writeEntry stores entry under key for ttl.
+func (c *resultCache) writeEntry(db *gorm.DB, key string, entry cacheEntry, ttl time.Duration) {
+	ctx := db.Statement.Context
+	value, err := json.Marshal(entry)
+	if err == nil {
+		err = c.store.Set(ctx, key, value, ttl)
+	}
+	if err != nil {
+		db.Logger.Warn(ctx, "result cache write: %v", err)
+	}
+}
+
+// cacheTables returns the sorted tables read by a query: its own, and the ones of the
+// relations it joins or preloads. Tables of raw SQL joins are not known.
+func cacheTables(stmt *gorm.Statement) []string {
+	tables := map[string]bool{stmt.Table: true}
+	add := func(relation *schema.Relationship) {
+		tables[relation.FieldSchema.Table] = true
+		if relation.JoinTable != nil {
+			tables[relation.JoinTable.Table] = true
+		}
+	}
+
+	names := make([]string, 0, len(stmt.Joins)+len(stmt.Preloads))
+	for _, join := range stmt.Joins {
+		names = append(names, join.Name)
+	}
+	for name := range stmt.Preloads {
+		names = append(names, name)
+	}
+	for _, name := range names {
+		if stmt.Schema == nil {
+			break
+		}
+		if name == clause.Associations {
+			for _, relation := range stmt.Schema.Relationships.Relations {
+				add(relation)
+			}
+			continue
+		}
+
+		relations := stmt.Schema.Relationships.Relations
+		for _, part := range strings.Split(name, ".") {
+			relation, ok := relations[part]
+			if !ok {
+				break
+			}
+			add(relation)
+			relations = relation.FieldSchema.Relationships.Relations
+		}
+	}
+
+	delete(tables, "")
+	sorted := make([]string, 0, len(tables))
+	for table := range tables {
+		sorted = append(sorted, table)
+	}
+	sort.Strings(sorted)
+	return sorted
+}
+
+// Cached caches the results of the Find, First and Count calls of the returned repository for
+// ttl, keyed by their SQL and arguments, in the Config.Cache store. Creates, updates and
+// deletes of the tables read by a result invalidate it, while raw SQL writes do not. Calls
+// inside transactions, or on connections without a store, are not cached. Results are stored
+// with encoding/gob, so values of interface types, such as the ones of maps, must be
+// registered with gob.Register.
+func (r *gormRepository) Cached(ttl time.Duration) IRepository {
+	clone := *r
+	clone.cacheTTL, clone.cacheStale = ttl, 0
+	return &clone
+}
+
+// CachedStale caches results like Cached, but once ttl has passed still serves them for up to
+// stale more without blocking, refreshing them in the background, so that callers such as
+// dashboards get slightly stale results instead of waiting for the database.
+func (r *gormRepository) CachedStale(ttl, stale time.Duration) IRepository {
+	clone := *r
+	clone.cacheTTL, clone.cacheStale = ttl, stale
+	return &clone
+}
+
+// NoCache makes the calls of the returned repository read the database, leaving the result,
+// entity and second-level caches untouched.
+func (r *gormRepository) NoCache() IRepository {
+	return r.with(r.db.Set(cacheModeKey, cacheBypass))
+}
+
+// RefreshCache makes the calls of the returned repository read the database and replace the
+// values cached for them, such as after writes the caches do not see.
+func (r *gormRepository) RefreshCache() IRepository {
+	return r.with(r.db.Set(cacheModeKey, cacheRefresh))
+}
+
+// cacheModeOf returns the cache mode of the statement of db.
+func cacheModeOf(db *gorm.DB) cacheMode {
+	mode, _ := db.Get(cacheModeKey)
+	m, _ := mode.(cacheMode)
+	return m
+}
+
+// resultCache returns the result cache of the calls of the repository, nil when they are not
+// cached.
+func (r *gormRepository) resultCache() *resultCache {
+	if r.cacheTTL <= 0 || inTransaction(r.db) || cacheModeOf(r.db) == cacheBypass || unmaskedRead(r.db) {
+		return nil
+	}
+	cache, _ := r.db.Config.Plugins[cachePluginName].(*resultCache)
+	return cache
+}
@@ -0,0 +1,293 @@
+package gormext
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// failingCache is a cache store whose every operation fails.
+type failingCache struct{}
+
+func (failingCache) Get(context.Context, string) ([]byte, bool, error) {
+	return nil, false, errors.New("down")
+}
+func (failingCache) Set(context.Context, string, []byte, time.Duration) error {
+	return errors.New("down")
+}
+func (failingCache) Delete(context.Context, ...string) error { return errors.New("down") }
+
+// newCachedRepository creates a repository of repoUser rows on a connection caching in store.
+// The database is a file, read by several connections.
+func newCachedRepository(t *testing.T, store CacheStore) (*Gorm, IRepository) {
+	dbCtx, err := NewDatabaseContext(filepath.Join(t.TempDir(), "cache.db"), "sqlite", "silent")
+	assert.NoError(t, err)
+	g, err := NewGorm(*dbCtx, nil, nil, nil, Config{Config: gorm.Config{Logger: logger.Discard}, Cache: store})
+	assert.NoError(t, err)
+	assert.NoError(t, g.Migrate(&repoUser{}))
+
+	repo := g.GetDB()
+	assert.NoError(t, repo.Create(&[]repoUser{{Name: "alice", Age: 30}, {Name: "bob", Age: 40}}))
+	return g, repo
+}
+
+// TestMemoryCache verifies that entries expire and the least recently used ones are evicted.
+func TestMemoryCache(t *testing.T) {
+	ctx := context.Background()
+	cache := NewMemoryCache(2)
+
+	assert.NoError(t, cache.Set(ctx, "a", []byte("1"), time.Minute))
+	assert.NoError(t, cache.Set(ctx, "b", []byte("2"), time.Minute))
+	_, ok, _ := cache.Get(ctx, "a")
+	assert.True(t, ok)
+	assert.NoError(t, cache.Set(ctx, "c", []byte("3"), time.Minute))
+	_, ok, _ = cache.Get(ctx, "b")
+	assert.False(t, ok, "the least recently used entry is evicted")
+	assert.Equal(t, 2, cache.Len())
+
+	assert.NoError(t, cache.Set(ctx, "d", []byte("4"), time.Nanosecond))
+	time.Sleep(time.Millisecond)
+	_, ok, _ = cache.Get(ctx, "d")
+	assert.False(t, ok, "expired entries are not returned")
+
+	assert.NoError(t, cache.Set(ctx, "e", []byte("5"), 0))
+	_, ok, _ = cache.Get(ctx, "e")
+	assert.True(t, ok, "entries without ttl do not expire")
+
+	assert.NoError(t, cache.Delete(ctx, "a", "missing"))
+	_, ok, _ = cache.Get(ctx, "a")
+	assert.False(t, ok)
+}
+
+// TestCachedResults verifies that Find, First and Count results are served from the cache
+// until they expire, keyed by their SQL and arguments.
+func TestCachedResults(t *testing.T) {
+	cache := NewMemoryCache(100)
+	_, repo := newCachedRepository(t, cache)
+	cached := repo.Cached(time.Minute)
+
+	var users []repoUser
+	assert.NoError(t, cached.Where("age > ?", 35).Find(&users))
+	assert.Len(t, users, 1)
+	var first repoUser
+	assert.NoError(t, cached.First(&first, "name = ?", "alice"))
+	var count int64
+	assert.NoError(t, cached.Table("repo_users").Count(&count))
+	assert.Equal(t, 4, cache.Len(), "three results and the version of their table")
+
+	assert.NoError(t, repo.Exec("DELETE FROM repo_users"))
+
+	users = nil
+	assert.NoError(t, cached.Where("age > ?", 35).Find(&users))
+	assert.Equal(t, "bob", users[0].Name)
+	first = repoUser{}
+	assert.NoError(t, cached.First(&first, "name = ?", "alice"))
+	assert.Equal(t, 30, first.Age)
+	assert.NoError(t, cached.Table("repo_users").Count(&count))
+	assert.Equal(t, int64(2), count)
+
+	users = nil
+	assert.NoError(t, cached.Where("age > ?", 20).Find(&users))
+	assert.Empty(t, users, "other arguments are another entry")
+	assert.Error(t, cached.First(&first, "name = ?", "bob"))
+	assert.Equal(t, 5, cache.Len(), "errors are not cached")
+
+	assert.NoError(t, repo.Find(&users))
+	assert.Empty(t, users, "uncached repositories read the database")
+	assert.NoError(t, repo.Cached(-1).Table("repo_users").Count(&count))
+	assert.Equal(t, int64(0), count)
+}
+
+// redacted is a string written out as a placeholder in API JSON.
+type redacted string
+
+func (redacted) MarshalJSON() ([]byte, error) { return []byte(`"***"`), nil }
+
+// apiAccount is a model whose JSON form, meant for APIs, leaves columns out.
+type apiAccount struct {
+	ID     uint
+	Name   string
+	Secret string   `json:"-"`
+	Token  redacted `json:"token"`
+}
+
+// TestCachedResultsKeepColumns verifies that cached results keep the columns their JSON form
+// leaves out or rewrites.
+func TestCachedResultsKeepColumns(t *testing.T) {
+	cache := NewMemoryCache(100)
+	g, repo := newCachedRepository(t, cache)
+	assert.NoError(t, g.Migrate(&apiAccount{}))
+	stored := apiAccount{Name: "alice", Secret: "s3cret", Token: "t0ken"}
+	assert.NoError(t, repo.Create(&stored))
+
+	var account apiAccount
+	assert.NoError(t, repo.Cached(time.Minute).First(&account))
+	account = apiAccount{Name: "stale"}
+	assert.NoError(t, repo.Cached(time.Minute).First(&account))
+	assert.Equal(t, stored, account)
+
+	var accounts []apiAccount
+	assert.NoError(t, repo.Cached(time.Minute).Find(&accounts))
+	assert.NoError(t, repo.Cached(time.Minute).Find(&accounts))
+	assert.Equal(t, []apiAccount{stored}, accounts)
+}
+
+// TestCachedResultsSkipped verifies that transactions and connections without a store read
+// the database, and that store failures fall back to it.
+func TestCachedResultsSkipped(t *testing.T) {
+	cache := NewMemoryCache(100)
+	_, repo := newCachedRepository(t, cache)
+	assert.NoError(t, repo.WithTransaction(func(tx IRepository) error {
+		var count int64
+		return tx.Cached(time.Minute).Table("repo_users").Count(&count)
+	}))
+	assert.Zero(t, cache.Len())
+
+	for _, store := range []CacheStore{nil, failingCache{}} {
+		_, repo := newCachedRepository(t, store)
+		var count int64
+		assert.NoError(t, repo.Cached(time.Minute).Table("repo_users").Count(&count))
+		assert.Equal(t, int64(2), count)
+	}
+}
+
+// cacheOrder is an order of a repoUser, to cache results reading two tables.
+type cacheOrder struct {
+	ID     uint `gorm:"primaryKey"`
+	UserID uint
+	User   repoUser
+}
+
+// TestCachedResultsInvalidation verifies that writes to the tables read by a result, directly
+// or through preloads, invalidate it once committed.
+func TestCachedResultsInvalidation(t *testing.T) {
+	cache := NewMemoryCache(100)
+	_, repo := newCachedRepository(t, cache)
+	assert.NoError(t, repo.Exec("CREATE TABLE cache_orders (id INTEGER PRIMARY KEY, user_id INTEGER)"))
+	assert.NoError(t, repo.Create(&cacheOrder{UserID: 1}))
+	cached := repo.Cached(time.Minute)
+
+	var count int64
+	assert.NoError(t, cached.Table("repo_users").Count(&count))
+	assert.NoError(t, repo.Create(&repoUser{Name: "carol"}))
+	assert.NoError(t, cached.Table("repo_users").Count(&count))
+	assert.Equal(t, int64(3), count, "creates invalidate")
+
+	var orders []cacheOrder
+	assert.NoError(t, cached.Preload("User").Find(&orders))
+	assert.Equal(t, "alice", orders[0].User.Name)
+	assert.NoError(t, repo.Where("id = ?", 1).Update(&repoUser{ID: 1, Name: "alicia"}))
+	assert.NoError(t, cached.Preload("User").Find(&orders))
+	assert.Equal(t, "alicia", orders[0].User.Name, "writes to preloaded tables invalidate")
+
+	assert.NoError(t, repo.WithTransaction(func(tx IRepository) error {
+		if err := tx.Delete(&repoUser{ID: 2}); err != nil {
+			return err
+		}
+		// A result read by another connection before the commit is not kept.
+		return cached.Table("repo_users").Count(&count)
+	}))
+	assert.NoError(t, cached.Table("repo_users").Count(&count))
+	assert.Equal(t, int64(2), count, "deletes invalidate once committed")
+}
+
+// TestCachedStaleResults verifies that expired results are served stale while refreshed in the
+// background, until the stale window passes.
+func TestCachedStaleResults(t *testing.T) {
+	_, repo := newCachedRepository(t, NewMemoryCache(100))
+	cached := repo.CachedStale(10*time.Millisecond, time.Minute)
+	count := func() int64 {
+		var count int64
+		assert.NoError(t, cached.Table("repo_users").Count(&count))
+		return count
+	}
+
+	assert.Equal(t, int64(2), count())
+	assert.NoError(t, repo.Exec("DELETE FROM repo_users WHERE id = 1"))
+	assert.Equal(t, int64(2), count(), "fresh results are served")
+
+	time.Sleep(20 * time.Millisecond)
+	assert.Equal(t, int64(2), count(), "expired results are served stale")
+	assert.Eventually(t, func() bool { return count() == 1 }, time.Second, 5*time.Millisecond, "stale results are refreshed")
+
+	stale := repo.CachedStale(time.Millisecond, time.Millisecond)
+	var users []repoUser
+	assert.NoError(t, stale.Find(&users))
+	assert.NoError(t, repo.Exec("DELETE FROM repo_users"))
+	time.Sleep(5 * time.Millisecond)
+	assert.NoError(t, stale.Find(&users))
+	assert.Empty(t, users, "results past the stale window are read again")
+}
+
+// TestNegativeCache verifies that lookups finding no record are cached until a create.
+func TestNegativeCache(t *testing.T) {
+	dbCtx, err := NewDatabaseContext(filepath.Join(t.TempDir(), "negative.db"), "sqlite", "silent")
+	assert.NoError(t, err)
+	g, err := NewGorm(*dbCtx, nil, nil, nil, Config{
+		Config:           gorm.Config{Logger: logger.Discard},
+		Cache:            NewMemoryCache(100),
+		NegativeCacheTTL: time.Minute,
+	})
+	assert.NoError(t, err)
+	assert.NoError(t, g.Migrate(&repoUser{}))
+	assert.NoError(t, g.CacheEntity(&repoUser{}, time.Minute))
+	repo := g.GetDB()
+
+	var user repoUser
+	cached := repo.Cached(time.Minute)
+	for i := 0; i < 2; i++ {
+		assert.ErrorIs(t, repo.FirstByID(7, &user), gorm.ErrRecordNotFound)
+		assert.ErrorIs(t, cached.First(&user, "name = ?", "dave"), gorm.ErrRecordNotFound)
+	}
+	assert.Equal(t, uint64(2), g.CacheStats()[0].Hits+g.CacheStats()[1].Hits, "misses are cached")
+
+	assert.NoError(t, repo.Exec("INSERT INTO repo_users (id, name) VALUES (7, 'dave')"))
+	assert.ErrorIs(t, repo.FirstByID(7, &user), gorm.ErrRecordNotFound, "raw SQL does not invalidate")
+
+	assert.NoError(t, repo.Create(&repoUser{ID: 8, Name: "erin"}))
+	assert.NoError(t, cached.First(&user, "name = ?", "dave"), "creates invalidate results")
+	assert.ErrorIs(t, repo.FirstByID(7, &user), gorm.ErrRecordNotFound, "creates invalidate their own entities only")
+	assert.NoError(t, repo.Delete(&repoUser{ID: 7}))
+	assert.NoError(t, repo.Create(&repoUser{ID: 7, Name: "dave"}))
+	assert.NoError(t, repo.FirstByID(7, &user))
+	assert.Equal(t, "dave", user.Name)
+}
+
+// TestCacheBypassAndRefresh verifies that NoCache reads the database without touching the
+// caches, and that RefreshCache replaces the cached values.
+func TestCacheBypassAndRefresh(t *testing.T) {
+	g, repo := newCachedRepository(t, NewMemoryCache(100))
+	assert.NoError(t, g.CacheEntity(&repoUser{}, time.Minute))
+	cached := repo.Cached(time.Minute)
+
+	var users []repoUser
+	var user repoUser
+	assert.NoError(t, cached.Find(&users))
+	assert.NoError(t, repo.FirstByID(1, &user))
+	assert.NoError(t, repo.Exec("UPDATE repo_users SET name = 'raw'"))
+
+	assert.NoError(t, cached.NoCache().Find(&users))
+	assert.Equal(t, "raw", users[0].Name)
+	assert.NoError(t, repo.NoCache().FirstByID(1, &user))
+	assert.Equal(t, "raw", user.Name)
+	assert.NoError(t, cached.Find(&users))
+	assert.Equal(t, "alice", users[0].Name, "bypassed calls leave the cache untouched")
+	assert.NoError(t, repo.FirstByID(1, &user))
+	assert.Equal(t, "alice", user.Name)
+
+	assert.NoError(t, cached.RefreshCache().Find(&users))
+	assert.Equal(t, "raw", users[0].Name)
+	assert.NoError(t, repo.RefreshCache().FirstByID(1, &user))
+	assert.Equal(t, "raw", user.Name)
+	users, user = nil, repoUser{}
+	assert.NoError(t, cached.Find(&users))
+	assert.Equal(t, "raw", users[0].Name, "refreshed calls replace the cached values")
+	assert.NoError(t, repo.FirstByID(1, &user))
+	assert.Equal(t, "raw", user.Name)
+}
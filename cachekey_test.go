@@ -0,0 +1,106 @@
+package gormext
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// TestDefaultCacheKey verifies that keys are deterministic and tell apart every part of their
+// input.
+func TestDefaultCacheKey(t *testing.T) {
+	key := func(input CacheKeyInput) string {
+		k, err := DefaultCacheKey(input)
+		assert.NoError(t, err)
+		return k
+	}
+
+	base := CacheKeyInput{Driver: "sqlite", SQL: "SELECT * FROM t WHERE a = ?", Args: []any{1}, Tables: []string{"t@1"}}
+	assert.Equal(t, key(base), key(base))
+	assert.Regexp(t, `^gormext:[0-9a-f]{64}$`, key(base))
+
+	inputs := []CacheKeyInput{
+		{Driver: "postgres", SQL: base.SQL, Args: []any{1}, Tables: base.Tables},
+		{Driver: "sqlite", SQL: base.SQL, Args: []any{"1"}, Tables: base.Tables},
+		{Driver: "sqlite", SQL: base.SQL, Args: []any{int64(1)}, Tables: base.Tables},
+		{Driver: "sqlite", SQL: base.SQL, Args: []any{1}, Tenant: "acme", Tables: base.Tables},
+		{Driver: "sqlite", SQL: base.SQL, Args: []any{1}, Tables: []string{"t@2"}},
+		{Driver: "sqlite", SQL: base.SQL, Args: []any{1}},
+	}
+	for _, input := range inputs {
+		assert.NotEqual(t, key(base), key(input), "%+v", input)
+	}
+
+	assert.NotEqual(t,
+		key(CacheKeyInput{SQL: "ab", Tenant: "c"}),
+		key(CacheKeyInput{SQL: "a", Tenant: "bc"}), "parts do not run into each other")
+	assert.NotEqual(t,
+		key(CacheKeyInput{Args: []any{1, 2}}),
+		key(CacheKeyInput{Args: []any{2, 1}}), "argument positions matter")
+	assert.Equal(t,
+		key(CacheKeyInput{Args: []any{sql.NullString{String: "a", Valid: true}}}),
+		key(CacheKeyInput{Args: []any{"a"}}), "valuers are keyed by their value")
+}
+
+// TestCacheKeyArgOrdering verifies that statements built from maps, whose iteration order
+// varies, always give the same key.
+func TestCacheKeyArgOrdering(t *testing.T) {
+	_, repo := newTestRepository(t)
+	db := repo.(*gormRepository).db
+
+	keys := make(map[string]bool)
+	for i := 0; i < 50; i++ {
+		conditions := map[string]any{"name": "alice", "age": 30, "active": true, "id": 1}
+		stmt, err := dryRun(db, &[]repoUser{}, func(db *gorm.DB, dest any) *gorm.DB {
+			return db.Where(conditions).Find(dest)
+		})
+		assert.NoError(t, err)
+
+		key, err := DefaultCacheKey(cacheKeyInput(db, stmt, nil))
+		assert.NoError(t, err)
+		keys[key] = true
+	}
+	assert.Len(t, keys, 1)
+}
+
+// TestNormalizeSQL verifies that whitespace is collapsed outside quotes only.
+func TestNormalizeSQL(t *testing.T) {
+	assert.Equal(t, "SELECT * FROM t WHERE a = ?", normalizeSQL("\n  SELECT *\n\tFROM t   WHERE a = ?  "))
+	assert.Equal(t, "SELECT 'a  b', \"c  d\" FROM t", normalizeSQL("SELECT  'a  b',  \"c  d\"\nFROM t"))
+	assert.NotEqual(t, normalizeSQL("SELECT 'a  b'"), normalizeSQL("SELECT 'a b'"))
+}
+
+// TestCacheKeyTenants verifies that the results of tenants are kept apart, and that the key
+// function can be overridden.
+func TestCacheKeyTenants(t *testing.T) {
+	var tenants []string
+	g, err := NewGorm(newTestDatabaseContext(), nil, nil, nil, Config{
+		Config: gorm.Config{Logger: logger.Discard},
+		Cache:  NewMemoryCache(100),
+		CacheKey: func(input CacheKeyInput) (string, error) {
+			tenants = append(tenants, input.Tenant)
+			return DefaultCacheKey(input)
+		},
+	})
+	assert.NoError(t, err)
+	assert.NoError(t, g.Migrate(&repoUser{}))
+	repo := g.GetDB()
+	assert.NoError(t, repo.Create(&repoUser{Name: "alice"}))
+
+	var count int64
+	acme := repo.WithContext(ContextWithTenant(context.Background(), "acme")).Cached(time.Minute)
+	assert.NoError(t, acme.Table("repo_users").Count(&count))
+	assert.NoError(t, repo.Exec("DELETE FROM repo_users"))
+
+	assert.NoError(t, acme.Table("repo_users").Count(&count))
+	assert.Equal(t, int64(1), count)
+	globex := repo.WithContext(ContextWithTenant(context.Background(), "globex")).Cached(time.Minute)
+	assert.NoError(t, globex.Table("repo_users").Count(&count))
+	assert.Equal(t, int64(0), count, "tenants do not share results")
+	assert.Equal(t, []string{"acme", "acme", "globex"}, tenants)
+}
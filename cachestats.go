@@ -0,0 +1,40 @@
+package gormext
+
+import "sync/atomic"
+
+type (
+	// CacheStat counts the lookups of a cache, reported by CacheStats.
+	CacheStat struct {
+		Cache    string  // "result" for Cached results, "entity" for cached entities.
+		Hits     uint64  // Lookups served from the cache.
+		Misses   uint64  // Lookups that read the database.
+		Stale    uint64  // Hits served stale, see CachedStale.
+		HitRatio float64 // Hits divided by lookups.
+	}
+
+	// cacheCounters counts the lookups of a cache.
+	cacheCounters struct {
+		hits   atomic.Uint64
+		misses atomic.Uint64
+		stale  atomic.Uint64
+	}
+)
+
+// CacheStats returns the lookup counts of the result and entity caches, nil without
+// Config.Cache.
+func (g *Gorm) CacheStats() []CacheStat {
+	cache, ok := g.connection.Config.Plugins[cachePluginName].(*resultCache)
+	if !ok {
+		return nil
+	}
+	return []CacheStat{cache.resultCounters.stat("result"), cache.entityCounters.stat("entity")}
+}
+
+// stat returns the counts as the stat of the named cache.
+func (c *cacheCounters) stat(name string) CacheStat {
+	stat := CacheStat{Cache: name, Hits: c.hits.Load(), Misses: c.misses.Load(), Stale: c.stale.Load()}
+	if lookups := stat.Hits + stat.Misses; lookups > 0 {
+		stat.HitRatio = float64(stat.Hits) / float64(lookups)
+	}
+	return stat
+}
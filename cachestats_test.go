@@ -0,0 +1,33 @@
+package gormext
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestCacheStats verifies that result and entity lookups are counted as hits, misses and stale
+// hits.
+func TestCacheStats(t *testing.T) {
+	g, repo := newCachedRepository(t, NewMemoryCache(100))
+	assert.NoError(t, g.CacheEntity(&repoUser{}, time.Minute))
+
+	var count int64
+	cached := repo.CachedStale(time.Nanosecond, time.Minute)
+	for i := 0; i < 3; i++ {
+		assert.NoError(t, cached.Table("repo_users").Count(&count))
+	}
+	var user repoUser
+	assert.NoError(t, repo.FirstByID(1, &user))
+	assert.NoError(t, repo.FirstByID(1, &user))
+	assert.Error(t, repo.FirstByID(9, &user))
+
+	assert.Equal(t, []CacheStat{
+		{Cache: "result", Hits: 2, Misses: 1, Stale: 2, HitRatio: 2.0 / 3},
+		{Cache: "entity", Hits: 1, Misses: 2, HitRatio: 1.0 / 3},
+	}, g.CacheStats())
+
+	g, _ = newTestRepository(t)
+	assert.Nil(t, g.CacheStats())
+}
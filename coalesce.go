@@ -0,0 +1,86 @@
+package gormext
+
+import (
+	"golang.org/x/sync/singleflight"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// coalescePluginName is the name of the query coalescing plugin registered by NewGorm.
+const coalescePluginName = "gormext:coalesce"
+
+type (
+	// readQuery runs a read on db, scanning its result into dest.
+	readQuery func(db *gorm.DB, dest any) *gorm.DB
+
+	// queryGroup coalesces identical reads running at the same time: one of them reads the
+	// database, and the others share its result. It is the gorm plugin of
+	// Config.CoalesceQueries.
+	queryGroup struct {
+		group singleflight.Group
+		key   CacheKeyFunc
+	}
+)
+
+// Name returns the plugin name.
+func (g *queryGroup) Name() string {
+	return coalescePluginName
+}
+
+// Initialize does nothing: reads are coalesced by the repositories of the connection.
+func (g *queryGroup) Initialize(*gorm.DB) error {
+	return nil
+}
+
+// load runs query on db into dest, sharing the result of an identical read in flight.
+func (g *queryGroup) load(db *gorm.DB, dest any, query readQuery) error {
+	stmt, err := dryRun(db, dest, query)
+	if err != nil {
+		return err
+	}
+
+	key, err := g.key(cacheKeyInput(db, stmt, nil))
+	if err != nil {
+		return query(db, dest).Error
+	}
+	return g.do(key, dest, func() error {
+		return query(db, dest).Error
+	})
+}
+
+// do runs read, filling dest, unless a read with the same key is in flight, whose result then
+// fills dest. The error of the read is shared as well.
+func (g *queryGroup) do(key string, dest any, read func() error) error {
+	var leader bool
+	var readErr error
+	value, err, _ := g.group.Do(key, func() (any, error) {
+		leader = true
+		if readErr = read(); readErr != nil {
+			return nil, readErr
+		}
+		return encodeResult(dest)
+	})
+	if leader {
+		return readErr
+	}
+	if err != nil {
+		return err
+	}
+	return decodeResult(value.([]byte), dest)
+}
+
+// dryRun returns the statement query would run on db, without running it.
+func dryRun(db *gorm.DB, dest any, query readQuery) (*gorm.Statement, error) {
+	dry := query(db.Session(&gorm.Session{DryRun: true, Logger: logger.Discard}), dest)
+	return dry.Statement, dry.Error
+}
+
+// queryGroup returns the group coalescing the reads of the repository, nil when they are not
+// coalesced.
+func (r *gormRepository) queryGroup() *queryGroup {
+	if inTransaction(r.db) || unmaskedRead(r.db) {
+		return nil
+	}
+	group, _ := r.db.Config.Plugins[coalescePluginName].(*queryGroup)
+	return group
+}
@@ -0,0 +1,58 @@
+package gormext
+
+import (
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// TestCoalesceQueries verifies that identical reads running at the same time share one query,
+// while other reads run their own.
+func TestCoalesceQueries(t *testing.T) {
+	dbCtx, err := NewDatabaseContext(filepath.Join(t.TempDir(), "coalesce.db"), "sqlite", "silent")
+	assert.NoError(t, err)
+	g, err := NewGorm(*dbCtx, nil, nil, nil, Config{Config: gorm.Config{Logger: logger.Discard}, CoalesceQueries: true})
+	assert.NoError(t, err)
+	assert.NoError(t, g.Migrate(&repoUser{}))
+	repo := g.GetDB()
+	assert.NoError(t, repo.Create(&[]repoUser{{Name: "alice", Age: 30}, {Name: "bob", Age: 40}}))
+
+	// Queries wait for release, so that the reads started meanwhile join the first one.
+	var queries atomic.Int32
+	release := make(chan struct{})
+	assert.NoError(t, g.connection.Callback().Query().Before("gorm:query").Register("test:block", func(db *gorm.DB) {
+		if !db.DryRun {
+			queries.Add(1)
+			<-release
+		}
+	}))
+
+	var wg sync.WaitGroup
+	results := make([][]repoUser, 10)
+	for i := range results {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.NoError(t, repo.Where("age > ?", 20).Find(&results[i]))
+		}()
+	}
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	assert.Equal(t, int32(1), queries.Load())
+	for _, users := range results {
+		assert.Len(t, users, 2)
+	}
+
+	var user repoUser
+	assert.ErrorIs(t, repo.First(&user, "name = ?", "carol"), gorm.ErrRecordNotFound)
+	assert.NoError(t, repo.First(&user, "name = ?", "bob"))
+	assert.Equal(t, int32(3), queries.Load(), "reads in sequence run their own query")
+}
@@ -0,0 +1,122 @@
+package gormext
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/gorm"
+)
+
+// ErrDuplicateKey matches unique constraint violations, whether translated by gorm
+// (Config.TranslateError) or reported as a *ConstraintError.
+var ErrDuplicateKey = gorm.ErrDuplicatedKey
+
+type (
+	// ConstraintMessage is the user-facing description of a unique constraint violation.
+	ConstraintMessage struct {
+		Field   string // Field the API layer should report, e.g. "email".
+		Message string // Message for users, e.g. "email already in use".
+	}
+
+	// ConstraintError is a unique constraint violation. It matches ErrDuplicateKey and unwraps
+	// to the *QueryError of the statement, then the driver error; its message is the registered
+	// one when the constraint is known.
+	ConstraintError struct {
+		Constraint string // Constraint name; "table.column" on SQLite, which reports no names.
+		Field      string // Registered field, if any.
+		Message    string // Registered message, if any.
+		Err        error  // *QueryError wrapping the driver error.
+	}
+)
+
+// RegisterConstraint registers the user-facing message of the unique constraint name. On
+// SQLite, register "table.column" (or "table.col1,table.col2" for composite constraints).
+func (g *Gorm) RegisterConstraint(name string, message ConstraintMessage) {
+	g.constraints.Store(name, message)
+}
+
+// Error returns the registered message, or the statement error for unknown constraints.
+func (e *ConstraintError) Error() string {
+	if e.Message != "" {
+		return e.Message
+	}
+	return e.Err.Error()
+}
+
+// Is reports whether target is ErrDuplicateKey.
+func (e *ConstraintError) Is(target error) bool {
+	return target == ErrDuplicateKey
+}
+
+// Unwrap returns the statement error.
+func (e *ConstraintError) Unwrap() error {
+	return e.Err
+}
+
+// registerConstraintCallbacks translates unique constraint violations of write statements.
+func (g *Gorm) registerConstraintCallbacks() error {
+	const name = "gormext:constraint"
+
+	callbacks := g.connection.Callback()
+	return errors.Join(
+		callbacks.Create().After("gorm:commit_or_rollback_transaction").Register(name, g.translateConstraintError),
+		callbacks.Update().After("gorm:commit_or_rollback_transaction").Register(name, g.translateConstraintError),
+		callbacks.Raw().After("gorm:raw").Register(name, g.translateConstraintError),
+	)
+}
+
+// translateConstraintError replaces a unique constraint violation with a *ConstraintError.
+func (g *Gorm) translateConstraintError(db *gorm.DB) {
+	if db.Error == nil {
+		return
+	}
+
+	cause := db.Error
+	var queryErr *QueryError
+	if errors.As(cause, &queryErr) {
+		cause = queryErr.Err
+	}
+
+	constraint, ok := uniqueConstraint(cause)
+	if !ok {
+		return
+	}
+
+	translated := &ConstraintError{Constraint: constraint, Err: db.Error}
+	if value, ok := g.constraints.Load(constraint); ok {
+		message := value.(ConstraintMessage)
+		translated.Field, translated.Message = message.Field, message.Message
+	}
+	db.Error = translated
+}
+
+// uniqueConstraint returns the constraint name of a unique constraint violation.
+func uniqueConstraint(err error) (string, bool) {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return pgErr.ConstraintName, pgErr.Code == "23505"
+	}
+
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) {
+		if mysqlErr.Number != 1062 {
+			return "", false
+		}
+		// Duplicate entry 'a@b.c' for key 'users.idx_users_email' (MySQL 8 prefixes the table).
+		_, key, _ := strings.Cut(mysqlErr.Message, " for key '")
+		key = strings.TrimSuffix(key, "'")
+		if i := strings.LastIndexByte(key, '.'); i >= 0 {
+			key = key[i+1:]
+		}
+		return key, true
+	}
+
+	// SQLite reports the columns: UNIQUE constraint failed: users.email
+	const sqlitePrefix = "UNIQUE constraint failed: "
+	if message := err.Error(); strings.HasPrefix(message, sqlitePrefix) {
+		return strings.ReplaceAll(strings.TrimPrefix(message, sqlitePrefix), ", ", ","), true
+	}
+	return "", false
+}
@@ -0,0 +1,55 @@
+package gormext
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+)
+
+// constraintUser has a unique email for constraint tests.
+type constraintUser struct {
+	ID    uint   `gorm:"primaryKey"`
+	Email string `gorm:"uniqueIndex"`
+}
+
+// TestConstraintMessages verifies duplicate key errors carry the registered message.
+func TestConstraintMessages(t *testing.T) {
+	g, repo := newTestRepository(t)
+	assert.NoError(t, g.Migrate(&constraintUser{}))
+	g.RegisterConstraint("constraint_users.email", ConstraintMessage{Field: "email", Message: "email already in use"})
+
+	assert.NoError(t, repo.Create(&constraintUser{Email: "ann@example.com"}))
+	err := repo.Create(&constraintUser{Email: "ann@example.com"})
+
+	var constraintErr *ConstraintError
+	if assert.ErrorAs(t, err, &constraintErr) {
+		assert.Equal(t, "email", constraintErr.Field)
+		assert.Equal(t, "email already in use", err.Error())
+	}
+	assert.ErrorIs(t, err, ErrDuplicateKey)
+
+	err = repo.Exec("INSERT INTO repo_users (id, name) VALUES (1, 'a'), (1, 'b')")
+	assert.ErrorIs(t, err, ErrDuplicateKey, "Unregistered constraints should still be translated")
+	assert.Contains(t, err.Error(), "UNIQUE constraint failed: repo_users.id")
+}
+
+// TestUniqueConstraint verifies constraint names are extracted from each driver's errors.
+func TestUniqueConstraint(t *testing.T) {
+	name, ok := uniqueConstraint(&pgconn.PgError{Code: "23505", ConstraintName: "idx_users_email"})
+	assert.True(t, ok)
+	assert.Equal(t, "idx_users_email", name)
+
+	name, ok = uniqueConstraint(&mysql.MySQLError{Number: 1062, Message: "Duplicate entry 'a@b.c' for key 'users.idx_users_email'"})
+	assert.True(t, ok)
+	assert.Equal(t, "idx_users_email", name)
+
+	name, ok = uniqueConstraint(errors.New("UNIQUE constraint failed: users.org_id, users.email"))
+	assert.True(t, ok)
+	assert.Equal(t, "users.org_id,users.email", name)
+
+	_, ok = uniqueConstraint(&pgconn.PgError{Code: "23503"})
+	assert.False(t, ok)
+}
@@ -3,6 +3,7 @@ package gormext
 import (
 	"errors"
 	"fmt"
+	"time"
 
 	"gorm.io/driver/mysql"
 	"gorm.io/driver/postgres"
@@ -30,9 +31,11 @@ type (
 
 	// DatabaseContext holds configuration settings for the database connection.
 	DatabaseContext struct {
-		loggerLevel SQLLoggerLevel
-		driver      SQLDriver
-		dsn         string
+		loggerLevel        SQLLoggerLevel
+		logger             logger.Interface
+		slowQueryThreshold time.Duration
+		driver             SQLDriver
+		dsn                string
 	}
 )
 
@@ -112,6 +115,28 @@ func (ctx *DatabaseContext) setLoggerLevel(level string) {
 	}
 }
 
+// SetLogger sets the logger of the SQL operations, used in place of GORM's default logger
+// when Config.Logger is nil. See the gormextlog package for zap, zerolog and logrus adapters.
+func (ctx *DatabaseContext) SetLogger(l logger.Interface) {
+	ctx.logger = l
+}
+
+// GetLogger returns the logger set with SetLogger, or nil.
+func (ctx DatabaseContext) GetLogger() logger.Interface {
+	return ctx.logger
+}
+
+// SetSlowQueryThreshold sets the duration above which statements are reported to the
+// (*Gorm).OnSlowQuery hooks. Zero, the default, disables the detection.
+func (ctx *DatabaseContext) SetSlowQueryThreshold(threshold time.Duration) {
+	ctx.slowQueryThreshold = threshold
+}
+
+// GetSlowQueryThreshold returns the slow query threshold, zero when the detection is disabled.
+func (ctx DatabaseContext) GetSlowQueryThreshold() time.Duration {
+	return ctx.slowQueryThreshold
+}
+
 // GetDSN returns the Data Source Name (DSN) for the database connection.
 func (ctx DatabaseContext) GetDSN() string {
 	return ctx.dsn
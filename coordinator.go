@@ -0,0 +1,101 @@
+package gormext
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// ErrPartialCommit is returned when a coordinated transaction failed to commit on a connection
+// after committing on others, whose compensations then ran.
+var ErrPartialCommit = errors.New("coordinated transaction partially committed")
+
+// TxParticipant is the part of a coordinated transaction running on one connection.
+type TxParticipant struct {
+	// Connection is the name of the registry connection, empty for the primary one.
+	Connection string
+
+	// Run performs the writes of the participant in its transaction.
+	Run func(tx IRepository) error
+
+	// Compensate undoes the committed writes of the participant when a later participant fails
+	// to commit, such as by deleting the inserted rows. It may be nil.
+	Compensate func(ctx context.Context, repo IRepository) error
+}
+
+// Coordinate runs a best-effort two-phase commit across connections of the registry. In the
+// prepare phase, each participant runs in its own transaction, and any failure rolls them all
+// back. In the commit phase, transactions commit in order; when a commit fails, the remaining
+// ones are rolled back and the committed participants are compensated in reverse order, and
+// the error matches ErrPartialCommit, unless the first commit failed and nothing committed.
+// Without a database-level protocol such as XA, a crash during the commit phase can still
+// leave connections inconsistent.
+func (r *Registry) Coordinate(ctx context.Context, participants ...TxParticipant) (err error) {
+	repos := make([]IRepository, len(participants))
+	for i, participant := range participants {
+		g := r.primary
+		if participant.Connection != "" {
+			var ok bool
+			if g, ok = r.Get(participant.Connection); !ok {
+				return fmt.Errorf("connection '%s' not registered", participant.Connection)
+			}
+		}
+		repos[i] = g.GetDB().WithContext(ctx)
+	}
+
+	txs := make([]ITransaction, 0, len(participants))
+	defer func() {
+		// Roll back the transactions left open by a failure; committed ones are ended already.
+		for _, tx := range txs {
+			if rollbackErr := tx.Rollback(); rollbackErr != nil {
+				err = errors.Join(err, rollbackErr)
+			}
+		}
+	}()
+
+	for i, participant := range participants {
+		tx, err := repos[i].Begin()
+		if err != nil {
+			return fmt.Errorf("failed to prepare connection '%s': %w", participantName(participant), err)
+		}
+		txs = append(txs, tx)
+
+		if err := participant.Run(tx); err != nil {
+			return fmt.Errorf("failed to prepare connection '%s': %w", participantName(participant), err)
+		}
+	}
+
+	for i, tx := range txs {
+		if err := tx.Commit(); err != nil {
+			err = fmt.Errorf("failed to commit connection '%s': %w", participantName(participants[i]), err)
+			if i == 0 {
+				// Nothing committed: every connection rolls back.
+				return err
+			}
+			return errors.Join(fmt.Errorf("%w: %w", ErrPartialCommit, err), compensate(ctx, participants[:i], repos[:i]))
+		}
+	}
+	return nil
+}
+
+// compensate runs the compensations of the committed participants in reverse order.
+func compensate(ctx context.Context, participants []TxParticipant, repos []IRepository) error {
+	var errs []error
+	for i := len(participants) - 1; i >= 0; i-- {
+		if participants[i].Compensate == nil {
+			continue
+		}
+		if err := participants[i].Compensate(ctx, repos[i]); err != nil {
+			errs = append(errs, fmt.Errorf("failed to compensate connection '%s': %w", participantName(participants[i]), err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// participantName returns the connection name of a participant, "primary" for the primary one.
+func participantName(participant TxParticipant) string {
+	if participant.Connection == "" {
+		return "primary"
+	}
+	return participant.Connection
+}
@@ -0,0 +1,87 @@
+package gormext
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestCoordinate verifies that coordinated transactions commit on every connection, roll back
+// everywhere when a participant fails, and compensate committed participants when a commit
+// fails.
+func TestCoordinate(t *testing.T) {
+	open := func(name string) *Gorm {
+		dbCtx, err := NewDatabaseContext(filepath.Join(t.TempDir(), name)+"?_foreign_keys=on", "sqlite", "silent")
+		assert.NoError(t, err)
+		g, err := NewGorm(*dbCtx, nil, nil, nil)
+		assert.NoError(t, err)
+		return g
+	}
+	orders, payments := open("orders.db"), open("payments.db")
+	assert.NoError(t, orders.connection.Exec("CREATE TABLE orders (id INTEGER PRIMARY KEY)").Error)
+	assert.NoError(t, payments.connection.Exec(`CREATE TABLE accounts (id INTEGER PRIMARY KEY);
+		CREATE TABLE payments (id INTEGER PRIMARY KEY, account_id INTEGER REFERENCES accounts (id) DEFERRABLE INITIALLY DEFERRED)`).Error)
+	assert.NoError(t, payments.connection.Exec("INSERT INTO accounts (id) VALUES (1)").Error)
+
+	registry, err := NewRegistry(orders)
+	assert.NoError(t, err)
+	assert.NoError(t, registry.Add("payments", payments))
+	ctx := context.Background()
+
+	var compensated []string
+	participants := func(orderID, accountID int, failRun error) []TxParticipant {
+		return []TxParticipant{{
+			Run: func(tx IRepository) error { return tx.Exec("INSERT INTO orders (id) VALUES (?)", orderID) },
+			Compensate: func(_ context.Context, repo IRepository) error {
+				compensated = append(compensated, "orders")
+				return repo.Exec("DELETE FROM orders WHERE id = ?", orderID)
+			},
+		}, {
+			Connection: "payments",
+			Run: func(tx IRepository) error {
+				if err := tx.Exec("INSERT INTO payments (account_id) VALUES (?)", accountID); err != nil {
+					return err
+				}
+				return failRun
+			},
+		}}
+	}
+	count := func(g *Gorm, table string) (n int64) {
+		assert.NoError(t, g.connection.Table(table).Count(&n).Error)
+		return n
+	}
+
+	assert.NoError(t, registry.Coordinate(ctx, participants(1, 1, nil)...))
+	assert.Equal(t, int64(1), count(orders, "orders"))
+	assert.Equal(t, int64(1), count(payments, "payments"))
+
+	errDeclined := errors.New("declined")
+	err = registry.Coordinate(ctx, participants(2, 1, errDeclined)...)
+	assert.ErrorIs(t, err, errDeclined)
+	assert.ErrorContains(t, err, "failed to prepare connection 'payments'")
+	assert.Equal(t, int64(1), count(orders, "orders"), "A failed prepare should roll back every connection")
+	assert.Empty(t, compensated)
+
+	// The deferred foreign key fails the payments commit, after orders committed.
+	err = registry.Coordinate(ctx, participants(3, 99, nil)...)
+	assert.ErrorIs(t, err, ErrPartialCommit)
+	assert.ErrorContains(t, err, "failed to commit connection 'payments'")
+	assert.Equal(t, []string{"orders"}, compensated)
+	assert.Equal(t, int64(1), count(orders, "orders"), "Committed participants should be compensated")
+	assert.Equal(t, int64(1), count(payments, "payments"))
+
+	// A failed first commit leaves nothing committed, so nothing to compensate.
+	compensated = nil
+	reversed := participants(4, 99, nil)
+	reversed[0], reversed[1] = reversed[1], reversed[0]
+	err = registry.Coordinate(ctx, reversed...)
+	assert.NotErrorIs(t, err, ErrPartialCommit)
+	assert.ErrorContains(t, err, "failed to commit connection 'payments'")
+	assert.Empty(t, compensated)
+	assert.Equal(t, int64(1), count(orders, "orders"), "Later participants should be rolled back")
+
+	assert.ErrorContains(t, registry.Coordinate(ctx, TxParticipant{Connection: "ledger"}), "connection 'ledger' not registered")
+}
@@ -0,0 +1,148 @@
+package gormext
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+// columnKind is the family of a column type, and its size in bits for integers.
+type columnKind struct {
+	family string
+	bits   int
+}
+
+var (
+	// ErrDestructiveMigration is returned by Migrate when a column change may lose data and
+	// AllowDestructive is not set.
+	ErrDestructiveMigration = errors.New("destructive schema change")
+
+	// destructiveStatement matches DDL dropping a table, column, constraint or index.
+	destructiveStatement = regexp.MustCompile(`(?i)\bDROP\s+(?:TABLE|COLUMN|CONSTRAINT|INDEX)\b`)
+
+	// columnKinds classifies the type names of the supported dialects.
+	columnKinds = map[string]columnKind{
+		"tinyint": {"integer", 8}, "smallint": {"integer", 16}, "int2": {"integer", 16}, "smallserial": {"integer", 16},
+		"mediumint": {"integer", 24}, "int": {"integer", 32}, "integer": {"integer", 32}, "int4": {"integer", 32},
+		"serial": {"integer", 32}, "bigint": {"integer", 64}, "int8": {"integer", 64}, "bigserial": {"integer", 64},
+
+		"real": {family: "float"}, "float": {family: "float"}, "float4": {family: "float"}, "float8": {family: "float"},
+		"double": {family: "float"}, "numeric": {family: "float"}, "decimal": {family: "float"}, "money": {family: "float"},
+
+		"bool": {family: "bool"}, "boolean": {family: "bool"},
+
+		"char": {family: "string"}, "character": {family: "string"}, "varchar": {family: "string"},
+		"nchar": {family: "string"}, "nvarchar": {family: "string"}, "bpchar": {family: "string"},
+		"text": {family: "string"}, "tinytext": {family: "string"}, "mediumtext": {family: "string"},
+		"longtext": {family: "string"}, "citext": {family: "string"}, "clob": {family: "string"},
+
+		"date": {family: "time"}, "time": {family: "time"}, "timetz": {family: "time"}, "timestamp": {family: "time"},
+		"timestamptz": {family: "time"}, "datetime": {family: "time"}, "interval": {family: "time"},
+
+		"blob": {family: "bytes"}, "tinyblob": {family: "bytes"}, "mediumblob": {family: "bytes"},
+		"longblob": {family: "bytes"}, "binary": {family: "bytes"}, "varbinary": {family: "bytes"}, "bytea": {family: "bytes"},
+	}
+)
+
+// checkDestructive plans the changes AutoMigrate would make for models and fails with
+// ErrDestructiveMigration, listing them, when some may lose data.
+func (g *Gorm) checkDestructive(conn *gorm.DB, models []any) error {
+	changes, err := g.planModels(conn, models)
+	if err != nil {
+		return err
+	}
+
+	var problems []error
+	for _, change := range changes {
+		if change.Destructive != "" {
+			problems = append(problems, fmt.Errorf("%s '%s.%s' %s", change.Kind, change.Table, change.Name, change.Destructive))
+		}
+	}
+	if err := errors.Join(problems...); err != nil {
+		return fmt.Errorf("%w, set AllowDestructive to apply it:\n%w", ErrDestructiveMigration, err)
+	}
+	return nil
+}
+
+// destructiveChange returns why altering column to match field may lose data, or an empty
+// string: the statements drop something, the type is narrowed, or NOT NULL is added to a
+// column holding NULLs.
+func (g *Gorm) destructiveChange(conn *gorm.DB, table *schema.Schema, field *schema.Field, column gorm.ColumnType, statements []string) (string, error) {
+	for _, statement := range statements {
+		if match := destructiveStatement.FindString(statement); match != "" {
+			return fmt.Sprintf("runs %s", strings.ToUpper(match)), nil
+		}
+	}
+
+	if reason := g.narrowing(field, column); reason != "" {
+		return reason, nil
+	}
+
+	if nullable, ok := column.Nullable(); ok && nullable && field.NotNull && !field.PrimaryKey {
+		var nulls int64
+		err := conn.Table(table.Table).
+			Where(clause.Expr{SQL: "? IS NULL", Vars: []any{clause.Column{Name: field.DBName}}}).
+			Count(&nulls).Error
+		if err != nil {
+			return "", fmt.Errorf("failed to count NULL values: %w", err)
+		}
+		if nulls > 0 {
+			return fmt.Sprintf("adds NOT NULL while %d rows hold NULL", nulls), nil
+		}
+	}
+	return "", nil
+}
+
+// narrowing returns how the type of field narrows the one of column, or an empty string.
+// Changes to text types and from integers to floats are widening; changes between other
+// families are narrowing.
+func (g *Gorm) narrowing(field *schema.Field, column gorm.ColumnType) string {
+	oldType := strings.ToLower(column.DatabaseTypeName())
+	newType := strings.ToLower(g.connection.Dialector.DataTypeOf(field))
+	oldKind, newKind := g.columnKind(oldType), g.columnKind(newType)
+	narrows := fmt.Sprintf("narrows type %s to %s", oldType, newType)
+
+	switch {
+	case oldKind.family != newKind.family:
+		if newKind.family == "string" || (oldKind.family == "integer" && newKind.family == "float") ||
+			(oldType == "tinyint" && newKind.family == "bool") {
+			return ""
+		}
+		return narrows
+	case oldKind.family == "integer" && newKind.bits < oldKind.bits:
+		return narrows
+	case oldKind.family == "string" && field.Size > 0 && strings.Contains(newType, "("):
+		if length, ok := column.Length(); !ok || length <= 0 || int64(field.Size) < length {
+			return narrows
+		}
+	case oldKind.family == "float" && field.Precision > 0:
+		if precision, scale, ok := column.DecimalSize(); ok && (int64(field.Precision) < precision || int64(field.Scale) < scale) {
+			return narrows
+		}
+	}
+	return ""
+}
+
+// columnKind classifies a type name such as "varchar(100)" or "double precision". Unknown
+// types form a family of their own.
+func (g *Gorm) columnKind(dataType string) columnKind {
+	name, _, _ := strings.Cut(dataType, "(")
+	if fields := strings.Fields(name); len(fields) > 0 {
+		name = fields[0]
+	}
+
+	kind, ok := columnKinds[name]
+	if !ok {
+		return columnKind{family: name}
+	}
+	// SQLite integers hold 64 bits whatever their declared type.
+	if kind.family == "integer" && g.databaseCtx.driver == SQLite {
+		kind.bits = 64
+	}
+	return kind
+}
@@ -0,0 +1,65 @@
+package gormext
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type (
+	guardItemV1 struct {
+		ID    uint
+		Code  string
+		Count int64
+		Note  *string
+	}
+
+	guardItemNarrow struct {
+		ID    uint
+		Code  int64
+		Count int64
+	}
+
+	guardItemNotNull struct {
+		ID    uint
+		Code  string `gorm:"not null"`
+		Count int64
+		Note  string `gorm:"not null;default:''"`
+	}
+)
+
+func (guardItemV1) TableName() string      { return "guard_items" }
+func (guardItemNarrow) TableName() string  { return "guard_items" }
+func (guardItemNotNull) TableName() string { return "guard_items" }
+
+// TestMigrateDestructive verifies that Migrate refuses changes that may lose data unless
+// AllowDestructive is set.
+func TestMigrateDestructive(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "guard.db")
+	dbCtx, err := NewDatabaseContext(path, "sqlite", "silent")
+	assert.NoError(t, err)
+	g, err := NewGorm(*dbCtx, nil, nil, nil)
+	assert.NoError(t, err)
+
+	assert.NoError(t, g.Migrate(&guardItemV1{}))
+	assert.NoError(t, g.connection.Create(&guardItemV1{Code: "a1", Count: 3}).Error)
+
+	err = g.Migrate(&guardItemNarrow{})
+	assert.True(t, errors.Is(err, ErrDestructiveMigration))
+	assert.ErrorContains(t, err, "alter_column 'guard_items.code' narrows type text to integer")
+
+	err = g.Migrate(&guardItemNotNull{})
+	assert.True(t, errors.Is(err, ErrDestructiveMigration))
+	assert.ErrorContains(t, err, "alter_column 'guard_items.note' adds NOT NULL while 1 rows hold NULL")
+	assert.NotContains(t, err.Error(), "guard_items.code", "Columns without NULLs can become NOT NULL")
+
+	var note *string
+	assert.NoError(t, g.connection.Table("guard_items").Select("note").Row().Scan(&note))
+	assert.Nil(t, note, "Refused migrations should leave the data untouched")
+
+	permissive, err := NewGorm(*dbCtx, nil, nil, nil, Config{AllowDestructive: true})
+	assert.NoError(t, err)
+	assert.NoError(t, permissive.Migrate(&guardItemNarrow{}))
+}
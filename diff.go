@@ -0,0 +1,116 @@
+package gormext
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// DiffReport is the row-level difference between the results of a query on two databases.
+// Rows are compared as a multiset, so row order does not matter but duplicates do.
+type DiffReport struct {
+	Query   string           // Name of the compared query.
+	RowsA   int              // Rows returned by the first database.
+	RowsB   int              // Rows returned by the second database.
+	OnlyInA []map[string]any // Rows returned only by the first database.
+	OnlyInB []map[string]any // Rows returned only by the second database.
+}
+
+// Equal reports whether both databases returned the same rows.
+func (r DiffReport) Equal() bool {
+	return len(r.OnlyInA) == 0 && len(r.OnlyInB) == 0
+}
+
+// String summarizes the report.
+func (r DiffReport) String() string {
+	if r.Equal() {
+		return fmt.Sprintf("sql query '%s': %d rows, no differences", r.Query, r.RowsA)
+	}
+	return fmt.Sprintf("sql query '%s': %d/%d rows, %d only in A, %d only in B",
+		r.Query, r.RowsA, r.RowsB, len(r.OnlyInA), len(r.OnlyInB))
+}
+
+// CompareQuery runs the cached query queryName with the named params on both gA and gB and
+// reports the rows returned by only one of them. Each connection uses its own cached version
+// of the query, so dialect-specific copies can be compared. Values are compared by their text
+// form, ignoring driver differences such as []byte versus string.
+func CompareQuery(ctx context.Context, gA, gB *Gorm, queryName string, params map[string]any) (DiffReport, error) {
+	report := DiffReport{Query: queryName}
+
+	rowsA, err := gA.queryRows(ctx, queryName, params)
+	if err != nil {
+		return report, err
+	}
+	rowsB, err := gB.queryRows(ctx, queryName, params)
+	if err != nil {
+		return report, err
+	}
+	report.RowsA, report.RowsB = len(rowsA), len(rowsB)
+
+	pending := make(map[string][]map[string]any, len(rowsB))
+	for _, row := range rowsB {
+		key := rowKey(row)
+		pending[key] = append(pending[key], row)
+	}
+
+	for _, row := range rowsA {
+		key := rowKey(row)
+		if matches := pending[key]; len(matches) > 0 {
+			pending[key] = matches[1:]
+			continue
+		}
+		report.OnlyInA = append(report.OnlyInA, row)
+	}
+
+	for _, row := range rowsB {
+		key := rowKey(row)
+		if matches := pending[key]; len(matches) > 0 {
+			pending[key] = matches[1:]
+			report.OnlyInB = append(report.OnlyInB, row)
+		}
+	}
+	return report, nil
+}
+
+// queryRows runs the named query and returns its rows as column maps.
+func (g *Gorm) queryRows(ctx context.Context, queryName string, params map[string]any) ([]map[string]any, error) {
+	query, args, err := g.bindNamedQuery(queryName, params)
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []map[string]any
+	if err := g.queryConn(ctx, queryName).Raw(query, args...).Scan(&rows).Error; err != nil {
+		return nil, fmt.Errorf("failed to run sql query '%s': %w", queryName, err)
+	}
+	return rows, nil
+}
+
+// rowKey returns a canonical text form of row used for comparison.
+func rowKey(row map[string]any) string {
+	columns := make([]string, 0, len(row))
+	for column := range row {
+		columns = append(columns, column)
+	}
+	sort.Strings(columns)
+
+	var key strings.Builder
+	for _, column := range columns {
+		key.WriteString(column)
+		key.WriteByte('=')
+		switch value := row[column].(type) {
+		case nil:
+			key.WriteString("NULL")
+		case []byte:
+			key.Write(value)
+		case time.Time:
+			key.WriteString(value.UTC().Format(time.RFC3339Nano))
+		default:
+			fmt.Fprint(&key, value)
+		}
+		key.WriteByte(0)
+	}
+	return key.String()
+}
@@ -0,0 +1,43 @@
+package gormext
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestCompareQuery verifies rows present on only one database are reported, ignoring order.
+func TestCompareQuery(t *testing.T) {
+	gA, repoA := newTestRepository(t)
+	gB, repoB := newTestRepository(t)
+
+	for _, name := range []string{"ann", "bob", "bob"} {
+		assert.NoError(t, repoA.Create(&repoUser{Name: name, Age: 30}))
+	}
+	for _, name := range []string{"bob", "cid", "ann"} {
+		assert.NoError(t, repoB.Create(&repoUser{Name: name, Age: 30}))
+	}
+
+	query := "SELECT name, age FROM repo_users WHERE age = :age"
+	gA.sqlQueries.Store("users", query)
+	gB.sqlQueries.Store("users", query)
+
+	report, err := CompareQuery(context.Background(), gA, gB, "users", map[string]any{"age": 30})
+	assert.NoError(t, err)
+	assert.False(t, report.Equal())
+	assert.Equal(t, 3, report.RowsA)
+	assert.Equal(t, 3, report.RowsB)
+	if assert.Len(t, report.OnlyInA, 1) && assert.Len(t, report.OnlyInB, 1) {
+		assert.Equal(t, "bob", report.OnlyInA[0]["name"])
+		assert.Equal(t, "cid", report.OnlyInB[0]["name"])
+	}
+
+	report, err = CompareQuery(context.Background(), gA, gA, "users", map[string]any{"age": 30})
+	assert.NoError(t, err)
+	assert.True(t, report.Equal())
+	assert.Equal(t, "sql query 'users': 3 rows, no differences", report.String())
+
+	_, err = CompareQuery(context.Background(), gA, gB, "users", nil)
+	assert.Error(t, err)
+}
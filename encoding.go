@@ -0,0 +1,149 @@
+package gormext
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+const (
+	// gzipFileExt marks gzip-compressed seed and query files, e.g. data.sql.gz.
+	gzipFileExt = ".gz"
+
+	// encryptedFileExt marks AES-GCM encrypted seed and query files, e.g. data.sql.enc or data.sql.gz.enc.
+	encryptedFileExt = ".enc"
+)
+
+// ErrNoKeyProvider is returned when an encrypted file is loaded without a configured KeyProvider.
+var ErrNoKeyProvider = errors.New("no key provider configured")
+
+type (
+	// KeyProvider supplies the AES key (16, 24 or 32 bytes) used to decrypt an encrypted seed
+	// or query file, identified by its path.
+	KeyProvider interface {
+		Key(path string) ([]byte, error)
+	}
+
+	// KeyProviderFunc adapts a function to the KeyProvider interface.
+	KeyProviderFunc func(path string) ([]byte, error)
+)
+
+// Key returns the key for path.
+func (f KeyProviderFunc) Key(path string) ([]byte, error) {
+	return f(path)
+}
+
+// StaticKey returns a KeyProvider using the same key for every file.
+func StaticKey(key []byte) KeyProvider {
+	return KeyProviderFunc(func(string) ([]byte, error) { return key, nil })
+}
+
+// EncryptFile encrypts content with AES-GCM using key, in the format expected for .enc files
+// (random nonce followed by the sealed content). It is meant for build tooling producing
+// encrypted seed and query files.
+func EncryptFile(key, content []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	return gcm.Seal(nonce, nonce, content, nil), nil
+}
+
+// decodeFile decodes a file read for the connection, using its key provider.
+func (g *Gorm) decodeFile(path string, content []byte) ([]byte, error) {
+	return decodeFile(g.keys, path, content)
+}
+
+// decodeFile undoes the encodings named by the suffixes of path, outermost first, so
+// data.sql.gz.enc is decrypted with a key from keys and then decompressed.
+func decodeFile(keys KeyProvider, path string, content []byte) ([]byte, error) {
+	name := path
+	for {
+		switch {
+		case strings.HasSuffix(name, encryptedFileExt):
+			plain, err := decrypt(keys, path, content)
+			if err != nil {
+				return nil, fmt.Errorf("failed to decrypt '%s': %w", path, err)
+			}
+			content, name = plain, strings.TrimSuffix(name, encryptedFileExt)
+		case strings.HasSuffix(name, gzipFileExt):
+			reader, err := gzip.NewReader(bytes.NewReader(content))
+			if err != nil {
+				return nil, fmt.Errorf("failed to decompress '%s': %w", path, err)
+			}
+			plain, err := io.ReadAll(reader)
+			if err != nil {
+				return nil, fmt.Errorf("failed to decompress '%s': %w", path, err)
+			}
+			content, name = plain, strings.TrimSuffix(name, gzipFileExt)
+		default:
+			return content, nil
+		}
+	}
+}
+
+// decrypt opens content sealed by EncryptFile with the key keys supplies for path.
+func decrypt(keys KeyProvider, path string, content []byte) ([]byte, error) {
+	if keys == nil {
+		return nil, ErrNoKeyProvider
+	}
+
+	key, err := keys.Key(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get key: %w", err)
+	}
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(content) < gcm.NonceSize() {
+		return nil, errors.New("encrypted content too short")
+	}
+	nonce, sealed := content[:gcm.NonceSize()], content[gcm.NonceSize():]
+	return gcm.Open(nil, nonce, sealed, nil)
+}
+
+// newGCM creates an AES-GCM cipher for key.
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("invalid encryption key: %w", err)
+	}
+	return cipher.NewGCM(block)
+}
+
+// trimEncodingExt strips the compression and encryption suffixes of path.
+func trimEncodingExt(path string) string {
+	for {
+		switch {
+		case strings.HasSuffix(path, encryptedFileExt):
+			path = strings.TrimSuffix(path, encryptedFileExt)
+		case strings.HasSuffix(path, gzipFileExt):
+			path = strings.TrimSuffix(path, gzipFileExt)
+		default:
+			return path
+		}
+	}
+}
+
+// readFile reads the file at path from disk and decodes it according to its suffixes.
+func (g *Gorm) readFile(path string) ([]byte, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return g.decodeFile(path, content)
+}
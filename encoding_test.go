@@ -0,0 +1,59 @@
+package gormext
+
+import (
+	"bytes"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// gzipContent compresses content for test fixtures.
+func gzipContent(t *testing.T, content string) []byte {
+	var buf bytes.Buffer
+	writer := gzip.NewWriter(&buf)
+	_, err := writer.Write([]byte(content))
+	assert.NoError(t, err)
+	assert.NoError(t, writer.Close())
+	return buf.Bytes()
+}
+
+// TestEncodedQueryAndSeedFiles verifies compressed and encrypted files are decoded when loaded.
+func TestEncodedQueryAndSeedFiles(t *testing.T) {
+	key := bytes.Repeat([]byte{7}, 32)
+	dir := t.TempDir()
+
+	encrypted, err := EncryptFile(key, gzipContent(t, "SELECT 'secret'"))
+	assert.NoError(t, err)
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "secret.sql.gz.enc"), encrypted, 0o644))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "plain.sql.gz"), gzipContent(t, "SELECT 'plain'"), 0o644))
+
+	seed, err := EncryptFile(key, []byte("CREATE TABLE seeded (id INTEGER); INSERT INTO seeded VALUES (1);"))
+	assert.NoError(t, err)
+	seedPath := filepath.Join(t.TempDir(), "seed.sql.enc")
+	assert.NoError(t, os.WriteFile(seedPath, seed, 0o644))
+
+	g, err := NewGorm(newTestDatabaseContext(), nil, []string{seedPath}, map[string]string{},
+		Config{QueryDirs: []string{dir}, KeyProvider: StaticKey(key)})
+	assert.NoError(t, err, "Unexpected error from NewGorm")
+
+	for name, want := range map[string]string{"secret": "SELECT 'secret'", "plain": "SELECT 'plain'"} {
+		query, err := g.GetQuery(name)
+		assert.NoError(t, err)
+		assert.Equal(t, want, query)
+	}
+
+	assert.NoError(t, g.Seed())
+	var count int64
+	assert.NoError(t, g.GetDB().Table("seeded").Count(&count))
+	assert.EqualValues(t, 1, count)
+
+	_, err = NewGorm(newTestDatabaseContext(), nil, []string{}, map[string]string{}, Config{QueryDirs: []string{dir}})
+	assert.ErrorIs(t, err, ErrNoKeyProvider)
+
+	_, err = NewGorm(newTestDatabaseContext(), nil, []string{}, map[string]string{},
+		Config{QueryDirs: []string{dir}, KeyProvider: StaticKey(bytes.Repeat([]byte{8}, 32))})
+	assert.ErrorContains(t, err, "failed to decrypt")
+}
@@ -0,0 +1,132 @@
+package gormext
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// entityTagPrefix prefixes the store keys holding the current version of the records of a
+// table cached by FirstByID, replaced by writes to the table not limited to known records.
+const entityTagPrefix = "gormext:entities:"
+
+// ErrNoCacheStore is returned when caching is set up on a connection without Config.Cache.
+var ErrNoCacheStore = errors.New("no cache store configured")
+
+// CacheEntity caches the records of model read by FirstByID for ttl, in the Config.Cache
+// store. Misses read the database and populate the cache. Creates, updates and deletes of
+// records with a primary key invalidate those records, while other writes to the table, such
+// as deletes by condition, invalidate all of its records; raw SQL writes do not. Lookups with
+// other conditions, such as Where or Table, or inside transactions read the database. With
+// Config.SecondLevelCache, other lookups by primary key are served from the cache as well.
+func (g *Gorm) CacheEntity(model any, ttl time.Duration) error {
+	cache, ok := g.connection.Config.Plugins[cachePluginName].(*resultCache)
+	if !ok {
+		return ErrNoCacheStore
+	}
+
+	stmt := &gorm.Statement{DB: g.connection}
+	if err := stmt.Parse(model); err != nil {
+		return fmt.Errorf("failed to parse cached entity model: %w", err)
+	}
+	cache.entities.Store(stmt.Schema.Table, ttl)
+	return nil
+}
+
+// entityCache returns the result cache and table of the records read into dest by FirstByID,
+// nil when they are not cached.
+func (r *gormRepository) entityCache(dest any) (*resultCache, string) {
+	cache, ok := r.db.Config.Plugins[cachePluginName].(*resultCache)
+	if !ok || inTransaction(r.db) || cacheModeOf(r.db) == cacheBypass || unmaskedRead(r.db) {
+		return nil, ""
+	}
+
+	stmt := r.db.Statement
+	if len(stmt.Clauses) > 0 || len(stmt.Joins) > 0 || len(stmt.Preloads) > 0 || len(stmt.Selects) > 0 ||
+		len(stmt.Omits) > 0 || stmt.Table != "" || stmt.Model != nil || stmt.Unscoped {
+		return nil, ""
+	}
+
+	parsed := &gorm.Statement{DB: r.db}
+	if err := parsed.Parse(dest); err != nil {
+		return nil, ""
+	}
+	if _, ok := cache.entities.Load(parsed.Schema.Table); !ok {
+		return nil, ""
+	}
+	return cache, parsed.Schema.Table
+}
+
+// loadEntity fills dest with the cached record of table with id, or reads it on db and caches
+// it for the TTL of the table.
+func (c *resultCache) loadEntity(db *gorm.DB, table string, id, dest any) error {
+	query := func(db *gorm.DB, dest any) *gorm.DB { return db.Where("id = ?", id).First(dest) }
+	ctx := db.Statement.Context
+	version, err := c.version(ctx, entityTagPrefix+table)
+	if err != nil {
+		db.Logger.Warn(ctx, "entity cache read: %v", err)
+		return query(db, dest).Error
+	}
+
+	ttl, _ := c.entities.Load(table)
+	tenant, _ := TenantFromContext(ctx)
+	return c.readThrough(db, &c.entityCounters, entityKey(table, version, tenant, fmt.Sprint(id)), dest, ttl.(time.Duration), 0, query)
+}
+
+// writtenIDs returns the primary keys of the records written by a statement, when their
+// table is cached by FirstByID. The keys are nil when the written records are not known.
+func (c *resultCache) writtenIDs(stmt *gorm.Statement) ([]string, bool) {
+	if _, ok := c.entities.Load(stmt.Table); !ok {
+		return nil, false
+	}
+	if stmt.Schema == nil || stmt.Schema.PrioritizedPrimaryField == nil {
+		return nil, true
+	}
+
+	entities := auditEntities(stmt)
+	ids := make([]string, 0, len(entities))
+	for _, entity := range entities {
+		id, zero := stmt.Schema.PrioritizedPrimaryField.ValueOf(stmt.Context, entity)
+		if zero {
+			return nil, true
+		}
+		ids = append(ids, fmt.Sprint(id))
+	}
+	if len(ids) == 0 {
+		return nil, true
+	}
+	return ids, true
+}
+
+// invalidateEntities drops the cached records of table with ids, or all of them when ids is
+// nil.
+func (c *resultCache) invalidateEntities(db *gorm.DB, table string, ids []string) {
+	ctx := db.Statement.Context
+	if ids == nil {
+		if err := c.store.Delete(ctx, entityTagPrefix+table); err != nil {
+			db.Logger.Warn(ctx, "entity cache invalidation of '%s': %v", table, err)
+		}
+		return
+	}
+
+	version, ok, err := c.store.Get(ctx, entityTagPrefix+table)
+	if err == nil && ok {
+		tenant, _ := TenantFromContext(ctx)
+		keys := make([]string, len(ids))
+		for i, id := range ids {
+			keys[i] = entityKey(table, string(version), tenant, id)
+		}
+		err = c.store.Delete(ctx, keys...)
+	}
+	if err != nil {
+		db.Logger.Warn(ctx, "entity cache invalidation of '%s': %v", table, err)
+	}
+}
+
+// entityKey returns the key of the record of table with id, cached at version for tenant.
+func entityKey(table, version, tenant, id string) string {
+	return entityTagPrefix + table + "@" + version + ":" + strconv.Quote(tenant) + ":" + id
+}
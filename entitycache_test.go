@@ -0,0 +1,57 @@
+package gormext
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+// TestCacheEntity verifies that FirstByID reads cached records through the cache, and that
+// writes invalidate the records they touch.
+func TestCacheEntity(t *testing.T) {
+	cache := NewMemoryCache(100)
+	g, repo := newCachedRepository(t, cache)
+	assert.NoError(t, g.CacheEntity(&repoUser{}, time.Minute))
+
+	var user repoUser
+	assert.NoError(t, repo.FirstByID(1, &user))
+	assert.NoError(t, repo.Exec("UPDATE repo_users SET name = 'raw'"))
+	user = repoUser{}
+	assert.NoError(t, repo.FirstByID(1, &user))
+	assert.Equal(t, "alice", user.Name, "records are read from the cache")
+	assert.NoError(t, repo.Where("age > ?", 0).FirstByID(1, &user))
+	assert.Equal(t, "raw", user.Name, "lookups with conditions read the database")
+
+	var other repoUser
+	assert.NoError(t, repo.FirstByID(2, &other))
+	assert.NoError(t, repo.Update(&repoUser{ID: 1, Name: "alicia", Age: 30}))
+	assert.NoError(t, repo.Exec("UPDATE repo_users SET name = 'raw2' WHERE id = 2"))
+	assert.NoError(t, repo.FirstByID(1, &user))
+	assert.Equal(t, "alicia", user.Name, "writes invalidate their records")
+	assert.NoError(t, repo.FirstByID(2, &other))
+	assert.Equal(t, "raw", other.Name, "writes keep the other records")
+
+	assert.NoError(t, repo.Where("age > ?", 0).Delete(&repoUser{}))
+	assert.ErrorIs(t, repo.FirstByID(2, &other), gorm.ErrRecordNotFound, "writes by condition invalidate the table")
+}
+
+// TestCacheEntityTTL verifies that cached records expire after the TTL of their model.
+func TestCacheEntityTTL(t *testing.T) {
+	g, repo := newCachedRepository(t, NewMemoryCache(100))
+	assert.NoError(t, g.CacheEntity(&repoUser{}, 10*time.Millisecond))
+
+	var user repoUser
+	assert.NoError(t, repo.FirstByID(1, &user))
+	assert.NoError(t, repo.Exec("UPDATE repo_users SET name = 'raw'"))
+	time.Sleep(20 * time.Millisecond)
+	assert.NoError(t, repo.FirstByID(1, &user))
+	assert.Equal(t, "raw", user.Name)
+}
+
+// TestCacheEntityNoStore verifies that CacheEntity needs a cache store.
+func TestCacheEntityNoStore(t *testing.T) {
+	g, _ := newTestRepository(t)
+	assert.ErrorIs(t, g.CacheEntity(&repoUser{}, time.Minute), ErrNoCacheStore)
+}
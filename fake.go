@@ -0,0 +1,236 @@
+package gormext
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+// defaultFakeBatchSize is the number of rows inserted per statement by a FakeSeeder.
+const defaultFakeBatchSize = 1000
+
+// fakeTimeEnd is the latest time generated for time fields, fixed so that seeded data is
+// reproducible.
+var fakeTimeEnd = time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+type (
+	// FakeGenerator returns the value of a field for the i-th generated row (starting at 0).
+	FakeGenerator func(r *rand.Rand, i int) any
+
+	// FakeSeeder is a Seeder inserting Count rows of synthetic data for Model, such as for load
+	// testing. Fields without a generator get a value inferred from their gormext fake tag
+	// (e.g. `gormext:"fake:email"`), their name (email, phone, url, city, name...) or their
+	// type. Auto-incremented primary keys, timestamps managed by gorm and soft delete fields
+	// are left to the database and gorm.
+	FakeSeeder struct {
+		SeedName   string                   // Seeder name, used for tracking.
+		Model      any                      // Model to insert, such as &User{}.
+		Count      int                      // Number of rows to insert.
+		BatchSize  int                      // Rows inserted per statement, 1000 by default.
+		Generators map[string]FakeGenerator // Generators by field or column name.
+		RandSeed   uint64                   // Seed of the random source, for reproducible data.
+	}
+
+	// fakeField is a field filled by a FakeSeeder.
+	fakeField struct {
+		field    *schema.Field
+		generate FakeGenerator
+	}
+)
+
+var (
+	// fakeSchemas caches the schemas parsed by fake seeders.
+	fakeSchemas = &sync.Map{}
+
+	// fakeKinds are the generators selected with the gormext fake tag, or by field name.
+	fakeKinds = map[string]FakeGenerator{
+		"first_name": func(r *rand.Rand, _ int) any { return pick(r, fakeFirstNames) },
+		"last_name":  func(r *rand.Rand, _ int) any { return pick(r, fakeLastNames) },
+		"name": func(r *rand.Rand, _ int) any {
+			return pick(r, fakeFirstNames) + " " + pick(r, fakeLastNames)
+		},
+		"email": func(r *rand.Rand, i int) any {
+			return fmt.Sprintf("%s.%d@%s", strings.ToLower(pick(r, fakeFirstNames)), i, pick(r, fakeDomains))
+		},
+		"phone": func(r *rand.Rand, _ int) any {
+			return fmt.Sprintf("+1 %03d-%03d-%04d", 200+r.IntN(800), r.IntN(1000), r.IntN(10000))
+		},
+		"url":  func(r *rand.Rand, i int) any { return fmt.Sprintf("https://%s/%d", pick(r, fakeDomains), i) },
+		"city": func(r *rand.Rand, _ int) any { return pick(r, fakeCities) },
+		"word": func(r *rand.Rand, _ int) any { return pick(r, fakeWords) },
+		"sentence": func(r *rand.Rand, _ int) any {
+			words := make([]string, 6+r.IntN(6))
+			for i := range words {
+				words[i] = pick(r, fakeWords)
+			}
+			return strings.ToUpper(words[0][:1]) + strings.Join(words, " ")[1:] + "."
+		},
+		"uuid": func(r *rand.Rand, _ int) any {
+			hi, lo := r.Uint64(), r.Uint64()
+			hi = hi&^0xf000 | 0x4000     // Version 4.
+			lo = lo&^(0xc<<60) | 0x8<<60 // RFC 4122 variant.
+			return fmt.Sprintf("%08x-%04x-%04x-%04x-%012x", hi>>32, hi>>16&0xffff, hi&0xffff, lo>>48, lo&0xffffffffffff)
+		},
+	}
+
+	// fakeNameKinds infers generators from field names, checked in order.
+	fakeNameKinds = []string{"email", "phone", "url", "city", "first_name", "last_name", "uuid", "name"}
+
+	fakeFirstNames = []string{"Ana", "Bruno", "Carla", "Diego", "Elena", "Felipe", "Gabriela", "Hugo", "Isabel", "João", "Karen", "Lucas", "Marina", "Nuno", "Olivia", "Pedro"}
+	fakeLastNames  = []string{"Almeida", "Barbosa", "Costa", "Dias", "Ferreira", "Gomes", "Lima", "Martins", "Nunes", "Oliveira", "Pereira", "Rocha", "Santos", "Silva"}
+	fakeCities     = []string{"Lisbon", "Porto", "São Paulo", "Recife", "Madrid", "Berlin", "Austin", "Toronto", "Tokyo", "Nairobi"}
+	fakeDomains    = []string{"example.com", "example.org", "example.net"}
+	fakeWords      = []string{"alpha", "bravo", "delta", "echo", "lorem", "ipsum", "dolor", "amet", "quick", "brown", "lazy", "river", "stone", "cloud", "orbit", "pixel"}
+)
+
+// Name returns the seeder name.
+func (s *FakeSeeder) Name() string {
+	return s.SeedName
+}
+
+// Run generates the rows and inserts them in batches through repo.
+func (s *FakeSeeder) Run(repo IRepository) error {
+	table, err := fakeSchema(repo, s.Model)
+	if err != nil {
+		return fmt.Errorf("failed to parse fake seed model %T: %w", s.Model, err)
+	}
+
+	fields, err := s.fields(table)
+	if err != nil {
+		return err
+	}
+
+	batchSize := s.BatchSize
+	if batchSize <= 0 {
+		batchSize = defaultFakeBatchSize
+	}
+
+	r := rand.New(rand.NewPCG(s.RandSeed, s.RandSeed))
+	ctx := context.Background()
+	for start := 0; start < s.Count; start += batchSize {
+		size := min(batchSize, s.Count-start)
+		batch := reflect.New(reflect.SliceOf(table.ModelType))
+		batch.Elem().Set(reflect.MakeSlice(batch.Elem().Type(), size, size))
+
+		for j := 0; j < size; j++ {
+			row := batch.Elem().Index(j)
+			for _, f := range fields {
+				if err := f.field.Set(ctx, row, f.generate(r, start+j)); err != nil {
+					return fmt.Errorf("invalid fake value for field '%s': %w", f.field.Name, err)
+				}
+			}
+		}
+
+		if err := repo.Create(batch.Interface()); err != nil {
+			return fmt.Errorf("failed to insert fake rows %d-%d: %w", start+1, start+size, err)
+		}
+	}
+	return nil
+}
+
+// fakeSchema parses model with the naming strategy of the connection of repo, or the default
+// one for repositories of other implementations.
+func fakeSchema(repo IRepository, model any) (*schema.Schema, error) {
+	if r, ok := repo.(*gormRepository); ok {
+		stmt := &gorm.Statement{DB: r.db}
+		if err := stmt.Parse(model); err != nil {
+			return nil, err
+		}
+		return stmt.Schema, nil
+	}
+	return schema.Parse(model, fakeSchemas, schema.NamingStrategy{})
+}
+
+// fields resolves the generator of every field to fill, in field order so that seeded data
+// is reproducible.
+func (s *FakeSeeder) fields(table *schema.Schema) ([]fakeField, error) {
+	for name := range s.Generators {
+		if table.LookUpField(name) == nil {
+			return nil, fmt.Errorf("fake generator for unknown field '%s' of %s", name, table.Name)
+		}
+	}
+
+	var fields []fakeField
+	for _, field := range table.Fields {
+		if field.DBName == "" || !field.Creatable {
+			continue
+		}
+		if generate, ok := s.Generators[field.Name]; ok {
+			fields = append(fields, fakeField{field, generate})
+			continue
+		}
+		if generate, ok := s.Generators[field.DBName]; ok {
+			fields = append(fields, fakeField{field, generate})
+			continue
+		}
+
+		if field.AutoIncrement || (field.PrimaryKey && field.HasDefaultValue) ||
+			field.AutoCreateTime > 0 || field.AutoUpdateTime > 0 || field.FieldType == reflect.TypeOf(gorm.DeletedAt{}) {
+			continue
+		}
+
+		generate, err := fakeGenerator(field)
+		if err != nil {
+			return nil, err
+		}
+		if generate != nil {
+			fields = append(fields, fakeField{field, generate})
+		}
+	}
+	return fields, nil
+}
+
+// fakeGenerator infers the generator of field from its tag, name or type. It returns nil for
+// types it cannot fill, leaving them at their zero value.
+func fakeGenerator(field *schema.Field) (FakeGenerator, error) {
+	if kind, ok := schema.ParseTagSetting(field.Tag.Get("gormext"), ";")["FAKE"]; ok {
+		generate, found := fakeKinds[strings.ToLower(kind)]
+		if !found {
+			return nil, fmt.Errorf("unknown fake kind '%s' for field '%s'", kind, field.Name)
+		}
+		return generate, nil
+	}
+
+	typ := field.IndirectFieldType
+	if typ.Kind() == reflect.String {
+		for _, kind := range fakeNameKinds {
+			if strings.Contains(field.DBName, kind) {
+				return fakeKinds[kind], nil
+			}
+		}
+		return fakeKinds["word"], nil
+	}
+
+	switch {
+	case typ == timeType:
+		return func(r *rand.Rand, _ int) any {
+			return fakeTimeEnd.Add(-time.Duration(r.Int64N(int64(365 * 24 * time.Hour)))).Truncate(time.Second)
+		}, nil
+	case typ.Kind() == reflect.Bool:
+		return func(r *rand.Rand, _ int) any { return r.IntN(2) == 1 }, nil
+	case typ.Kind() >= reflect.Int && typ.Kind() <= reflect.Uint64:
+		// Keep values within the field: int8 and uint8 hold less than 10000.
+		limit := 10000
+		if bits := typ.Bits(); bits == 8 && typ.Kind() <= reflect.Int64 {
+			limit = 1 << 7
+		} else if bits == 8 {
+			limit = 1 << 8
+		}
+		return func(r *rand.Rand, _ int) any { return r.IntN(limit) }, nil
+	case typ.Kind() == reflect.Float32 || typ.Kind() == reflect.Float64:
+		return func(r *rand.Rand, _ int) any { return float64(r.IntN(1000000)) / 100 }, nil
+	}
+	return nil, nil
+}
+
+// pick returns a random element of values.
+func pick(r *rand.Rand, values []string) string {
+	return values[r.IntN(len(values))]
+}
@@ -0,0 +1,102 @@
+package gormext
+
+import (
+	"math/rand/v2"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+// fakeCustomer is filled by a FakeSeeder.
+type fakeCustomer struct {
+	ID        uint
+	FullName  string
+	Email     string
+	Home      string `gormext:"fake:city"`
+	Age       int
+	Score     float64
+	Active    bool
+	BirthDate time.Time
+	CreatedAt time.Time
+	DeletedAt gorm.DeletedAt
+}
+
+// TestFakeSeeder verifies that synthetic rows are generated from tags, names, types and
+// custom generators, and inserted in batches.
+func TestFakeSeeder(t *testing.T) {
+	g := newFileSeedGorm(t, t.TempDir())
+	assert.NoError(t, g.Migrate(&fakeCustomer{}))
+
+	g.RegisterSeeder(&FakeSeeder{
+		SeedName:  "customers",
+		Model:     &fakeCustomer{},
+		Count:     250,
+		BatchSize: 100,
+		RandSeed:  7,
+		Generators: map[string]FakeGenerator{
+			"age": func(r *rand.Rand, i int) any { return 18 + i%50 },
+		},
+	})
+	assert.NoError(t, g.Seed())
+
+	var customers []fakeCustomer
+	assert.NoError(t, g.GetDB().Order("id").Find(&customers))
+	assert.Len(t, customers, 250)
+
+	emails := make(map[string]bool)
+	for i, customer := range customers {
+		assert.Contains(t, customer.Email, "@example.")
+		assert.Contains(t, fakeCities, customer.Home)
+		assert.Len(t, strings.Fields(customer.FullName), 2)
+		assert.Equal(t, 18+i%50, customer.Age)
+		assert.False(t, customer.BirthDate.IsZero())
+		assert.False(t, customer.CreatedAt.IsZero(), "Timestamps should be left to gorm")
+		emails[customer.Email] = true
+	}
+	assert.Len(t, emails, 250, "Emails should be unique")
+
+	uuid := fakeKinds["uuid"](rand.New(rand.NewPCG(1, 1)), 0).(string)
+	assert.Regexp(t, `^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`, uuid)
+
+	err := (&FakeSeeder{Model: &fakeCustomer{}, Count: 1, Generators: map[string]FakeGenerator{"missing": nil}}).Run(g.GetDB())
+	assert.ErrorContains(t, err, "unknown field 'missing'")
+}
+
+// fakeResident is filled by a FakeSeeder on a connection with custom naming.
+type fakeResident struct {
+	ID        uint
+	Residence string
+	Floor     int8
+	Rooms     uint8
+	MovedIn   time.Time
+}
+
+// TestFakeSeederNamingAndSeed verifies that fields are inferred from the columns of the
+// connection naming strategy, that integers fit their field and that a seed reproduces the
+// same rows, times included.
+func TestFakeSeederNamingAndSeed(t *testing.T) {
+	seed := func() []fakeResident {
+		g, err := NewGorm(newTestDatabaseContext(), nil, nil, nil, Config{Config: gorm.Config{
+			NamingStrategy: schema.NamingStrategy{NameReplacer: strings.NewReplacer("Residence", "City")},
+		}})
+		assert.NoError(t, err)
+		assert.NoError(t, g.Migrate(&fakeResident{}))
+		assert.NoError(t, (&FakeSeeder{Model: &fakeResident{}, Count: 50, RandSeed: 3}).Run(g.GetDB()))
+
+		var residents []fakeResident
+		assert.NoError(t, g.GetDB().Order("id").Find(&residents))
+		return residents
+	}
+
+	residents := seed()
+	assert.Len(t, residents, 50)
+	for _, resident := range residents {
+		assert.Contains(t, fakeCities, resident.Residence, "the city column should get cities")
+		assert.GreaterOrEqual(t, resident.Floor, int8(0))
+	}
+	assert.Equal(t, residents, seed())
+}
@@ -0,0 +1,98 @@
+package gormext
+
+import (
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+// golangMigrateVersion is the row of the golang-migrate version table, holding the current
+// version and whether the migration to it failed partway.
+type golangMigrateVersion struct {
+	Version int64
+	Dirty   bool
+}
+
+// TableName returns the version table of golang-migrate.
+func (golangMigrateVersion) TableName() string {
+	return "schema_migrations"
+}
+
+// createGolangMigrateTable creates the golang-migrate version table when missing, as
+// golang-migrate does, since AutoMigrate would alter its columns.
+func (g *Gorm) createGolangMigrateTable(conn *gorm.DB) error {
+	for _, statement := range splitStatements(golangMigrateDDL(g.databaseCtx.driver), g.databaseCtx.driver) {
+		if err := conn.Exec(statement).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// golangMigrateApplied returns the migrations golang-migrate considers applied: those up to its
+// current version, which is flagged dirty when its migration failed partway. The current
+// version is returned even without a known migration, so that reverting it fails instead of
+// skipping it.
+func (g *Gorm) golangMigrateApplied(conn *gorm.DB) ([]schemaMigration, error) {
+	var current []golangMigrateVersion
+	if err := conn.Limit(1).Find(&current).Error; err != nil {
+		return nil, fmt.Errorf("failed to read applied migrations: %w", err)
+	}
+	if len(current) == 0 || current[0].Version < 0 {
+		return nil, nil
+	}
+
+	version := uint64(current[0].Version)
+	var applied []schemaMigration
+	for _, m := range g.migrations {
+		if m.version > version {
+			break
+		}
+		applied = append(applied, schemaMigration{Version: m.version, Name: m.name, Checksum: m.checksum})
+	}
+	if g.findMigration(version) == nil {
+		applied = append(applied, schemaMigration{Version: version})
+	}
+	applied[len(applied)-1].Dirty = current[0].Dirty
+	return applied, nil
+}
+
+// golangMigrateTarget returns the golang-migrate version after migrating version in direction:
+// the version itself when applied, the previous known version (or none) when reverted.
+func (g *Gorm) golangMigrateTarget(version uint64, direction string) uint64 {
+	if direction == migrationUp {
+		return version
+	}
+
+	var previous uint64
+	for _, m := range g.migrations {
+		if m.version >= version {
+			break
+		}
+		previous = m.version
+	}
+	return previous
+}
+
+// setGolangMigrateVersion replaces the golang-migrate version row. Version 0 leaves the table
+// empty, as golang-migrate does once every migration is reverted.
+func (g *Gorm) setGolangMigrateVersion(tx *gorm.DB, version uint64, dirty bool) error {
+	return tx.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Session(&gorm.Session{AllowGlobalUpdate: true}).Delete(&golangMigrateVersion{}).Error; err != nil {
+			return err
+		}
+		if version == 0 {
+			return nil
+		}
+		return tx.Create(&golangMigrateVersion{Version: int64(version), Dirty: dirty}).Error
+	})
+}
+
+// golangMigrateDDL returns the statements golang-migrate creates its version table with.
+func golangMigrateDDL(driver SQLDriver) string {
+	if driver == SQLite {
+		return "CREATE TABLE IF NOT EXISTS schema_migrations (version uint64, dirty bool);\n" +
+			"CREATE UNIQUE INDEX IF NOT EXISTS version_unique ON schema_migrations (version);"
+	}
+	return "CREATE TABLE IF NOT EXISTS schema_migrations (version bigint NOT NULL PRIMARY KEY, dirty boolean NOT NULL);"
+}
@@ -0,0 +1,54 @@
+package gormext
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestGolangMigrate verifies that migrations adopt and maintain a golang-migrate version table.
+func TestGolangMigrate(t *testing.T) {
+	dir := t.TempDir()
+	writeSQLFiles(t, dir, map[string]string{
+		"000001_create_users.up.sql":   "CREATE TABLE users (id INTEGER PRIMARY KEY, name TEXT);",
+		"000001_create_users.down.sql": "DROP TABLE users;",
+		"000002_add_email.up.sql":      "ALTER TABLE users ADD COLUMN email TEXT;",
+		"000002_add_email.down.sql":    "ALTER TABLE users DROP COLUMN email;",
+	})
+
+	dbCtx, err := NewDatabaseContext(filepath.Join(t.TempDir(), "golang-migrate.db"), "sqlite", "silent")
+	assert.NoError(t, err)
+	g, err := NewGorm(*dbCtx, nil, nil, nil, Config{MigrationsDir: dir, GolangMigrate: true})
+	assert.NoError(t, err)
+
+	// A database migrated to version 1 by golang-migrate.
+	conn := g.connection
+	assert.NoError(t, conn.Exec(golangMigrateDDL(SQLite)).Error)
+	assert.NoError(t, conn.Exec("CREATE TABLE users (id INTEGER PRIMARY KEY, name TEXT)").Error)
+	assert.NoError(t, conn.Exec("INSERT INTO schema_migrations (version, dirty) VALUES (1, false)").Error)
+
+	current := func() []golangMigrateVersion {
+		var rows []golangMigrateVersion
+		assert.NoError(t, conn.Find(&rows).Error)
+		return rows
+	}
+
+	ctx := context.Background()
+	assert.NoError(t, g.MigrateUp(ctx), "Version 1 should not be applied again")
+	assert.True(t, conn.Migrator().HasColumn("users", "email"))
+	assert.Equal(t, []golangMigrateVersion{{Version: 2}}, current())
+
+	assert.NoError(t, g.MigrateDown(ctx))
+	assert.False(t, conn.Migrator().HasColumn("users", "email"))
+	assert.Equal(t, []golangMigrateVersion{{Version: 1}}, current())
+
+	assert.NoError(t, g.MigrateDown(ctx))
+	assert.False(t, conn.Migrator().HasTable("users"))
+	assert.Empty(t, current(), "Reverting every migration should empty the version table")
+
+	assert.NoError(t, conn.Exec("INSERT INTO schema_migrations (version, dirty) VALUES (2, true)").Error)
+	assert.True(t, errors.Is(g.MigrateUp(ctx), ErrDirtyMigration))
+}
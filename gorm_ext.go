@@ -2,7 +2,10 @@ package gormext
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"fmt"
+	"io/fs"
 	"os"
 	"sync"
 	"time"
@@ -10,27 +13,61 @@ import (
 	"gorm.io/gorm"
 )
 
-
 // IRepository defines an interface for repository operations.
 type IRepository interface {
-	WithTransaction(fn func(tx IRepository) error) error // Execute operations within a transaction.
-	WithContext(ctx context.Context) IRepository         // Set context for queries.
-	FirstByID(id any, dest any) error                    // Find a record by its ID.
-	First(dest any, conds ...any) error                  // Return the first record that matches the condition.
-	Find(dest any) error                                 // Find all records.
-	Create(entity any) error                             // Create a new record.
-	Update(entity any) error                             // Update an existing record.
-	Delete(entity any) error                             // Delete a record.
-	Exec(sql string, value ...any) error                 // Execute a SQL query.
-	IDEqual(id any) IRepository                          // Add condition "ID = ?".
-	IDIn(ids []any) IRepository                          // Add condition "ID IN (?)".
-	Where(query any, args ...any) IRepository            // Add a WHERE clause.
-	Joins(query string, args ...any) IRepository         // Add a JOIN clause.
-	Preload(query string, args ...any) IRepository       // Add a PRELOAD clause.
-	Order(value any) IRepository                         // Add an ORDER BY clause.
-	IsActive() IRepository                               // Filter records where "active IS TRUE".
-	Table(name string, args ...any) IRepository          // Specify the table to query.
-	Count(count *int64) error                            // Count records matching the query.
+	WithTransaction(fn func(tx IRepository) error) error                                  // Execute operations within a transaction.
+	WithTransactionOpts(opts TxOptions, fn func(tx IRepository) error) error              // Execute operations within a transaction with options.
+	Begin() (ITransaction, error)                                                         // Start a transaction committed or rolled back manually.
+	AfterCommit(fn func())                                                                // Run fn once the transaction commits.
+	AfterRollback(fn func())                                                              // Run fn once the transaction rolls back.
+	WithContext(ctx context.Context) IRepository                                          // Set context for queries.
+	Cached(ttl time.Duration) IRepository                                                 // Cache Find, First and Count results for ttl.
+	CachedStale(ttl, stale time.Duration) IRepository                                     // Cache results, serving them stale while refreshed.
+	NoCache() IRepository                                                                 // Bypass the caches.
+	RefreshCache() IRepository                                                            // Read the database, replacing cached values.
+	WithoutTenantScope() IRepository                                                      // Read and write the rows of every tenant.
+	FirstByID(id any, dest any) error                                                     // Find a record by its ID.
+	First(dest any, conds ...any) error                                                   // Return the first record that matches the condition.
+	Find(dest any) error                                                                  // Find all records.
+	FindInBatches(dest any, batchSize int, fn func(batch IRepository, n int) error) error // Process records in batches.
+	Create(entity any) error                                                              // Create a new record.
+	Update(entity any) error                                                              // Update an existing record.
+	Delete(entity any) error                                                              // Delete a record.
+	Restore(entity any) error                                                             // Restore a soft-deleted record.
+	RestoreByID(model any, id any) error                                                  // Restore the soft-deleted record of model by ID.
+	Exec(sql string, value ...any) error                                                  // Execute a SQL query.
+	IDEqual(id any) IRepository                                                           // Add condition "ID = ?".
+	IDIn(ids []any) IRepository                                                           // Add condition "ID IN (?)".
+	Where(query any, args ...any) IRepository                                             // Add a WHERE clause.
+	Not(query any, args ...any) IRepository                                               // Add a negated WHERE clause.
+	Or(query any, args ...any) IRepository                                                // Add a condition joined with OR.
+	Joins(query string, args ...any) IRepository                                          // Add a JOIN clause.
+	Preload(query string, args ...any) IRepository                                        // Add a PRELOAD clause.
+	Order(value any) IRepository                                                          // Add an ORDER BY clause.
+	LockForUpdate() IRepository                                                           // Lock selected rows with FOR UPDATE.
+	LockShare() IRepository                                                               // Lock selected rows with FOR SHARE.
+	SkipLocked() IRepository                                                              // Skip rows locked by other transactions.
+	IsActive() IRepository                                                                // Filter active records, "active IS TRUE" by default.
+	WithStatus(values ...string) IRepository                                              // Filter records by status.
+	OnlyTrashed() IRepository                                                             // Filter soft-deleted records.
+	WhereJSONEquals(path string, value any) IRepository                                   // Add condition "JSON value at path = value".
+	WhereJSONContains(path string, value any) IRepository                                 // Add condition "JSON value at path contains value".
+	Table(name string, args ...any) IRepository                                           // Specify the table to query.
+	Scopes(fns ...func(IRepository) IRepository) IRepository                              // Apply reusable query fragments.
+	Count(count *int64) error                                                             // Count records matching the query.
+	Rows() (*sql.Rows, error)                                                             // Run the query and return its rows.
+
+	CountBy(column string) (map[string]int64, error) // Count records grouped by a column.
+	SumInt64(column string) (int64, error)           // Sum an integer column.
+	MaxTime(column string) (time.Time, error)        // Return the latest value of a time column.
+	PluckStrings(column string) ([]string, error)    // Return the values of a string column.
+}
+
+// ITransaction is a transaction started by IRepository.Begin, to end with Commit or Rollback.
+type ITransaction interface {
+	IRepository
+	Commit() error   // Commit the transaction.
+	Rollback() error // Roll back the transaction, doing nothing once it ended.
 }
 
 // Repository is a function type that receives a *gorm.DB connection and returns an IRepository.
@@ -39,18 +76,216 @@ type Repository func(*gorm.DB) IRepository
 // Config wraps the GORM configuration.
 type Config struct {
 	gorm.Config
+
+	// QueriesFS is an optional filesystem (such as an embed.FS) whose .sql files are cached as queries.
+	QueriesFS fs.FS
+
+	// QueriesRoot is the directory of QueriesFS holding the query files, "." by default.
+	QueriesRoot string
+
+	// QueryDirs lists directories (searched recursively) or glob patterns of .sql files to cache.
+	// Query names derive from the relative file path: users/find_active.sql becomes users.find_active.
+	// Driver variants such as report.postgres.sql and report.mysql.sql are resolved by GetQuery("report").
+	QueryDirs []string
+
+	// QuerySources lists additional query sets to load, such as an HTTPQuerySource.
+	QuerySources []QuerySource
+
+	// KeyProvider supplies the keys of encrypted (.enc) seed and query files. Compressed (.gz)
+	// files need no configuration.
+	KeyProvider KeyProvider
+
+	// Profile optionally applies a preset of pool, prepared statement and logging defaults,
+	// such as ProfileWebService. Settings made explicitly on the embedded gorm.Config win.
+	Profile *Profile
+
+	// Dialector optionally replaces the dialector built from the DatabaseContext, to use a
+	// driver such as an HTTP-based serverless driver. The context's driver still selects the
+	// SQL dialect used by the package.
+	Dialector gorm.Dialector
+
+	// MigrationsDir is a directory of versioned migration files applied by MigrateUp, named
+	// <version>_<name>.up.sql and <version>_<name>.down.sql.
+	MigrationsDir string
+
+	// MigrationsFS is an optional filesystem (such as an embed.FS) holding migration files named
+	// like the ones of MigrationsDir, so that services ship their migrations in the binary.
+	MigrationsFS fs.FS
+
+	// MigrationsRoot is the directory of MigrationsFS holding the migration files, "." by default.
+	MigrationsRoot string
+
+	// GolangMigrate keeps migration history in the version table of golang-migrate, a single
+	// schema_migrations row holding the current version and a dirty flag, so that databases
+	// migrated with golang-migrate can adopt gormext without a second history. Its file format
+	// is the one of MigrationsDir. Like golang-migrate, migrations then run outside
+	// transactions, so scripts may manage their own.
+	GolangMigrate bool
+
+	// AllowDestructive lets Migrate apply column changes that may lose data, which it refuses
+	// by default: type narrowing, dropped constraints or indexes, and NOT NULL added to columns
+	// holding NULLs. MigratePlan reports such changes as Destructive.
+	AllowDestructive bool
+
+	// ValidateQueries prepares every cached query while connecting, failing fast on invalid SQL.
+	ValidateQueries bool
+
+	// SQLComments appends a sqlcommenter comment to every statement, holding the Application
+	// name, the route set with WithRoute and the traceparent of the context span, so that
+	// statements seen by the database (such as in pg_stat_statements) map back to code paths.
+	// Comments vary with the trace, so every statement would be prepared anew and kept in the
+	// unbounded statement cache of PrepareStmt: the two cannot be set together.
+	SQLComments bool
+
+	// Application is the application name of SQL comments.
+	Application string
+
+	// CollectQueryStats aggregates the count, latency and errors of the statements by
+	// fingerprint, reported by QueryStats.
+	CollectQueryStats bool
+
+	// Cache stores the results of the repositories returned by Cached, such as a MemoryCache
+	// or a gormextredis store. Without it, Cached has no effect.
+	Cache CacheStore
+
+	// CacheKey returns the store keys of cached results and coalesced reads, DefaultCacheKey
+	// by default. Applications override it to add their own isolation, such as a namespace
+	// per deployment.
+	CacheKey CacheKeyFunc
+
+	// NegativeCacheTTL caches for this short time the lookups that found no record: First
+	// calls of Cached repositories and FirstByID calls of CacheEntity models then return
+	// gorm.ErrRecordNotFound from the cache, sparing the database repeated lookups of missing
+	// IDs. Creates invalidate them like other writes. Zero disables negative caching.
+	NegativeCacheTTL time.Duration
+
+	// SecondLevelCache serves the queries selecting entities of the models cached with
+	// CacheEntity by primary key only, from the Cache store: lookups such as First(&user, id),
+	// Find(&users, ids) or IDIn(ids).Find(&users) read the cached entities and query the
+	// database for the missing ones only, caching them in turn.
+	SecondLevelCache bool
+
+	// CoalesceQueries shares the result of a Find, First or Count among the identical reads
+	// (same SQL and arguments) running at the same time outside transactions, so that only
+	// one of them reads the database. Coalesced reads share the error of the first one too,
+	// including its context cancellation. Result cache misses are always coalesced.
+	CoalesceQueries bool
+
+	// SchemaPerTenant runs every statement in the Postgres schema (through search_path) or
+	// the MySQL database of the tenant of its context, set with ContextWithTenant or
+	// ForTenant, and in the default one of the DSN without tenant. Connections are switched
+	// only when they served another tenant last, and transactions keep the schema they began
+	// in. It rules out PrepareStmt.
+	SchemaPerTenant bool
+
+	// TenantSchema returns the schema or database of a tenant, the tenant ID by default.
+	TenantSchema func(tenantID string) string
+
+	// TenantColumn scopes the statements of the models having this column (such as
+	// tenant_id) to the tenant of their context, for tenants sharing tables: reads, updates
+	// and deletes are filtered by the column and creates set it. Statements of these models
+	// fail with ErrTenantRequired without tenant, unless run by a repository returned by
+	// WithoutTenantScope. Raw SQL and statements by table name only are not scoped.
+	TenantColumn string
+
+	// TenantConnections gives the tenants having a dedicated database their own connection
+	// pool, opened on first use with the pool settings of Profile, while the other tenants
+	// share the default pool. Dedicated databases use the driver of the DatabaseContext. It
+	// rules out PrepareStmt.
+	TenantConnections TenantConnectionResolver
+
+	// TenantSetting hands the tenant of the context to Postgres row-level security policies:
+	// every transaction, whether started by WithTransaction, Begin or gorm around a write,
+	// sets this setting (such as app.current_tenant) locally to the tenant ID, for policies
+	// such as USING (tenant_id = current_setting('app.current_tenant')). Statements outside
+	// transactions run without it.
+	TenantSetting string
+
+	// TenantRole optionally returns the role transactions of a tenant switch to with SET
+	// LOCAL ROLE, along with TenantSetting, none when empty.
+	TenantRole func(tenantID string) string
+
+	// TenantCatalog resolves the tenants from the gormext_tenants catalog, see TenantCatalog:
+	// their schema with SchemaPerTenant, their dedicated database when they have a DSN
+	// (instead of TenantConnections), and the tenants of MigrateAllTenants and
+	// WithSeedTenants. Statements of tenants missing from the catalog then fail with
+	// ErrTenantNotFound.
+	TenantCatalog bool
+
+	// TenantCatalogTTL is the time catalog lookups are cached, DefaultTenantCatalogTTL by
+	// default.
+	TenantCatalogTTL time.Duration
+
+	// IDGenerator is the name of the generator of the fields tagged `gormext:"id"`,
+	// DefaultIDGenerator by default, see RegisterIDGenerator. Switching it changes the
+	// identifiers of every such model at once.
+	IDGenerator string
+
+	// OptimisticLocking makes the updates of records with a Version integer field (or a field
+	// tagged `gormext:"version"`) conditional on the version they were read at, incrementing
+	// it. Updates of records modified or deleted since fail with ErrOptimisticLock. Updates of
+	// several records are not versioned.
+	OptimisticLocking bool
+
+	// StampActor sets the CreatedBy field of created records, the UpdatedBy field of created
+	// and updated ones and the DeletedBy field of soft-deleted ones (or the fields tagged
+	// `gormext:"createdBy"`, `gormext:"updatedBy"` and `gormext:"deletedBy"`) to the ID of the
+	// context Actor, see ContextWithActor. Statements without actor leave them alone, and
+	// explicitly set CreatedBy values are kept.
+	StampActor bool
+
+	// UTCTime sets the NowFunc of gorm, filling CreatedAt and UpdatedAt, to return UTC times
+	// truncated to TimePrecision, and converts the time fields of queried records to UTC, so
+	// that times do not carry the time zone of the driver or the host.
+	UTCTime bool
+
+	// TimePrecision is the precision of the times of UTCTime, DefaultTimePrecision by default.
+	TimePrecision time.Duration
+
+	// MaskFields redacts the string fields tagged `gormext:"mask:<kind>"` of queried records,
+	// unless the context Actor has the UnmaskRole. Kinds are email (j***@example.com), name
+	// (J***), last4, phone and card (******1234), and full (****, also for `gormext:"mask"`).
+	// Unmasked reads bypass the result cache and coalescing, so that masked readers never see
+	// their results. Records read masked must not be saved back.
+	MaskFields bool
+
+	// UnmaskRole is the role of the actors reading unmasked values, DefaultUnmaskRole by
+	// default.
+	UnmaskRole string
 }
 
 // Gorm encapsulates the database connection and additional functionalities.
 type Gorm struct {
-	connection  *gorm.DB
-	sqlQueries  *sync.Map
-	databaseCtx DatabaseContext
-	repository  Repository
-	seedQueries []string
+	connection       *gorm.DB
+	sqlQueries       *sync.Map
+	querySources     *sync.Map
+	variants         *sync.Map
+	templates        *sync.Map
+	constraints      *sync.Map
+	statuses         *statusConditions
+	ids              *idGenerators
+	tenants          *sync.Map
+	tenantSchema     func(tenantID string) string
+	tenantCatalog    bool
+	catalog          *TenantCatalog
+	databaseCtx      DatabaseContext
+	repository       Repository
+	seeds            []seedUnit
+	migrations       []*migration
+	migrationHooks   migrationHooks
+	runHooks         []func(RunEvent)
+	slowQueryHooks   []func(SlowQuery)
+	queryStats       *queryStats
+	maintenance      maintenanceMode
+	shadow           *shadowWriter
+	registry         *Registry
+	keys             KeyProvider
+	golangMigrate    bool
+	allowDestructive bool
 }
 
 // NewGorm initializes a new instance of Gorm.
+// When repository is nil, the default NewRepository implementation is used.
 func NewGorm(
 	databaseCtx DatabaseContext,
 	repository Repository,
@@ -63,51 +298,216 @@ func NewGorm(
 		return nil, fmt.Errorf("failed to get dialector: %w", err)
 	}
 
-	gormConfig := &gorm.Config{}
+	cfg := Config{}
 	if len(config) > 0 {
-		gormConfig = &config[0].Config
+		cfg = config[0]
+	}
+
+	if err := ValidateConfig(databaseCtx, cfg); err != nil {
+		return nil, err
 	}
 
-	conn, err := gorm.Open(dialector(), gormConfig)
+	if cfg.Logger == nil {
+		cfg.Logger = databaseCtx.logger
+	}
+
+	if cfg.UTCTime {
+		cfg.NowFunc = utcNow(cfg.NowFunc, cfg.TimePrecision)
+	}
+
+	open := dialector()
+	if cfg.Profile != nil {
+		cfg.Profile.configure(&cfg.Config)
+		if profileDialector := cfg.Profile.dialector(databaseCtx); profileDialector != nil {
+			open = profileDialector
+		}
+	}
+	if cfg.Dialector != nil {
+		open = cfg.Dialector
+	}
+
+	conn, err := gorm.Open(open, &cfg.Config)
 	if err != nil {
 		return nil, fmt.Errorf("failed to open database connection: %w", err)
 	}
 
+	if cfg.Profile != nil {
+		if err := cfg.Profile.apply(conn); err != nil {
+			return nil, err
+		}
+	}
+
+	if repository == nil {
+		repository = NewRepository
+	}
+
 	g := &Gorm{
-		connection:  conn,
-		databaseCtx: databaseCtx,
-		repository:  repository,
-		seedQueries: seedQueryPaths,
-		sqlQueries:  &sync.Map{},
+		connection:       conn,
+		databaseCtx:      databaseCtx,
+		repository:       repository,
+		sqlQueries:       &sync.Map{},
+		querySources:     &sync.Map{},
+		variants:         &sync.Map{},
+		templates:        &sync.Map{},
+		constraints:      &sync.Map{},
+		statuses:         &statusConditions{},
+		ids:              newIDGenerators(cfg.IDGenerator),
+		tenants:          &sync.Map{},
+		tenantCatalog:    cfg.TenantCatalog,
+		keys:             cfg.KeyProvider,
+		golangMigrate:    cfg.GolangMigrate,
+		allowDestructive: cfg.AllowDestructive,
+	}
+	g.catalog = newTenantCatalog(g, cfg.TenantCatalogTTL)
+
+	if cfg.SchemaPerTenant {
+		if err := g.useTenantPool(cfg.TenantSchema); err != nil {
+			return nil, err
+		}
+	}
+
+	if cfg.TenantConnections != nil {
+		g.useTenantRouter(cfg.TenantConnections, cfg.Profile)
+	} else if cfg.TenantCatalog {
+		g.useTenantRouter(g.catalog, cfg.Profile)
+	}
+
+	for _, path := range seedQueryPaths {
+		g.RegisterSeedFile(path)
+	}
+
+	if err := errors.Join(g.registerMaintenanceCallbacks(), g.registerConstraintCallbacks(), g.registerQueryErrorCallbacks(), g.registerSlowQueryCallbacks()); err != nil {
+		return nil, fmt.Errorf("failed to register callbacks: %w", err)
+	}
+
+	if cfg.CollectQueryStats {
+		if err := g.registerQueryStatsCallbacks(); err != nil {
+			return nil, fmt.Errorf("failed to register callbacks: %w", err)
+		}
+	}
+
+	if cfg.SQLComments {
+		if err := g.registerCommentCallbacks(cfg.Application); err != nil {
+			return nil, fmt.Errorf("failed to register callbacks: %w", err)
+		}
+	}
+
+	if cfg.CacheKey == nil {
+		cfg.CacheKey = DefaultCacheKey
+	}
+
+	if err := conn.Use(g.statuses); err != nil {
+		return nil, fmt.Errorf("failed to register status conditions: %w", err)
+	}
+
+	if err := conn.Use(g.ids); err != nil {
+		return nil, fmt.Errorf("failed to register ID generators: %w", err)
+	}
+
+	if cfg.Cache != nil {
+		if err := conn.Use(&resultCache{store: cfg.Cache, key: cfg.CacheKey, entityRead: cfg.SecondLevelCache, negativeTTL: cfg.NegativeCacheTTL}); err != nil {
+			return nil, fmt.Errorf("failed to register result cache: %w", err)
+		}
+	}
+
+	if cfg.TenantColumn != "" {
+		if err := conn.Use(&tenantScope{column: cfg.TenantColumn}); err != nil {
+			return nil, fmt.Errorf("failed to register tenant scope: %w", err)
+		}
+	}
+
+	if cfg.TenantSetting != "" {
+		if err := conn.Use(&rowLevelSecurity{setting: cfg.TenantSetting, role: cfg.TenantRole}); err != nil {
+			return nil, fmt.Errorf("failed to register row-level security: %w", err)
+		}
+	}
+
+	if cfg.MaskFields {
+		role := cfg.UnmaskRole
+		if role == "" {
+			role = DefaultUnmaskRole
+		}
+		if err := conn.Use(&masking{role: role}); err != nil {
+			return nil, fmt.Errorf("failed to register field masking: %w", err)
+		}
+	}
+
+	if cfg.UTCTime {
+		if err := conn.Use(&utcTime{}); err != nil {
+			return nil, fmt.Errorf("failed to register UTC time normalization: %w", err)
+		}
+	}
+
+	if cfg.StampActor {
+		if err := conn.Use(&actorStamp{}); err != nil {
+			return nil, fmt.Errorf("failed to register actor stamping: %w", err)
+		}
+	}
+
+	if cfg.OptimisticLocking {
+		if err := conn.Use(&optimisticLock{}); err != nil {
+			return nil, fmt.Errorf("failed to register optimistic locking: %w", err)
+		}
+	}
+
+	if cfg.CoalesceQueries {
+		if err := conn.Use(&queryGroup{key: cfg.CacheKey}); err != nil {
+			return nil, fmt.Errorf("failed to register query coalescing: %w", err)
+		}
 	}
 
 	if err := g.cacheSQLQueries(sqlQueryPaths); err != nil {
 		return nil, fmt.Errorf("failed to cache SQL queries: %w", err)
 	}
 
-	return g, nil
-}
+	if cfg.QueriesFS != nil {
+		if err := g.cacheSQLQueriesFS(cfg.QueriesFS, cfg.QueriesRoot); err != nil {
+			return nil, fmt.Errorf("failed to cache SQL queries: %w", err)
+		}
+	}
 
-// Seed executes seed queries to initialize the database.
-func (g *Gorm) Seed() error {
-	for _, queryPath := range g.seedQueries {
-		content, err := os.ReadFile(queryPath)
-		if err != nil {
-			return fmt.Errorf("failed to read seed file '%s': %w", queryPath, err)
+	for _, dir := range cfg.QueryDirs {
+		if err := g.cacheSQLQueriesDir(dir); err != nil {
+			return nil, fmt.Errorf("failed to cache SQL queries: %w", err)
+		}
+	}
+
+	if err := g.LoadQueries(context.Background(), cfg.QuerySources...); err != nil {
+		return nil, err
+	}
+
+	if cfg.MigrationsDir != "" {
+		if err := g.loadMigrations(os.DirFS(cfg.MigrationsDir), ".", cfg.MigrationsDir); err != nil {
+			return nil, err
 		}
+	}
 
-		if err := g.connection.Exec(string(content)).Error; err != nil {
-			return fmt.Errorf("failed to execute seed query from file '%s': %w", queryPath, err)
+	if cfg.MigrationsFS != nil {
+		root := cfg.MigrationsRoot
+		if root == "" {
+			root = "."
+		}
+		if err := g.loadMigrations(cfg.MigrationsFS, root, root); err != nil {
+			return nil, err
 		}
+	}
 
-		time.Sleep(10 * time.Millisecond) // Throttle to avoid overwhelming the database.
+	if cfg.ValidateQueries {
+		if err := g.ValidateQueries(context.Background()); err != nil {
+			return nil, err
+		}
 	}
-	return nil
+
+	return g, nil
 }
 
-// GetQuery retrieves a cached SQL query by name.
+// GetQuery retrieves a cached SQL query by name. A driver-specific variant cached as
+// "<name>.<driver alias>" (e.g. report.postgres from report.postgres.sql) takes precedence.
 func (g *Gorm) GetQuery(name string) (string, error) {
-	cachedQuery, found := g.sqlQueries.Load(name)
+	cachedQuery, found := g.sqlQueries.Load(name + "." + g.databaseCtx.GetDriverAlias())
+	if !found {
+		cachedQuery, found = g.sqlQueries.Load(name)
+	}
 	if !found {
 		return "", fmt.Errorf("sql query '%s' not found", name)
 	}
@@ -120,25 +520,90 @@ func (g *Gorm) GetQuery(name string) (string, error) {
 	return queryStr, nil
 }
 
+// ExecQuery executes the cached query name with args.
+func (g *Gorm) ExecQuery(ctx context.Context, name string, args ...any) error {
+	query, err := g.GetQuery(name)
+	if err != nil {
+		return err
+	}
+
+	if err := g.queryConn(ctx, name).Exec(query, args...).Error; err != nil {
+		return fmt.Errorf("failed to execute sql query '%s': %w", name, err)
+	}
+	return nil
+}
+
+// SelectQuery runs the cached query name with args and scans its rows into dest.
+func (g *Gorm) SelectQuery(ctx context.Context, name string, dest any, args ...any) error {
+	query, err := g.GetQuery(name)
+	if err != nil {
+		return err
+	}
+
+	if err := g.queryConn(ctx, name).Raw(query, args...).Scan(dest).Error; err != nil {
+		return fmt.Errorf("failed to run sql query '%s': %w", name, err)
+	}
+	return nil
+}
+
 // GetDB returns a repository instance for database operations.
 func (g *Gorm) GetDB() IRepository {
 	return g.repository(g.connection)
 }
 
-// Migrate runs auto-migration for the given models.
+// Use registers a gorm plugin on the connection, such as the tracing plugin of gormextotel.
+func (g *Gorm) Use(plugin gorm.Plugin) error {
+	if err := g.connection.Use(plugin); err != nil {
+		return fmt.Errorf("failed to register plugin '%s': %w", plugin.Name(), err)
+	}
+	return nil
+}
+
+// Close closes the underlying database connection pool, and the pools of the tenants with a
+// dedicated database.
+func (g *Gorm) Close() error {
+	sqlDB, err := g.connection.DB()
+	if err != nil {
+		return fmt.Errorf("failed to get database connection: %w", err)
+	}
+	if router, ok := g.connection.ConnPool.(*tenantRouter); ok {
+		return errors.Join(router.close(), sqlDB.Close())
+	}
+	return sqlDB.Close()
+}
+
+// Migrate runs auto-migration for the given models. It is allowed during maintenance mode, and
+// holds the migration lock so that instances starting together do not race. Column changes
+// that may lose data fail with ErrDestructiveMigration unless AllowDestructive is set.
 func (g *Gorm) Migrate(models ...any) error {
-	return g.connection.AutoMigrate(models...)
+	ctx := WithMaintenanceBypass(context.Background())
+	return g.withMigrationLock(ctx, func() error {
+		return g.migrate(ctx, models)
+	})
+}
+
+// migrate runs auto-migration for models with ctx, checking for destructive changes.
+func (g *Gorm) migrate(ctx context.Context, models []any) error {
+	conn := g.connection.WithContext(ctx)
+	if !g.allowDestructive {
+		if err := g.checkDestructive(conn, models); err != nil {
+			return err
+		}
+	}
+	return conn.AutoMigrate(models...)
 }
 
 // cacheSQLQueries reads and stores SQL queries based on the provided file paths.
 func (g *Gorm) cacheSQLQueries(queriesPaths map[string]string) error {
 	for name, path := range queriesPaths {
-		content, err := os.ReadFile(path)
+		content, err := g.readFile(path)
 		if err != nil {
 			return fmt.Errorf("failed to read SQL file '%s': %w", path, err)
 		}
 
-		g.sqlQueries.Store(name, string(content))
+		if err := g.storeQuery(name, string(content), path); err != nil {
+			return err
+		}
 	}
 	return nil
 }
@@ -3,12 +3,16 @@ package gormext
 
 import (
 	"context"
+	"database/sql"
 	"fmt"
 	"os"
 	"testing"
+	"testing/fstest"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
 )
 
 // =======================
@@ -19,25 +23,57 @@ type DummyRepo struct{}
 
 func (d *DummyRepo) WithTransaction(fn func(tx IRepository) error) error { return fn(d) }
 func (d *DummyRepo) WithContext(ctx context.Context) IRepository         { return d }
+func (d *DummyRepo) Cached(ttl time.Duration) IRepository                { return d }
+func (d *DummyRepo) CachedStale(ttl, stale time.Duration) IRepository    { return d }
+func (d *DummyRepo) NoCache() IRepository                                { return d }
+func (d *DummyRepo) RefreshCache() IRepository                           { return d }
+func (d *DummyRepo) WithoutTenantScope() IRepository                     { return d }
+func (d *DummyRepo) Begin() (ITransaction, error)                        { return nil, nil }
+func (d *DummyRepo) AfterCommit(fn func())                               { fn() }
+func (d *DummyRepo) AfterRollback(fn func())                             {}
 func (d *DummyRepo) FirstByID(id any, dest any) error                    { return nil }
 func (d *DummyRepo) First(dest any, conds ...any) error                  { return nil }
 func (d *DummyRepo) Find(dest any) error                                 { return nil }
-func (d *DummyRepo) Create(entity any) error                             { return nil }
-func (d *DummyRepo) Update(entity any) error                             { return nil }
-func (d *DummyRepo) Delete(entity any) error                             { return nil }
-func (d *DummyRepo) Exec(sql string, value ...any) error                 { return nil }
-func (d *DummyRepo) IDEqual(id any) IRepository                          { return d }
-func (d *DummyRepo) IDIn(ids []any) IRepository                          { return d }
-func (d *DummyRepo) Where(query any, args ...any) IRepository            { return d }
-func (d *DummyRepo) Joins(query string, args ...any) IRepository         { return d }
-func (d *DummyRepo) Preload(query string, args ...any) IRepository       { return d }
-func (d *DummyRepo) Order(value any) IRepository                         { return d }
-func (d *DummyRepo) IsActive() IRepository                               { return d }
-func (d *DummyRepo) Table(name string, args ...any) IRepository          { return d }
+func (d *DummyRepo) FindInBatches(dest any, batchSize int, fn func(batch IRepository, n int) error) error {
+	return fn(d, 1)
+}
+func (d *DummyRepo) WithTransactionOpts(opts TxOptions, fn func(tx IRepository) error) error {
+	return fn(d)
+}
+func (d *DummyRepo) Create(entity any) error                                 { return nil }
+func (d *DummyRepo) Update(entity any) error                                 { return nil }
+func (d *DummyRepo) Delete(entity any) error                                 { return nil }
+func (d *DummyRepo) Exec(sql string, value ...any) error                     { return nil }
+func (d *DummyRepo) IDEqual(id any) IRepository                              { return d }
+func (d *DummyRepo) IDIn(ids []any) IRepository                              { return d }
+func (d *DummyRepo) Where(query any, args ...any) IRepository                { return d }
+func (d *DummyRepo) Not(query any, args ...any) IRepository                  { return d }
+func (d *DummyRepo) Or(query any, args ...any) IRepository                   { return d }
+func (d *DummyRepo) Joins(query string, args ...any) IRepository             { return d }
+func (d *DummyRepo) Preload(query string, args ...any) IRepository           { return d }
+func (d *DummyRepo) Order(value any) IRepository                             { return d }
+func (d *DummyRepo) LockForUpdate() IRepository                              { return d }
+func (d *DummyRepo) LockShare() IRepository                                  { return d }
+func (d *DummyRepo) SkipLocked() IRepository                                 { return d }
+func (d *DummyRepo) IsActive() IRepository                                   { return d }
+func (d *DummyRepo) WithStatus(values ...string) IRepository                 { return d }
+func (d *DummyRepo) OnlyTrashed() IRepository                                { return d }
+func (d *DummyRepo) WhereJSONEquals(path string, value any) IRepository      { return d }
+func (d *DummyRepo) WhereJSONContains(path string, value any) IRepository    { return d }
+func (d *DummyRepo) Restore(entity any) error                                { return nil }
+func (d *DummyRepo) RestoreByID(model any, id any) error                     { return nil }
+func (d *DummyRepo) Table(name string, args ...any) IRepository              { return d }
+func (d *DummyRepo) Scopes(fns ...func(IRepository) IRepository) IRepository { return d }
 func (d *DummyRepo) Count(count *int64) error {
 	*count = 0
 	return nil
 }
+func (d *DummyRepo) Rows() (*sql.Rows, error) { return nil, nil }
+
+func (d *DummyRepo) CountBy(column string) (map[string]int64, error) { return nil, nil }
+func (d *DummyRepo) SumInt64(column string) (int64, error)           { return 0, nil }
+func (d *DummyRepo) MaxTime(column string) (time.Time, error)        { return time.Time{}, nil }
+func (d *DummyRepo) PluckStrings(column string) ([]string, error)    { return nil, nil }
 
 func dummyRepository(db *gorm.DB) IRepository {
 	return &DummyRepo{}
@@ -96,6 +132,24 @@ func TestNewGormDialectorFailure(t *testing.T) {
 	assert.Contains(t, err.Error(), "failed to get dialector")
 }
 
+// TestNewGormContextLogger verifies that the database context logger is used unless
+// Config.Logger is set.
+func TestNewGormContextLogger(t *testing.T) {
+	dbCtx := newTestDatabaseContext()
+	contextLogger := logger.Default.LogMode(logger.Silent)
+	dbCtx.SetLogger(contextLogger)
+	assert.Equal(t, contextLogger, dbCtx.GetLogger())
+
+	g, err := NewGorm(dbCtx, nil, nil, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, contextLogger, g.connection.Config.Logger)
+
+	configLogger := logger.Default.LogMode(logger.Error)
+	g, err = NewGorm(dbCtx, nil, nil, nil, Config{Config: gorm.Config{Logger: configLogger}})
+	assert.NoError(t, err)
+	assert.Equal(t, configLogger, g.connection.Config.Logger, "Config.Logger should take precedence")
+}
+
 // TestCacheSQLQueriesFailure verifies failure when reading a non-existent SQL file.
 func TestCacheSQLQueriesFailure(t *testing.T) {
 	sqlQueryPaths := map[string]string{"nonexistent": "/nonexistent/path.sql"}
@@ -140,3 +194,51 @@ func TestMigrateSuccess(t *testing.T) {
 	assert.NoError(t, err, "Failed to query sqlite_master")
 	assert.NotEmpty(t, tableName, "Table for DummyModel was not created")
 }
+
+// TestNewGormQueriesFS verifies queries are cached from an embedded filesystem.
+func TestNewGormQueriesFS(t *testing.T) {
+	queries := fstest.MapFS{
+		"sql/find_user.sql": {Data: []byte("SELECT * FROM users WHERE id = ?")},
+		"sql/README.md":     {Data: []byte("docs")},
+		"sql/nested/x.sql":  {Data: []byte("SELECT 2")},
+		"other/ignored.sql": {Data: []byte("SELECT 3")},
+	}
+
+	g, err := NewGorm(newTestDatabaseContext(), dummyRepository, []string{}, map[string]string{}, Config{
+		QueriesFS:   queries,
+		QueriesRoot: "sql",
+	})
+	assert.NoError(t, err, "Unexpected error from NewGorm")
+
+	query, err := g.GetQuery("find_user")
+	assert.NoError(t, err, "Failed to retrieve embedded query")
+	assert.Equal(t, "SELECT * FROM users WHERE id = ?", query)
+
+	_, err = g.GetQuery("README")
+	assert.Error(t, err, "Non-SQL files should not be cached")
+
+	_, err = NewGorm(newTestDatabaseContext(), dummyRepository, []string{}, map[string]string{}, Config{
+		QueriesFS:   queries,
+		QueriesRoot: "missing",
+	})
+	assert.ErrorContains(t, err, "failed to cache SQL queries")
+}
+
+// TestExecSelectQuery verifies cached queries run by name and errors name the query.
+func TestExecSelectQuery(t *testing.T) {
+	g, _ := newTestRepository(t)
+	g.sqlQueries.Store("insert_user", "INSERT INTO repo_users (name, age) VALUES (?, ?)")
+	g.sqlQueries.Store("user_names", "SELECT name FROM repo_users WHERE age > ? ORDER BY name")
+	g.sqlQueries.Store("broken", "SELECT FROM")
+
+	ctx := context.Background()
+	assert.NoError(t, g.ExecQuery(ctx, "insert_user", "ann", 30))
+	assert.NoError(t, g.ExecQuery(ctx, "insert_user", "bob", 10))
+
+	var names []string
+	assert.NoError(t, g.SelectQuery(ctx, "user_names", &names, 20))
+	assert.Equal(t, []string{"ann"}, names)
+
+	assert.ErrorContains(t, g.SelectQuery(ctx, "broken", &names), "sql query 'broken'")
+	assert.ErrorContains(t, g.ExecQuery(ctx, "missing"), "sql query 'missing' not found")
+}
@@ -0,0 +1,55 @@
+// Package gormextfx provides the gormext connection to uber-fx applications:
+//
+//	fx.New(fx.Supply(databaseCtx), gormextfx.Module)
+package gormextfx
+
+import (
+	"context"
+
+	"github.com/raykavin/gormext"
+	"go.uber.org/fx"
+)
+
+// Module provides *gormext.Gorm, its gormext.IRepository and its health check. It needs a
+// gormext.DatabaseContext; a gormext.Config and a gormext.Repository are used when supplied.
+var Module = fx.Module("gormext",
+	fx.Provide(New, NewRepository, NewHealthCheck),
+)
+
+// Params are the dependencies of New.
+type Params struct {
+	fx.In
+
+	DatabaseContext gormext.DatabaseContext
+	Config          gormext.Config     `optional:"true"`
+	Repository      gormext.Repository `optional:"true"`
+}
+
+// New connects to the database and closes the connection when the application stops.
+// The connection is checked when the application starts.
+func New(lc fx.Lifecycle, p Params) (*gormext.Gorm, error) {
+	g, err := gormext.NewGorm(p.DatabaseContext, p.Repository, nil, nil, p.Config)
+	if err != nil {
+		return nil, err
+	}
+
+	lc.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			return g.HealthCheck(ctx).Err
+		},
+		OnStop: func(context.Context) error {
+			return g.Close()
+		},
+	})
+	return g, nil
+}
+
+// NewRepository returns the repository of g.
+func NewRepository(g *gormext.Gorm) gormext.IRepository {
+	return g.GetDB()
+}
+
+// NewHealthCheck returns the health check of g, for readiness probes.
+func NewHealthCheck(g *gormext.Gorm) func(ctx context.Context) gormext.Health {
+	return g.HealthCheck
+}
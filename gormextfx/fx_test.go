@@ -0,0 +1,30 @@
+package gormextfx
+
+import (
+	"context"
+	"testing"
+
+	"github.com/raykavin/gormext"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/fx"
+	"go.uber.org/fx/fxtest"
+)
+
+// TestModule verifies that the module provides a working connection closed on stop.
+func TestModule(t *testing.T) {
+	dbCtx, err := gormext.NewDatabaseContext(":memory:", "sqlite", "silent")
+	assert.NoError(t, err)
+
+	var (
+		repo   gormext.IRepository
+		health func(ctx context.Context) gormext.Health
+	)
+	app := fxtest.New(t, fx.Supply(*dbCtx), Module, fx.Populate(&repo, &health))
+	app.RequireStart()
+
+	assert.NoError(t, repo.Exec("SELECT 1"))
+	assert.True(t, health(context.Background()).Healthy)
+
+	app.RequireStop()
+	assert.False(t, health(context.Background()).Healthy, "Stopping the application should close the connection")
+}
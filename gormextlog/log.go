@@ -0,0 +1,111 @@
+// Package gormextlog adapts structured loggers to gorm's logger.Interface, so that SQL logs
+// flow into the application logging pipeline:
+//
+//	dbCtx.SetLogger(gormextlog.NewZap(zapLogger, logger.Config{SlowThreshold: time.Second}))
+package gormextlog
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+	"gorm.io/gorm/utils"
+)
+
+type (
+	// field is a key and value attached to a log entry.
+	field struct {
+		key   string
+		value any
+	}
+
+	// writer writes a log entry to the adapted logger.
+	writer func(level logger.LogLevel, msg string, fields []field)
+
+	// adapter implements logger.Interface on top of a writer, honoring the level, slow
+	// threshold, record not found and parameterized queries settings of logger.Config.
+	adapter struct {
+		config logger.Config
+		write  writer
+	}
+)
+
+// newAdapter returns an adapter writing with write. The level defaults to logger.Warn, as in
+// gorm's default logger.
+func newAdapter(config logger.Config, write writer) *adapter {
+	if config.LogLevel == 0 {
+		config.LogLevel = logger.Warn
+	}
+	return &adapter{config: config, write: write}
+}
+
+// LogMode returns a copy of the adapter logging at level.
+func (a *adapter) LogMode(level logger.LogLevel) logger.Interface {
+	clone := *a
+	clone.config.LogLevel = level
+	return &clone
+}
+
+// Info logs an informational message.
+func (a *adapter) Info(_ context.Context, msg string, data ...any) {
+	a.log(logger.Info, msg, data)
+}
+
+// Warn logs a warning message.
+func (a *adapter) Warn(_ context.Context, msg string, data ...any) {
+	a.log(logger.Warn, msg, data)
+}
+
+// Error logs an error message.
+func (a *adapter) Error(_ context.Context, msg string, data ...any) {
+	a.log(logger.Error, msg, data)
+}
+
+// Trace logs a statement: as an error when it failed, as a warning when it exceeded the slow
+// threshold, and as information otherwise.
+func (a *adapter) Trace(_ context.Context, begin time.Time, fc func() (sql string, rowsAffected int64), err error) {
+	if a.config.LogLevel <= logger.Silent {
+		return
+	}
+
+	elapsed := time.Since(begin)
+	switch {
+	case err != nil && a.config.LogLevel >= logger.Error &&
+		(!a.config.IgnoreRecordNotFoundError || !errors.Is(err, gorm.ErrRecordNotFound)):
+		a.write(logger.Error, "sql failed", append(statementFields(fc, elapsed), field{"error", err}))
+	case a.config.SlowThreshold != 0 && elapsed > a.config.SlowThreshold && a.config.LogLevel >= logger.Warn:
+		a.write(logger.Warn, "slow sql", append(statementFields(fc, elapsed), field{"threshold", a.config.SlowThreshold}))
+	case a.config.LogLevel >= logger.Info:
+		a.write(logger.Info, "sql", statementFields(fc, elapsed))
+	}
+}
+
+// ParamsFilter leaves the parameters out of the logged statements when the adapter is
+// configured with ParameterizedQueries.
+func (a *adapter) ParamsFilter(_ context.Context, sql string, params ...any) (string, []any) {
+	if a.config.ParameterizedQueries {
+		return sql, nil
+	}
+	return sql, params
+}
+
+// log writes a formatted message when the adapter level allows it.
+func (a *adapter) log(level logger.LogLevel, msg string, data []any) {
+	if a.config.LogLevel >= level {
+		a.write(level, fmt.Sprintf(msg, data...), []field{{"file", utils.FileWithLineNum()}})
+	}
+}
+
+// statementFields returns the fields describing a statement: its SQL, duration, affected rows,
+// when known, and the caller location.
+func statementFields(fc func() (string, int64), elapsed time.Duration) []field {
+	sql, rows := fc()
+	fields := []field{{"sql", sql}, {"elapsed", elapsed}}
+	if rows != -1 {
+		fields = append(fields, field{"rows", rows})
+	}
+	return append(fields, field{"file", utils.FileWithLineNum()})
+}
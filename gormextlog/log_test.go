@@ -0,0 +1,78 @@
+package gormextlog
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/raykavin/gormext"
+	"github.com/rs/zerolog"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// TestAdapters verifies that statements and their failures are written to zap, zerolog and
+// logrus through the database context logger.
+func TestAdapters(t *testing.T) {
+	adapters := map[string]func(*bytes.Buffer) logger.Interface{
+		"zap": func(buf *bytes.Buffer) logger.Interface {
+			core := zapcore.NewCore(zapcore.NewJSONEncoder(zap.NewProductionEncoderConfig()), zapcore.AddSync(buf), zap.DebugLevel)
+			return NewZap(zap.New(core), logger.Config{LogLevel: logger.Info})
+		},
+		"zerolog": func(buf *bytes.Buffer) logger.Interface {
+			return NewZerolog(zerolog.New(buf), logger.Config{LogLevel: logger.Info})
+		},
+		"logrus": func(buf *bytes.Buffer) logger.Interface {
+			l := logrus.New()
+			l.SetOutput(buf)
+			l.SetFormatter(&logrus.JSONFormatter{})
+			return NewLogrus(l, logger.Config{LogLevel: logger.Info})
+		},
+	}
+
+	for name, adapter := range adapters {
+		t.Run(name, func(t *testing.T) {
+			var buf bytes.Buffer
+			dbCtx, err := gormext.NewDatabaseContext(":memory:", "sqlite", "silent")
+			assert.NoError(t, err)
+			dbCtx.SetLogger(adapter(&buf))
+			g, err := gormext.NewGorm(*dbCtx, nil, nil, nil)
+			assert.NoError(t, err)
+
+			assert.NoError(t, g.GetDB().Exec("SELECT 1"))
+			assert.Error(t, g.GetDB().Exec("SELECT * FROM missing"))
+
+			out := buf.String()
+			assert.Contains(t, out, `"sql":"SELECT 1"`)
+			assert.Contains(t, out, "sql failed")
+			assert.Contains(t, out, "no such table: missing")
+		})
+	}
+}
+
+// TestAdapterLevels verifies that the adapter honors the level, slow threshold and record not
+// found settings.
+func TestAdapterLevels(t *testing.T) {
+	var entries []string
+	a := newAdapter(logger.Config{SlowThreshold: time.Millisecond, IgnoreRecordNotFoundError: true},
+		func(_ logger.LogLevel, msg string, _ []field) { entries = append(entries, msg) })
+	statement := func() (string, int64) { return "SELECT 1", 1 }
+	ctx := context.Background()
+
+	a.Trace(ctx, time.Now(), statement, nil)
+	a.Trace(ctx, time.Now().Add(-time.Second), statement, nil)
+	a.Trace(ctx, time.Now(), statement, gorm.ErrRecordNotFound)
+	a.Info(ctx, "connected to %s", "sqlite")
+	a.LogMode(logger.Info).Info(ctx, "connected to %s", "sqlite")
+	a.LogMode(logger.Silent).Error(ctx, "failed")
+	assert.Equal(t, []string{"slow sql", "connected to sqlite"}, entries)
+
+	sql, params := newAdapter(logger.Config{ParameterizedQueries: true}, nil).ParamsFilter(ctx, "SELECT ?", 1)
+	assert.Equal(t, "SELECT ?", sql)
+	assert.Nil(t, params)
+}
@@ -0,0 +1,28 @@
+package gormextlog
+
+import (
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm/logger"
+)
+
+// NewLogrus returns a gorm logger writing to l, a *logrus.Logger or *logrus.Entry. The level,
+// slow threshold, record not found and parameterized queries settings of config apply as in
+// gorm's default logger.
+func NewLogrus(l logrus.FieldLogger, config logger.Config) logger.Interface {
+	return newAdapter(config, func(level logger.LogLevel, msg string, fields []field) {
+		logrusFields := make(logrus.Fields, len(fields))
+		for _, f := range fields {
+			logrusFields[f.key] = f.value
+		}
+
+		entry := l.WithFields(logrusFields)
+		switch level {
+		case logger.Error:
+			entry.Error(msg)
+		case logger.Warn:
+			entry.Warn(msg)
+		default:
+			entry.Info(msg)
+		}
+	})
+}
@@ -0,0 +1,26 @@
+package gormextlog
+
+import (
+	"go.uber.org/zap"
+	"gorm.io/gorm/logger"
+)
+
+// NewZap returns a gorm logger writing to l. The level, slow threshold, record not found and
+// parameterized queries settings of config apply as in gorm's default logger.
+func NewZap(l *zap.Logger, config logger.Config) logger.Interface {
+	return newAdapter(config, func(level logger.LogLevel, msg string, fields []field) {
+		zapFields := make([]zap.Field, 0, len(fields))
+		for _, f := range fields {
+			zapFields = append(zapFields, zap.Any(f.key, f.value))
+		}
+
+		switch level {
+		case logger.Error:
+			l.Error(msg, zapFields...)
+		case logger.Warn:
+			l.Warn(msg, zapFields...)
+		default:
+			l.Info(msg, zapFields...)
+		}
+	})
+}
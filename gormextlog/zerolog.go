@@ -0,0 +1,28 @@
+package gormextlog
+
+import (
+	"github.com/rs/zerolog"
+	"gorm.io/gorm/logger"
+)
+
+// NewZerolog returns a gorm logger writing to l. The level, slow threshold, record not found
+// and parameterized queries settings of config apply as in gorm's default logger.
+func NewZerolog(l zerolog.Logger, config logger.Config) logger.Interface {
+	return newAdapter(config, func(level logger.LogLevel, msg string, fields []field) {
+		var event *zerolog.Event
+		switch level {
+		case logger.Error:
+			event = l.Error()
+		case logger.Warn:
+			event = l.Warn()
+		default:
+			event = l.Info()
+		}
+
+		keyValues := make([]any, 0, 2*len(fields))
+		for _, f := range fields {
+			keyValues = append(keyValues, f.key, f.value)
+		}
+		event.Fields(keyValues).Msg(msg)
+	})
+}
@@ -0,0 +1,146 @@
+// Package gormextotel traces gormext statements with OpenTelemetry:
+//
+//	g.Use(gormextotel.NewPlugin())
+package gormextotel
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/raykavin/gormext"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/gorm"
+)
+
+const (
+	// instrumentationName identifies the tracer of the plugin.
+	instrumentationName = "github.com/raykavin/gormext/gormextotel"
+
+	// spanKey is the statement instance setting holding the span of the running statement.
+	spanKey = "gormext:otel_span"
+)
+
+type (
+	// Plugin is a gorm plugin creating a client span for every statement: repository
+	// operations, cached queries and raw SQL. Spans are children of the span found in the
+	// statement context, and are tagged with the driver, operation, table, cached query name
+	// and affected rows.
+	Plugin struct {
+		tracer        trace.Tracer
+		withStatement bool
+	}
+
+	// Option configures a Plugin.
+	Option func(*Plugin)
+)
+
+// WithTracerProvider sets the provider of the plugin tracer, the global provider by default.
+func WithTracerProvider(provider trace.TracerProvider) Option {
+	return func(p *Plugin) {
+		p.tracer = provider.Tracer(instrumentationName)
+	}
+}
+
+// WithoutStatement leaves the SQL text out of spans, such as when it may hold sensitive
+// literals.
+func WithoutStatement() Option {
+	return func(p *Plugin) {
+		p.withStatement = false
+	}
+}
+
+// NewPlugin returns a tracing plugin, to register with (*gormext.Gorm).Use.
+func NewPlugin(opts ...Option) *Plugin {
+	p := &Plugin{withStatement: true}
+	for _, opt := range opts {
+		opt(p)
+	}
+	if p.tracer == nil {
+		p.tracer = otel.GetTracerProvider().Tracer(instrumentationName)
+	}
+	return p
+}
+
+// Name returns the plugin name.
+func (p *Plugin) Name() string {
+	return "gormext:otel"
+}
+
+// Initialize registers the callbacks starting and ending spans around every statement.
+func (p *Plugin) Initialize(db *gorm.DB) error {
+	callbacks := db.Callback()
+	register := func(operation string, before, after interface {
+		Register(name string, fn func(*gorm.DB)) error
+	}) error {
+		return errors.Join(
+			before.Register("gormext:otel_before_"+operation, p.start(operation)),
+			after.Register("gormext:otel_after_"+operation, p.end(operation)),
+		)
+	}
+
+	return errors.Join(
+		register("create", callbacks.Create().Before("gorm:create"), callbacks.Create().After("gorm:create")),
+		register("query", callbacks.Query().Before("gorm:query"), callbacks.Query().After("gorm:query")),
+		register("update", callbacks.Update().Before("gorm:update"), callbacks.Update().After("gorm:update")),
+		register("delete", callbacks.Delete().Before("gorm:delete"), callbacks.Delete().After("gorm:delete")),
+		register("row", callbacks.Row().Before("gorm:row"), callbacks.Row().After("gorm:row")),
+		register("raw", callbacks.Raw().Before("gorm:raw"), callbacks.Raw().After("gorm:raw")),
+	)
+}
+
+// start starts the span of a statement and makes it current in the statement context, so that
+// drivers and nested statements see it.
+func (p *Plugin) start(operation string) func(*gorm.DB) {
+	return func(db *gorm.DB) {
+		ctx, span := p.tracer.Start(db.Statement.Context, spanName(db, operation), trace.WithSpanKind(trace.SpanKindClient))
+		db.Statement.Context = ctx
+		db.InstanceSet(spanKey, span)
+	}
+}
+
+// end tags the span of a statement with its outcome and ends it.
+func (p *Plugin) end(operation string) func(*gorm.DB) {
+	return func(db *gorm.DB) {
+		value, ok := db.InstanceGet(spanKey)
+		if !ok {
+			return
+		}
+		span := value.(trace.Span)
+		defer span.End()
+
+		attributes := []attribute.KeyValue{
+			attribute.String("db.system", db.Dialector.Name()),
+			attribute.String("db.operation", operation),
+			attribute.Int64("db.rows_affected", db.Statement.RowsAffected),
+		}
+		if db.Statement.Table != "" {
+			attributes = append(attributes, attribute.String("db.sql.table", db.Statement.Table))
+		}
+		if name := gormext.QueryName(db); name != "" {
+			attributes = append(attributes, attribute.String("gormext.query_name", name))
+		}
+		if p.withStatement {
+			attributes = append(attributes, attribute.String("db.statement", db.Statement.SQL.String()))
+		}
+		span.SetAttributes(attributes...)
+
+		if db.Error != nil && !errors.Is(db.Error, gorm.ErrRecordNotFound) {
+			span.RecordError(db.Error)
+			span.SetStatus(codes.Error, db.Error.Error())
+		}
+	}
+}
+
+// spanName names the span of a statement after its cached query, or its operation and table.
+func spanName(db *gorm.DB, operation string) string {
+	if name := gormext.QueryName(db); name != "" {
+		return name
+	}
+	if db.Statement.Table != "" {
+		return fmt.Sprintf("%s %s", operation, db.Statement.Table)
+	}
+	return operation
+}
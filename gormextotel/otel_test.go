@@ -0,0 +1,70 @@
+package gormextotel
+
+import (
+	"context"
+	"testing"
+
+	"github.com/raykavin/gormext"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+type tracedUser struct {
+	ID   uint
+	Name string
+}
+
+// TestPlugin verifies that statements are traced as children of the caller span, tagged with
+// their table, cached query name, rows and errors.
+func TestPlugin(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+
+	dbCtx, err := gormext.NewDatabaseContext(":memory:", "sqlite", "silent")
+	assert.NoError(t, err)
+	g, err := gormext.NewGorm(*dbCtx, nil, nil, map[string]string{})
+	assert.NoError(t, err)
+	assert.NoError(t, g.Migrate(&tracedUser{}))
+	assert.NoError(t, g.Use(NewPlugin(WithTracerProvider(provider))))
+	assert.NoError(t, g.RegisterQuery("users.rename", "UPDATE traced_users SET name = ?"))
+
+	ctx, parent := provider.Tracer("test").Start(context.Background(), "handler")
+	repo := g.GetDB().WithContext(ctx)
+	assert.NoError(t, repo.Create(&tracedUser{Name: "Ana"}))
+	assert.NoError(t, g.ExecQuery(ctx, "users.rename", "Bia"))
+	assert.Error(t, repo.Exec("SELECT * FROM missing"))
+	parent.End()
+
+	spans := recorder.Ended()
+	if !assert.Len(t, spans, 4) {
+		return
+	}
+
+	create, query, failed := spans[0], spans[1], spans[2]
+	assert.Equal(t, "create traced_users", create.Name())
+	assert.Equal(t, parent.SpanContext().SpanID(), create.Parent().SpanID(), "Spans should continue the caller trace")
+	assert.Contains(t, create.Attributes(), attribute.String("db.system", "sqlite"))
+	assert.Contains(t, create.Attributes(), attribute.String("db.sql.table", "traced_users"))
+	assert.Contains(t, create.Attributes(), attribute.Int64("db.rows_affected", 1))
+
+	assert.Equal(t, "users.rename", query.Name())
+	assert.Contains(t, query.Attributes(), attribute.String("gormext.query_name", "users.rename"))
+	assert.Contains(t, query.Attributes(), attribute.String("db.statement", "UPDATE traced_users SET name = ?"))
+
+	assert.Equal(t, "raw", failed.Name())
+	assert.Equal(t, codes.Error, failed.Status().Code)
+
+	recorder = tracetest.NewSpanRecorder()
+	provider = sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	g, err = gormext.NewGorm(*dbCtx, nil, nil, nil)
+	assert.NoError(t, err)
+	assert.NoError(t, g.Use(NewPlugin(WithTracerProvider(provider), WithoutStatement())))
+	assert.ErrorContains(t, g.Use(NewPlugin()), "failed to register plugin 'gormext:otel'")
+	assert.NoError(t, g.GetDB().Exec("SELECT 1"))
+	for _, attr := range recorder.Ended()[0].Attributes() {
+		assert.NotEqual(t, attribute.Key("db.statement"), attr.Key, "WithoutStatement should leave the SQL out")
+	}
+}
@@ -0,0 +1,188 @@
+// Package gormextprom exports gormext metrics to Prometheus:
+//
+//	collector, err := gormextprom.NewCollector(g)
+//	prometheus.MustRegister(collector)
+package gormextprom
+
+import (
+	"errors"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/raykavin/gormext"
+	"gorm.io/gorm"
+)
+
+const (
+	// namespace prefixes the metric names.
+	namespace = "gormext"
+
+	// startKey is the statement instance setting holding the start time of the statement.
+	startKey = "gormext:prom_start"
+)
+
+// Collector is a prometheus.Collector reporting the statements, connection pool, seeds and
+// migrations of a Gorm instance.
+type Collector struct {
+	g *gormext.Gorm
+
+	queryDuration *prometheus.HistogramVec
+	queryErrors   *prometheus.CounterVec
+	runDuration   *prometheus.HistogramVec
+
+	openConnections  *prometheus.Desc
+	inUseConnections *prometheus.Desc
+	idleConnections  *prometheus.Desc
+	waitCount        *prometheus.Desc
+	waitDuration     *prometheus.Desc
+
+	cacheHits     *prometheus.Desc
+	cacheMisses   *prometheus.Desc
+	cacheStale    *prometheus.Desc
+	cacheHitRatio *prometheus.Desc
+}
+
+// NewCollector instruments g and returns its collector, to register with a Prometheus registry.
+// Statements are labeled by operation (create, query, update, delete, row or raw) and cached
+// query name, empty for other statements. Cache lookups, reported with Config.Cache, are
+// labeled by cache (result or entity).
+func NewCollector(g *gormext.Gorm) (*Collector, error) {
+	c := &Collector{
+		g: g,
+		queryDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "query_duration_seconds",
+			Help:      "Duration of the statements, by operation and cached query name.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"operation", "query"}),
+		queryErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "query_errors_total",
+			Help:      "Statements that failed, by operation and cached query name.",
+		}, []string{"operation", "query"}),
+		runDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "run_duration_seconds",
+			Help:      "Duration of the seed and migration runs, by kind, name and result.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"kind", "name", "result"}),
+
+		openConnections:  prometheus.NewDesc(namespace+"_pool_open_connections", "Open connections, in use and idle.", nil, nil),
+		inUseConnections: prometheus.NewDesc(namespace+"_pool_in_use_connections", "Connections in use.", nil, nil),
+		idleConnections:  prometheus.NewDesc(namespace+"_pool_idle_connections", "Idle connections.", nil, nil),
+		waitCount:        prometheus.NewDesc(namespace+"_pool_wait_total", "Connections waited for.", nil, nil),
+		waitDuration:     prometheus.NewDesc(namespace+"_pool_wait_seconds_total", "Time spent waiting for connections.", nil, nil),
+
+		cacheHits:     prometheus.NewDesc(namespace+"_cache_hits_total", "Lookups served from the cache, by cache.", []string{"cache"}, nil),
+		cacheMisses:   prometheus.NewDesc(namespace+"_cache_misses_total", "Lookups that read the database, by cache.", []string{"cache"}, nil),
+		cacheStale:    prometheus.NewDesc(namespace+"_cache_stale_hits_total", "Hits served stale while refreshed, by cache.", []string{"cache"}, nil),
+		cacheHitRatio: prometheus.NewDesc(namespace+"_cache_hit_ratio", "Hits divided by lookups, by cache.", []string{"cache"}, nil),
+	}
+
+	if err := g.Use(c); err != nil {
+		return nil, err
+	}
+	g.OnRun(c.observeRun)
+	return c, nil
+}
+
+// Name returns the name of the plugin timing statements.
+func (c *Collector) Name() string {
+	return "gormext:prometheus"
+}
+
+// Initialize registers the callbacks timing every statement.
+func (c *Collector) Initialize(db *gorm.DB) error {
+	callbacks := db.Callback()
+	register := func(operation string, before, after interface {
+		Register(name string, fn func(*gorm.DB)) error
+	}) error {
+		return errors.Join(
+			before.Register("gormext:prom_before_"+operation, c.start),
+			after.Register("gormext:prom_after_"+operation, c.observe(operation)),
+		)
+	}
+
+	return errors.Join(
+		register("create", callbacks.Create().Before("gorm:create"), callbacks.Create().After("gorm:create")),
+		register("query", callbacks.Query().Before("gorm:query"), callbacks.Query().After("gorm:query")),
+		register("update", callbacks.Update().Before("gorm:update"), callbacks.Update().After("gorm:update")),
+		register("delete", callbacks.Delete().Before("gorm:delete"), callbacks.Delete().After("gorm:delete")),
+		register("row", callbacks.Row().Before("gorm:row"), callbacks.Row().After("gorm:row")),
+		register("raw", callbacks.Raw().Before("gorm:raw"), callbacks.Raw().After("gorm:raw")),
+	)
+}
+
+// Describe sends the descriptors of the metrics.
+func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
+	c.queryDuration.Describe(ch)
+	c.queryErrors.Describe(ch)
+	c.runDuration.Describe(ch)
+	ch <- c.openConnections
+	ch <- c.inUseConnections
+	ch <- c.idleConnections
+	ch <- c.waitCount
+	ch <- c.waitDuration
+	ch <- c.cacheHits
+	ch <- c.cacheMisses
+	ch <- c.cacheStale
+	ch <- c.cacheHitRatio
+}
+
+// Collect sends the metrics, reading the cache and connection pool statistics.
+func (c *Collector) Collect(ch chan<- prometheus.Metric) {
+	c.queryDuration.Collect(ch)
+	c.queryErrors.Collect(ch)
+	c.runDuration.Collect(ch)
+
+	for _, stat := range c.g.CacheStats() {
+		ch <- prometheus.MustNewConstMetric(c.cacheHits, prometheus.CounterValue, float64(stat.Hits), stat.Cache)
+		ch <- prometheus.MustNewConstMetric(c.cacheMisses, prometheus.CounterValue, float64(stat.Misses), stat.Cache)
+		ch <- prometheus.MustNewConstMetric(c.cacheStale, prometheus.CounterValue, float64(stat.Stale), stat.Cache)
+		ch <- prometheus.MustNewConstMetric(c.cacheHitRatio, prometheus.GaugeValue, stat.HitRatio, stat.Cache)
+	}
+
+	stats, err := c.g.PoolStats()
+	if err != nil {
+		ch <- prometheus.NewInvalidMetric(c.openConnections, err)
+		return
+	}
+	ch <- prometheus.MustNewConstMetric(c.openConnections, prometheus.GaugeValue, float64(stats.OpenConnections))
+	ch <- prometheus.MustNewConstMetric(c.inUseConnections, prometheus.GaugeValue, float64(stats.InUse))
+	ch <- prometheus.MustNewConstMetric(c.idleConnections, prometheus.GaugeValue, float64(stats.Idle))
+	ch <- prometheus.MustNewConstMetric(c.waitCount, prometheus.CounterValue, float64(stats.WaitCount))
+	ch <- prometheus.MustNewConstMetric(c.waitDuration, prometheus.CounterValue, stats.WaitDuration.Seconds())
+}
+
+// start records the start time of a statement.
+func (c *Collector) start(db *gorm.DB) {
+	db.InstanceSet(startKey, time.Now())
+}
+
+// observe records the duration and failure of a statement.
+func (c *Collector) observe(operation string) func(*gorm.DB) {
+	return func(db *gorm.DB) {
+		start, ok := db.InstanceGet(startKey)
+		if !ok {
+			return
+		}
+
+		query := gormext.QueryName(db)
+		c.queryDuration.WithLabelValues(operation, query).Observe(time.Since(start.(time.Time)).Seconds())
+		if db.Error != nil && !errors.Is(db.Error, gorm.ErrRecordNotFound) {
+			c.queryErrors.WithLabelValues(operation, query).Inc()
+		}
+	}
+}
+
+// observeRun records the duration of a seed or migration run.
+func (c *Collector) observeRun(event gormext.RunEvent) {
+	name, result := event.Name, "success"
+	if event.Direction != "" {
+		name += " " + event.Direction
+	}
+	if event.Err != nil {
+		result = "error"
+	}
+	c.runDuration.WithLabelValues(event.Kind, name, result).Observe(event.Duration.Seconds())
+}
@@ -0,0 +1,103 @@
+package gormextprom
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/raykavin/gormext"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+// failingSeeder is a seeder that always fails.
+type failingSeeder struct{}
+
+func (failingSeeder) Name() string                  { return "failing" }
+func (failingSeeder) Run(gormext.IRepository) error { return errors.New("boom") }
+
+// TestCollector verifies that statements, pool statistics, seeds and migrations are reported.
+func TestCollector(t *testing.T) {
+	dbCtx, err := gormext.NewDatabaseContext(":memory:", "sqlite", "silent")
+	assert.NoError(t, err)
+	g, err := gormext.NewGorm(*dbCtx, nil, nil, nil)
+	assert.NoError(t, err)
+
+	collector, err := NewCollector(g)
+	assert.NoError(t, err)
+	registry := prometheus.NewPedanticRegistry()
+	assert.NoError(t, registry.Register(collector))
+
+	assert.NoError(t, g.RegisterMigration(gormext.Migration{
+		Version: 1,
+		Name:    "create_items",
+		Up:      func(tx *gorm.DB) error { return tx.Exec("CREATE TABLE items (id INTEGER)").Error },
+	}))
+	assert.NoError(t, g.MigrateUp(context.Background()))
+	assert.NoError(t, g.RegisterQuery("items.add", "INSERT INTO items (id) VALUES (?)"))
+	assert.NoError(t, g.ExecQuery(context.Background(), "items.add", 1))
+	assert.Error(t, g.GetDB().Exec("SELECT * FROM missing"))
+	g.RegisterSeeder(failingSeeder{})
+	assert.Error(t, g.Seed())
+
+	assert.Equal(t, 1.0, testutil.ToFloat64(collector.queryErrors.WithLabelValues("raw", "")))
+	assert.Equal(t, 0.0, testutil.ToFloat64(collector.queryErrors.WithLabelValues("raw", "items.add")))
+	assert.Equal(t, 1, testutil.CollectAndCount(collector, "gormext_pool_open_connections"))
+
+	expected := `
+# HELP gormext_pool_in_use_connections Connections in use.
+# TYPE gormext_pool_in_use_connections gauge
+gormext_pool_in_use_connections 0
+`
+	assert.NoError(t, testutil.GatherAndCompare(registry, strings.NewReader(expected), "gormext_pool_in_use_connections"))
+
+	families, err := registry.Gather()
+	assert.NoError(t, err)
+	series := make(map[string]uint64)
+	for _, family := range families {
+		if family.GetName() != "gormext_run_duration_seconds" && family.GetName() != "gormext_query_duration_seconds" {
+			continue
+		}
+		for _, metric := range family.GetMetric() {
+			var labels []string
+			for _, label := range metric.GetLabel() {
+				labels = append(labels, label.GetValue())
+			}
+			series[family.GetName()+"{"+strings.Join(labels, ",")+"}"] = metric.GetHistogram().GetSampleCount()
+		}
+	}
+	assert.Equal(t, uint64(1), series["gormext_query_duration_seconds{raw,items.add}"])
+	assert.Equal(t, uint64(1), series["gormext_run_duration_seconds{migration,1_create_items up,success}"])
+	assert.Equal(t, uint64(1), series["gormext_run_duration_seconds{seed,failing,error}"])
+}
+
+// TestCollectorCache verifies that cache lookups are reported by cache.
+func TestCollectorCache(t *testing.T) {
+	dbCtx, err := gormext.NewDatabaseContext(":memory:", "sqlite", "silent")
+	assert.NoError(t, err)
+	g, err := gormext.NewGorm(*dbCtx, nil, nil, nil, gormext.Config{Cache: gormext.NewMemoryCache(10)})
+	assert.NoError(t, err)
+	collector, err := NewCollector(g)
+	assert.NoError(t, err)
+
+	var count int64
+	cached := g.GetDB().Cached(time.Minute).Table("sqlite_master")
+	assert.NoError(t, cached.Count(&count))
+	assert.NoError(t, cached.Count(&count))
+
+	expected := `
+# HELP gormext_cache_hit_ratio Hits divided by lookups, by cache.
+# TYPE gormext_cache_hit_ratio gauge
+gormext_cache_hit_ratio{cache="entity"} 0
+gormext_cache_hit_ratio{cache="result"} 0.5
+# HELP gormext_cache_hits_total Lookups served from the cache, by cache.
+# TYPE gormext_cache_hits_total counter
+gormext_cache_hits_total{cache="entity"} 0
+gormext_cache_hits_total{cache="result"} 1
+`
+	assert.NoError(t, testutil.CollectAndCompare(collector, strings.NewReader(expected), "gormext_cache_hits_total", "gormext_cache_hit_ratio"))
+}
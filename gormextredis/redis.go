@@ -0,0 +1,58 @@
+// Package gormextredis stores the gormext result cache in Redis:
+//
+//	client := redis.NewClient(&redis.Options{Addr: "localhost:6379"})
+//	g, err := gormext.NewGorm(dbCtx, nil, nil, nil, gormext.Config{Cache: gormextredis.NewStore(client)})
+package gormextredis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/raykavin/gormext"
+	"github.com/redis/go-redis/v9"
+)
+
+// Store is a gormext.CacheStore keeping entries in Redis, expired by Redis itself.
+type Store struct {
+	client redis.UniversalClient
+}
+
+var _ gormext.CacheStore = (*Store)(nil)
+
+// NewStore returns a cache store using client.
+func NewStore(client redis.UniversalClient) *Store {
+	return &Store{client: client}
+}
+
+// Get returns the value of key, if any.
+func (s *Store) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	value, err := s.client.Get(ctx, key).Bytes()
+	switch {
+	case errors.Is(err, redis.Nil):
+		return nil, false, nil
+	case err != nil:
+		return nil, false, fmt.Errorf("failed to get cache entry '%s': %w", key, err)
+	}
+	return value, true, nil
+}
+
+// Set stores value under key for ttl.
+func (s *Store) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	if err := s.client.Set(ctx, key, value, ttl).Err(); err != nil {
+		return fmt.Errorf("failed to set cache entry '%s': %w", key, err)
+	}
+	return nil
+}
+
+// Delete removes keys.
+func (s *Store) Delete(ctx context.Context, keys ...string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	if err := s.client.Del(ctx, keys...).Err(); err != nil {
+		return fmt.Errorf("failed to delete cache entries: %w", err)
+	}
+	return nil
+}
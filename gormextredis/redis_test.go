@@ -0,0 +1,78 @@
+package gormextredis
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/raykavin/gormext"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+)
+
+// repoItem is the model cached by the tests.
+type repoItem struct {
+	ID   uint
+	Name string
+}
+
+// TestStore verifies that entries are stored, expired and deleted.
+func TestStore(t *testing.T) {
+	server := miniredis.RunT(t)
+	store := NewStore(redis.NewClient(&redis.Options{Addr: server.Addr()}))
+	ctx := context.Background()
+
+	_, ok, err := store.Get(ctx, "missing")
+	assert.NoError(t, err)
+	assert.False(t, ok)
+
+	assert.NoError(t, store.Set(ctx, "a", []byte("1"), time.Minute))
+	assert.NoError(t, store.Set(ctx, "b", []byte("2"), time.Second))
+	value, ok, err := store.Get(ctx, "a")
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, []byte("1"), value)
+
+	server.FastForward(2 * time.Second)
+	_, ok, _ = store.Get(ctx, "b")
+	assert.False(t, ok)
+
+	assert.NoError(t, store.Delete(ctx, "a"))
+	_, ok, _ = store.Get(ctx, "a")
+	assert.False(t, ok)
+
+	server.Close()
+	_, _, err = store.Get(ctx, "a")
+	assert.Error(t, err)
+}
+
+// TestStoreCachesResults verifies that cached repository results are served from Redis.
+func TestStoreCachesResults(t *testing.T) {
+	server := miniredis.RunT(t)
+	dbCtx, err := gormext.NewDatabaseContext(":memory:", "sqlite", "silent")
+	assert.NoError(t, err)
+	g, err := gormext.NewGorm(*dbCtx, nil, nil, nil, gormext.Config{
+		Cache: NewStore(redis.NewClient(&redis.Options{Addr: server.Addr()})),
+	})
+	assert.NoError(t, err)
+	assert.NoError(t, g.Migrate(&repoItem{}))
+	repo := g.GetDB()
+	assert.NoError(t, repo.Create(&repoItem{Name: "a"}))
+
+	var items []repoItem
+	assert.NoError(t, repo.Cached(time.Minute).Find(&items))
+	assert.Len(t, items, 1)
+	assert.Len(t, server.Keys(), 2, "the result and the version of its table")
+
+	// Raw SQL writes do not invalidate.
+	assert.NoError(t, repo.Exec("DELETE FROM repo_items"))
+	items = nil
+	assert.NoError(t, repo.Cached(time.Minute).Find(&items))
+	assert.Len(t, items, 1)
+
+	assert.NoError(t, repo.Create(&repoItem{Name: "b"}))
+	items = nil
+	assert.NoError(t, repo.Cached(time.Minute).Find(&items))
+	assert.Equal(t, []repoItem{{ID: 2, Name: "b"}}, items, "creates invalidate the results of the table")
+}
@@ -0,0 +1,35 @@
+// Package gormextwire provides the gormext connection to google/wire injectors:
+//
+//	wire.Build(gormextwire.ProviderSet, ...)
+package gormextwire
+
+import (
+	"context"
+
+	"github.com/google/wire"
+	"github.com/raykavin/gormext"
+)
+
+// ProviderSet provides *gormext.Gorm, its gormext.IRepository and its health check from a
+// gormext.DatabaseContext and a gormext.Config.
+var ProviderSet = wire.NewSet(New, NewRepository, NewHealthCheck)
+
+// New connects to the database with the default repository. The returned cleanup closes
+// the connection.
+func New(databaseCtx gormext.DatabaseContext, config gormext.Config) (*gormext.Gorm, func(), error) {
+	g, err := gormext.NewGorm(databaseCtx, nil, nil, nil, config)
+	if err != nil {
+		return nil, nil, err
+	}
+	return g, func() { _ = g.Close() }, nil
+}
+
+// NewRepository returns the repository of g.
+func NewRepository(g *gormext.Gorm) gormext.IRepository {
+	return g.GetDB()
+}
+
+// NewHealthCheck returns the health check of g, for readiness probes.
+func NewHealthCheck(g *gormext.Gorm) func(ctx context.Context) gormext.Health {
+	return g.HealthCheck
+}
@@ -0,0 +1,25 @@
+package gormextwire
+
+import (
+	"context"
+	"testing"
+
+	"github.com/raykavin/gormext"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestProviders verifies that the providers build a working connection closed by cleanup.
+func TestProviders(t *testing.T) {
+	dbCtx, err := gormext.NewDatabaseContext(":memory:", "sqlite", "silent")
+	assert.NoError(t, err)
+
+	g, cleanup, err := New(*dbCtx, gormext.Config{})
+	assert.NoError(t, err)
+
+	assert.NoError(t, NewRepository(g).Exec("SELECT 1"))
+	health := NewHealthCheck(g)
+	assert.True(t, health(context.Background()).Healthy)
+
+	cleanup()
+	assert.False(t, health(context.Background()).Healthy, "Cleanup should close the connection")
+}
@@ -0,0 +1,301 @@
+package gormext
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/big"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+// DefaultIDGenerator is the generator of the fields tagged `gormext:"id"` without
+// Config.IDGenerator.
+const DefaultIDGenerator = "uuid"
+
+const (
+	// idPluginName is the name of the plugin holding the ID generators.
+	idPluginName = "gormext:id_generators"
+
+	// crockford is the Crockford base32 alphabet of ULIDs.
+	crockford = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+	// base62 is the alphabet of KSUIDs.
+	base62 = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+
+	// ksuidEpoch is the KSUID epoch, in Unix seconds.
+	ksuidEpoch = 1400000000
+)
+
+// snowflakeEpoch is the epoch of snowflake IDs, leaving them 69 years from 2020.
+var snowflakeEpoch = time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+type (
+	// IDGenerator generates the primary keys (or other identifiers) of created records, see
+	// RegisterIDGenerator.
+	IDGenerator interface {
+		// NewID returns a new identifier, a string or an integer set to the field as gorm
+		// converts them.
+		NewID(ctx context.Context) (any, error)
+	}
+
+	// IDGeneratorFunc adapts a function to an IDGenerator.
+	IDGeneratorFunc func(ctx context.Context) (any, error)
+
+	// idGenerators is the plugin filling the fields tagged `gormext:"id"` of created records
+	// with the generator of their tag, or the default one.
+	idGenerators struct {
+		fallback   string
+		generators sync.Map // name -> IDGenerator
+		fields     sync.Map // reflect.Type of the model -> []idField
+	}
+
+	// idField is a field filled by an ID generator, and the name of its generator, empty for
+	// the default one.
+	idField struct {
+		field     *schema.Field
+		generator string
+	}
+
+	// ulidGenerator generates ULIDs, incrementing the random part of IDs generated within the
+	// same millisecond so that they stay sorted.
+	ulidGenerator struct {
+		mu     sync.Mutex
+		millis uint64
+		last   [16]byte
+	}
+
+	// snowflakeGenerator generates snowflake IDs: milliseconds since 2020 in 41 bits, the node
+	// in 10 bits and a sequence in 12 bits.
+	snowflakeGenerator struct {
+		node int64
+
+		mu       sync.Mutex
+		millis   int64
+		sequence int64
+	}
+
+	// ksuidGenerator generates KSUIDs: seconds since the KSUID epoch in 4 bytes and 16 random
+	// bytes, in base62.
+	ksuidGenerator struct{}
+)
+
+// NewID calls f.
+func (f IDGeneratorFunc) NewID(ctx context.Context) (any, error) {
+	return f(ctx)
+}
+
+// newIDGenerators returns the plugin with the built-in generators, using fallback for the
+// fields of untagged generator.
+func newIDGenerators(fallback string) *idGenerators {
+	if fallback == "" {
+		fallback = DefaultIDGenerator
+	}
+	fallback = strings.ToLower(fallback)
+
+	ids := &idGenerators{fallback: fallback}
+	node0, _ := NewSnowflakeGenerator(0)
+	ids.generators.Store("uuid", IDGeneratorFunc(func(context.Context) (any, error) {
+		id, err := uuid.NewV7()
+		return id.String(), err
+	}))
+	ids.generators.Store("ulid", NewULIDGenerator())
+	ids.generators.Store("snowflake", node0)
+	ids.generators.Store("ksuid", NewKSUIDGenerator())
+	return ids
+}
+
+// RegisterIDGenerator registers generator as name, case-insensitively, for the fields tagged
+// `gormext:"id:<name>"`, or every field tagged `gormext:"id"` when name is
+// Config.IDGenerator. The built-in generators are "uuid" (UUID v7), "ulid", "ksuid" and
+// "snowflake" (of node 0, see NewSnowflakeGenerator), which registering replaces.
+//
+// Tagged fields are filled on create when zero, before the BeforeCreate hooks.
+func (g *Gorm) RegisterIDGenerator(name string, generator IDGenerator) {
+	g.ids.generators.Store(strings.ToLower(name), generator)
+}
+
+// Name returns the plugin name.
+func (ids *idGenerators) Name() string {
+	return idPluginName
+}
+
+// Initialize registers the callback filling the tagged fields of created records.
+func (ids *idGenerators) Initialize(db *gorm.DB) error {
+	return db.Callback().Create().Before("gorm:before_create").Register("gormext:id", ids.fill)
+}
+
+// fill sets the zero tagged fields of the created records to new identifiers.
+func (ids *idGenerators) fill(db *gorm.DB) {
+	stmt := db.Statement
+	if db.Error != nil || stmt.Schema == nil {
+		return
+	}
+
+	fields := ids.fieldsOf(stmt.Schema)
+	if len(fields) == 0 {
+		return
+	}
+	for _, entity := range auditEntities(stmt) {
+		for _, f := range fields {
+			if _, zero := f.field.ValueOf(stmt.Context, entity); !zero {
+				continue
+			}
+			if err := ids.set(stmt.Context, f, entity); err != nil {
+				db.AddError(fmt.Errorf("failed to generate '%s' of '%s': %w", f.field.DBName, stmt.Table, err))
+				return
+			}
+		}
+	}
+}
+
+// set sets the field of entity to an identifier of its generator.
+func (ids *idGenerators) set(ctx context.Context, f idField, entity reflect.Value) error {
+	name := f.generator
+	if name == "" {
+		name = ids.fallback
+	}
+	generator, ok := ids.generators.Load(name)
+	if !ok {
+		return fmt.Errorf("unknown ID generator '%s'", name)
+	}
+
+	id, err := generator.(IDGenerator).NewID(ctx)
+	if err != nil {
+		return err
+	}
+	return f.field.Set(ctx, entity, id)
+}
+
+// fieldsOf returns the fields of table tagged `gormext:"id"`.
+func (ids *idGenerators) fieldsOf(table *schema.Schema) []idField {
+	if fields, ok := ids.fields.Load(table.ModelType); ok {
+		return fields.([]idField)
+	}
+
+	var fields []idField
+	for _, field := range table.Fields {
+		if generator, ok := schema.ParseTagSetting(field.Tag.Get("gormext"), ";")["ID"]; ok {
+			if generator == "ID" {
+				generator = ""
+			}
+			// Tag values are case-insensitive like the tag keys: id:ULID selects "ulid".
+			generator = strings.ToLower(generator)
+			fields = append(fields, idField{field: field, generator: generator})
+		}
+	}
+	ids.fields.Store(table.ModelType, fields)
+	return fields
+}
+
+// NewULIDGenerator returns a generator of ULIDs, 26-character identifiers sorted by creation
+// time.
+func NewULIDGenerator() IDGenerator {
+	return &ulidGenerator{}
+}
+
+// NewID returns a new ULID.
+func (u *ulidGenerator) NewID(context.Context) (any, error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	millis := uint64(time.Now().UnixMilli())
+	if millis <= u.millis {
+		// Increment the 80-bit random part, keeping the time of the previous ID.
+		for i := 15; i >= 6; i-- {
+			if u.last[i]++; u.last[i] != 0 {
+				break
+			}
+			if i == 6 {
+				return nil, errors.New("ULID random part overflow within the millisecond")
+			}
+		}
+	} else {
+		u.millis = millis
+		binary.BigEndian.PutUint16(u.last[0:], uint16(millis>>32))
+		binary.BigEndian.PutUint32(u.last[2:], uint32(millis))
+		if _, err := rand.Read(u.last[6:]); err != nil {
+			return nil, err
+		}
+	}
+
+	// Encode the 128 bits in 26 base32 characters, 2 padding bits first.
+	var (
+		id [26]byte
+		hi = binary.BigEndian.Uint64(u.last[0:])
+		lo = binary.BigEndian.Uint64(u.last[8:])
+	)
+	for i := 25; i >= 0; i-- {
+		id[i] = crockford[lo&0x1f]
+		lo = lo>>5 | hi<<59
+		hi >>= 5
+	}
+	return string(id[:]), nil
+}
+
+// NewSnowflakeGenerator returns a generator of snowflake IDs, 63-bit integers sorted by
+// creation time, for node (0 to 1023), which must be unique among the instances generating
+// IDs for the same tables.
+func NewSnowflakeGenerator(node int64) (IDGenerator, error) {
+	if node < 0 || node > 1023 {
+		return nil, fmt.Errorf("snowflake node %d is out of range 0-1023", node)
+	}
+	return &snowflakeGenerator{node: node}, nil
+}
+
+// NewID returns a new snowflake ID, waiting for the next millisecond once 4096 IDs were
+// generated in the current one.
+func (s *snowflakeGenerator) NewID(context.Context) (any, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	millis := time.Since(snowflakeEpoch).Milliseconds()
+	if millis <= s.millis {
+		millis = s.millis
+		if s.sequence = (s.sequence + 1) & 0xfff; s.sequence == 0 {
+			for millis <= s.millis {
+				time.Sleep(time.Millisecond / 10)
+				millis = time.Since(snowflakeEpoch).Milliseconds()
+			}
+		}
+	} else {
+		s.sequence = 0
+	}
+	s.millis = millis
+	return millis<<22 | s.node<<12 | s.sequence, nil
+}
+
+// NewKSUIDGenerator returns a generator of KSUIDs, 27-character identifiers sorted by creation
+// second.
+func NewKSUIDGenerator() IDGenerator {
+	return ksuidGenerator{}
+}
+
+// NewID returns a new KSUID.
+func (ksuidGenerator) NewID(context.Context) (any, error) {
+	var raw [20]byte
+	binary.BigEndian.PutUint32(raw[:4], uint32(time.Now().Unix()-ksuidEpoch))
+	if _, err := rand.Read(raw[4:]); err != nil {
+		return nil, err
+	}
+
+	var (
+		id    [27]byte
+		value = new(big.Int).SetBytes(raw[:])
+		base  = big.NewInt(62)
+		digit = new(big.Int)
+	)
+	for i := 26; i >= 0; i-- {
+		value.DivMod(value, base, digit)
+		id[i] = base62[digit.Int64()]
+	}
+	return string(id[:]), nil
+}
@@ -0,0 +1,84 @@
+package gormext
+
+import (
+	"context"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type (
+	// idNote is a model whose identifiers are generated by the default generator and by
+	// named ones.
+	idNote struct {
+		ID      string `gorm:"primaryKey" gormext:"id"`
+		Ref     int64  `gormext:"id:snowflake"`
+		Public  string `gormext:"id:KSUID"`
+		Unknown string `gormext:"id:missing"`
+	}
+
+	// sequenceNote is a model whose key comes from a custom generator.
+	sequenceNote struct {
+		ID string `gorm:"primaryKey" gormext:"id:sequence"`
+	}
+)
+
+// TestIDGenerators verifies the shape and ordering of the built-in generators.
+func TestIDGenerators(t *testing.T) {
+	ctx := context.Background()
+	snowflake, err := NewSnowflakeGenerator(7)
+	assert.NoError(t, err)
+	_, err = NewSnowflakeGenerator(1024)
+	assert.ErrorContains(t, err, "out of range")
+
+	ulid := NewULIDGenerator()
+	var ulids []string
+	var flakes []int64
+	for range 1000 {
+		id, err := ulid.NewID(ctx)
+		assert.NoError(t, err)
+		ulids = append(ulids, id.(string))
+
+		flake, err := snowflake.NewID(ctx)
+		assert.NoError(t, err)
+		flakes = append(flakes, flake.(int64))
+	}
+	assert.Len(t, ulids[0], 26)
+	assert.True(t, sort.StringsAreSorted(ulids), "ULIDs sort by creation")
+	assert.True(t, sort.SliceIsSorted(flakes, func(i, j int) bool { return flakes[i] < flakes[j] }))
+	assert.NotEqual(t, flakes[0], flakes[1])
+	assert.Equal(t, int64(7), flakes[0]>>12&0x3ff)
+
+	ksuid, err := NewKSUIDGenerator().NewID(ctx)
+	assert.NoError(t, err)
+	assert.Len(t, ksuid, 27)
+}
+
+// TestIDGeneratorCallback verifies that tagged fields are filled on create by their
+// generator, named case-insensitively, unless set or unknown.
+func TestIDGeneratorCallback(t *testing.T) {
+	dbCtx := newTestDatabaseContext()
+	g, err := NewGorm(dbCtx, nil, nil, nil, Config{IDGenerator: "ulid"})
+	assert.NoError(t, err)
+	repo := g.GetDB()
+	assert.NoError(t, g.Migrate(&idNote{}, &sequenceNote{}))
+
+	note := idNote{Unknown: "set"}
+	assert.NoError(t, repo.Create(&note))
+	assert.Len(t, note.ID, 26)
+	assert.NotZero(t, note.Ref)
+	assert.Len(t, note.Public, 27)
+	assert.Equal(t, "set", note.Unknown)
+
+	assert.ErrorContains(t, repo.Create(&idNote{}), "unknown ID generator 'missing'")
+
+	next := 0
+	g.RegisterIDGenerator("Sequence", IDGeneratorFunc(func(context.Context) (any, error) {
+		next++
+		return next, nil
+	}))
+	notes := []sequenceNote{{}, {ID: "kept"}, {}}
+	assert.NoError(t, repo.Create(&notes))
+	assert.Equal(t, []sequenceNote{{ID: "1"}, {ID: "kept"}, {ID: "2"}}, notes)
+}
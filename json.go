@@ -0,0 +1,161 @@
+package gormext
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+// JSON is a field type storing a Go value in a JSON column: jsonb on Postgres, json on MySQL
+// and sqlite. Query its content with WhereJSONEquals and WhereJSONContains.
+type JSON[T any] struct {
+	Data T
+}
+
+// NewJSON returns the JSON field holding data.
+func NewJSON[T any](data T) JSON[T] {
+	return JSON[T]{Data: data}
+}
+
+// Value implements the driver Valuer interface, encoding the data as JSON text.
+func (j JSON[T]) Value() (driver.Value, error) {
+	encoded, err := json.Marshal(j.Data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode JSON value: %w", err)
+	}
+	return string(encoded), nil
+}
+
+// Scan implements the Scanner interface, decoding the JSON text of the column. NULL leaves the
+// zero value.
+func (j *JSON[T]) Scan(src any) error {
+	var data T
+	switch src := src.(type) {
+	case nil:
+	case []byte:
+		if err := json.Unmarshal(src, &data); err != nil {
+			return fmt.Errorf("failed to decode JSON value: %w", err)
+		}
+	case string:
+		if err := json.Unmarshal([]byte(src), &data); err != nil {
+			return fmt.Errorf("failed to decode JSON value: %w", err)
+		}
+	default:
+		return fmt.Errorf("failed to decode JSON value: unsupported type %T", src)
+	}
+	j.Data = data
+	return nil
+}
+
+// MarshalJSON encodes the data, so that the field serializes as its value.
+func (j JSON[T]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(j.Data)
+}
+
+// UnmarshalJSON decodes the data.
+func (j *JSON[T]) UnmarshalJSON(b []byte) error {
+	return json.Unmarshal(b, &j.Data)
+}
+
+// GormDataType returns the general data type of the field.
+func (JSON[T]) GormDataType() string {
+	return "json"
+}
+
+// GormDBDataType returns the column type of the field for the driver of db.
+func (JSON[T]) GormDBDataType(db *gorm.DB, _ *schema.Field) string {
+	if db.Dialector.Name() == "postgres" {
+		return "JSONB"
+	}
+	return "JSON"
+}
+
+// WhereJSONEquals adds the condition that the JSON value at path equals value, compared as
+// JSON. The path is the column followed by dot-separated object keys or array indexes, such
+// as "attributes.sizes.0".
+func (r *gormRepository) WhereJSONEquals(path string, value any) IRepository {
+	return r.whereJSON(path, value, false)
+}
+
+// WhereJSONContains adds the condition that the JSON value at path contains value: an array
+// containing it as element, or an object containing its keys and values. On sqlite, only
+// arrays containing a scalar value match.
+func (r *gormRepository) WhereJSONContains(path string, value any) IRepository {
+	return r.whereJSON(path, value, true)
+}
+
+// whereJSON adds the JSON condition on path in the dialect of the connection.
+func (r *gormRepository) whereJSON(path string, value any, contains bool) IRepository {
+	column, keys, _ := strings.Cut(path, ".")
+	if column == "" {
+		return r.failed(fmt.Errorf("failed to build JSON condition on '%s': empty column", path))
+	}
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return r.failed(fmt.Errorf("failed to build JSON condition on '%s': %w", path, err))
+	}
+
+	var segments []string
+	if keys != "" {
+		segments = strings.Split(keys, ".")
+	}
+	col := clause.Column{Name: column}
+
+	var condition clause.Expr
+	switch name := r.db.Dialector.Name(); {
+	case name == "postgres" && contains:
+		condition = clause.Expr{SQL: "? #> CAST(? AS text[]) @> CAST(? AS jsonb)", Vars: []any{col, postgresJSONPath(segments), string(encoded)}}
+	case name == "postgres":
+		condition = clause.Expr{SQL: "? #> CAST(? AS text[]) = CAST(? AS jsonb)", Vars: []any{col, postgresJSONPath(segments), string(encoded)}}
+	case name == "mysql" && contains:
+		condition = clause.Expr{SQL: "JSON_CONTAINS(?, ?, ?)", Vars: []any{col, string(encoded), jsonPath(segments)}}
+	case name == "mysql":
+		condition = clause.Expr{SQL: "JSON_EXTRACT(?, ?) = CAST(? AS JSON)", Vars: []any{col, jsonPath(segments), string(encoded)}}
+	case name == "sqlite" && contains:
+		condition = clause.Expr{
+			SQL:  "EXISTS (SELECT 1 FROM json_each(?, ?) WHERE json_each.value = json_extract(?, '$'))",
+			Vars: []any{col, jsonPath(segments), string(encoded)},
+		}
+	case name == "sqlite":
+		condition = clause.Expr{SQL: "json_extract(?, ?) = json_extract(?, '$')", Vars: []any{col, jsonPath(segments), string(encoded)}}
+	default:
+		return r.failed(fmt.Errorf("%w: JSON conditions on '%s'", ErrUnsupportedDriver, name))
+	}
+	return r.with(r.db.Where(condition))
+}
+
+// failed returns the repository whose calls fail with err.
+func (r *gormRepository) failed(err error) IRepository {
+	db := r.db.Session(&gorm.Session{})
+	db.AddError(err)
+	return r.with(db)
+}
+
+// jsonPath returns the SQL/JSON path of the keys of segments, as MySQL and sqlite take them.
+func jsonPath(segments []string) string {
+	var b strings.Builder
+	b.WriteString("$")
+	for _, segment := range segments {
+		if _, err := strconv.Atoi(segment); err == nil {
+			b.WriteString("[" + segment + "]")
+			continue
+		}
+		b.WriteString(`."` + strings.ReplaceAll(segment, `"`, `\"`) + `"`)
+	}
+	return b.String()
+}
+
+// postgresJSONPath returns the text array literal of segments, as the #> operator takes it.
+func postgresJSONPath(segments []string) string {
+	quoted := make([]string, len(segments))
+	for i, segment := range segments {
+		quoted[i] = `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(segment) + `"`
+	}
+	return "{" + strings.Join(quoted, ",") + "}"
+}
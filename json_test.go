@@ -0,0 +1,91 @@
+package gormext
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type (
+	// productAttributes is the JSON document of a product.
+	productAttributes struct {
+		Color string   `json:"color"`
+		Sizes []string `json:"sizes"`
+		Stock int      `json:"stock"`
+	}
+
+	// jsonProduct is a model with a JSON column.
+	jsonProduct struct {
+		ID         uint
+		Name       string
+		Attributes JSON[productAttributes]
+		Tags       JSON[[]string]
+	}
+)
+
+// TestJSONField verifies that JSON fields round-trip through the database and serialize as
+// their value.
+func TestJSONField(t *testing.T) {
+	g, repo := newTestRepository(t)
+	assert.NoError(t, g.Migrate(&jsonProduct{}))
+
+	product := jsonProduct{
+		Name:       "shirt",
+		Attributes: NewJSON(productAttributes{Color: "red", Sizes: []string{"S", "M"}, Stock: 3}),
+		Tags:       NewJSON([]string{"summer"}),
+	}
+	assert.NoError(t, repo.Create(&product))
+
+	var stored jsonProduct
+	assert.NoError(t, repo.FirstByID(product.ID, &stored))
+	assert.Equal(t, product.Attributes, stored.Attributes)
+	assert.Equal(t, []string{"summer"}, stored.Tags.Data)
+
+	encoded, err := json.Marshal(stored.Tags)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `["summer"]`, string(encoded))
+
+	var empty JSON[[]string]
+	assert.NoError(t, empty.Scan(nil))
+	assert.Nil(t, empty.Data)
+	assert.Error(t, empty.Scan(42))
+}
+
+// TestWhereJSON verifies the JSON conditions on sqlite and their SQL on the other drivers.
+func TestWhereJSON(t *testing.T) {
+	g, repo := newTestRepository(t)
+	assert.NoError(t, g.Migrate(&jsonProduct{}))
+	assert.NoError(t, repo.Create(&[]jsonProduct{
+		{Name: "shirt", Attributes: NewJSON(productAttributes{Color: "red", Sizes: []string{"S", "M"}, Stock: 3})},
+		{Name: "cap", Attributes: NewJSON(productAttributes{Color: "blue", Sizes: []string{"M"}, Stock: 0})},
+	}))
+
+	names := func(repo IRepository) []string {
+		var products []jsonProduct
+		assert.NoError(t, repo.Order("id").Find(&products))
+		var names []string
+		for _, product := range products {
+			names = append(names, product.Name)
+		}
+		return names
+	}
+	assert.Equal(t, []string{"shirt"}, names(repo.WhereJSONEquals("attributes.color", "red")))
+	assert.Equal(t, []string{"cap"}, names(repo.WhereJSONEquals("attributes.stock", 0)))
+	assert.Equal(t, []string{"shirt"}, names(repo.WhereJSONEquals("attributes.sizes.0", "S")))
+	assert.Equal(t, []string{"shirt", "cap"}, names(repo.WhereJSONContains("attributes.sizes", "M")))
+	assert.Empty(t, names(repo.WhereJSONContains("attributes.sizes", "XL")))
+	assert.ErrorContains(t, repo.WhereJSONEquals(".color", "red").Find(&[]jsonProduct{}), "empty column")
+
+	postgres := NewRepository(newDryRunGorm(t, PostgreSQL).connection)
+	assert.Equal(t, `SELECT * FROM "repo_users" WHERE "attributes" #> CAST('{"color"}' AS text[]) = CAST('"red"' AS jsonb)`,
+		renderSQL(postgres.WhereJSONEquals("attributes.color", "red")))
+	assert.Equal(t, `SELECT * FROM "repo_users" WHERE "attributes" #> CAST('{"sizes"}' AS text[]) @> CAST('"M"' AS jsonb)`,
+		renderSQL(postgres.WhereJSONContains("attributes.sizes", "M")))
+
+	mysql := NewRepository(newDryRunGorm(t, MySQL).connection)
+	assert.Equal(t, "SELECT * FROM `repo_users` WHERE JSON_EXTRACT(`attributes`, '$.\"color\"') = CAST('\"red\"' AS JSON)",
+		renderSQL(mysql.WhereJSONEquals("attributes.color", "red")))
+	assert.Equal(t, "SELECT * FROM `repo_users` WHERE JSON_CONTAINS(`attributes`, '\"M\"', '$.\"sizes\"')",
+		renderSQL(mysql.WhereJSONContains("attributes.sizes", "M")))
+}
@@ -0,0 +1,106 @@
+package gormext
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"strings"
+	"sync"
+)
+
+// migrationLockName identifies the lock serializing migrations and seeding across instances.
+const migrationLockName = "gormext:migrate"
+
+// processLocks holds the in-process mutexes used when no database-level lock is available.
+var processLocks sync.Map
+
+// withMigrationLock runs fn holding a database-level lock, so that instances starting at the
+// same time migrate and seed one after the other: pg_advisory_lock on Postgres, GET_LOCK on
+// MySQL, and a lock file next to the database on SQLite.
+func (g *Gorm) withMigrationLock(ctx context.Context, fn func() error) (err error) {
+	unlock, err := g.lockMigrations(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to acquire migration lock: %w", err)
+	}
+	defer func() {
+		if unlockErr := unlock(); unlockErr != nil {
+			err = errors.Join(err, fmt.Errorf("failed to release migration lock: %w", unlockErr))
+		}
+	}()
+	return fn()
+}
+
+// lockMigrations acquires the migration lock for the driver and returns its release function.
+func (g *Gorm) lockMigrations(ctx context.Context) (func() error, error) {
+	switch g.databaseCtx.driver {
+	case PostgreSQL:
+		key := fnv.New64a()
+		key.Write([]byte(migrationLockName))
+		return g.sessionLock(ctx, "SELECT pg_advisory_lock($1)", "SELECT pg_advisory_unlock($1)", int64(key.Sum64()))
+	case MySQL:
+		return g.sessionLock(ctx, "SELECT GET_LOCK(?, -1)", "SELECT RELEASE_LOCK(?)", migrationLockName)
+	}
+
+	path := sqliteFilePath(g.databaseCtx.dsn)
+	if path == "" {
+		return processLock(g.databaseCtx.dsn), nil
+	}
+	return lockFile(path + ".lock")
+}
+
+// sessionLock acquires a session-level lock on a dedicated connection, which holds it until
+// the returned function releases it.
+func (g *Gorm) sessionLock(ctx context.Context, lockSQL, unlockSQL string, key any) (func() error, error) {
+	sqlDB, err := g.connection.DB()
+	if err != nil {
+		return nil, err
+	}
+	conn, err := sqlDB.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// GET_LOCK returns 1 when granted and NULL on errors; pg_advisory_lock returns void.
+	var granted sql.NullInt64
+	if g.databaseCtx.driver == MySQL {
+		err = conn.QueryRowContext(ctx, lockSQL, key).Scan(&granted)
+		if err == nil && granted.Int64 != 1 {
+			err = fmt.Errorf("lock '%s' not granted", migrationLockName)
+		}
+	} else {
+		_, err = conn.ExecContext(ctx, lockSQL, key)
+	}
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	return func() error {
+		defer conn.Close()
+		_, err := conn.ExecContext(context.Background(), unlockSQL, key)
+		return err
+	}, nil
+}
+
+// processLock locks the in-process mutex for key, for databases private to the process such
+// as in-memory SQLite databases.
+func processLock(key string) func() error {
+	value, _ := processLocks.LoadOrStore(key, &sync.Mutex{})
+	mu := value.(*sync.Mutex)
+	mu.Lock()
+	return func() error {
+		mu.Unlock()
+		return nil
+	}
+}
+
+// sqliteFilePath returns the database file of a SQLite DSN, or "" for in-memory databases.
+func sqliteFilePath(dsn string) string {
+	path, query, _ := strings.Cut(strings.TrimPrefix(dsn, "file:"), "?")
+	if path == "" || path == ":memory:" || strings.Contains(query, "mode=memory") {
+		return ""
+	}
+	return path
+}
@@ -0,0 +1,9 @@
+//go:build !unix
+
+package gormext
+
+// lockFile falls back to an in-process lock on systems without flock, which only serializes
+// the migrations of a single process.
+func lockFile(path string) (func() error, error) {
+	return processLock(path), nil
+}
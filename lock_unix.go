@@ -0,0 +1,26 @@
+//go:build unix
+
+package gormext
+
+import (
+	"os"
+	"syscall"
+)
+
+// lockFile takes an exclusive lock on the file at path, creating it when needed. The lock is
+// released by the returned function, or by the system when the process exits.
+func lockFile(path string) (func() error, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR, 0o600)
+	if err != nil {
+		return nil, err
+	}
+	if err := syscall.Flock(int(file.Fd()), syscall.LOCK_EX); err != nil {
+		file.Close()
+		return nil, err
+	}
+
+	return func() error {
+		defer file.Close()
+		return syscall.Flock(int(file.Fd()), syscall.LOCK_UN)
+	}, nil
+}
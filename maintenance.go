@@ -0,0 +1,117 @@
+package gormext
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"gorm.io/gorm"
+)
+
+// internalStatementKey is the statement setting marking the statements gormext runs to manage
+// transactions, such as savepoints, which maintenance mode allows.
+const internalStatementKey = "gormext:internal_statement"
+
+// ErrMaintenanceMode is returned by mutating operations while maintenance mode is enabled.
+var ErrMaintenanceMode = errors.New("database is in maintenance mode")
+
+type (
+	// maintenanceMode holds the runtime maintenance switch shared by the connection callbacks.
+	maintenanceMode struct {
+		mu      sync.RWMutex
+		enabled bool
+		message string
+	}
+
+	// maintenanceBypassKey marks contexts allowed to write during maintenance.
+	maintenanceBypassKey struct{}
+
+	// Health reports the state of the database connection.
+	Health struct {
+		Healthy     bool   // Whether the database answered a ping.
+		Maintenance bool   // Whether maintenance mode is enabled.
+		Message     string // Maintenance message, if any.
+		Err         error  // Ping error, if any.
+	}
+)
+
+// SetMaintenanceMode enables or disables maintenance mode at runtime. While enabled, creates,
+// updates, deletes and raw statements fail with ErrMaintenanceMode; reads keep working.
+func (g *Gorm) SetMaintenanceMode(on bool, message string) {
+	g.maintenance.mu.Lock()
+	defer g.maintenance.mu.Unlock()
+
+	g.maintenance.enabled = on
+	g.maintenance.message = message
+}
+
+// MaintenanceMode reports whether maintenance mode is enabled and its message.
+func (g *Gorm) MaintenanceMode() (bool, string) {
+	g.maintenance.mu.RLock()
+	defer g.maintenance.mu.RUnlock()
+
+	return g.maintenance.enabled, g.maintenance.message
+}
+
+// HealthCheck pings the database and reports the connection and maintenance state.
+func (g *Gorm) HealthCheck(ctx context.Context) Health {
+	health := Health{}
+	health.Maintenance, health.Message = g.MaintenanceMode()
+
+	sqlDB, err := g.connection.DB()
+	if err == nil {
+		err = sqlDB.PingContext(ctx)
+	}
+	health.Healthy, health.Err = err == nil, err
+	return health
+}
+
+// WithMaintenanceBypass returns a context whose operations are allowed while maintenance mode
+// is enabled, for the migrations and backfills the maintenance window is for.
+func WithMaintenanceBypass(ctx context.Context) context.Context {
+	return context.WithValue(ctx, maintenanceBypassKey{}, true)
+}
+
+// registerMaintenanceCallbacks blocks mutating statements on the connection while maintenance is enabled.
+func (g *Gorm) registerMaintenanceCallbacks() error {
+	const name = "gormext:maintenance"
+
+	callbacks := g.connection.Callback()
+	return errors.Join(
+		callbacks.Create().Before("gorm:begin_transaction").Register(name, g.maintenance.guard),
+		callbacks.Update().Before("gorm:begin_transaction").Register(name, g.maintenance.guard),
+		callbacks.Delete().Before("gorm:begin_transaction").Register(name, g.maintenance.guard),
+		callbacks.Raw().Before("gorm:raw").Register(name, g.maintenance.guard),
+	)
+}
+
+// guard fails the statement when maintenance is enabled and the context has no bypass.
+func (m *maintenanceMode) guard(db *gorm.DB) {
+	m.mu.RLock()
+	enabled, message := m.enabled, m.message
+	m.mu.RUnlock()
+
+	if !enabled {
+		return
+	}
+	if ctx := db.Statement.Context; ctx != nil && ctx.Value(maintenanceBypassKey{}) != nil {
+		return
+	}
+	if _, ok := db.Get(internalStatementKey); ok {
+		return
+	}
+
+	if message == "" {
+		db.AddError(ErrMaintenanceMode)
+		return
+	}
+	db.AddError(fmt.Errorf("%w: %s", ErrMaintenanceMode, message))
+}
+
+// internalStatement returns a new statement of db marked as run by gormext to manage its
+// transaction, which maintenance mode allows. Errors of the statements the dialector runs for
+// it, such as savepoints, are reported on the returned statement.
+func internalStatement(db *gorm.DB) *gorm.DB {
+	return db.Session(&gorm.Session{}).Set(internalStatementKey, true)
+}
@@ -0,0 +1,77 @@
+package gormext
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestMaintenanceModeBlocksWrites verifies writes fail during maintenance while reads keep working.
+func TestMaintenanceModeBlocksWrites(t *testing.T) {
+	g, repo := newTestRepository(t)
+	assert.NoError(t, repo.Create(&repoUser{Name: "ann"}))
+
+	g.SetMaintenanceMode(true, "deploying v2")
+
+	err := repo.Create(&repoUser{Name: "bob"})
+	assert.ErrorIs(t, err, ErrMaintenanceMode)
+	assert.ErrorContains(t, err, "deploying v2")
+	assert.ErrorIs(t, repo.Exec("DELETE FROM repo_users"), ErrMaintenanceMode)
+	assert.ErrorIs(t, repo.Where("name = ?", "ann").Delete(&repoUser{}), ErrMaintenanceMode)
+
+	var users []repoUser
+	assert.NoError(t, repo.Find(&users), "Reads should keep working")
+	assert.Len(t, users, 1)
+
+	bypass := repo.WithContext(WithMaintenanceBypass(context.Background()))
+	assert.NoError(t, bypass.Create(&repoUser{Name: "ops"}), "Bypass context should be allowed to write")
+	assert.NoError(t, g.Migrate(&repoUser{}), "Migrations should run during maintenance")
+
+	health := g.HealthCheck(context.Background())
+	assert.True(t, health.Healthy)
+	assert.True(t, health.Maintenance)
+	assert.Equal(t, "deploying v2", health.Message)
+
+	g.SetMaintenanceMode(false, "")
+	assert.NoError(t, repo.Create(&repoUser{Name: "bob"}), "Writes should resume after maintenance")
+}
+
+// TestMaintenanceModeTransactions verifies that transaction options and nested transactions
+// keep working during maintenance, while their writes stay blocked.
+func TestMaintenanceModeTransactions(t *testing.T) {
+	g, repo := newTestRepository(t)
+	assert.NoError(t, repo.Create(&repoUser{Name: "ann"}))
+	g.SetMaintenanceMode(true, "")
+
+	var users []repoUser
+	assert.NoError(t, repo.WithTransactionOpts(TxOptions{ReadOnly: true}, func(tx IRepository) error {
+		return tx.Find(&users)
+	}))
+	assert.Len(t, users, 1)
+
+	assert.NoError(t, repo.WithTransaction(func(tx IRepository) error {
+		return tx.WithTransaction(func(nested IRepository) error {
+			return nested.Find(&users)
+		})
+	}))
+
+	err := repo.WithTransaction(func(tx IRepository) error {
+		nested, err := tx.Begin()
+		assert.NoError(t, err)
+		defer nested.Rollback()
+		assert.ErrorIs(t, nested.Create(&repoUser{Name: "bob"}), ErrMaintenanceMode)
+		return nested.Commit()
+	})
+	assert.NoError(t, err)
+
+	bypass := repo.WithContext(WithMaintenanceBypass(context.Background()))
+	assert.NoError(t, bypass.WithTransaction(func(tx IRepository) error {
+		return tx.WithTransaction(func(nested IRepository) error {
+			return nested.Create(&repoUser{Name: "ops"})
+		})
+	}))
+	var count int64
+	assert.NoError(t, repo.Table("repo_users").Count(&count))
+	assert.Equal(t, int64(2), count)
+}
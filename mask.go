@@ -0,0 +1,157 @@
+package gormext
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"unicode/utf8"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+// DefaultUnmaskRole is the role of the actors reading unmasked values without
+// Config.UnmaskRole.
+const DefaultUnmaskRole = "unmasked"
+
+const (
+	// maskPluginName is the name of the masking plugin.
+	maskPluginName = "gormext:mask"
+
+	// unmaskedKey is the statement setting of the internal reads of stored values, such as the
+	// ones of the Auditor, which are not masked.
+	unmaskedKey = "gormext:unmasked"
+)
+
+// maskKinds are the maskings of the fields tagged `gormext:"mask:<kind>"`.
+var maskKinds = map[string]func(string) string{
+	"full": func(string) string { return "****" },
+	"email": func(value string) string {
+		local, domain, ok := strings.Cut(value, "@")
+		if !ok || local == "" {
+			return "****"
+		}
+		return firstRune(local) + "***@" + domain
+	},
+	"name":  func(value string) string { return firstRune(strings.TrimSpace(value)) + "***" },
+	"last4": maskLast4,
+	"phone": maskLast4,
+	"card":  maskLast4,
+}
+
+// maskLast4 masks every character of value but the last 4.
+func maskLast4(value string) string {
+	runes := []rune(value)
+	if len(runes) <= 4 {
+		return strings.Repeat("*", len(runes))
+	}
+	return strings.Repeat("*", len(runes)-4) + string(runes[len(runes)-4:])
+}
+
+// firstRune returns the first character of value.
+func firstRune(value string) string {
+	r, size := utf8.DecodeRuneInString(value)
+	if size == 0 {
+		return ""
+	}
+	return string(r)
+}
+
+// masking is the plugin of Config.MaskFields, redacting the sensitive fields of queried
+// records for the actors without the unmask role.
+type masking struct {
+	role string
+}
+
+// Name returns the plugin name.
+func (m *masking) Name() string {
+	return maskPluginName
+}
+
+// Initialize registers the callback masking the queried records, after their AfterFind hooks.
+func (m *masking) Initialize(db *gorm.DB) error {
+	return db.Callback().Query().After("gorm:after_query").Register("gormext:mask", m.mask)
+}
+
+// unmasked reports whether db reads unmasked values: an internal read, or one of an actor with
+// the unmask role.
+func (m *masking) unmasked(db *gorm.DB) bool {
+	if _, ok := db.Get(unmaskedKey); ok {
+		return true
+	}
+	actor, _ := ActorFromContext(db.Statement.Context)
+	return actor.HasRole(m.role)
+}
+
+// mask redacts the masked fields of the queried records. Fields of unknown kinds are fully
+// redacted, and fail the statement.
+func (m *masking) mask(db *gorm.DB) {
+	stmt := db.Statement
+	if stmt.Schema == nil || m.unmasked(db) {
+		return
+	}
+
+	for _, entity := range auditEntities(stmt) {
+		if entity.Type() != stmt.Schema.ModelType {
+			return
+		}
+		if err := maskEntity(stmt, entity); err != nil {
+			db.AddError(err)
+			return
+		}
+	}
+}
+
+// maskEntity redacts the masked fields of entity, a record of the schema of stmt. Masking
+// already masked values leaves them unchanged.
+func maskEntity(stmt *gorm.Statement, entity reflect.Value) error {
+	var err error
+	for _, field := range stmt.Schema.Fields {
+		kind, ok := schema.ParseTagSetting(field.Tag.Get("gormext"), ";")["MASK"]
+		if !ok || field.IndirectFieldType.Kind() != reflect.String {
+			continue
+		}
+		if kind == "MASK" {
+			kind = "full"
+		}
+
+		mask, found := maskKinds[strings.ToLower(kind)]
+		if !found {
+			mask = maskKinds["full"]
+			if err == nil {
+				err = fmt.Errorf("unknown mask kind '%s' for field '%s'", kind, field.Name)
+			}
+		}
+		value := reflect.Indirect(field.ReflectValueOf(stmt.Context, entity))
+		if value.IsValid() && value.String() != "" {
+			value.SetString(mask(value.String()))
+		}
+	}
+	return err
+}
+
+// maskedEntity returns entity, a queried record, as the readers without the unmask role get
+// it: a masked copy when masking is enabled, for caches shared between readers. It is false
+// when the record cannot be copied.
+func maskedEntity(db *gorm.DB, entity reflect.Value) (any, bool) {
+	if _, ok := db.Config.Plugins[maskPluginName].(*masking); !ok {
+		return entity.Interface(), true
+	}
+
+	// Copy through JSON, so that the pointed values of the record are not masked with it.
+	masked := reflect.New(entity.Type())
+	if encoded, err := json.Marshal(entity.Interface()); err != nil || json.Unmarshal(encoded, masked.Interface()) != nil {
+		return nil, false
+	}
+	// Unknown mask kinds fail the query itself, in the mask callback.
+	_ = maskEntity(db.Statement, masked.Elem())
+	return masked.Interface(), true
+}
+
+// unmaskedRead reports whether db reads records unmasked while masking is enabled, which must
+// then not be shared with other readers through the caches or coalesced reads.
+func unmaskedRead(db *gorm.DB) bool {
+	m, ok := db.Config.Plugins[maskPluginName].(*masking)
+	return ok && m.unmasked(db)
+}
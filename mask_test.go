@@ -0,0 +1,102 @@
+package gormext
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type (
+	// maskedCustomer is a model with sensitive fields.
+	maskedCustomer struct {
+		ID    uint
+		Name  string  `gormext:"mask:name"`
+		Email string  `gormext:"mask:email"`
+		Phone *string `gormext:"mask:phone"`
+		Notes string  `gormext:"mask"`
+		City  string
+	}
+
+	// misMaskedCustomer is a model with a masked field of unknown kind.
+	misMaskedCustomer struct {
+		ID    uint
+		Email string `gormext:"mask:hash"`
+	}
+)
+
+// TestMaskKinds verifies the masking of every kind.
+func TestMaskKinds(t *testing.T) {
+	assert.Equal(t, "j***@example.com", maskKinds["email"]("jane@example.com"))
+	assert.Equal(t, "****", maskKinds["email"]("not an email"))
+	assert.Equal(t, "É***", maskKinds["name"]("Élodie Martin"))
+	assert.Equal(t, "*******1234", maskKinds["phone"]("+5511991234"))
+	assert.Equal(t, "***", maskKinds["card"]("123"))
+	assert.Equal(t, "****", maskKinds["full"]("secret"))
+}
+
+// TestMaskFields verifies that queried records are masked unless the actor has the unmask
+// role, whose reads bypass the result cache.
+func TestMaskFields(t *testing.T) {
+	g, err := NewGorm(newTestDatabaseContext(), nil, nil, nil, Config{MaskFields: true, Cache: NewMemoryCache(100)})
+	assert.NoError(t, err)
+	assert.NoError(t, g.Migrate(&maskedCustomer{}, &misMaskedCustomer{}))
+	phone := "+5511991234"
+	assert.NoError(t, g.GetDB().Create(&maskedCustomer{Name: "Jane Doe", Email: "jane@example.com", Phone: &phone, Notes: "vip", City: "Recife"}))
+
+	var customer maskedCustomer
+	assert.NoError(t, g.GetDB().Cached(time.Minute).First(&customer))
+	assert.Equal(t, "J***", customer.Name)
+	assert.Equal(t, "j***@example.com", customer.Email)
+	assert.Equal(t, "*******1234", *customer.Phone)
+	assert.Equal(t, "****", customer.Notes)
+	assert.Equal(t, "Recife", customer.City)
+
+	admin := g.GetDB().WithContext(WithActor(context.Background(), Actor{ID: "dpo", Roles: []string{DefaultUnmaskRole}}))
+	assert.NoError(t, admin.Cached(time.Minute).First(&customer))
+	assert.Equal(t, "Jane Doe", customer.Name)
+	assert.Equal(t, "jane@example.com", customer.Email)
+
+	assert.NoError(t, g.GetDB().Cached(time.Minute).First(&customer))
+	assert.Equal(t, "j***@example.com", customer.Email, "unmasked reads are not cached")
+
+	assert.NoError(t, g.GetDB().Create(&misMaskedCustomer{Email: "jane@example.com"}))
+	var misMasked misMaskedCustomer
+	assert.ErrorContains(t, g.GetDB().First(&misMasked), "unknown mask kind 'hash'")
+	assert.Equal(t, "****", misMasked.Email)
+}
+
+// TestMaskSecondLevelCache verifies that the second-level cache holds masked records only, and
+// that readers with the unmask role bypass it.
+func TestMaskSecondLevelCache(t *testing.T) {
+	g, err := NewGorm(newTestDatabaseContext(), nil, nil, nil, Config{
+		MaskFields:       true,
+		Cache:            NewMemoryCache(100),
+		SecondLevelCache: true,
+	})
+	assert.NoError(t, err)
+	assert.NoError(t, g.Migrate(&maskedCustomer{}))
+	assert.NoError(t, g.CacheEntity(&maskedCustomer{}, time.Minute))
+	phone := "+5511991234"
+	customer := maskedCustomer{Name: "Jane Doe", Email: "jane@example.com", Phone: &phone}
+	assert.NoError(t, g.GetDB().Create(&customer))
+
+	var customers []maskedCustomer
+	assert.NoError(t, g.GetDB().IDIn([]any{customer.ID}).Find(&customers))
+	assert.Equal(t, "j***@example.com", customers[0].Email)
+	assert.Equal(t, "*******1234", *customers[0].Phone)
+
+	var found maskedCustomer
+	assert.NoError(t, g.GetDB().FirstByID(customer.ID, &found))
+	assert.Equal(t, "j***@example.com", found.Email, "entities are cached masked")
+	assert.Equal(t, "J***", found.Name)
+	assert.Equal(t, "*******1234", *found.Phone)
+
+	admin := g.GetDB().WithContext(WithActor(context.Background(), Actor{ID: "dpo", Roles: []string{DefaultUnmaskRole}}))
+	customers = nil
+	assert.NoError(t, admin.IDIn([]any{customer.ID}).Find(&customers))
+	assert.Equal(t, "jane@example.com", customers[0].Email, "unmasked reads bypass the cache")
+	assert.NoError(t, admin.FirstByID(customer.ID, &found))
+	assert.Equal(t, "jane@example.com", found.Email)
+}
@@ -0,0 +1,64 @@
+package gormext
+
+import "gorm.io/gorm"
+
+type (
+	// MigrationEvent describes the versioned migration a hook runs around.
+	MigrationEvent struct {
+		Version   uint64
+		Name      string
+		Direction string // "up" when the migration is applied, "down" when it is reverted.
+	}
+
+	// MigrationHook runs around a versioned migration, with the transaction the migration runs
+	// in (or the connection, on MySQL and with GolangMigrate). An error fails the migration.
+	MigrationHook func(tx *gorm.DB, event MigrationEvent) error
+
+	// migrationHooks holds the hooks registered around migrations.
+	migrationHooks struct {
+		before, after               []MigrationHook
+		beforeVersion, afterVersion map[uint64][]MigrationHook
+	}
+)
+
+// OnBeforeMigration registers hook to run before every migration is applied or reverted.
+func (g *Gorm) OnBeforeMigration(hook MigrationHook) {
+	g.migrationHooks.before = append(g.migrationHooks.before, hook)
+}
+
+// OnAfterMigration registers hook to run after every migration is applied or reverted, before
+// it is recorded, such as to emit audit events.
+func (g *Gorm) OnAfterMigration(hook MigrationHook) {
+	g.migrationHooks.after = append(g.migrationHooks.after, hook)
+}
+
+// OnBeforeVersion registers hook to run before the migration with version is applied or
+// reverted, after the hooks registered with OnBeforeMigration.
+func (g *Gorm) OnBeforeVersion(version uint64, hook MigrationHook) {
+	if g.migrationHooks.beforeVersion == nil {
+		g.migrationHooks.beforeVersion = make(map[uint64][]MigrationHook)
+	}
+	g.migrationHooks.beforeVersion[version] = append(g.migrationHooks.beforeVersion[version], hook)
+}
+
+// OnAfterVersion registers hook to run after the migration with version is applied or
+// reverted, such as to backfill a new column, before the hooks registered with
+// OnAfterMigration.
+func (g *Gorm) OnAfterVersion(version uint64, hook MigrationHook) {
+	if g.migrationHooks.afterVersion == nil {
+		g.migrationHooks.afterVersion = make(map[uint64][]MigrationHook)
+	}
+	g.migrationHooks.afterVersion[version] = append(g.migrationHooks.afterVersion[version], hook)
+}
+
+// runMigrationHooks runs the hooks of each list in order, stopping at the first error.
+func runMigrationHooks(tx *gorm.DB, event MigrationEvent, lists ...[]MigrationHook) error {
+	for _, hooks := range lists {
+		for _, hook := range hooks {
+			if err := hook(tx, event); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
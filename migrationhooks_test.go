@@ -0,0 +1,68 @@
+package gormext
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+// TestMigrationHooks verifies that hooks run around migrations in order, in the migration
+// transaction, and fail the migration on error.
+func TestMigrationHooks(t *testing.T) {
+	dir := t.TempDir()
+	writeSQLFiles(t, dir, map[string]string{
+		"1_create_users.up.sql":   "CREATE TABLE users (id INTEGER PRIMARY KEY, name TEXT);",
+		"1_create_users.down.sql": "DROP TABLE users;",
+		"2_add_slug.up.sql":       "ALTER TABLE users ADD COLUMN slug TEXT;",
+		"2_add_slug.down.sql":     "ALTER TABLE users DROP COLUMN slug;",
+	})
+	g := newMigrationGorm(t, dir)
+	ctx := context.Background()
+
+	var events []string
+	record := func(stage string) MigrationHook {
+		return func(_ *gorm.DB, event MigrationEvent) error {
+			events = append(events, fmt.Sprintf("%s %s %d_%s", stage, event.Direction, event.Version, event.Name))
+			return nil
+		}
+	}
+	g.OnBeforeMigration(record("before"))
+	g.OnAfterMigration(record("after"))
+	g.OnBeforeVersion(2, record("before version"))
+	g.OnAfterVersion(2, func(tx *gorm.DB, event MigrationEvent) error {
+		if event.Direction == "up" {
+			return tx.Exec("UPDATE users SET slug = lower(name)").Error
+		}
+		return nil
+	})
+
+	assert.NoError(t, g.MigrateTo(ctx, 1))
+	assert.NoError(t, g.connection.Exec("INSERT INTO users (name) VALUES ('Ana')").Error)
+	assert.NoError(t, g.MigrateUp(ctx))
+	assert.NoError(t, g.MigrateDown(ctx))
+	assert.Equal(t, []string{
+		"before up 1_create_users",
+		"after up 1_create_users",
+		"before up 2_add_slug",
+		"before version up 2_add_slug",
+		"after up 2_add_slug",
+		"before down 2_add_slug",
+		"before version down 2_add_slug",
+		"after down 2_add_slug",
+	}, events)
+
+	g.OnAfterVersion(2, func(*gorm.DB, MigrationEvent) error { return errors.New("backfill failed") })
+	err := g.MigrateUp(ctx)
+	assert.ErrorContains(t, err, "failed to run hook after up 2_add_slug: backfill failed")
+	assert.False(t, g.connection.Migrator().HasColumn("users", "slug"), "A failing hook should roll back the migration")
+
+	g.migrationHooks.afterVersion[2] = g.migrationHooks.afterVersion[2][:1]
+	assert.NoError(t, g.MigrateUp(ctx))
+	var slug string
+	assert.NoError(t, g.connection.Raw("SELECT slug FROM users").Scan(&slug).Error)
+	assert.Equal(t, "ana", slug, "Version hooks should backfill data")
+}
@@ -0,0 +1,480 @@
+package gormext
+
+import (
+	"cmp"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"slices"
+	"strconv"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	// migrationUp and migrationDown are the directions of a migration.
+	migrationUp   = "up"
+	migrationDown = "down"
+)
+
+type (
+	// Migration is a versioned schema change written in Go. Up applies it and the optional
+	// Down reverts it. Versions are usually timestamps such as 20240102150405.
+	Migration struct {
+		Version uint64
+		Name    string
+		Up      func(tx *gorm.DB) error
+		Down    func(tx *gorm.DB) error
+	}
+
+	// migration is a versioned migration, from files or Go.
+	migration struct {
+		version  uint64
+		name     string
+		up       func(tx *gorm.DB) error
+		down     func(tx *gorm.DB) error
+		checksum string // Checksum of the up script, empty for Go migrations.
+		script   string // Up script, empty for Go migrations.
+	}
+
+	// MigrationRecord is the status of a versioned migration, as reported by MigrationStatus.
+	MigrationRecord struct {
+		Version   uint64     `json:"version"`
+		Name      string     `json:"name"`
+		Checksum  string     `json:"checksum,omitempty"`   // Recorded checksum when applied, else of the up script.
+		Applied   bool       `json:"applied"`              // Whether the migration is applied.
+		AppliedAt *time.Time `json:"applied_at,omitempty"` // When it was applied, unknown with GolangMigrate.
+		Dirty     bool       `json:"dirty"`                // Whether it failed partway and must be fixed manually.
+		Missing   bool       `json:"missing,omitempty"`    // Whether it is applied but no longer registered.
+		Modified  bool       `json:"modified,omitempty"`   // Whether its script changed since it was applied.
+	}
+
+	// schemaMigration records an applied migration in the schema_migrations table.
+	schemaMigration struct {
+		Version   uint64 `gorm:"primaryKey;autoIncrement:false"`
+		Name      string
+		Checksum  string `gorm:"size:64"`
+		Dirty     bool
+		AppliedAt time.Time
+	}
+)
+
+var (
+	// ErrDirtyMigration is returned when a migration failed partway on a driver without
+	// transactional DDL, leaving the schema to be fixed manually.
+	ErrDirtyMigration = errors.New("database has a dirty migration")
+
+	// ErrMigrationModified is returned when the script of an applied migration no longer has
+	// the checksum recorded when it was applied.
+	ErrMigrationModified = errors.New("applied migration was modified")
+
+	// ErrMissingDownMigration is returned when reverting a migration that has no down script.
+	ErrMissingDownMigration = errors.New("migration has no down script")
+
+	// migrationFileName matches migration files such as 20240102150405_create_users.up.sql.
+	migrationFileName = regexp.MustCompile(`^(\d+)_(.+)\.(up|down)\.sql$`)
+)
+
+// TableName returns the table recording applied migrations.
+func (schemaMigration) TableName() string {
+	return "schema_migrations"
+}
+
+// RegisterMigration adds a migration written in Go, alongside the migration files.
+func (g *Gorm) RegisterMigration(m Migration) error {
+	if m.Up == nil {
+		return fmt.Errorf("migration %d has no up function", m.Version)
+	}
+
+	if err := g.addMigrationStep(m.Version, m.Name, migrationUp, m.Up, ""); err != nil {
+		return err
+	}
+	if m.Down != nil {
+		return g.addMigrationStep(m.Version, m.Name, migrationDown, m.Down, "")
+	}
+	return nil
+}
+
+// MigrateUp applies every pending migration, in version order.
+func (g *Gorm) MigrateUp(ctx context.Context) error {
+	if len(g.migrations) == 0 {
+		return nil
+	}
+	return g.MigrateTo(ctx, g.migrations[len(g.migrations)-1].version)
+}
+
+// MigrateDown reverts the last applied migration.
+func (g *Gorm) MigrateDown(ctx context.Context) error {
+	return g.RollbackLast(ctx, 1)
+}
+
+// RollbackLast reverts the last n applied migrations, in reverse version order. Nothing is
+// reverted when one of them has no down script.
+func (g *Gorm) RollbackLast(ctx context.Context, n int) error {
+	return g.withMigrationLock(ctx, func() error {
+		conn, applied, err := g.migrationState(ctx)
+		if err != nil || n <= 0 {
+			return err
+		}
+		return g.rollback(conn, applied[len(applied)-min(n, len(applied)):])
+	})
+}
+
+// MigrateDownTo reverts the applied migrations above version, in reverse version order,
+// leaving pending migrations unapplied. Nothing is reverted when one of them has no down script.
+func (g *Gorm) MigrateDownTo(ctx context.Context, version uint64) error {
+	return g.withMigrationLock(ctx, func() error {
+		conn, applied, err := g.migrationState(ctx)
+		if err != nil {
+			return err
+		}
+		return g.rollback(conn, appliedAbove(applied, version))
+	})
+}
+
+// MigrateTo migrates the schema to version: pending migrations up to version are applied in
+// version order, and applied migrations above it are reverted in reverse order.
+//
+// On Postgres and SQLite each migration runs in a transaction with its bookkeeping. MySQL
+// commits DDL implicitly, so a migration failing partway there is left dirty in the
+// schema_migrations table and further migrations fail with ErrDirtyMigration.
+//
+// Migrations hold a database-level lock, so that instances starting together apply them once.
+// Scripts modified after being applied are detected by their checksum and fail the migration
+// with ErrMigrationModified.
+func (g *Gorm) MigrateTo(ctx context.Context, version uint64) error {
+	return g.withMigrationLock(ctx, func() error {
+		return g.migrateTo(ctx, version)
+	})
+}
+
+// migrateTo implements MigrateTo, holding the migration lock.
+func (g *Gorm) migrateTo(ctx context.Context, version uint64) error {
+	conn, applied, err := g.migrationState(ctx)
+	if err != nil {
+		return err
+	}
+
+	isApplied := make(map[uint64]bool, len(applied))
+	for _, record := range applied {
+		isApplied[record.Version] = true
+	}
+
+	if err := g.rollback(conn, appliedAbove(applied, version)); err != nil {
+		return err
+	}
+
+	for _, m := range g.migrations {
+		if m.version > version {
+			break
+		}
+		if isApplied[m.version] {
+			continue
+		}
+		if err := g.runMigration(conn, m, migrationUp); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// MigrationStatus lists the registered and applied migrations in version order, such as for
+// an admin endpoint. It only reads the migration table, and reports dirty migrations instead
+// of failing on them.
+func (g *Gorm) MigrationStatus() ([]MigrationRecord, error) {
+	applied, err := g.appliedMigrations(g.connection.WithContext(WithMaintenanceBypass(context.Background())))
+	if err != nil {
+		return nil, err
+	}
+
+	records := make(map[uint64]schemaMigration, len(applied))
+	for _, record := range applied {
+		records[record.Version] = record
+	}
+
+	status := make([]MigrationRecord, 0, len(g.migrations))
+	for _, m := range g.migrations {
+		record, ok := records[m.version]
+		if !ok {
+			status = append(status, MigrationRecord{Version: m.version, Name: m.name, Checksum: m.checksum})
+			continue
+		}
+		applied := appliedRecord(record, m.name)
+		applied.Modified = m.modified(record)
+		status = append(status, applied)
+		delete(records, m.version)
+	}
+
+	for _, record := range records {
+		missing := appliedRecord(record, record.Name)
+		missing.Missing = true
+		status = append(status, missing)
+	}
+	slices.SortFunc(status, func(a, b MigrationRecord) int {
+		return cmp.Compare(a.Version, b.Version)
+	})
+	return status, nil
+}
+
+// appliedRecord returns the status of an applied migration named name.
+func appliedRecord(record schemaMigration, name string) MigrationRecord {
+	status := MigrationRecord{Version: record.Version, Name: name, Checksum: record.Checksum, Applied: true, Dirty: record.Dirty}
+	if !record.AppliedAt.IsZero() {
+		appliedAt := record.AppliedAt
+		status.AppliedAt = &appliedAt
+	}
+	return status
+}
+
+// migrationState prepares the schema_migrations table and returns the applied migrations in
+// version order, failing when one of them is dirty or was modified since it was applied.
+func (g *Gorm) migrationState(ctx context.Context) (*gorm.DB, []schemaMigration, error) {
+	conn := g.connection.WithContext(WithMaintenanceBypass(ctx))
+
+	var err error
+	if g.golangMigrate {
+		err = g.createGolangMigrateTable(conn)
+	} else {
+		err = conn.AutoMigrate(&schemaMigration{})
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create migration table: %w", err)
+	}
+
+	applied, err := g.appliedMigrations(conn)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, record := range applied {
+		if record.Dirty {
+			return nil, nil, fmt.Errorf("%w: version %d failed partway and must be fixed manually", ErrDirtyMigration, record.Version)
+		}
+		if m := g.findMigration(record.Version); m != nil && m.modified(record) {
+			return nil, nil, fmt.Errorf("%w: the checksum of %d_%s is %s instead of %s", ErrMigrationModified, m.version, m.name, m.checksum, record.Checksum)
+		}
+	}
+	return conn, applied, nil
+}
+
+// rollback reverts the applied migrations in reverse order, after checking that each of them
+// has a down step.
+func (g *Gorm) rollback(conn *gorm.DB, applied []schemaMigration) error {
+	steps := make([]*migration, 0, len(applied))
+	for i := len(applied) - 1; i >= 0; i-- {
+		m := g.findMigration(applied[i].Version)
+		if m == nil {
+			return fmt.Errorf("applied migration %d not found", applied[i].Version)
+		}
+		if m.down == nil {
+			return fmt.Errorf("%w: %d_%s", ErrMissingDownMigration, m.version, m.name)
+		}
+		steps = append(steps, m)
+	}
+
+	for _, m := range steps {
+		if err := g.runMigration(conn, m, migrationDown); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// appliedAbove returns the applied migrations whose version is above version.
+func appliedAbove(applied []schemaMigration, version uint64) []schemaMigration {
+	i := len(applied)
+	for i > 0 && applied[i-1].Version > version {
+		i--
+	}
+	return applied[i:]
+}
+
+// appliedMigrations returns the applied migrations in version order, or none when the
+// migration table does not exist yet.
+func (g *Gorm) appliedMigrations(conn *gorm.DB) ([]schemaMigration, error) {
+	if !conn.Migrator().HasTable(&schemaMigration{}) {
+		return nil, nil
+	}
+	if g.golangMigrate {
+		return g.golangMigrateApplied(conn)
+	}
+
+	var applied []schemaMigration
+	if err := conn.Order("version").Find(&applied).Error; err != nil {
+		return nil, fmt.Errorf("failed to read applied migrations: %w", err)
+	}
+	return applied, nil
+}
+
+// runMigration applies or reverts m and records the result, in a single transaction when the
+// driver supports transactional DDL.
+func (g *Gorm) runMigration(conn *gorm.DB, m *migration, direction string) (err error) {
+	step := m.up
+	if direction == migrationDown {
+		step = m.down
+	}
+	if step == nil {
+		return fmt.Errorf("migration %d_%s has no %s script", m.version, m.name, direction)
+	}
+	defer g.reportRun(RunEvent{Kind: RunMigration, Name: fmt.Sprintf("%d_%s", m.version, m.name), Direction: direction}, time.Now(), &err)
+
+	hooks, event := g.migrationHooks, MigrationEvent{Version: m.version, Name: m.name, Direction: direction}
+	run := func(tx *gorm.DB) error {
+		if err := runMigrationHooks(tx, event, hooks.before, hooks.beforeVersion[m.version]); err != nil {
+			return fmt.Errorf("failed to run hook before %s %d_%s: %w", direction, m.version, m.name, err)
+		}
+		if err := step(tx); err != nil {
+			return fmt.Errorf("failed to migrate %s %d_%s: %w", direction, m.version, m.name, err)
+		}
+		if err := runMigrationHooks(tx, event, hooks.afterVersion[m.version], hooks.after); err != nil {
+			return fmt.Errorf("failed to run hook after %s %d_%s: %w", direction, m.version, m.name, err)
+		}
+		return nil
+	}
+	record := &schemaMigration{Version: m.version, Name: m.name, Checksum: m.checksum, AppliedAt: time.Now().UTC()}
+
+	if g.databaseCtx.driver != MySQL && !g.golangMigrate {
+		return conn.Transaction(func(tx *gorm.DB) error {
+			if err := run(tx); err != nil {
+				return err
+			}
+			return g.recordMigration(tx, record, direction)
+		})
+	}
+
+	// Without transactional DDL the record is marked dirty until the migration completes.
+	record.Dirty = true
+	if err := g.recordMigration(conn, record, direction); err != nil {
+		return err
+	}
+	if err := run(conn); err != nil {
+		return err
+	}
+	record.Dirty = false
+	return g.recordMigration(conn, record, direction)
+}
+
+// recordMigration stores an applied or dirty migration, or removes a reverted one.
+func (g *Gorm) recordMigration(tx *gorm.DB, record *schemaMigration, direction string) error {
+	var err error
+	switch {
+	case g.golangMigrate:
+		err = g.setGolangMigrateVersion(tx, g.golangMigrateTarget(record.Version, direction), record.Dirty)
+	case direction == migrationUp || record.Dirty:
+		err = tx.Save(record).Error
+	default:
+		err = tx.Delete(record).Error
+	}
+	if err != nil {
+		return fmt.Errorf("failed to record migration %d: %w", record.Version, err)
+	}
+	return nil
+}
+
+// loadMigrations reads the migration files found in the root directory of fsys.
+func (g *Gorm) loadMigrations(fsys fs.FS, root, origin string) error {
+	entries, err := fs.ReadDir(fsys, root)
+	if err != nil {
+		return fmt.Errorf("failed to read migration directory '%s': %w", origin, err)
+	}
+
+	for _, entry := range entries {
+		match := migrationFileName.FindStringSubmatch(trimEncodingExt(entry.Name()))
+		if entry.IsDir() || match == nil {
+			continue
+		}
+
+		source := path.Join(origin, entry.Name())
+		version, err := strconv.ParseUint(match[1], 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid migration version in '%s': %w", source, err)
+		}
+
+		content, err := fs.ReadFile(fsys, path.Join(root, entry.Name()))
+		if err != nil {
+			return fmt.Errorf("failed to read migration file '%s': %w", source, err)
+		}
+		if content, err = g.decodeFile(source, content); err != nil {
+			return err
+		}
+
+		checksum := ""
+		if match[3] == migrationUp {
+			sum := sha256.Sum256(content)
+			checksum = hex.EncodeToString(sum[:])
+		}
+		if err := g.addMigrationStep(version, match[2], match[3], g.sqlMigration(string(content)), checksum); err != nil {
+			return fmt.Errorf("invalid migration file '%s': %w", source, err)
+		}
+		if match[3] == migrationUp {
+			g.findMigration(version).script = string(content)
+		}
+	}
+	return nil
+}
+
+// sqlMigration returns a migration step executing the statements of script one at a time.
+func (g *Gorm) sqlMigration(script string) func(tx *gorm.DB) error {
+	return func(tx *gorm.DB) error {
+		for i, statement := range splitStatements(script, g.databaseCtx.driver) {
+			if err := tx.Exec(statement).Error; err != nil {
+				return fmt.Errorf("statement %d: %w", i+1, err)
+			}
+		}
+		return nil
+	}
+}
+
+// addMigrationStep adds the up or down step of a migration, keeping migrations sorted by version.
+func (g *Gorm) addMigrationStep(version uint64, name, direction string, step func(tx *gorm.DB) error, checksum string) error {
+	if version == 0 {
+		return errors.New("migration versions must be greater than zero")
+	}
+
+	m := g.findMigration(version)
+	if m == nil {
+		m = &migration{version: version, name: name}
+		i, _ := slices.BinarySearchFunc(g.migrations, version, func(m *migration, v uint64) int {
+			return cmp.Compare(m.version, v)
+		})
+		g.migrations = slices.Insert(g.migrations, i, m)
+	}
+	if m.name != name {
+		return fmt.Errorf("migration %d is named both '%s' and '%s'", version, m.name, name)
+	}
+
+	if direction == migrationUp {
+		if m.up != nil {
+			return fmt.Errorf("migration %d has several up scripts", version)
+		}
+		m.up, m.checksum = step, checksum
+		return nil
+	}
+	if m.down != nil {
+		return fmt.Errorf("migration %d has several down scripts", version)
+	}
+	m.down = step
+	return nil
+}
+
+// modified reports whether the up script of m differs from the one recorded as applied.
+// Go migrations and histories without checksums cannot be verified.
+func (m *migration) modified(record schemaMigration) bool {
+	return m.checksum != "" && record.Checksum != "" && m.checksum != record.Checksum
+}
+
+// findMigration returns the migration with version, or nil.
+func (g *Gorm) findMigration(version uint64) *migration {
+	i, found := slices.BinarySearchFunc(g.migrations, version, func(m *migration, v uint64) int {
+		return cmp.Compare(m.version, v)
+	})
+	if !found {
+		return nil
+	}
+	return g.migrations[i]
+}
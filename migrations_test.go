@@ -0,0 +1,237 @@
+package gormext
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+// newMigrationGorm creates a Gorm instance migrating from dir.
+func newMigrationGorm(t *testing.T, dir string) *Gorm {
+	dbCtx, err := NewDatabaseContext(filepath.Join(t.TempDir(), "migrations.db"), "sqlite", "silent")
+	assert.NoError(t, err)
+
+	g, err := NewGorm(*dbCtx, nil, nil, nil, Config{MigrationsDir: dir})
+	assert.NoError(t, err, "Unexpected error from NewGorm")
+	return g
+}
+
+// TestMigrations verifies that versioned migrations are applied, reverted and recorded.
+func TestMigrations(t *testing.T) {
+	dir := t.TempDir()
+	writeSQLFiles(t, dir, map[string]string{
+		"20240101000000_create_users.up.sql":   "CREATE TABLE users (id INTEGER PRIMARY KEY, name TEXT);",
+		"20240101000000_create_users.down.sql": "DROP TABLE users;",
+		"20240201000000_add_email.up.sql":      "ALTER TABLE users ADD COLUMN email TEXT;\nCREATE INDEX idx_users_email ON users (email);",
+		"20240201000000_add_email.down.sql":    "DROP INDEX idx_users_email;\nALTER TABLE users DROP COLUMN email;",
+		"README.md":                            "not a migration",
+	})
+	g := newMigrationGorm(t, dir)
+	assert.NoError(t, g.RegisterMigration(Migration{
+		Version: 20240301000000,
+		Name:    "seed_admin",
+		Up: func(tx *gorm.DB) error {
+			return tx.Exec("INSERT INTO users (name, email) VALUES ('admin', 'admin@example.com')").Error
+		},
+	}))
+
+	ctx := context.Background()
+	migrator := g.connection.Migrator()
+	versions := func() []uint64 {
+		var applied []uint64
+		assert.NoError(t, g.connection.Model(&schemaMigration{}).Order("version").Pluck("version", &applied).Error)
+		return applied
+	}
+
+	assert.NoError(t, g.MigrateTo(ctx, 20240101000000))
+	assert.True(t, migrator.HasTable("users"))
+	assert.False(t, migrator.HasColumn("users", "email"))
+
+	assert.NoError(t, g.MigrateUp(ctx))
+	assert.Equal(t, []uint64{20240101000000, 20240201000000, 20240301000000}, versions())
+	assert.NoError(t, g.MigrateUp(ctx), "Applied migrations should be skipped")
+
+	err := g.MigrateDown(ctx)
+	assert.True(t, errors.Is(err, ErrMissingDownMigration))
+	assert.ErrorContains(t, err, "20240301000000_seed_admin")
+
+	assert.NoError(t, g.connection.Delete(&schemaMigration{Version: 20240301000000}).Error)
+	assert.NoError(t, g.MigrateDown(ctx))
+	assert.False(t, migrator.HasColumn("users", "email"))
+	assert.NoError(t, g.MigrateTo(ctx, 0))
+	assert.False(t, migrator.HasTable("users"))
+	assert.Empty(t, versions())
+}
+
+// TestRollback verifies that RollbackLast and MigrateDownTo revert migrations in reverse order,
+// refusing to start when a down script is missing.
+func TestRollback(t *testing.T) {
+	dir := t.TempDir()
+	writeSQLFiles(t, dir, map[string]string{
+		"1_create_a.up.sql":   "CREATE TABLE a (id INTEGER);",
+		"1_create_a.down.sql": "DROP TABLE a;",
+		"2_create_b.up.sql":   "CREATE TABLE b (id INTEGER);",
+		"3_create_c.up.sql":   "CREATE TABLE c (id INTEGER);",
+		"3_create_c.down.sql": "DROP TABLE c;",
+		"4_create_d.up.sql":   "CREATE TABLE d (a_id INTEGER);",
+		"4_create_d.down.sql": "DROP TABLE d;",
+	})
+	g := newMigrationGorm(t, dir)
+	migrator := g.connection.Migrator()
+	ctx := context.Background()
+	assert.NoError(t, g.MigrateUp(ctx))
+
+	assert.NoError(t, g.RollbackLast(ctx, 0))
+	assert.NoError(t, g.RollbackLast(ctx, 2))
+	assert.False(t, migrator.HasTable("d"))
+	assert.False(t, migrator.HasTable("c"))
+	assert.True(t, migrator.HasTable("b"))
+
+	assert.NoError(t, g.MigrateUp(ctx))
+	err := g.MigrateDownTo(ctx, 1)
+	assert.True(t, errors.Is(err, ErrMissingDownMigration))
+	assert.ErrorContains(t, err, "2_create_b")
+	assert.True(t, migrator.HasTable("d"), "Nothing should be reverted when a down script is missing")
+
+	assert.NoError(t, g.MigrateDownTo(ctx, 2))
+	assert.False(t, migrator.HasTable("c"))
+	assert.True(t, migrator.HasTable("b"))
+
+	assert.True(t, errors.Is(g.RollbackLast(ctx, 10), ErrMissingDownMigration))
+	assert.True(t, migrator.HasTable("a"))
+}
+
+// TestMigrationStatus verifies that MigrationStatus lists applied, pending, dirty and missing
+// migrations.
+func TestMigrationStatus(t *testing.T) {
+	dir := t.TempDir()
+	writeSQLFiles(t, dir, map[string]string{
+		"1_create_a.up.sql": "CREATE TABLE a (id INTEGER);",
+		"2_create_b.up.sql": "CREATE TABLE b (id INTEGER);",
+	})
+	g := newMigrationGorm(t, dir)
+
+	status, err := g.MigrationStatus()
+	assert.NoError(t, err)
+	assert.Len(t, status, 2)
+	assert.False(t, status[0].Applied)
+	assert.Len(t, status[0].Checksum, 64, "Pending migrations should report the checksum of their script")
+	assert.False(t, g.connection.Migrator().HasTable(&schemaMigration{}), "The status should not create the migration table")
+
+	assert.NoError(t, g.MigrateTo(context.Background(), 1))
+	assert.NoError(t, g.connection.Create(&schemaMigration{Version: 3, Name: "removed", Dirty: true}).Error)
+
+	status, err = g.MigrationStatus()
+	assert.NoError(t, err)
+	if assert.Len(t, status, 3) {
+		assert.Equal(t, uint64(1), status[0].Version)
+		assert.True(t, status[0].Applied)
+		assert.NotNil(t, status[0].AppliedAt)
+
+		assert.Equal(t, MigrationRecord{Version: 2, Name: "create_b", Checksum: status[1].Checksum}, status[1])
+
+		assert.Equal(t, MigrationRecord{Version: 3, Name: "removed", Applied: true, Dirty: true, Missing: true}, status[2])
+	}
+}
+
+// TestEmbeddedMigrations verifies that migrations load from a filesystem and that scripts
+// modified after being applied are detected.
+func TestEmbeddedMigrations(t *testing.T) {
+	fsys := fstest.MapFS{
+		"db/migrations/1_create_a.up.sql":   {Data: []byte("CREATE TABLE a (id INTEGER);")},
+		"db/migrations/1_create_a.down.sql": {Data: []byte("DROP TABLE a;")},
+		"db/migrations/2_create_b.up.sql":   {Data: []byte("CREATE TABLE b (id INTEGER);")},
+	}
+	dbCtx, err := NewDatabaseContext(filepath.Join(t.TempDir(), "embedded.db"), "sqlite", "silent")
+	assert.NoError(t, err)
+	config := Config{MigrationsFS: fsys, MigrationsRoot: "db/migrations"}
+
+	g, err := NewGorm(*dbCtx, nil, nil, nil, config)
+	assert.NoError(t, err)
+	assert.NoError(t, g.MigrateUp(context.Background()))
+	assert.True(t, g.connection.Migrator().HasTable("b"))
+
+	fsys["db/migrations/1_create_a.up.sql"] = &fstest.MapFile{Data: []byte("CREATE TABLE a (id INTEGER, name TEXT);")}
+	g, err = NewGorm(*dbCtx, nil, nil, nil, config)
+	assert.NoError(t, err)
+
+	err = g.MigrateUp(context.Background())
+	assert.True(t, errors.Is(err, ErrMigrationModified))
+	assert.ErrorContains(t, err, "1_create_a")
+
+	status, err := g.MigrationStatus()
+	assert.NoError(t, err)
+	assert.True(t, status[0].Modified)
+	assert.False(t, status[1].Modified)
+}
+
+// TestMigrationErrors verifies that failed migrations roll back and invalid sets are rejected.
+func TestMigrationErrors(t *testing.T) {
+	dir := t.TempDir()
+	writeSQLFiles(t, dir, map[string]string{
+		"1_create.up.sql": "CREATE TABLE things (id INTEGER);",
+		"2_broken.up.sql": "CREATE TABLE others (id INTEGER);\nINSERT INTO missing VALUES (1);",
+	})
+	g := newMigrationGorm(t, dir)
+
+	err := g.MigrateUp(context.Background())
+	assert.ErrorContains(t, err, "failed to migrate up 2_broken: statement 2")
+	assert.True(t, g.connection.Migrator().HasTable("things"))
+	assert.False(t, g.connection.Migrator().HasTable("others"), "Failed migrations should be rolled back")
+
+	assert.ErrorContains(t, g.RegisterMigration(Migration{Version: 1, Name: "other", Up: func(*gorm.DB) error { return nil }}),
+		"migration 1 is named both 'create' and 'other'")
+	assert.Error(t, g.RegisterMigration(Migration{Version: 3, Name: "empty"}))
+
+	assert.NoError(t, g.connection.Create(&schemaMigration{Version: 9, Dirty: true}).Error)
+	assert.True(t, errors.Is(g.MigrateUp(context.Background()), ErrDirtyMigration))
+}
+
+// TestMigrationLock verifies that instances migrating the same database concurrently apply
+// each migration once.
+func TestMigrationLock(t *testing.T) {
+	dsn := filepath.Join(t.TempDir(), "locked.db") + "?_busy_timeout=5000"
+	var runs atomic.Int32
+
+	instances := make([]*Gorm, 3)
+	for i := range instances {
+		dbCtx, err := NewDatabaseContext(dsn, "sqlite", "silent")
+		assert.NoError(t, err)
+		g, err := NewGorm(*dbCtx, nil, nil, nil)
+		assert.NoError(t, err)
+		assert.NoError(t, g.RegisterMigration(Migration{Version: 1, Name: "slow", Up: func(tx *gorm.DB) error {
+			runs.Add(1)
+			time.Sleep(50 * time.Millisecond)
+			return tx.Exec("CREATE TABLE slow (id INTEGER)").Error
+		}}))
+		instances[i] = g
+	}
+
+	var wg sync.WaitGroup
+	errs := make([]error, len(instances))
+	for i, g := range instances {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs[i] = g.MigrateUp(context.Background())
+		}()
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		assert.NoError(t, err)
+	}
+	assert.EqualValues(t, 1, runs.Load(), "Migrations should run once")
+
+	assert.Equal(t, "/data/app.db", sqliteFilePath("file:/data/app.db?cache=shared"))
+	assert.Empty(t, sqliteFilePath("file::memory:?cache=shared"))
+	assert.Empty(t, sqliteFilePath("file:app?mode=memory"))
+}
@@ -0,0 +1,69 @@
+package gormext
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type (
+	// UUIDModel is an embeddable base model with a UUID v7 primary key, generated on create
+	// when empty, timestamps and soft delete. UUID v7 keys are sortable by creation time, which
+	// keeps inserts at the end of the primary key index.
+	//
+	// Models defining their own BeforeCreate hook must call the one of UUIDModel.
+	UUIDModel struct {
+		ID        string `gorm:"primaryKey;size:36"`
+		CreatedAt time.Time
+		UpdatedAt time.Time
+		DeletedAt gorm.DeletedAt `gorm:"index"`
+	}
+
+	// UUIDv4Model is UUIDModel with random UUID v4 primary keys, for keys that must not reveal
+	// their creation time.
+	UUIDv4Model struct {
+		ID        string `gorm:"primaryKey;size:36"`
+		CreatedAt time.Time
+		UpdatedAt time.Time
+		DeletedAt gorm.DeletedAt `gorm:"index"`
+	}
+
+	// AutoIDModel is an embeddable base model with an auto-increment primary key, timestamps
+	// and soft delete.
+	AutoIDModel struct {
+		ID        uint `gorm:"primaryKey;autoIncrement"`
+		CreatedAt time.Time
+		UpdatedAt time.Time
+		DeletedAt gorm.DeletedAt `gorm:"index"`
+	}
+)
+
+// BeforeCreate generates the UUID v7 primary key when empty.
+func (m *UUIDModel) BeforeCreate(*gorm.DB) error {
+	if m.ID != "" {
+		return nil
+	}
+
+	id, err := uuid.NewV7()
+	if err != nil {
+		return fmt.Errorf("failed to generate primary key: %w", err)
+	}
+	m.ID = id.String()
+	return nil
+}
+
+// BeforeCreate generates the UUID v4 primary key when empty.
+func (m *UUIDv4Model) BeforeCreate(*gorm.DB) error {
+	if m.ID != "" {
+		return nil
+	}
+
+	id, err := uuid.NewRandom()
+	if err != nil {
+		return fmt.Errorf("failed to generate primary key: %w", err)
+	}
+	m.ID = id.String()
+	return nil
+}
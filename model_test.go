@@ -0,0 +1,65 @@
+package gormext
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+type (
+	// uuidNote is a model on the UUID v7 base model.
+	uuidNote struct {
+		UUIDModel
+		Body string
+	}
+
+	// uuidV4Note is a model on the UUID v4 base model.
+	uuidV4Note struct {
+		UUIDv4Model
+		Body string
+	}
+
+	// autoIDNote is a model on the auto-increment base model.
+	autoIDNote struct {
+		AutoIDModel
+		Body string
+	}
+)
+
+// TestBaseModels verifies the primary keys generated by the base models, their timestamps
+// and soft delete.
+func TestBaseModels(t *testing.T) {
+	g, repo := newTestRepository(t)
+	assert.NoError(t, g.Migrate(&uuidNote{}, &uuidV4Note{}, &autoIDNote{}))
+
+	notes := []uuidNote{{Body: "first"}, {Body: "second"}}
+	assert.NoError(t, repo.Create(&notes))
+	first, err := uuid.Parse(notes[0].ID)
+	assert.NoError(t, err)
+	assert.Equal(t, uuid.Version(7), first.Version())
+	assert.Less(t, notes[0].ID, notes[1].ID, "UUID v7 keys sort by creation")
+	assert.False(t, notes[0].CreatedAt.IsZero())
+
+	kept := uuidNote{UUIDModel: UUIDModel{ID: "0190a7e2-0000-7000-8000-000000000000"}}
+	assert.NoError(t, repo.Create(&kept))
+	assert.Equal(t, "0190a7e2-0000-7000-8000-000000000000", kept.ID)
+
+	v4 := uuidV4Note{Body: "random"}
+	assert.NoError(t, repo.Create(&v4))
+	id, err := uuid.Parse(v4.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, uuid.Version(4), id.Version())
+
+	auto := autoIDNote{Body: "auto"}
+	assert.NoError(t, repo.Create(&auto))
+	assert.Equal(t, uint(1), auto.ID)
+
+	assert.NoError(t, repo.Delete(&notes[0]))
+	var found []uuidNote
+	assert.NoError(t, repo.Find(&found))
+	assert.Len(t, found, 2)
+	var count int64
+	assert.NoError(t, repo.Table("uuid_notes").Count(&count))
+	assert.Equal(t, int64(3), count, "deleted notes are soft-deleted")
+}
@@ -0,0 +1,144 @@
+package gormext
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// ExecNamed executes the cached query queryName, binding its :name placeholders from params.
+func (g *Gorm) ExecNamed(queryName string, params map[string]any) error {
+	query, args, err := g.bindNamedQuery(queryName, params)
+	if err != nil {
+		return err
+	}
+
+	if err := g.queryConn(context.Background(), queryName).Exec(query, args...).Error; err != nil {
+		return fmt.Errorf("failed to execute sql query '%s': %w", queryName, err)
+	}
+	return nil
+}
+
+// QueryNamed runs the cached query queryName, binding its :name placeholders from params,
+// and scans the result into dest.
+func (g *Gorm) QueryNamed(queryName string, dest any, params map[string]any) error {
+	query, args, err := g.bindNamedQuery(queryName, params)
+	if err != nil {
+		return err
+	}
+
+	if err := g.queryConn(context.Background(), queryName).Raw(query, args...).Scan(dest).Error; err != nil {
+		return fmt.Errorf("failed to run sql query '%s': %w", queryName, err)
+	}
+	return nil
+}
+
+// bindNamedQuery loads the cached query queryName and binds its named placeholders.
+func (g *Gorm) bindNamedQuery(queryName string, params map[string]any) (string, []any, error) {
+	query, err := g.GetQuery(queryName)
+	if err != nil {
+		return "", nil, err
+	}
+
+	bound, args, err := bindNamed(query, g.databaseCtx.driver, params)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to bind sql query '%s': %w", queryName, err)
+	}
+	return bound, args, nil
+}
+
+// bindNamed replaces :name placeholders in query with positional ? placeholders and returns
+// the matching arguments. Placeholders inside string literals, quoted identifiers and comments
+// are left alone, as are Postgres :: casts. Literals of driver end like the database ends them:
+// on MySQL, backslashes escape quotes. Slice parameters expand like any gorm argument.
+func bindNamed(query string, driver SQLDriver, params map[string]any) (string, []any, error) {
+	return bindNamedFunc(query, driver, func(name string) (any, bool) {
+		value, ok := params[name]
+		return value, ok
+	})
+}
+
+// bindNamedFunc binds :name placeholders like bindNamed, resolving values with lookup.
+func bindNamedFunc(query string, driver SQLDriver, lookup func(name string) (any, bool)) (string, []any, error) {
+	var (
+		out  strings.Builder
+		args []any
+	)
+	out.Grow(len(query))
+
+	for i := 0; i < len(query); {
+		c := query[i]
+		switch {
+		case c == '\'' || c == '"' || c == '`':
+			end := skipQuoted(query, i, c)
+			if driver == MySQL {
+				end = skipQuotedEscaped(query, i, c)
+			}
+			out.WriteString(query[i:end])
+			i = end
+		case c == '-' && strings.HasPrefix(query[i:], "--"):
+			end := strings.IndexByte(query[i:], '\n')
+			if end < 0 {
+				end = len(query) - i
+			}
+			out.WriteString(query[i : i+end])
+			i += end
+		case c == '/' && strings.HasPrefix(query[i:], "/*"):
+			end := strings.Index(query[i+2:], "*/")
+			if end < 0 {
+				end = len(query) - i
+			} else {
+				end += 4
+			}
+			out.WriteString(query[i : i+end])
+			i += end
+		case c == ':' && strings.HasPrefix(query[i:], "::"):
+			out.WriteString("::")
+			i += 2
+		case c == ':' && i+1 < len(query) && isNameStart(query[i+1]):
+			end := i + 1
+			for end < len(query) && isNamePart(query[end]) {
+				end++
+			}
+
+			name := query[i+1 : end]
+			value, ok := lookup(name)
+			if !ok {
+				return "", nil, fmt.Errorf("missing value for parameter '%s'", name)
+			}
+			out.WriteByte('?')
+			args = append(args, value)
+			i = end
+		default:
+			out.WriteByte(c)
+			i++
+		}
+	}
+	return out.String(), args, nil
+}
+
+// skipQuoted returns the index just past the quoted section starting at start, treating a
+// doubled quote character as an escaped quote.
+func skipQuoted(query string, start int, quote byte) int {
+	for i := start + 1; i < len(query); i++ {
+		if query[i] != quote {
+			continue
+		}
+		if i+1 < len(query) && query[i+1] == quote {
+			i++
+			continue
+		}
+		return i + 1
+	}
+	return len(query)
+}
+
+// isNameStart reports whether c can start a placeholder name.
+func isNameStart(c byte) bool {
+	return c == '_' || ('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z')
+}
+
+// isNamePart reports whether c can continue a placeholder name.
+func isNamePart(c byte) bool {
+	return isNameStart(c) || ('0' <= c && c <= '9')
+}
@@ -0,0 +1,47 @@
+package gormext
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestBindNamed verifies placeholders are bound in order while literals, comments and casts are kept.
+func TestBindNamed(t *testing.T) {
+	query := "SELECT ':skip', \"a:b\", created::date -- :comment\nFROM users /* :block */ WHERE id IN :ids AND name = :name OR nick = :name"
+
+	bound, args, err := bindNamed(query, PostgreSQL, map[string]any{"ids": []int{1, 2}, "name": "ann"})
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT ':skip', \"a:b\", created::date -- :comment\nFROM users /* :block */ WHERE id IN ? AND name = ? OR nick = ?", bound)
+	assert.Equal(t, []any{[]int{1, 2}, "ann", "ann"}, args)
+
+	_, _, err = bindNamed("SELECT :missing", PostgreSQL, nil)
+	assert.ErrorContains(t, err, "missing value for parameter 'missing'")
+
+	// MySQL escapes quotes in literals with backslashes, other databases by doubling them.
+	bound, args, err = bindNamed(`SELECT 'it\'s :name', 'x' WHERE a = :id`, MySQL, map[string]any{"id": 1})
+	assert.NoError(t, err)
+	assert.Equal(t, `SELECT 'it\'s :name', 'x' WHERE a = ?`, bound)
+	assert.Equal(t, []any{1}, args)
+	bound, _, err = bindNamed(`SELECT 'C:\', :id`, PostgreSQL, map[string]any{"id": 1})
+	assert.NoError(t, err)
+	assert.Equal(t, `SELECT 'C:\', ?`, bound)
+}
+
+// TestExecQueryNamed verifies named execution and querying of cached queries.
+func TestExecQueryNamed(t *testing.T) {
+	g, _ := newTestRepository(t)
+	g.sqlQueries.Store("insert_user", "INSERT INTO repo_users (name, age) VALUES (:name, :age)")
+	g.sqlQueries.Store("users_by_age", "SELECT name FROM repo_users WHERE age >= :min ORDER BY name")
+
+	assert.NoError(t, g.ExecNamed("insert_user", map[string]any{"name": "ann", "age": 30}))
+	assert.NoError(t, g.ExecNamed("insert_user", map[string]any{"name": "bob", "age": 20}))
+
+	var names []string
+	assert.NoError(t, g.QueryNamed("users_by_age", &names, map[string]any{"min": 25}))
+	assert.Equal(t, []string{"ann"}, names)
+
+	err := g.ExecNamed("insert_user", map[string]any{"name": "cid"})
+	assert.ErrorContains(t, err, "failed to bind sql query 'insert_user'")
+	assert.Error(t, g.QueryNamed("unknown", &names, nil))
+}
@@ -0,0 +1,128 @@
+package gormext
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+// optimisticLockKey is the statement instance setting holding the version an update expects.
+const optimisticLockKey = "gormext:optimistic_lock"
+
+// ErrOptimisticLock is returned by updates of versioned records modified or deleted since they
+// were read, see Config.OptimisticLocking.
+var ErrOptimisticLock = errors.New("record was modified concurrently")
+
+// optimisticLock is the plugin of Config.OptimisticLocking, making the updates of versioned
+// records conditional on the version they were read at and incrementing it.
+type optimisticLock struct{}
+
+// Name returns the plugin name.
+func (l *optimisticLock) Name() string {
+	return "gormext:optimistic_lock"
+}
+
+// Initialize registers the callbacks checking and incrementing versions around updates.
+func (l *optimisticLock) Initialize(db *gorm.DB) error {
+	const name = "gormext:optimistic_lock"
+	callbacks := db.Callback().Update()
+	return errors.Join(
+		callbacks.Before("gorm:update").Register(name, l.check),
+		callbacks.After("gorm:update").Register(name+"_result", l.result),
+	)
+}
+
+// check adds the version condition to the update of a versioned record and increments its
+// version. Updates of several records, without primary key, are left alone.
+func (l *optimisticLock) check(db *gorm.DB) {
+	stmt := db.Statement
+	if db.Error != nil || stmt.Schema == nil {
+		return
+	}
+	field := versionField(stmt.Schema)
+	if field == nil {
+		return
+	}
+	entity := reflect.Indirect(stmt.ReflectValue)
+	if entity.Kind() != reflect.Struct {
+		return
+	}
+	if _, ok := primaryConditions(stmt, entity); !ok {
+		return
+	}
+
+	current, _ := field.ValueOf(stmt.Context, entity)
+	version := reflect.ValueOf(current)
+	var next any
+	if version.CanInt() {
+		next = version.Int() + 1
+	} else {
+		next = version.Uint() + 1
+	}
+
+	switch dest := stmt.Dest.(type) {
+	case map[string]any:
+		dest[field.DBName] = next
+	default:
+		// Updates from another struct than the model only write its non-zero fields.
+		if !sameEntity(stmt.Dest, stmt.Model) {
+			return
+		}
+		if err := field.Set(stmt.Context, entity, next); err != nil {
+			db.AddError(fmt.Errorf("failed to increment version of '%s': %w", stmt.Table, err))
+			return
+		}
+	}
+
+	stmt.AddClause(clause.Where{Exprs: []clause.Expression{
+		clause.Eq{Column: clause.Column{Table: clause.CurrentTable, Name: field.DBName}, Value: current},
+	}})
+	db.InstanceSet(optimisticLockKey, current)
+}
+
+// result fails the update that matched no row with ErrOptimisticLock, restoring the version of
+// the record when the update did not apply.
+func (l *optimisticLock) result(db *gorm.DB) {
+	current, ok := db.InstanceGet(optimisticLockKey)
+	if !ok || (db.Error == nil && db.RowsAffected > 0) {
+		return
+	}
+
+	stmt := db.Statement
+	if err := versionField(stmt.Schema).Set(stmt.Context, reflect.Indirect(stmt.ReflectValue), current); err != nil {
+		db.AddError(err)
+	}
+	if db.Error == nil {
+		db.AddError(fmt.Errorf("%w: '%s' at version %v", ErrOptimisticLock, stmt.Table, current))
+	}
+}
+
+// versionField returns the version field of table: the integer field tagged
+// `gormext:"version"`, or named Version.
+func versionField(table *schema.Schema) *schema.Field {
+	var version *schema.Field
+	for _, field := range table.Fields {
+		if _, ok := schema.ParseTagSetting(field.Tag.Get("gormext"), ";")["VERSION"]; ok {
+			version = field
+			break
+		}
+		if field.Name == "Version" {
+			version = field
+		}
+	}
+	if version == nil || version.FieldType.Kind() == reflect.Pointer ||
+		(version.DataType != schema.Int && version.DataType != schema.Uint) {
+		return nil
+	}
+	return version
+}
+
+// sameEntity reports whether a and b point to the same value.
+func sameEntity(a, b any) bool {
+	va, vb := reflect.ValueOf(a), reflect.ValueOf(b)
+	return va.Kind() == reflect.Pointer && vb.Kind() == reflect.Pointer && va.Pointer() == vb.Pointer()
+}
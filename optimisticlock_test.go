@@ -0,0 +1,51 @@
+package gormext
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// versionedDoc is a model with a version column.
+type versionedDoc struct {
+	ID      uint
+	Title   string
+	Version uint
+}
+
+// TestOptimisticLocking verifies that updates increment the version and fail with
+// ErrOptimisticLock on stale records.
+func TestOptimisticLocking(t *testing.T) {
+	g, err := NewGorm(newTestDatabaseContext(), nil, nil, nil, Config{OptimisticLocking: true})
+	assert.NoError(t, err)
+	repo := g.GetDB()
+	assert.NoError(t, g.Migrate(&versionedDoc{}))
+
+	doc := versionedDoc{Title: "draft"}
+	assert.NoError(t, repo.Create(&doc))
+	stale := doc
+
+	doc.Title = "final"
+	assert.NoError(t, repo.Update(&doc))
+	assert.Equal(t, uint(1), doc.Version)
+
+	stale.Title = "lost"
+	err = repo.Update(&stale)
+	assert.ErrorIs(t, err, ErrOptimisticLock)
+	assert.Equal(t, uint(0), stale.Version, "the version of a failed update is restored")
+
+	var stored versionedDoc
+	assert.NoError(t, repo.FirstByID(doc.ID, &stored))
+	assert.Equal(t, versionedDoc{ID: doc.ID, Title: "final", Version: 1}, stored)
+
+	conn := g.connection
+	assert.NoError(t, conn.Model(&stored).Updates(map[string]any{"title": "edited"}).Error)
+	assert.NoError(t, conn.First(&stored, doc.ID).Error)
+	assert.Equal(t, uint(2), stored.Version)
+	assert.ErrorIs(t, conn.Model(&stale).Updates(map[string]any{"title": "lost"}).Error, ErrOptimisticLock)
+
+	// Updates of several records are not versioned.
+	assert.NoError(t, conn.Model(&versionedDoc{}).Where("1 = 1").Update("title", "bulk").Error)
+	assert.NoError(t, conn.First(&stored, doc.ID).Error)
+	assert.Equal(t, versionedDoc{ID: doc.ID, Title: "bulk", Version: 2}, stored)
+}
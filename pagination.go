@@ -0,0 +1,190 @@
+package gormext
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+const (
+	// Query parameter names used when building pagination links.
+	pageParam     = "page"
+	pageSizeParam = "page_size"
+	cursorParam   = "cursor"
+)
+
+type (
+	// PageResult holds a page of query results and the metadata needed to navigate between pages.
+	// Offset pagination uses Page and PageSize; cursor pagination sets Cursor, NextCursor and
+	// PrevCursor.
+	PageResult[T any] struct {
+		Items      []T
+		Total      int64
+		Page       int
+		PageSize   int
+		Cursor     bool // Whether the page uses cursor pagination, even with no other page.
+		NextCursor string
+		PrevCursor string
+	}
+
+	// PageEnvelope is the standard JSON envelope for paginated API responses.
+	PageEnvelope[T any] struct {
+		Data  []T       `json:"data"`
+		Meta  PageMeta  `json:"meta"`
+		Links PageLinks `json:"links"`
+	}
+
+	// PageMeta describes the position of a page within the full result set.
+	PageMeta struct {
+		Total      int64  `json:"total"`
+		Page       int    `json:"page,omitempty"`
+		PageSize   int    `json:"page_size"`
+		TotalPages int    `json:"total_pages,omitempty"`
+		NextCursor string `json:"next_cursor,omitempty"`
+		PrevCursor string `json:"prev_cursor,omitempty"`
+	}
+
+	// PageLinks holds the navigation links of a page. Empty links are omitted.
+	PageLinks struct {
+		Self  string `json:"self"`
+		First string `json:"first,omitempty"`
+		Prev  string `json:"prev,omitempty"`
+		Next  string `json:"next,omitempty"`
+		Last  string `json:"last,omitempty"`
+	}
+)
+
+// NewPageResult creates an offset-based PageResult. Page numbers start at 1.
+func NewPageResult[T any](items []T, total int64, page, pageSize int) PageResult[T] {
+	if page < 1 {
+		page = 1
+	}
+	return PageResult[T]{Items: items, Total: total, Page: page, PageSize: pageSize}
+}
+
+// NewCursorPageResult creates a cursor-based PageResult. The first page has no previous
+// cursor and the last one no next cursor.
+func NewCursorPageResult[T any](items []T, pageSize int, next, prev string) PageResult[T] {
+	return PageResult[T]{Items: items, PageSize: pageSize, Cursor: true, NextCursor: next, PrevCursor: prev}
+}
+
+// IsCursor reports whether the result uses cursor pagination.
+func (p PageResult[T]) IsCursor() bool {
+	return p.Cursor || p.NextCursor != "" || p.PrevCursor != ""
+}
+
+// TotalPages returns the number of pages available for the page size.
+func (p PageResult[T]) TotalPages() int {
+	if p.PageSize <= 0 {
+		return 0
+	}
+	return int((p.Total + int64(p.PageSize) - 1) / int64(p.PageSize))
+}
+
+// HasNext reports whether a page exists after the current one.
+func (p PageResult[T]) HasNext() bool {
+	if p.IsCursor() {
+		return p.NextCursor != ""
+	}
+	return p.Page < p.TotalPages()
+}
+
+// HasPrev reports whether a page exists before the current one.
+func (p PageResult[T]) HasPrev() bool {
+	if p.IsCursor() {
+		return p.PrevCursor != ""
+	}
+	return p.Page > 1
+}
+
+// Links builds the navigation links of the page relative to base, preserving its other query parameters.
+func (p PageResult[T]) Links(base *url.URL) PageLinks {
+	if p.IsCursor() {
+		links := PageLinks{Self: base.String()}
+		if p.NextCursor != "" {
+			links.Next = p.cursorURL(base, p.NextCursor)
+		}
+		if p.PrevCursor != "" {
+			links.Prev = p.cursorURL(base, p.PrevCursor)
+		}
+		return links
+	}
+
+	links := PageLinks{
+		Self:  p.pageURL(base, p.Page),
+		First: p.pageURL(base, 1),
+	}
+	if last := p.TotalPages(); last > 0 {
+		links.Last = p.pageURL(base, last)
+	}
+	if p.HasPrev() {
+		links.Prev = p.pageURL(base, p.Page-1)
+	}
+	if p.HasNext() {
+		links.Next = p.pageURL(base, p.Page+1)
+	}
+	return links
+}
+
+// Envelope wraps the page in the standard API envelope with links relative to base.
+func (p PageResult[T]) Envelope(base *url.URL) PageEnvelope[T] {
+	items := p.Items
+	if items == nil {
+		items = []T{}
+	}
+
+	meta := PageMeta{
+		Total:      p.Total,
+		PageSize:   p.PageSize,
+		NextCursor: p.NextCursor,
+		PrevCursor: p.PrevCursor,
+	}
+	if !p.IsCursor() {
+		meta.Page = p.Page
+		meta.TotalPages = p.TotalPages()
+	}
+
+	return PageEnvelope[T]{Data: items, Meta: meta, Links: p.Links(base)}
+}
+
+// LinkHeader renders the navigation links as an RFC 5988 Link header value.
+func (p PageResult[T]) LinkHeader(base *url.URL) string {
+	links := p.Links(base)
+
+	var parts []string
+	for _, link := range []struct{ rel, href string }{
+		{"first", links.First},
+		{"prev", links.Prev},
+		{"next", links.Next},
+		{"last", links.Last},
+	} {
+		if link.href != "" {
+			parts = append(parts, fmt.Sprintf("<%s>; rel=\"%s\"", link.href, link.rel))
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// pageURL returns base with the page and page size query parameters set.
+func (p PageResult[T]) pageURL(base *url.URL, page int) string {
+	u := *base
+	query := u.Query()
+	query.Set(pageParam, strconv.Itoa(page))
+	query.Set(pageSizeParam, strconv.Itoa(p.PageSize))
+	u.RawQuery = query.Encode()
+	return u.String()
+}
+
+// cursorURL returns base with the cursor query parameter set and the page parameter removed.
+func (p PageResult[T]) cursorURL(base *url.URL, cursor string) string {
+	u := *base
+	query := u.Query()
+	query.Del(pageParam)
+	query.Set(cursorParam, cursor)
+	if p.PageSize > 0 {
+		query.Set(pageSizeParam, strconv.Itoa(p.PageSize))
+	}
+	u.RawQuery = query.Encode()
+	return u.String()
+}
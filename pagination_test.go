@@ -0,0 +1,59 @@
+package gormext
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestPageResultEnvelope verifies the envelope metadata and links of an offset page.
+func TestPageResultEnvelope(t *testing.T) {
+	base, _ := url.Parse("https://api.example.com/users?sort=name")
+	page := NewPageResult([]string{"c", "d"}, 5, 2, 2)
+
+	env := page.Envelope(base)
+	assert.Equal(t, []string{"c", "d"}, env.Data)
+	assert.Equal(t, PageMeta{Total: 5, Page: 2, PageSize: 2, TotalPages: 3}, env.Meta)
+	assert.Equal(t, "https://api.example.com/users?page=1&page_size=2&sort=name", env.Links.Prev)
+	assert.Equal(t, "https://api.example.com/users?page=3&page_size=2&sort=name", env.Links.Next)
+	assert.Equal(t, "https://api.example.com/users?page=3&page_size=2&sort=name", env.Links.Last)
+}
+
+// TestPageResultLinkHeader verifies the RFC 5988 Link header omits unavailable relations.
+func TestPageResultLinkHeader(t *testing.T) {
+	base, _ := url.Parse("/users")
+	page := NewPageResult([]int{1, 2}, 4, 1, 2)
+
+	assert.Equal(t,
+		`</users?page=1&page_size=2>; rel="first", </users?page=2&page_size=2>; rel="next", </users?page=2&page_size=2>; rel="last"`,
+		page.LinkHeader(base))
+}
+
+// TestPageResultCursorLinks verifies cursor pages link by cursor instead of page number.
+func TestPageResultCursorLinks(t *testing.T) {
+	base, _ := url.Parse("/events?page=4")
+	page := PageResult[int]{PageSize: 10, NextCursor: "abc"}
+
+	env := page.Envelope(base)
+	assert.Equal(t, []int{}, env.Data, "Empty pages should render an empty array")
+	assert.Zero(t, env.Meta.Page)
+	assert.Equal(t, "/events?cursor=abc&page_size=10", env.Links.Next)
+	assert.Empty(t, env.Links.Prev)
+	assert.Equal(t, `</events?cursor=abc&page_size=10>; rel="next"`, page.LinkHeader(base))
+}
+
+// TestPageResultSingleCursorPage verifies that a cursor page without other pages keeps cursor
+// pagination rather than linking to offset pages.
+func TestPageResultSingleCursorPage(t *testing.T) {
+	base, _ := url.Parse("/events")
+	page := NewCursorPageResult([]int{1, 2}, 10, "", "")
+
+	assert.True(t, page.IsCursor())
+	assert.False(t, page.HasNext())
+	assert.False(t, page.HasPrev())
+	env := page.Envelope(base)
+	assert.Equal(t, PageMeta{PageSize: 10}, env.Meta)
+	assert.Equal(t, PageLinks{Self: "/events"}, env.Links)
+	assert.Empty(t, page.LinkHeader(base))
+}
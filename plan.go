@@ -0,0 +1,221 @@
+package gormext
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// Kinds of planned schema changes.
+const (
+	ChangeCreateTable      ChangeKind = "create_table"
+	ChangeAddColumn        ChangeKind = "add_column"
+	ChangeAlterColumn      ChangeKind = "alter_column"
+	ChangeCreateConstraint ChangeKind = "create_constraint"
+	ChangeCreateIndex      ChangeKind = "create_index"
+	ChangeMigration        ChangeKind = "migration"
+)
+
+type (
+	// ChangeKind is the kind of a planned schema change.
+	ChangeKind string
+
+	// PlannedChange is a schema change that AutoMigrate or a pending versioned migration would make.
+	PlannedChange struct {
+		Kind  ChangeKind // Kind of change.
+		Table string     // Table changed, empty for versioned migrations.
+		Name  string     // Column, constraint, index or migration name.
+		SQL   []string   // Statements that would run, empty when they cannot be rendered up front.
+
+		// Destructive tells why the change may lose data, such as a narrowed column type, and is
+		// empty for safe changes. Migrate refuses destructive changes unless AllowDestructive is set.
+		Destructive string
+	}
+
+	// captureLogger records the SQL of the statements traced through it.
+	captureLogger struct {
+		logger.Interface
+		statements []string
+	}
+)
+
+// MigratePlan reports the changes Migrate would make for models, then the pending versioned
+// migrations, without applying anything. The SQL of column changes SQLite applies by
+// rebuilding the table, and of Go migrations, cannot be rendered and is left empty.
+func (g *Gorm) MigratePlan(models ...any) ([]PlannedChange, error) {
+	conn := g.connection.WithContext(WithMaintenanceBypass(context.Background()))
+	changes, err := g.planModels(conn, models)
+	if err != nil {
+		return nil, err
+	}
+
+	pending, err := g.pendingMigrations(conn)
+	if err != nil {
+		return nil, err
+	}
+	for _, m := range pending {
+		var statements []string
+		if m.script != "" {
+			statements = splitStatements(m.script, g.databaseCtx.driver)
+		}
+		changes = append(changes, PlannedChange{Kind: ChangeMigration, Name: fmt.Sprintf("%d_%s", m.version, m.name), SQL: statements})
+	}
+	return changes, nil
+}
+
+// planModels returns the changes AutoMigrate would make for models.
+func (g *Gorm) planModels(conn *gorm.DB, models []any) ([]PlannedChange, error) {
+	query := conn.Migrator()
+
+	var changes []PlannedChange
+	for _, model := range models {
+		table, err := g.parseModel(model)
+		if err != nil {
+			return nil, err
+		}
+
+		// plan records the change made by ddl. check, when set, returns why it may lose data.
+		plan := func(kind ChangeKind, name string, ddl func(m gorm.Migrator) error, check func(statements []string) (string, error)) error {
+			statements, rendered, err := g.dryRunDDL(conn, ddl)
+			if err != nil {
+				return fmt.Errorf("failed to plan %s '%s' of table '%s': %w", kind, name, table.Table, err)
+			}
+			// Columns already matching their field produce no statement.
+			if kind == ChangeAlterColumn && rendered && len(statements) == 0 {
+				return nil
+			}
+
+			change := PlannedChange{Kind: kind, Table: table.Table, Name: name, SQL: statements}
+			if check != nil {
+				if change.Destructive, err = check(statements); err != nil {
+					return fmt.Errorf("failed to plan %s '%s' of table '%s': %w", kind, name, table.Table, err)
+				}
+			}
+			changes = append(changes, change)
+			return nil
+		}
+
+		if !query.HasTable(model) {
+			if err := plan(ChangeCreateTable, table.Table, func(m gorm.Migrator) error { return m.CreateTable(model) }, nil); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		columnTypes, err := query.ColumnTypes(model)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read columns of table '%s': %w", table.Table, err)
+		}
+		columns := make(map[string]gorm.ColumnType, len(columnTypes))
+		for _, columnType := range columnTypes {
+			columns[columnType.Name()] = columnType
+		}
+
+		for _, name := range table.DBNames {
+			columnType, ok := columns[name]
+			if !ok {
+				err = plan(ChangeAddColumn, name, func(m gorm.Migrator) error { return m.AddColumn(model, name) }, nil)
+			} else {
+				field := table.FieldsByDBName[name]
+				err = plan(ChangeAlterColumn, name, func(m gorm.Migrator) error { return m.MigrateColumn(model, field, columnType) },
+					func(statements []string) (string, error) {
+						return g.destructiveChange(conn, table, field, columnType, statements)
+					})
+			}
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		if !conn.DisableForeignKeyConstraintWhenMigrating && !conn.IgnoreRelationshipsWhenMigrating {
+			for _, rel := range table.Relationships.Relations {
+				constraint := rel.ParseConstraint()
+				if rel.Field.IgnoreMigration || constraint == nil || constraint.Schema != table || query.HasConstraint(model, constraint.Name) {
+					continue
+				}
+				if err := plan(ChangeCreateConstraint, constraint.Name, func(m gorm.Migrator) error { return m.CreateConstraint(model, constraint.Name) }, nil); err != nil {
+					return nil, err
+				}
+			}
+		}
+
+		for _, check := range table.ParseCheckConstraints() {
+			if query.HasConstraint(model, check.Name) {
+				continue
+			}
+			if err := plan(ChangeCreateConstraint, check.Name, func(m gorm.Migrator) error { return m.CreateConstraint(model, check.Name) }, nil); err != nil {
+				return nil, err
+			}
+		}
+
+		for _, index := range table.ParseIndexes() {
+			if query.HasIndex(model, index.Name) {
+				continue
+			}
+			if err := plan(ChangeCreateIndex, index.Name, func(m gorm.Migrator) error { return m.CreateIndex(model, index.Name) }, nil); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return changes, nil
+}
+
+// dryRunDDL runs ddl on a dry run migrator and returns the statements it would execute.
+// rendered is false when the statements cannot be known without running them.
+func (g *Gorm) dryRunDDL(conn *gorm.DB, ddl func(m gorm.Migrator) error) (statements []string, rendered bool, err error) {
+	capture := &captureLogger{Interface: logger.Discard}
+	dry := conn.Session(&gorm.Session{DryRun: true, Logger: capture})
+
+	// Dialects reading the schema while building DDL, such as SQLite table rebuilds, fail to
+	// run dry; the change is then reported without its SQL.
+	defer func() {
+		if recover() != nil {
+			statements, rendered, err = nil, false, nil
+		}
+	}()
+
+	if err := ddl(dry.Migrator()); err != nil && !errors.Is(err, gorm.ErrDryRunModeUnsupported) {
+		return nil, true, err
+	}
+	return capture.statements, true, nil
+}
+
+// pendingMigrations returns the versioned migrations not applied yet, in version order.
+func (g *Gorm) pendingMigrations(conn *gorm.DB) ([]*migration, error) {
+	if len(g.migrations) == 0 {
+		return nil, nil
+	}
+
+	applied, err := g.appliedMigrations(conn)
+	if err != nil {
+		return nil, err
+	}
+	isApplied := make(map[uint64]bool, len(applied))
+	for _, record := range applied {
+		isApplied[record.Version] = true
+	}
+
+	var pending []*migration
+	for _, m := range g.migrations {
+		if !isApplied[m.version] {
+			pending = append(pending, m)
+		}
+	}
+	return pending, nil
+}
+
+// LogMode returns the logger itself, which records statements at any level.
+func (l *captureLogger) LogMode(logger.LogLevel) logger.Interface {
+	return l
+}
+
+// Trace records the SQL of a statement.
+func (l *captureLogger) Trace(_ context.Context, _ time.Time, fc func() (string, int64), _ error) {
+	if sql, _ := fc(); sql != "" {
+		l.statements = append(l.statements, sql)
+	}
+}
@@ -0,0 +1,86 @@
+package gormext
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type (
+	planUserV1 struct {
+		ID   uint
+		Name string
+	}
+
+	planUserV2 struct {
+		ID    uint
+		Name  string
+		Email string `gorm:"index"`
+	}
+
+	planUserV3 struct {
+		ID   uint
+		Name int64 `gorm:"not null"`
+	}
+)
+
+func (planUserV1) TableName() string { return "plan_users" }
+func (planUserV2) TableName() string { return "plan_users" }
+func (planUserV3) TableName() string { return "plan_users" }
+
+// TestMigratePlan verifies that MigratePlan reports schema changes and pending migrations
+// without applying them.
+func TestMigratePlan(t *testing.T) {
+	dir := t.TempDir()
+	writeSQLFiles(t, dir, map[string]string{
+		"20240101000000_create_audit.up.sql": "CREATE TABLE audit (id INTEGER PRIMARY KEY);\nCREATE INDEX idx_audit ON audit (id);",
+	})
+	g := newMigrationGorm(t, dir)
+	migrator := g.connection.Migrator()
+
+	changes, err := g.MigratePlan(&planUserV1{})
+	assert.NoError(t, err)
+	if assert.Len(t, changes, 2) {
+		assert.Equal(t, ChangeCreateTable, changes[0].Kind)
+		assert.Equal(t, "plan_users", changes[0].Table)
+		assert.Len(t, changes[0].SQL, 1)
+		assert.Contains(t, changes[0].SQL[0], "CREATE TABLE `plan_users`")
+
+		assert.Equal(t, ChangeMigration, changes[1].Kind)
+		assert.Equal(t, "20240101000000_create_audit", changes[1].Name)
+		assert.Equal(t, []string{"CREATE TABLE audit (id INTEGER PRIMARY KEY)", "CREATE INDEX idx_audit ON audit (id)"}, changes[1].SQL)
+	}
+	assert.False(t, migrator.HasTable("plan_users"), "The plan should not create tables")
+
+	assert.NoError(t, g.Migrate(&planUserV1{}))
+	assert.NoError(t, g.MigrateUp(context.Background()))
+
+	changes, err = g.MigratePlan(&planUserV1{})
+	assert.NoError(t, err)
+	assert.Empty(t, changes, "An up to date schema should have no changes")
+
+	changes, err = g.MigratePlan(&planUserV2{})
+	assert.NoError(t, err)
+	if assert.Len(t, changes, 2) {
+		assert.Equal(t, PlannedChange{
+			Kind:  ChangeAddColumn,
+			Table: "plan_users",
+			Name:  "email",
+			SQL:   []string{"ALTER TABLE `plan_users` ADD `email` text"},
+		}, changes[0])
+		assert.Equal(t, ChangeCreateIndex, changes[1].Kind)
+		assert.Equal(t, "idx_plan_users_email", changes[1].Name)
+	}
+	assert.False(t, migrator.HasColumn("plan_users", "email"), "The plan should not add columns")
+
+	// SQLite alters columns by rebuilding the table, which cannot be rendered up front.
+	changes, err = g.MigratePlan(&planUserV3{})
+	assert.NoError(t, err)
+	assert.Equal(t, []PlannedChange{{
+		Kind:        ChangeAlterColumn,
+		Table:       "plan_users",
+		Name:        "name",
+		Destructive: "narrows type text to integer",
+	}}, changes)
+}
@@ -0,0 +1,143 @@
+package gormext
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"gorm.io/driver/mysql"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// Profile is a preset of operational defaults for a kind of workload, selected with
+// Config.Profile. Zero values leave the corresponding setting untouched.
+type Profile struct {
+	Name            string          // Profile name, for diagnostics.
+	MaxOpenConns    int             // Maximum open connections.
+	MaxIdleConns    int             // Maximum idle connections kept in the pool.
+	ConnMaxLifetime time.Duration   // Maximum lifetime of a connection.
+	ConnMaxIdleTime time.Duration   // Maximum idle time before a connection is closed.
+	PrepareStmt     bool            // Cache prepared statements.
+	LogLevel        logger.LogLevel // Log level of the default logger, used when Config.Logger is nil.
+	SlowThreshold   time.Duration   // Slow query threshold of the default logger.
+
+	// LazyConnect skips connecting while opening, so connections are only made on first use.
+	// On MySQL it also skips the server version probe.
+	LazyConnect bool
+
+	// ConnectRetries retries statements failing to establish a connection, such as on a
+	// cold start, waiting ConnectRetryDelay before the first retry and doubling it after.
+	ConnectRetries    int
+	ConnectRetryDelay time.Duration
+
+	// SimpleProtocol makes Postgres use the simple query protocol without server-side prepared
+	// statements, as needed behind transaction-pooling proxies such as RDS Proxy or PgBouncer.
+	SimpleProtocol bool
+}
+
+var (
+	// ProfileWebService suits long-running services handling many short requests: a
+	// moderate pool kept warm, prepared statements, and only slow queries and errors logged.
+	ProfileWebService = Profile{
+		Name:            "web-service",
+		MaxOpenConns:    25,
+		MaxIdleConns:    25,
+		ConnMaxLifetime: 30 * time.Minute,
+		ConnMaxIdleTime: 5 * time.Minute,
+		PrepareStmt:     true,
+		LogLevel:        logger.Warn,
+		SlowThreshold:   200 * time.Millisecond,
+	}
+
+	// ProfileBatchWorker suits jobs running few long statements: a small pool, long-lived
+	// connections, and a generous slow query threshold.
+	ProfileBatchWorker = Profile{
+		Name:            "batch-worker",
+		MaxOpenConns:    4,
+		MaxIdleConns:    2,
+		ConnMaxLifetime: 2 * time.Hour,
+		ConnMaxIdleTime: 30 * time.Minute,
+		PrepareStmt:     true,
+		LogLevel:        logger.Warn,
+		SlowThreshold:   10 * time.Second,
+	}
+
+	// ProfileServerless suits scale-to-zero environments such as Lambda or Cloud Run: a
+	// minimal pool whose idle connections are closed quickly, connections made on demand with
+	// fast retries, and no prepared statements, which connection proxies such as RDS Proxy or
+	// PgBouncer may not support.
+	ProfileServerless = Profile{
+		Name:              "serverless",
+		MaxOpenConns:      2,
+		MaxIdleConns:      1,
+		ConnMaxLifetime:   5 * time.Minute,
+		ConnMaxIdleTime:   10 * time.Second,
+		PrepareStmt:       false,
+		LogLevel:          logger.Error,
+		SlowThreshold:     time.Second,
+		LazyConnect:       true,
+		ConnectRetries:    3,
+		ConnectRetryDelay: 50 * time.Millisecond,
+		SimpleProtocol:    true,
+	}
+)
+
+// dialector returns the dialector the profile needs for databaseCtx, or nil when the default
+// dialector fits.
+func (p *Profile) dialector(databaseCtx DatabaseContext) gorm.Dialector {
+	switch {
+	case databaseCtx.driver == PostgreSQL && p.SimpleProtocol:
+		return postgres.New(postgres.Config{DSN: databaseCtx.dsn, PreferSimpleProtocol: true})
+	case databaseCtx.driver == MySQL && p.LazyConnect:
+		return mysql.New(mysql.Config{DSN: databaseCtx.dsn, SkipInitializeWithVersion: true})
+	}
+	return nil
+}
+
+// configure applies the GORM settings of the profile to cfg before the connection is opened.
+func (p *Profile) configure(cfg *gorm.Config) {
+	cfg.PrepareStmt = cfg.PrepareStmt || p.PrepareStmt
+	cfg.DisableAutomaticPing = cfg.DisableAutomaticPing || p.LazyConnect
+	if cfg.Logger == nil && p.LogLevel != 0 {
+		cfg.Logger = logger.New(log.New(os.Stdout, "\r\n", log.LstdFlags), logger.Config{
+			SlowThreshold:             p.SlowThreshold,
+			LogLevel:                  p.LogLevel,
+			IgnoreRecordNotFoundError: true,
+		})
+	}
+}
+
+// apply applies the pool and retry settings of the profile to the opened conn.
+func (p *Profile) apply(conn *gorm.DB) error {
+	sqlDB, err := conn.DB()
+	if err != nil {
+		return fmt.Errorf("failed to apply profile '%s': %w", p.Name, err)
+	}
+
+	if p.MaxOpenConns > 0 {
+		sqlDB.SetMaxOpenConns(p.MaxOpenConns)
+	}
+	if p.MaxIdleConns > 0 {
+		sqlDB.SetMaxIdleConns(p.MaxIdleConns)
+	}
+	if p.ConnMaxLifetime > 0 {
+		sqlDB.SetConnMaxLifetime(p.ConnMaxLifetime)
+	}
+	if p.ConnMaxIdleTime > 0 {
+		sqlDB.SetConnMaxIdleTime(p.ConnMaxIdleTime)
+	}
+
+	if p.ConnectRetries > 0 {
+		pool := &retryConnPool{ConnPool: sqlDB, db: sqlDB, retries: p.ConnectRetries, delay: p.ConnectRetryDelay}
+		if prepared, ok := conn.ConnPool.(*gorm.PreparedStmtDB); ok {
+			prepared.ConnPool = pool
+		} else {
+			conn.ConnPool = pool
+		}
+		conn.Statement.ConnPool = conn.ConnPool
+	}
+	return nil
+}
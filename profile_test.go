@@ -0,0 +1,23 @@
+package gormext
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestProfileDefaults verifies a profile configures the pool and prepared statements.
+func TestProfileDefaults(t *testing.T) {
+	g, err := NewGorm(newTestDatabaseContext(), nil, []string{}, map[string]string{}, Config{Profile: &ProfileBatchWorker})
+	assert.NoError(t, err, "Unexpected error from NewGorm")
+
+	sqlDB, err := g.connection.DB()
+	assert.NoError(t, err)
+	assert.Equal(t, ProfileBatchWorker.MaxOpenConns, sqlDB.Stats().MaxOpenConnections)
+	assert.True(t, g.connection.Config.PrepareStmt)
+	assert.NotNil(t, g.connection.Config.Logger)
+
+	g, err = NewGorm(newTestDatabaseContext(), nil, []string{}, map[string]string{}, Config{Profile: &ProfileServerless})
+	assert.NoError(t, err, "Unexpected error from NewGorm")
+	assert.False(t, g.connection.Config.PrepareStmt)
+}
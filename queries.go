@@ -0,0 +1,168 @@
+package gormext
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+const (
+	// sqlFileExt is the extension of cached SQL query files.
+	sqlFileExt = ".sql"
+
+	// runtimeQuerySource is the source recorded for queries registered with RegisterQuery.
+	runtimeQuerySource = "registered at runtime"
+
+	// queryNameKey is the statement setting holding the name of the cached query being run.
+	queryNameKey = "gormext:query_name"
+)
+
+// QueryName returns the name of the cached query a statement runs, or an empty string for
+// other statements. It is meant for callbacks and plugins, such as to label metrics.
+func QueryName(db *gorm.DB) string {
+	name, _ := db.Get(queryNameKey)
+	s, _ := name.(string)
+	return s
+}
+
+// queryConn returns a connection with ctx to run the cached query name, labeled for QueryName.
+func (g *Gorm) queryConn(ctx context.Context, name string) *gorm.DB {
+	return g.connection.WithContext(ctx).Set(queryNameKey, name)
+}
+
+// RegisterQuery caches query under name, replacing any query already cached with that name.
+func (g *Gorm) RegisterQuery(name, query string) error {
+	if name == "" || strings.TrimSpace(query) == "" {
+		return fmt.Errorf("invalid sql query '%s': name and query are required", name)
+	}
+
+	g.querySources.Store(name, runtimeQuerySource)
+	g.sqlQueries.Store(name, query)
+	return nil
+}
+
+// ListQueries returns the names of the cached queries, sorted.
+func (g *Gorm) ListQueries() []string {
+	var names []string
+	g.sqlQueries.Range(func(key, _ any) bool {
+		names = append(names, key.(string))
+		return true
+	})
+	sort.Strings(names)
+	return names
+}
+
+// RemoveQuery removes the cached query name, if any.
+func (g *Gorm) RemoveQuery(name string) {
+	g.sqlQueries.Delete(name)
+	g.querySources.Delete(name)
+	g.templates.Delete(name)
+}
+
+// cacheSQLQueriesFS reads and stores the .sql files found under root in fsys, recursively.
+func (g *Gorm) cacheSQLQueriesFS(fsys fs.FS, root string) error {
+	if root == "" {
+		root = "."
+	}
+	return walkSQLFiles(fsys, root, root, g.cacheSQLQueryFile)
+}
+
+// cacheSQLQueriesDir reads and stores the .sql files of a directory (recursively) or matching a glob pattern.
+func (g *Gorm) cacheSQLQueriesDir(dir string) error {
+	dir = filepath.ToSlash(filepath.Clean(dir))
+	if !hasGlobMeta(dir) {
+		return walkSQLFiles(os.DirFS(dir), ".", dir, g.cacheSQLQueryFile)
+	}
+
+	// Names are relative to the deepest directory of the pattern without wildcards.
+	base, pattern := splitGlob(dir)
+	fsys := os.DirFS(base)
+	matches, err := fs.Glob(fsys, pattern)
+	if err != nil {
+		return fmt.Errorf("invalid SQL glob pattern '%s': %w", dir, err)
+	}
+
+	for _, match := range matches {
+		if !isSQLFile(match) {
+			continue
+		}
+		if err := g.cacheSQLQueryFile(fsys, match, queryName(match), path.Join(base, match)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// walkSQLFiles walks root in fsys and calls fn for every .sql file with its query name.
+// origin replaces root in the source paths given to fn and in errors.
+func walkSQLFiles(fsys fs.FS, root, origin string, fn func(fsys fs.FS, filePath, name, source string) error) error {
+	return fs.WalkDir(fsys, root, func(filePath string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return fmt.Errorf("failed to read SQL directory '%s': %w", path.Join(origin, filePath), err)
+		}
+		if entry.IsDir() || !isSQLFile(filePath) {
+			return nil
+		}
+
+		rel := filePath
+		if root != "." {
+			rel = strings.TrimPrefix(filePath, root+"/")
+		}
+		return fn(fsys, filePath, queryName(rel), path.Join(origin, rel))
+	})
+}
+
+// cacheSQLQueryFile reads a file of fsys and stores it under name, using source in error messages.
+func (g *Gorm) cacheSQLQueryFile(fsys fs.FS, filePath, name, source string) error {
+	content, err := fs.ReadFile(fsys, filePath)
+	if err != nil {
+		return fmt.Errorf("failed to read SQL file '%s': %w", source, err)
+	}
+	if content, err = g.decodeFile(source, content); err != nil {
+		return err
+	}
+	return g.storeQuery(name, string(content), source)
+}
+
+// storeQuery caches a query, failing if another file already defined the same name.
+func (g *Gorm) storeQuery(name, query, source string) error {
+	if previous, loaded := g.querySources.LoadOrStore(name, source); loaded && previous != source {
+		return fmt.Errorf("sql query '%s' is defined by both '%s' and '%s'", name, previous, source)
+	}
+	g.sqlQueries.Store(name, query)
+	return nil
+}
+
+// queryName derives a query name from a slash-separated relative file path,
+// e.g. users/find_active.sql (or users/find_active.sql.gz) becomes users.find_active.
+func queryName(rel string) string {
+	return strings.ReplaceAll(strings.TrimSuffix(trimEncodingExt(rel), sqlFileExt), "/", ".")
+}
+
+// isSQLFile reports whether p is a .sql file, possibly compressed or encrypted.
+func isSQLFile(p string) bool {
+	return path.Ext(trimEncodingExt(p)) == sqlFileExt
+}
+
+// splitGlob splits a slash-separated glob pattern into its deepest directory without
+// wildcards and the pattern relative to it.
+func splitGlob(pattern string) (string, string) {
+	if i := strings.IndexAny(pattern, "*?["); i >= 0 {
+		if j := strings.LastIndex(pattern[:i], "/"); j >= 0 {
+			return pattern[:j], pattern[j+1:]
+		}
+	}
+	return ".", pattern
+}
+
+// hasGlobMeta reports whether pattern contains glob wildcards.
+func hasGlobMeta(pattern string) bool {
+	return strings.ContainsAny(pattern, "*?[")
+}
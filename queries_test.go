@@ -0,0 +1,122 @@
+package gormext
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// writeSQLFiles creates the given files (relative path to content) under dir.
+func writeSQLFiles(t *testing.T, dir string, files map[string]string) {
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		assert.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		assert.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	}
+}
+
+// TestQueryDirsDiscovery verifies queries are discovered recursively and named after their relative path.
+func TestQueryDirsDiscovery(t *testing.T) {
+	dir := t.TempDir()
+	writeSQLFiles(t, dir, map[string]string{
+		"users/find_active.sql":    "SELECT * FROM users WHERE active",
+		"reports/daily/totals.sql": "SELECT 1",
+		"reports/daily/notes.txt":  "ignored",
+		"ping.sql":                 "SELECT 2",
+	})
+
+	g, err := NewGorm(newTestDatabaseContext(), dummyRepository, []string{}, map[string]string{}, Config{QueryDirs: []string{dir}})
+	assert.NoError(t, err, "Unexpected error from NewGorm")
+
+	for name, want := range map[string]string{
+		"users.find_active":    "SELECT * FROM users WHERE active",
+		"reports.daily.totals": "SELECT 1",
+		"ping":                 "SELECT 2",
+	} {
+		query, err := g.GetQuery(name)
+		assert.NoError(t, err, "Query %s not found", name)
+		assert.Equal(t, want, query)
+	}
+}
+
+// TestQueryDirsGlob verifies glob patterns name queries relative to their fixed directory.
+func TestQueryDirsGlob(t *testing.T) {
+	dir := t.TempDir()
+	writeSQLFiles(t, dir, map[string]string{
+		"reports/a.sql": "SELECT 'a'",
+		"reports/b.sql": "SELECT 'b'",
+		"users/c.sql":   "SELECT 'c'",
+	})
+
+	g, err := NewGorm(newTestDatabaseContext(), dummyRepository, []string{}, map[string]string{}, Config{
+		QueryDirs: []string{filepath.Join(dir, "reports", "*.sql")},
+	})
+	assert.NoError(t, err, "Unexpected error from NewGorm")
+
+	_, err = g.GetQuery("a")
+	assert.NoError(t, err)
+	_, err = g.GetQuery("c")
+	assert.Error(t, err, "Files outside the pattern should not be cached")
+}
+
+// TestQueryDirsCollision verifies two files resolving to the same name are rejected.
+func TestQueryDirsCollision(t *testing.T) {
+	dir := t.TempDir()
+	writeSQLFiles(t, dir, map[string]string{
+		"users/find.sql": "SELECT 1",
+		"users.find.sql": "SELECT 2",
+	})
+
+	_, err := NewGorm(newTestDatabaseContext(), dummyRepository, []string{}, map[string]string{}, Config{QueryDirs: []string{dir}})
+	assert.ErrorContains(t, err, "sql query 'users.find' is defined by both")
+}
+
+// TestGetQueryDriverVariant verifies GetQuery prefers the variant of the connection's driver.
+func TestGetQueryDriverVariant(t *testing.T) {
+	dir := t.TempDir()
+	writeSQLFiles(t, dir, map[string]string{
+		"report.sql":          "SELECT 'generic'",
+		"report.sqlite.sql":   "SELECT 'sqlite'",
+		"report.postgres.sql": "SELECT 'postgres'",
+		"search.mysql.sql":    "SELECT 'mysql'",
+	})
+
+	g, err := NewGorm(newTestDatabaseContext(), dummyRepository, []string{}, map[string]string{}, Config{QueryDirs: []string{dir}})
+	assert.NoError(t, err, "Unexpected error from NewGorm")
+
+	query, err := g.GetQuery("report")
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT 'sqlite'", query)
+
+	query, err = g.GetQuery("report.postgres")
+	assert.NoError(t, err, "Variants should stay addressable by their full name")
+	assert.Equal(t, "SELECT 'postgres'", query)
+
+	_, err = g.GetQuery("search")
+	assert.Error(t, err, "Variants of other drivers should not be used")
+}
+
+// TestQueryRegistry verifies queries can be registered, listed and removed at runtime.
+func TestQueryRegistry(t *testing.T) {
+	dir := t.TempDir()
+	writeSQLFiles(t, dir, map[string]string{"users/list.sql": "SELECT 1"})
+
+	g, err := NewGorm(newTestDatabaseContext(), dummyRepository, []string{}, map[string]string{}, Config{QueryDirs: []string{dir}})
+	assert.NoError(t, err, "Unexpected error from NewGorm")
+
+	assert.NoError(t, g.RegisterQuery("ping", "SELECT 2"))
+	assert.NoError(t, g.RegisterQuery("users.list", "SELECT 3"), "Runtime queries should replace cached ones")
+	assert.Error(t, g.RegisterQuery("empty", " "))
+	assert.Equal(t, []string{"ping", "users.list"}, g.ListQueries())
+
+	query, err := g.GetQuery("users.list")
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT 3", query)
+
+	g.RemoveQuery("ping")
+	assert.Equal(t, []string{"users.list"}, g.ListQueries())
+	_, err = g.GetQuery("ping")
+	assert.Error(t, err)
+}
@@ -0,0 +1,90 @@
+package gormext
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// queryErrorStartKey is the statement instance setting holding the start time of the statement.
+const queryErrorStartKey = "gormext:query_error_start"
+
+// QueryError is the error of a failed statement, carrying the statement metadata. It unwraps to
+// the underlying error, so errors.Is(err, gorm.ErrRecordNotFound) still holds; a
+// *ConstraintError wraps it in turn, keeping its user-facing message.
+type QueryError struct {
+	Operation string        // create, query, update, delete, row or raw.
+	Table     string        // Table of the statement, if known.
+	QueryName string        // Cached query name, empty for other statements.
+	Duration  time.Duration // Duration of the statement.
+	Err       error         // Underlying error.
+}
+
+// Error describes the failed statement by cached query name, or operation and table.
+func (e *QueryError) Error() string {
+	switch {
+	case e.QueryName != "":
+		return fmt.Sprintf("sql query '%s' failed: %v", e.QueryName, e.Err)
+	case e.Table != "":
+		return fmt.Sprintf("%s on table '%s' failed: %v", e.Operation, e.Table, e.Err)
+	default:
+		return fmt.Sprintf("%s failed: %v", e.Operation, e.Err)
+	}
+}
+
+// Unwrap returns the underlying error.
+func (e *QueryError) Unwrap() error {
+	return e.Err
+}
+
+// registerQueryErrorCallbacks registers the callbacks wrapping the errors of every statement in
+// a *QueryError, before unique constraint violations are translated.
+func (g *Gorm) registerQueryErrorCallbacks() error {
+	const (
+		startName = "gormext:query_error_start"
+		wrapName  = "gormext:query_error"
+	)
+
+	start := func(db *gorm.DB) {
+		db.InstanceSet(queryErrorStartKey, time.Now())
+	}
+
+	callbacks := g.connection.Callback()
+	return errors.Join(
+		callbacks.Create().Before("gorm:begin_transaction").Register(startName, start),
+		callbacks.Create().After("gorm:commit_or_rollback_transaction").Before("gormext:constraint").Register(wrapName, wrapQueryError("create")),
+		callbacks.Query().Before("gorm:query").Register(startName, start),
+		callbacks.Query().After("gorm:query").Register(wrapName, wrapQueryError("query")),
+		callbacks.Update().Before("gorm:begin_transaction").Register(startName, start),
+		callbacks.Update().After("gorm:commit_or_rollback_transaction").Before("gormext:constraint").Register(wrapName, wrapQueryError("update")),
+		callbacks.Delete().Before("gorm:begin_transaction").Register(startName, start),
+		callbacks.Delete().After("gorm:commit_or_rollback_transaction").Register(wrapName, wrapQueryError("delete")),
+		callbacks.Row().Before("gorm:row").Register(startName, start),
+		callbacks.Row().After("gorm:row").Register(wrapName, wrapQueryError("row")),
+		callbacks.Raw().Before("gorm:raw").Register(startName, start),
+		callbacks.Raw().After("gorm:raw").Before("gormext:constraint").Register(wrapName, wrapQueryError("raw")),
+	)
+}
+
+// wrapQueryError returns the callback wrapping the error of a failed statement of operation.
+func wrapQueryError(operation string) func(*gorm.DB) {
+	return func(db *gorm.DB) {
+		var queryErr *QueryError
+		if db.Error == nil || errors.As(db.Error, &queryErr) {
+			return
+		}
+
+		queryErr = &QueryError{
+			Operation: operation,
+			Table:     db.Statement.Table,
+			QueryName: QueryName(db),
+			Err:       db.Error,
+		}
+		if start, ok := db.InstanceGet(queryErrorStartKey); ok {
+			queryErr.Duration = time.Since(start.(time.Time))
+		}
+		db.Error = queryErr
+	}
+}
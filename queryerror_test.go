@@ -0,0 +1,51 @@
+package gormext
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+type erroredUser struct {
+	ID    uint
+	Email string `gorm:"uniqueIndex"`
+}
+
+// TestQueryError verifies that failed statements carry their operation, table, cached query
+// name and duration, while keeping the underlying errors matchable.
+func TestQueryError(t *testing.T) {
+	g, err := NewGorm(newTestDatabaseContext(), nil, nil, nil)
+	assert.NoError(t, err)
+	assert.NoError(t, g.Migrate(&erroredUser{}))
+	assert.NoError(t, g.RegisterQuery("users.broken", "SELECT FROM"))
+	repo := g.GetDB()
+
+	var queryErr *QueryError
+	err = repo.FirstByID(42, &erroredUser{})
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	if assert.True(t, errors.As(err, &queryErr)) {
+		assert.Equal(t, "query", queryErr.Operation)
+		assert.Equal(t, "errored_users", queryErr.Table)
+		assert.Positive(t, queryErr.Duration)
+		assert.Equal(t, "query on table 'errored_users' failed: record not found", err.Error())
+	}
+
+	err = g.ExecQuery(context.Background(), "users.broken")
+	if assert.True(t, errors.As(err, &queryErr)) {
+		assert.Equal(t, "raw", queryErr.Operation)
+		assert.Equal(t, "users.broken", queryErr.QueryName)
+		assert.ErrorContains(t, err, "sql query 'users.broken' failed: ")
+	}
+
+	g.RegisterConstraint("errored_users.email", ConstraintMessage{Field: "email", Message: "email already in use"})
+	assert.NoError(t, repo.Create(&erroredUser{Email: "ana@example.com"}))
+	err = repo.Create(&erroredUser{Email: "ana@example.com"})
+	assert.EqualError(t, err, "email already in use", "Constraint messages should stay user-facing")
+	if assert.True(t, errors.As(err, &queryErr)) {
+		assert.Equal(t, "create", queryErr.Operation)
+		assert.Equal(t, "errored_users", queryErr.Table)
+	}
+}
@@ -0,0 +1,206 @@
+package gormext
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"maps"
+	"net/http"
+	"os"
+	"sync"
+)
+
+type (
+	// QuerySource loads a set of named SQL queries, for example from an artifact store.
+	QuerySource interface {
+		Load(ctx context.Context) (map[string]string, error)
+	}
+
+	// FSQuerySource loads the .sql files of a filesystem such as an embed.FS, recursively.
+	// Query names derive from the file paths as for Config.QueryDirs.
+	FSQuerySource struct {
+		FS   fs.FS       // Filesystem holding the query files.
+		Root string      // Directory of FS holding the query files, "." by default.
+		Keys KeyProvider // Keys of encrypted (.enc) files, if any.
+	}
+
+	// FileQuerySource loads the .sql files of a directory on disk, recursively.
+	FileQuerySource struct {
+		Dir  string      // Directory holding the query files.
+		Keys KeyProvider // Keys of encrypted (.enc) files, if any.
+	}
+
+	// HTTPQuerySource loads queries from a URL serving a JSON object of query names to SQL,
+	// such as an artifact store or a presigned object storage URL. Responses are cached and
+	// revalidated with ETags; when the URL cannot be reached the last loaded queries are
+	// returned. With CachePath set, the cache survives restarts.
+	HTTPQuerySource struct {
+		URL       string       // URL of the JSON query set.
+		Client    *http.Client // HTTP client, http.DefaultClient when nil.
+		Header    http.Header  // Extra request headers, such as Authorization.
+		CachePath string       // Optional file persisting the last response.
+
+		mu    sync.Mutex
+		cache *httpQueryCache
+	}
+
+	// httpQueryCache is the last query set loaded by an HTTPQuerySource.
+	httpQueryCache struct {
+		ETag    string            `json:"etag"`
+		Queries map[string]string `json:"queries"`
+	}
+)
+
+// LoadQueries loads and caches the queries of sources. Loading a source again replaces the
+// queries it defined, so it can be used to refresh remote query sets at runtime.
+func (g *Gorm) LoadQueries(ctx context.Context, sources ...QuerySource) error {
+	for _, source := range sources {
+		name := querySourceName(source)
+		queries, err := source.Load(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to load sql queries from '%s': %w", name, err)
+		}
+
+		for queryName, query := range queries {
+			if err := g.storeQuery(queryName, query, name); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Load reads the .sql files of the filesystem.
+func (s FSQuerySource) Load(context.Context) (map[string]string, error) {
+	root := s.Root
+	if root == "" {
+		root = "."
+	}
+	return loadSQLFiles(s.FS, root, root, s.Keys)
+}
+
+// String describes the source.
+func (s FSQuerySource) String() string {
+	if s.Root == "" {
+		return "fs:."
+	}
+	return "fs:" + s.Root
+}
+
+// Load reads the .sql files of the directory.
+func (s FileQuerySource) Load(context.Context) (map[string]string, error) {
+	return loadSQLFiles(os.DirFS(s.Dir), ".", s.Dir, s.Keys)
+}
+
+// String describes the source.
+func (s FileQuerySource) String() string {
+	return s.Dir
+}
+
+// Load fetches the query set, revalidating the cached one with its ETag.
+func (s *HTTPQuerySource) Load(ctx context.Context) (map[string]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.cache == nil && s.CachePath != "" {
+		s.cache = readHTTPQueryCache(s.CachePath)
+	}
+
+	queries, err := s.fetch(ctx)
+	if err != nil {
+		if s.cache != nil {
+			return maps.Clone(s.cache.Queries), nil
+		}
+		return nil, err
+	}
+	return maps.Clone(queries), nil
+}
+
+// String describes the source.
+func (s *HTTPQuerySource) String() string {
+	return s.URL
+}
+
+// fetch requests the query set and updates the cache.
+func (s *HTTPQuerySource) fetch(ctx context.Context) (map[string]string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.URL, nil)
+	if err != nil {
+		return nil, err
+	}
+	for key, values := range s.Header {
+		req.Header[key] = values
+	}
+	if s.cache != nil && s.cache.ETag != "" {
+		req.Header.Set("If-None-Match", s.cache.ETag)
+	}
+
+	client := s.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotModified && s.cache != nil:
+		return s.cache.Queries, nil
+	case resp.StatusCode != http.StatusOK:
+		return nil, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+
+	var queries map[string]string
+	if err := json.NewDecoder(resp.Body).Decode(&queries); err != nil {
+		return nil, fmt.Errorf("failed to decode query set: %w", err)
+	}
+
+	s.cache = &httpQueryCache{ETag: resp.Header.Get("ETag"), Queries: queries}
+	if s.CachePath != "" {
+		if content, err := json.Marshal(s.cache); err == nil {
+			_ = os.WriteFile(s.CachePath, content, 0o600) // The cache is best effort.
+		}
+	}
+	return queries, nil
+}
+
+// readHTTPQueryCache reads a persisted query cache, returning nil when it is missing or invalid.
+func readHTTPQueryCache(path string) *httpQueryCache {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+
+	var cache httpQueryCache
+	if json.Unmarshal(content, &cache) != nil || cache.Queries == nil {
+		return nil
+	}
+	return &cache
+}
+
+// loadSQLFiles reads the .sql files under root in fsys into a map of query names.
+func loadSQLFiles(fsys fs.FS, root, origin string, keys KeyProvider) (map[string]string, error) {
+	queries := make(map[string]string)
+	err := walkSQLFiles(fsys, root, origin, func(fsys fs.FS, filePath, name, source string) error {
+		content, err := fs.ReadFile(fsys, filePath)
+		if err != nil {
+			return fmt.Errorf("failed to read SQL file '%s': %w", source, err)
+		}
+		if content, err = decodeFile(keys, source, content); err != nil {
+			return err
+		}
+		queries[name] = string(content)
+		return nil
+	})
+	return queries, err
+}
+
+// querySourceName describes source in errors and query collisions.
+func querySourceName(source QuerySource) string {
+	if stringer, ok := source.(fmt.Stringer); ok {
+		return stringer.String()
+	}
+	return fmt.Sprintf("%T", source)
+}
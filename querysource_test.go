@@ -0,0 +1,69 @@
+package gormext
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestQuerySourcesConfig verifies file and filesystem sources are loaded by NewGorm.
+func TestQuerySourcesConfig(t *testing.T) {
+	dir := t.TempDir()
+	writeSQLFiles(t, dir, map[string]string{"users/list.sql": "SELECT 1"})
+	fsys := fstest.MapFS{"queries/ping.sql": {Data: []byte("SELECT 2")}}
+
+	g, err := NewGorm(newTestDatabaseContext(), nil, []string{}, map[string]string{}, Config{
+		QuerySources: []QuerySource{FileQuerySource{Dir: dir}, FSQuerySource{FS: fsys, Root: "queries"}},
+	})
+	assert.NoError(t, err, "Unexpected error from NewGorm")
+
+	for name, want := range map[string]string{"users.list": "SELECT 1", "ping": "SELECT 2"} {
+		query, err := g.GetQuery(name)
+		assert.NoError(t, err)
+		assert.Equal(t, want, query)
+	}
+}
+
+// TestHTTPQuerySource verifies remote query sets are revalidated with ETags and cached.
+func TestHTTPQuerySource(t *testing.T) {
+	var fetches, notModified atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fetches.Add(1)
+		assert.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			notModified.Add(1)
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		_, _ = w.Write([]byte(`{"ping": "SELECT 1"}`))
+	}))
+
+	cachePath := filepath.Join(t.TempDir(), "queries.json")
+	source := &HTTPQuerySource{URL: server.URL, Header: http.Header{"Authorization": {"Bearer token"}}, CachePath: cachePath}
+
+	g, err := NewGorm(newTestDatabaseContext(), nil, []string{}, map[string]string{}, Config{QuerySources: []QuerySource{source}})
+	assert.NoError(t, err, "Unexpected error from NewGorm")
+	assert.NoError(t, g.LoadQueries(context.Background(), source), "Reloading a source should not collide with itself")
+	assert.EqualValues(t, 2, fetches.Load())
+	assert.EqualValues(t, 1, notModified.Load())
+
+	query, err := g.GetQuery("ping")
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT 1", query)
+
+	server.Close()
+	restarted := &HTTPQuerySource{URL: server.URL, CachePath: cachePath}
+	queries, err := restarted.Load(context.Background())
+	assert.NoError(t, err, "Unreachable sources should fall back to the persisted cache")
+	assert.Equal(t, map[string]string{"ping": "SELECT 1"}, queries)
+
+	_, err = (&HTTPQuerySource{URL: server.URL}).Load(context.Background())
+	assert.Error(t, err)
+}
@@ -0,0 +1,191 @@
+package gormext
+
+import (
+	"errors"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	// maxQueryFingerprints bounds the number of fingerprints tracked; statements of new
+	// fingerprints are ignored once it is reached.
+	maxQueryFingerprints = 1000
+
+	// maxQuerySamples bounds the durations kept per fingerprint to estimate percentiles.
+	maxQuerySamples = 1024
+
+	// queryStatsStartKey is the statement instance setting holding the start time of the statement.
+	queryStatsStartKey = "gormext:query_stats_start"
+)
+
+var (
+	// fingerprintLiterals matches string and numeric literals and numbered placeholders.
+	fingerprintLiterals = regexp.MustCompile(`'(?:[^']|'')*'|\$\d+|\b\d+(?:\.\d+)?\b`)
+
+	// fingerprintInLists matches IN lists of placeholders.
+	fingerprintInLists = regexp.MustCompile(`(?i)\bIN\s*\(\s*\?(?:\s*,\s*\?)*\s*\)`)
+
+	// fingerprintRows matches repeated rows of placeholders, such as multi-row VALUES.
+	fingerprintRows = regexp.MustCompile(`(\([?,\s]+\))(?:\s*,\s*\([?,\s]+\))+`)
+)
+
+type (
+	// QueryStat aggregates the statements sharing a fingerprint: their SQL with literals,
+	// placeholders, IN lists and multi-row values normalized.
+	QueryStat struct {
+		Fingerprint string        // Normalized SQL of the statements.
+		Count       int64         // Statements run.
+		Errors      int64         // Statements that failed.
+		ErrorRate   float64       // Errors divided by Count.
+		Total       time.Duration // Sum of the durations.
+		Mean        time.Duration // Mean duration.
+		P95         time.Duration // 95th percentile of the recent durations.
+		Max         time.Duration // Longest duration.
+	}
+
+	// queryStats collects the statistics of the statements by fingerprint.
+	queryStats struct {
+		mu      sync.Mutex
+		entries map[string]*queryStatEntry
+	}
+
+	// queryStatEntry accumulates the statements of a fingerprint.
+	queryStatEntry struct {
+		count   int64
+		errors  int64
+		total   time.Duration
+		max     time.Duration
+		samples []time.Duration
+		next    int
+	}
+)
+
+// QueryStats returns the statistics of the statements run since the connection was opened or
+// the statistics were reset, by fingerprint and slowest first by total duration. It returns
+// nil unless Config.CollectQueryStats is set.
+func (g *Gorm) QueryStats() []QueryStat {
+	if g.queryStats == nil {
+		return nil
+	}
+
+	g.queryStats.mu.Lock()
+	defer g.queryStats.mu.Unlock()
+
+	stats := make([]QueryStat, 0, len(g.queryStats.entries))
+	for fingerprint, entry := range g.queryStats.entries {
+		samples := append([]time.Duration(nil), entry.samples...)
+		sort.Slice(samples, func(i, j int) bool { return samples[i] < samples[j] })
+
+		stats = append(stats, QueryStat{
+			Fingerprint: fingerprint,
+			Count:       entry.count,
+			Errors:      entry.errors,
+			ErrorRate:   float64(entry.errors) / float64(entry.count),
+			Total:       entry.total,
+			Mean:        entry.total / time.Duration(entry.count),
+			P95:         samples[(len(samples)*95+99)/100-1],
+			Max:         entry.max,
+		})
+	}
+
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].Total != stats[j].Total {
+			return stats[i].Total > stats[j].Total
+		}
+		return stats[i].Fingerprint < stats[j].Fingerprint
+	})
+	return stats
+}
+
+// ResetQueryStats discards the statistics collected so far.
+func (g *Gorm) ResetQueryStats() {
+	if g.queryStats == nil {
+		return
+	}
+
+	g.queryStats.mu.Lock()
+	defer g.queryStats.mu.Unlock()
+	g.queryStats.entries = make(map[string]*queryStatEntry)
+}
+
+// registerQueryStatsCallbacks registers the callbacks collecting the statistics of every
+// statement.
+func (g *Gorm) registerQueryStatsCallbacks() error {
+	const (
+		startName  = "gormext:query_stats_start"
+		recordName = "gormext:query_stats"
+	)
+
+	g.queryStats = &queryStats{entries: make(map[string]*queryStatEntry)}
+	start := func(db *gorm.DB) {
+		db.InstanceSet(queryStatsStartKey, time.Now())
+	}
+
+	callbacks := g.connection.Callback()
+	return errors.Join(
+		callbacks.Create().Before("gorm:create").Register(startName, start),
+		callbacks.Create().After("gorm:create").Register(recordName, g.queryStats.record),
+		callbacks.Query().Before("gorm:query").Register(startName, start),
+		callbacks.Query().After("gorm:query").Register(recordName, g.queryStats.record),
+		callbacks.Update().Before("gorm:update").Register(startName, start),
+		callbacks.Update().After("gorm:update").Register(recordName, g.queryStats.record),
+		callbacks.Delete().Before("gorm:delete").Register(startName, start),
+		callbacks.Delete().After("gorm:delete").Register(recordName, g.queryStats.record),
+		callbacks.Row().Before("gorm:row").Register(startName, start),
+		callbacks.Row().After("gorm:row").Register(recordName, g.queryStats.record),
+		callbacks.Raw().Before("gorm:raw").Register(startName, start),
+		callbacks.Raw().After("gorm:raw").Register(recordName, g.queryStats.record),
+	)
+}
+
+// record adds a statement to the statistics of its fingerprint.
+func (s *queryStats) record(db *gorm.DB) {
+	start, ok := db.InstanceGet(queryStatsStartKey)
+	if !ok || db.DryRun || db.Statement.SQL.Len() == 0 {
+		return
+	}
+
+	duration := time.Since(start.(time.Time))
+	fingerprint := Fingerprint(db.Statement.SQL.String())
+	failed := db.Error != nil && !errors.Is(db.Error, gorm.ErrRecordNotFound)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry, ok := s.entries[fingerprint]
+	if !ok {
+		if len(s.entries) >= maxQueryFingerprints {
+			return
+		}
+		entry = &queryStatEntry{}
+		s.entries[fingerprint] = entry
+	}
+
+	entry.count++
+	entry.total += duration
+	entry.max = max(entry.max, duration)
+	if failed {
+		entry.errors++
+	}
+	if len(entry.samples) < maxQuerySamples {
+		entry.samples = append(entry.samples, duration)
+	} else {
+		entry.samples[entry.next] = duration
+		entry.next = (entry.next + 1) % maxQuerySamples
+	}
+}
+
+// Fingerprint normalizes query so that statements differing only by their values share it:
+// literals and numbered placeholders become ?, IN lists and multi-row values collapse to a
+// single element, and whitespace is collapsed.
+func Fingerprint(query string) string {
+	query = fingerprintLiterals.ReplaceAllString(query, "?")
+	query = fingerprintInLists.ReplaceAllString(query, "IN (?)")
+	query = fingerprintRows.ReplaceAllString(query, "$1")
+	return strings.Join(strings.Fields(query), " ")
+}
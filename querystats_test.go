@@ -0,0 +1,58 @@
+package gormext
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestFingerprint verifies that statements differing only by their values share a fingerprint.
+func TestFingerprint(t *testing.T) {
+	tests := map[string]string{
+		"SELECT * FROM users WHERE id = 42":                         "SELECT * FROM users WHERE id = ?",
+		"SELECT * FROM users WHERE name = 'O''Brien' AND age > 3.5": "SELECT * FROM users WHERE name = ? AND age > ?",
+		"SELECT * FROM t1 WHERE id IN (?, ?, ?)":                    "SELECT * FROM t1 WHERE id IN (?)",
+		"INSERT INTO users (a, b) VALUES ($1, $2), ($3, $4)":        "INSERT INTO users (a, b) VALUES (?, ?)",
+		"SELECT *\n  FROM users\n  LIMIT 10":                        "SELECT * FROM users LIMIT ?",
+	}
+	for query, expected := range tests {
+		assert.Equal(t, expected, Fingerprint(query), query)
+	}
+}
+
+// TestQueryStats verifies that statements are aggregated by fingerprint and that statistics
+// can be reset.
+func TestQueryStats(t *testing.T) {
+	g, err := NewGorm(newTestDatabaseContext(), nil, nil, nil)
+	assert.NoError(t, err)
+	assert.Nil(t, g.QueryStats(), "Statistics should be disabled by default")
+
+	g, err = NewGorm(newTestDatabaseContext(), nil, nil, nil, Config{CollectQueryStats: true})
+	assert.NoError(t, err)
+	assert.NoError(t, g.connection.Exec("CREATE TABLE items (id INTEGER)").Error)
+	for i := range 20 {
+		assert.NoError(t, g.connection.Exec(fmt.Sprintf("INSERT INTO items (id) VALUES (%d)", i)).Error)
+	}
+	assert.Error(t, g.connection.Exec("SELECT * FROM missing WHERE id = 1").Error)
+
+	stats := make(map[string]QueryStat)
+	for _, stat := range g.QueryStats() {
+		stats[stat.Fingerprint] = stat
+	}
+	assert.Len(t, stats, 3)
+
+	insert := stats["INSERT INTO items (id) VALUES (?)"]
+	assert.Equal(t, int64(20), insert.Count)
+	assert.Zero(t, insert.Errors)
+	assert.Equal(t, insert.Total/20, insert.Mean)
+	assert.LessOrEqual(t, insert.P95, insert.Max)
+	assert.Positive(t, insert.P95)
+
+	missing := stats["SELECT * FROM missing WHERE id = ?"]
+	assert.Equal(t, int64(1), missing.Errors)
+	assert.Equal(t, 1.0, missing.ErrorRate)
+
+	g.ResetQueryStats()
+	assert.Empty(t, g.QueryStats())
+}
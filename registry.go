@@ -0,0 +1,134 @@
+package gormext
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"gorm.io/gorm"
+)
+
+// Registry holds named connections alongside a primary one, and routes the statements on
+// selected models to them, e.g. audit logs to a separate database. Routing is resolved when
+// each statement runs, so repositories from GetDB keep working unchanged.
+//
+// Raw statements and statements run inside a transaction stay on the connection they were
+// started on. Routed connections should use the same driver as the primary, whose dialect
+// builds the SQL.
+type Registry struct {
+	primary     *Gorm
+	mu          sync.RWMutex
+	connections map[string]*Gorm
+	routes      map[string]*Gorm
+}
+
+// NewRegistry creates the registry routing the statements of primary.
+func NewRegistry(primary *Gorm) (*Registry, error) {
+	if primary.registry != nil {
+		return nil, errors.New("registry already configured for this connection")
+	}
+
+	r := &Registry{primary: primary, connections: make(map[string]*Gorm), routes: make(map[string]*Gorm)}
+
+	const name = "gormext:route"
+	callbacks := primary.connection.Callback()
+	err := errors.Join(
+		callbacks.Query().Before("gorm:query").Register(name, r.route),
+		callbacks.Row().Before("gorm:row").Register(name, r.route),
+		callbacks.Create().Before("gorm:begin_transaction").Register(name, r.route),
+		callbacks.Update().Before("gorm:begin_transaction").Register(name, r.route),
+		callbacks.Delete().Before("gorm:begin_transaction").Register(name, r.route),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to register routing callbacks: %w", err)
+	}
+
+	primary.registry = r
+	return r, nil
+}
+
+// Add registers the connection g under name.
+func (r *Registry) Add(name string, g *Gorm) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.connections[name]; ok {
+		return fmt.Errorf("connection '%s' already registered", name)
+	}
+	r.connections[name] = g
+	return nil
+}
+
+// Get returns the connection registered under name.
+func (r *Registry) Get(name string) (*Gorm, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	g, ok := r.connections[name]
+	return g, ok
+}
+
+// Route sends the statements on the given models to the connection registered under name.
+func (r *Registry) Route(name string, models ...any) error {
+	target, ok := r.Get(name)
+	if !ok {
+		return fmt.Errorf("connection '%s' not registered", name)
+	}
+
+	tables := make([]string, len(models))
+	for i, model := range models {
+		table, err := r.primary.parseModel(model)
+		if err != nil {
+			return err
+		}
+		tables[i] = table.Table
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, table := range tables {
+		r.routes[table] = target
+	}
+	return nil
+}
+
+// For returns the connection the statements on model are routed to.
+func (r *Registry) For(model any) (*Gorm, error) {
+	table, err := r.primary.parseModel(model)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if target, ok := r.routes[table.Table]; ok {
+		return target, nil
+	}
+	return r.primary, nil
+}
+
+// GetDB returns a repository of the primary connection whose statements are routed.
+func (r *Registry) GetDB() IRepository {
+	return r.primary.GetDB()
+}
+
+// route switches the statement to the connection its table is routed to.
+func (r *Registry) route(db *gorm.DB) {
+	stmt := db.Statement
+	if db.Error != nil || stmt.Table == "" {
+		return
+	}
+	if _, inTx := stmt.ConnPool.(gorm.TxCommitter); inTx {
+		return
+	}
+
+	r.mu.RLock()
+	target, ok := r.routes[stmt.Table]
+	r.mu.RUnlock()
+
+	if ok {
+		stmt.ConnPool = target.connection.ConnPool
+	}
+}
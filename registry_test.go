@@ -0,0 +1,60 @@
+package gormext
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// routedAudit is stored on the audit connection.
+type routedAudit struct {
+	ID     uint
+	Action string
+}
+
+// routedUser stays on the primary connection.
+type routedUser struct {
+	ID   uint
+	Name string
+}
+
+// TestRegistryRouting verifies that statements on routed models run on their connection.
+func TestRegistryRouting(t *testing.T) {
+	dir := t.TempDir()
+	primary := newFileSeedGorm(t, dir)
+	audit := newFileSeedGorm(t, dir)
+	assert.NoError(t, primary.Migrate(&routedUser{}, &routedAudit{}))
+	assert.NoError(t, audit.Migrate(&routedAudit{}))
+
+	registry, err := NewRegistry(primary)
+	assert.NoError(t, err)
+	_, err = NewRegistry(primary)
+	assert.Error(t, err, "A connection should have a single registry")
+
+	assert.NoError(t, registry.Add("audit", audit))
+	assert.Error(t, registry.Add("audit", audit))
+	assert.Error(t, registry.Route("reports", &routedAudit{}))
+	assert.NoError(t, registry.Route("audit", &routedAudit{}))
+
+	target, err := registry.For(&routedAudit{})
+	assert.NoError(t, err)
+	assert.Same(t, audit, target)
+
+	repo := registry.GetDB()
+	assert.NoError(t, repo.Create(&routedUser{Name: "ana"}))
+	assert.NoError(t, repo.Create(&routedAudit{Action: "login"}))
+
+	// Raw statements are not routed.
+	count := func(g *Gorm, table string) int64 {
+		var n int64
+		assert.NoError(t, g.connection.Raw("SELECT COUNT(*) FROM "+table).Scan(&n).Error)
+		return n
+	}
+	assert.EqualValues(t, 1, count(primary, "routed_users"))
+	assert.EqualValues(t, 0, count(primary, "routed_audits"), "Routed writes should not reach the primary")
+	assert.EqualValues(t, 1, count(audit, "routed_audits"))
+
+	var audits []routedAudit
+	assert.NoError(t, repo.Find(&audits))
+	assert.Len(t, audits, 1, "Routed reads should use the routed connection")
+}
@@ -0,0 +1,399 @@
+package gormext
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	// ErrLockingUnsupported is returned when row-level locking is requested on a driver that lacks it.
+	ErrLockingUnsupported = errors.New("row-level locking is not supported by the database driver")
+
+	// defaultActiveCondition is the prebuilt IsActive condition of models without their own,
+	// sparing gorm from parsing it on every call.
+	defaultActiveCondition = clause.Expr{SQL: "active IS TRUE"}
+)
+
+// gormRepository is the default IRepository implementation backed directly by *gorm.DB.
+type gormRepository struct {
+	db         *gorm.DB
+	lock       clause.Locking
+	joinTx     bool
+	hooks      *txHooks
+	cacheTTL   time.Duration
+	cacheStale time.Duration
+}
+
+// NewRepository returns the default IRepository implementation for the given connection.
+// It satisfies the Repository type and is used by NewGorm when no repository is provided.
+func NewRepository(db *gorm.DB) IRepository {
+	return &gormRepository{db: db}
+}
+
+// NewTxRepository returns the default IRepository implementation, whose WithContext joins the
+// transaction carried by the context (see ContextWithTx), so that service code composes
+// repository calls into one transaction without passing transaction handles. It satisfies the
+// Repository type.
+func NewTxRepository(db *gorm.DB) IRepository {
+	return &gormRepository{db: db, joinTx: true}
+}
+
+// with returns a copy of the repository wrapping the given connection. Chaining on a statement
+// gorm already owns returns that same instance, and then the repository is reused as well
+// instead of allocating a new one per call.
+func (r *gormRepository) with(db *gorm.DB) IRepository {
+	if db == r.db {
+		return r
+	}
+
+	clone := *r
+	clone.db = db
+	return &clone
+}
+
+// WithTransaction executes fn within a transaction, committing if it returns nil. Inside a
+// transaction, fn runs in a savepoint instead, rolled back alone when fn fails.
+func (r *gormRepository) WithTransaction(fn func(tx IRepository) error) error {
+	return r.transaction(r.db, nil, func(tx *gormRepository) error {
+		return fn(tx)
+	})
+}
+
+// WithContext sets the context used by subsequent queries. The tenant carried by ctx, set by
+// middleware with ContextWithTenant, is bound to the returned repository: a context without
+// tenant keeps the one bound before, such as by ForTenant. Repositories created by
+// NewTxRepository join the transaction carried by ctx, if any.
+func (r *gormRepository) WithContext(ctx context.Context) IRepository {
+	ctx = bindTenant(ctx, r.db.Statement.Context)
+	if r.joinTx {
+		if tx, ok := TxFromContext(ctx); ok {
+			// Sessions of a connection share its callbacks, telling apart transactions of
+			// other connections.
+			if tx, ok := tx.(*gormRepository); ok && tx.db.Callback() == r.db.Callback() {
+				return r.withTx(tx.db.WithContext(ctx), tx.hooks)
+			}
+		}
+	}
+	return r.with(r.db.WithContext(ctx))
+}
+
+// FirstByID finds the record with the given ID, read through the cache of its model when
+// enabled with CacheEntity.
+func (r *gormRepository) FirstByID(id any, dest any) error {
+	if cache, table := r.entityCache(dest); cache != nil {
+		return cache.loadEntity(r.db, table, id, dest)
+	}
+	return r.db.Where("id = ?", id).First(dest).Error
+}
+
+// First returns the first record matching the conditions.
+func (r *gormRepository) First(dest any, conds ...any) error {
+	return r.read(dest, func(db *gorm.DB, dest any) *gorm.DB { return db.First(dest, conds...) })
+}
+
+// Find returns all records matching the query.
+func (r *gormRepository) Find(dest any) error {
+	return r.read(dest, func(db *gorm.DB, dest any) *gorm.DB { return db.Find(dest) })
+}
+
+// FindInBatches loads records batchSize at a time, calling fn for each batch with its number (starting at 1).
+// The batch repository wraps the statement of the current batch; returning an error from fn stops processing.
+func (r *gormRepository) FindInBatches(dest any, batchSize int, fn func(batch IRepository, n int) error) error {
+	return r.db.FindInBatches(dest, batchSize, func(tx *gorm.DB, n int) error {
+		return fn(r.with(tx), n)
+	}).Error
+}
+
+// Create inserts a new record.
+func (r *gormRepository) Create(entity any) error {
+	return r.db.Create(entity).Error
+}
+
+// Update saves all fields of an existing record.
+func (r *gormRepository) Update(entity any) error {
+	return r.db.Save(entity).Error
+}
+
+// Delete deletes a record.
+func (r *gormRepository) Delete(entity any) error {
+	return r.db.Delete(entity).Error
+}
+
+// Exec executes a raw SQL statement.
+func (r *gormRepository) Exec(sql string, values ...any) error {
+	return r.db.Exec(sql, values...).Error
+}
+
+// IDEqual adds the condition "id = ?".
+func (r *gormRepository) IDEqual(id any) IRepository {
+	return r.with(r.db.Where("id = ?", id))
+}
+
+// IDIn adds the condition "id IN (?)".
+func (r *gormRepository) IDIn(ids []any) IRepository {
+	return r.with(r.db.Where("id IN (?)", ids))
+}
+
+// Where adds a WHERE clause. Passing another repository chain as query groups its conditions in parentheses.
+func (r *gormRepository) Where(query any, args ...any) IRepository {
+	return r.with(r.db.Where(condition(query), args...))
+}
+
+// Not adds a negated WHERE clause.
+func (r *gormRepository) Not(query any, args ...any) IRepository {
+	return r.with(r.db.Not(condition(query), args...))
+}
+
+// Or adds a condition joined to the previous ones with OR.
+// As in SQL, AND binds tighter than OR, so group conditions by passing a repository chain.
+func (r *gormRepository) Or(query any, args ...any) IRepository {
+	return r.with(r.db.Or(condition(query), args...))
+}
+
+// Joins adds a JOIN clause.
+func (r *gormRepository) Joins(query string, args ...any) IRepository {
+	return r.with(r.db.Joins(query, args...))
+}
+
+// Preload preloads the given association.
+func (r *gormRepository) Preload(query string, args ...any) IRepository {
+	return r.with(r.db.Preload(query, args...))
+}
+
+// Order adds an ORDER BY clause.
+func (r *gormRepository) Order(value any) IRepository {
+	return r.with(r.db.Order(value))
+}
+
+// IsActive filters the active records of the model: with the condition registered by
+// RegisterActive, or the one of the field tagged `gormext:"active"` (a boolean column),
+// `gormext:"active:enabled"` (a column equal to one of the comma-separated values) or
+// `gormext:"active:null"` (a column that is NULL, such as deleted_at), and by default
+// "active IS TRUE".
+func (r *gormRepository) IsActive() IRepository {
+	return r.with(r.db.Where(activeExpr{}))
+}
+
+// WithStatus filters records whose status is one of values, in the field tagged
+// `gormext:"status"` or the "status" column. Without values, it filters nothing.
+func (r *gormRepository) WithStatus(values ...string) IRepository {
+	if len(values) == 0 {
+		return r
+	}
+	return r.with(r.db.Where(statusExpr{values: values}))
+}
+
+// Table specifies the table to query.
+func (r *gormRepository) Table(name string, args ...any) IRepository {
+	return r.with(r.db.Table(name, args...))
+}
+
+// Scopes applies the given query fragments in order.
+func (r *gormRepository) Scopes(fns ...func(IRepository) IRepository) IRepository {
+	var repo IRepository = r
+	for _, fn := range fns {
+		repo = fn(repo)
+	}
+	return repo
+}
+
+// LockForUpdate locks the selected rows for update (SELECT ... FOR UPDATE).
+func (r *gormRepository) LockForUpdate() IRepository {
+	return r.locking(clause.Locking{Strength: clause.LockingStrengthUpdate})
+}
+
+// LockShare locks the selected rows in share mode (SELECT ... FOR SHARE, or LOCK IN SHARE
+// MODE on MariaDB).
+func (r *gormRepository) LockShare() IRepository {
+	return r.locking(clause.Locking{Strength: clause.LockingStrengthShare})
+}
+
+// SkipLocked skips rows locked by other transactions, locking for update unless a lock was already chosen.
+func (r *gormRepository) SkipLocked() IRepository {
+	locking := r.lock
+	if locking.Strength == "" {
+		locking.Strength = clause.LockingStrengthUpdate
+	}
+	locking.Options = clause.LockingOptionsSkipLocked
+	return r.locking(locking)
+}
+
+// locking applies the locking clause, recording an error on drivers without row-level locking.
+func (r *gormRepository) locking(locking clause.Locking) IRepository {
+	var db *gorm.DB
+	if locking.Strength == clause.LockingStrengthShare && mariaDB(r.db) {
+		db = r.db.Clauses(shareModeLock{options: locking.Options})
+	} else {
+		db = r.db.Clauses(locking)
+	}
+	if name := db.Dialector.Name(); name == "sqlite" {
+		db.AddError(fmt.Errorf("%w: %s", ErrLockingUnsupported, name))
+	}
+
+	repo := r.with(db).(*gormRepository)
+	repo.lock = locking
+	return repo
+}
+
+// shareModeLock is the share mode locking clause of MariaDB, which lacks FOR SHARE.
+type shareModeLock struct {
+	options string
+}
+
+// Name returns the name of the locking clause it replaces.
+func (shareModeLock) Name() string {
+	return "FOR"
+}
+
+// Build writes LOCK IN SHARE MODE and the locking options.
+func (l shareModeLock) Build(builder clause.Builder) {
+	builder.WriteString("LOCK IN SHARE MODE")
+	if l.options != "" {
+		builder.WriteByte(' ')
+		builder.WriteString(l.options)
+	}
+}
+
+// MergeClause replaces the locking clause, dropping its FOR keyword.
+func (l shareModeLock) MergeClause(c *clause.Clause) {
+	c.Name = ""
+	c.Expression = l
+}
+
+// mariaDB reports whether db is connected to a MariaDB server, as the mysql dialector detects
+// from the server version.
+func mariaDB(db *gorm.DB) bool {
+	dialector, ok := db.Dialector.(*mysql.Dialector)
+	return ok && strings.Contains(dialector.ServerVersion, "MariaDB")
+}
+
+// Count counts the records matching the query.
+func (r *gormRepository) Count(count *int64) error {
+	return r.read(count, func(db *gorm.DB, dest any) *gorm.DB { return db.Count(dest.(*int64)) })
+}
+
+// read runs query into dest through the result cache, or coalesced with identical reads, when
+// enabled.
+func (r *gormRepository) read(dest any, query readQuery) error {
+	if cache := r.resultCache(); cache != nil {
+		return cache.load(r.db, dest, r.cacheTTL, r.cacheStale, query)
+	}
+	if group := r.queryGroup(); group != nil {
+		return group.load(r.db, dest, query)
+	}
+	return query(r.db, dest).Error
+}
+
+// Rows runs the query and returns its rows, which the caller must close.
+func (r *gormRepository) Rows() (*sql.Rows, error) {
+	return r.db.Rows()
+}
+
+// CountBy counts the records matching the query grouped by column, keyed by the column value.
+// Like the other single-column helpers it scans rows directly, bypassing gorm's reflection.
+func (r *gormRepository) CountBy(column string) (map[string]int64, error) {
+	// Group by the same quoted column as selected, rather than Group's raw name.
+	col := clause.Column{Name: column}
+	rows, err := r.db.Select("?, COUNT(*)", col).Clauses(clause.GroupBy{Columns: []clause.Column{col}}).Rows()
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	counts := make(map[string]int64)
+	for rows.Next() {
+		var (
+			key   sql.NullString
+			count int64
+		)
+		if err := rows.Scan(&key, &count); err != nil {
+			return nil, err
+		}
+		counts[key.String] += count
+	}
+	return counts, rows.Err()
+}
+
+// SumInt64 returns the sum of an integer column over the records matching the query, 0 when none match.
+func (r *gormRepository) SumInt64(column string) (int64, error) {
+	var sum int64
+	err := r.db.Select("COALESCE(SUM(?), 0)", clause.Column{Name: column}).Row().Scan(&sum)
+	return sum, err
+}
+
+// MaxTime returns the latest value of a time column over the records matching the query,
+// the zero time when none match.
+func (r *gormRepository) MaxTime(column string) (time.Time, error) {
+	var value any
+	if err := r.db.Select("MAX(?)", clause.Column{Name: column}).Row().Scan(&value); err != nil {
+		return time.Time{}, err
+	}
+
+	switch v := value.(type) {
+	case nil:
+		return time.Time{}, nil
+	case time.Time:
+		return v, nil
+	case []byte:
+		return parseTime(string(v))
+	case string:
+		return parseTime(v)
+	default:
+		return time.Time{}, fmt.Errorf("unsupported time value %T", value)
+	}
+}
+
+// PluckStrings returns the values of a string column for the records matching the query, with
+// NULL values as empty strings.
+func (r *gormRepository) PluckStrings(column string) ([]string, error) {
+	rows, err := r.db.Select("?", clause.Column{Name: column}).Rows()
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var values []string
+	for rows.Next() {
+		var value sql.NullString
+		if err := rows.Scan(&value); err != nil {
+			return nil, err
+		}
+		values = append(values, value.String)
+	}
+	return values, rows.Err()
+}
+
+// timeLayouts lists the text formats drivers return aggregated time values in, such as SQLite.
+var timeLayouts = []string{
+	"2006-01-02 15:04:05.999999999Z07:00",
+	"2006-01-02T15:04:05.999999999Z07:00",
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02",
+}
+
+// parseTime parses a time value returned as text.
+func parseTime(value string) (time.Time, error) {
+	for _, layout := range timeLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid time value '%s'", value)
+}
+
+// condition unwraps repository chains used as query conditions so gorm can group them.
+func condition(query any) any {
+	if repo, ok := query.(*gormRepository); ok {
+		return repo.db
+	}
+	return query
+}
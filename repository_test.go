@@ -0,0 +1,216 @@
+package gormext
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/mysql"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+type repoUser struct {
+	ID     uint `gorm:"primaryKey"`
+	Name   string
+	Age    int
+	Active bool
+}
+
+// newTestRepository creates a Gorm instance using the default repository over in-memory SQLite.
+func newTestRepository(t *testing.T) (*Gorm, IRepository) {
+	g, err := NewGorm(newTestDatabaseContext(), nil, []string{}, map[string]string{})
+	assert.NoError(t, err, "Unexpected error from NewGorm")
+	assert.NoError(t, g.Migrate(&repoUser{}), "Migration failed")
+	return g, g.GetDB()
+}
+
+// renderSQL returns the SELECT statement the default repository would run for repo.
+func renderSQL(repo IRepository) string {
+	db := repo.(*gormRepository).db
+	return db.ToSQL(func(tx *gorm.DB) *gorm.DB {
+		return tx.Find(&[]repoUser{})
+	})
+}
+
+// TestNewGormDefaultRepository verifies NewGorm falls back to the default repository.
+func TestNewGormDefaultRepository(t *testing.T) {
+	_, repo := newTestRepository(t)
+	_, ok := repo.(*gormRepository)
+	assert.True(t, ok, "Expected default repository, got %T", repo)
+}
+
+// TestOrPrecedence verifies Or follows SQL precedence and that repository chains group conditions.
+func TestOrPrecedence(t *testing.T) {
+	_, repo := newTestRepository(t)
+
+	// Flat chains are emitted as is, so AND binds tighter: name = "ann" OR (age > 30 AND active IS TRUE).
+	sql := renderSQL(repo.Where("name = ?", "ann").Or("age > ?", 30).Where("active IS TRUE"))
+	assert.Equal(t, "SELECT * FROM `repo_users` WHERE name = \"ann\" OR age > 30 AND active IS TRUE", sql)
+
+	sql = renderSQL(repo.Where(repo.Where("name = ?", "ann").Or("age > ?", 30)).Where("active IS TRUE"))
+	assert.Equal(t, "SELECT * FROM `repo_users` WHERE (name = \"ann\" OR age > 30) AND active IS TRUE", sql)
+}
+
+// TestNotPrecedence verifies Not negates only its own condition.
+func TestNotPrecedence(t *testing.T) {
+	_, repo := newTestRepository(t)
+
+	sql := renderSQL(repo.Where("age > ?", 18).Not("name = ?", "bob"))
+	assert.Equal(t, "SELECT * FROM `repo_users` WHERE age > 18 AND NOT name = \"bob\"", sql)
+
+	sql = renderSQL(repo.Not(repo.Where("name = ?", "bob").Or("age < ?", 18)))
+	assert.Equal(t, "SELECT * FROM `repo_users` WHERE NOT (name = \"bob\" OR age < 18)", sql)
+}
+
+// TestOrFindsRecords verifies Or conditions are applied when querying.
+func TestOrFindsRecords(t *testing.T) {
+	_, repo := newTestRepository(t)
+	for _, u := range []repoUser{{Name: "ann", Age: 20}, {Name: "bob", Age: 40}, {Name: "cid", Age: 30}} {
+		assert.NoError(t, repo.Create(&u))
+	}
+
+	var users []repoUser
+	err := repo.Where("name = ?", "ann").Or("age > ?", 35).Order("id").Find(&users)
+	assert.NoError(t, err)
+	if assert.Len(t, users, 2) {
+		assert.Equal(t, "ann", users[0].Name)
+		assert.Equal(t, "bob", users[1].Name)
+	}
+}
+
+// olderThan is a reusable scope filtering users above the given age.
+func olderThan(age int) func(IRepository) IRepository {
+	return func(repo IRepository) IRepository {
+		return repo.Where("age > ?", age)
+	}
+}
+
+// TestScopes verifies scopes are applied in order and compose with other conditions.
+func TestScopes(t *testing.T) {
+	_, repo := newTestRepository(t)
+
+	sql := renderSQL(repo.Scopes(olderThan(18), func(r IRepository) IRepository { return r.IsActive() }).Order("name"))
+	assert.Equal(t, "SELECT * FROM `repo_users` WHERE age > 18 AND active IS TRUE ORDER BY name", sql)
+}
+
+// newDryRunGorm creates a Gorm instance for driver that renders SQL without connecting.
+func newDryRunGorm(t *testing.T, driver SQLDriver) *Gorm {
+	dialectors := map[SQLDriver]gorm.Dialector{
+		PostgreSQL: postgres.New(postgres.Config{DSN: "host=localhost"}),
+		MySQL:      mysql.New(mysql.Config{DSN: "user@tcp(localhost)/db", SkipInitializeWithVersion: true}),
+	}
+
+	db, err := gorm.Open(dialectors[driver], &gorm.Config{DryRun: true, DisableAutomaticPing: true})
+	assert.NoError(t, err, "Unexpected error opening dry-run connection")
+	return &Gorm{connection: db, databaseCtx: DatabaseContext{driver: driver}, repository: NewRepository, sqlQueries: &sync.Map{}}
+}
+
+// TestLockingClauses verifies the locking clauses rendered for each driver.
+func TestLockingClauses(t *testing.T) {
+	pg := newDryRunGorm(t, PostgreSQL).GetDB()
+	assert.Equal(t, `SELECT * FROM "repo_users" WHERE id = 1 FOR UPDATE`, renderSQL(pg.IDEqual(1).LockForUpdate()))
+	assert.Equal(t, `SELECT * FROM "repo_users" FOR SHARE SKIP LOCKED`, renderSQL(pg.LockShare().SkipLocked()))
+	assert.Equal(t, `SELECT * FROM "repo_users" FOR UPDATE SKIP LOCKED`, renderSQL(pg.SkipLocked()))
+
+	my := newDryRunGorm(t, MySQL).GetDB()
+	assert.Equal(t, "SELECT * FROM `repo_users` FOR UPDATE SKIP LOCKED", renderSQL(my.LockForUpdate().SkipLocked()))
+	assert.Equal(t, "SELECT * FROM `repo_users` FOR SHARE", renderSQL(my.LockShare()))
+
+	db, err := gorm.Open(mysql.New(mysql.Config{DSN: "user@tcp(localhost)/db", ServerVersion: "10.11.6-MariaDB", SkipInitializeWithVersion: true}),
+		&gorm.Config{DryRun: true, DisableAutomaticPing: true})
+	assert.NoError(t, err)
+	maria := NewRepository(db)
+	assert.Equal(t, "SELECT * FROM `repo_users` LOCK IN SHARE MODE", renderSQL(maria.LockShare()))
+	assert.Equal(t, "SELECT * FROM `repo_users` LOCK IN SHARE MODE SKIP LOCKED", renderSQL(maria.LockShare().SkipLocked()))
+	assert.Equal(t, "SELECT * FROM `repo_users` FOR UPDATE", renderSQL(maria.LockShare().LockForUpdate()))
+}
+
+// TestLockingUnsupportedOnSQLite verifies SQLite reports an error instead of silently dropping the lock.
+func TestLockingUnsupportedOnSQLite(t *testing.T) {
+	_, repo := newTestRepository(t)
+
+	var users []repoUser
+	err := repo.LockForUpdate().SkipLocked().Find(&users)
+	assert.ErrorIs(t, err, ErrLockingUnsupported)
+
+	assert.NoError(t, repo.Find(&users), "Locking errors must not leak into the base repository")
+}
+
+// TestFindInBatches verifies records are streamed in batches and processing stops on error.
+func TestFindInBatches(t *testing.T) {
+	_, repo := newTestRepository(t)
+	for i := 0; i < 5; i++ {
+		assert.NoError(t, repo.Create(&repoUser{Name: "user", Age: i}))
+	}
+
+	var (
+		users []repoUser
+		sizes []int
+	)
+	err := repo.Order("id").FindInBatches(&users, 2, func(batch IRepository, n int) error {
+		assert.Equal(t, len(sizes)+1, n, "Batch numbers should start at 1")
+		sizes = append(sizes, len(users))
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []int{2, 2, 1}, sizes)
+
+	errStop := errors.New("stop")
+	calls := 0
+	err = repo.FindInBatches(&users, 2, func(batch IRepository, n int) error {
+		calls++
+		return errStop
+	})
+	assert.ErrorIs(t, err, errStop)
+	assert.Equal(t, 1, calls)
+}
+
+// repoEvent is a model with a time column for aggregate tests.
+type repoEvent struct {
+	ID         uint `gorm:"primaryKey"`
+	Kind       string
+	Points     int64
+	OccurredAt time.Time
+}
+
+// TestPrimitiveAggregates verifies the single-column helpers scan aggregates and plucked values.
+func TestPrimitiveAggregates(t *testing.T) {
+	g, repo := newTestRepository(t)
+	assert.NoError(t, g.Migrate(&repoEvent{}))
+
+	events := repo.Table("repo_events")
+	latest, err := events.MaxTime("occurred_at")
+	assert.NoError(t, err)
+	assert.True(t, latest.IsZero(), "Empty tables should have no latest time")
+
+	base := time.Date(2024, 5, 1, 12, 30, 0, 0, time.UTC)
+	for i, kind := range []string{"login", "login", "purchase"} {
+		assert.NoError(t, repo.Create(&repoEvent{Kind: kind, Points: int64(10 * (i + 1)), OccurredAt: base.Add(time.Duration(i) * time.Hour)}))
+	}
+
+	counts, err := repo.Table("repo_events").CountBy("kind")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]int64{"login": 2, "purchase": 1}, counts)
+
+	// Columns needing quotes are grouped by the quoted column, as selected.
+	assert.NoError(t, repo.Exec(`CREATE TABLE repo_labels ("label kind" TEXT)`))
+	assert.NoError(t, repo.Exec(`INSERT INTO repo_labels VALUES ('a'), ('a'), ('b')`))
+	counts, err = repo.Table("repo_labels").CountBy("label kind")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]int64{"a": 2, "b": 1}, counts)
+
+	sum, err := repo.Table("repo_events").Where("kind = ?", "login").SumInt64("points")
+	assert.NoError(t, err)
+	assert.EqualValues(t, 30, sum)
+
+	latest, err = repo.Table("repo_events").MaxTime("occurred_at")
+	assert.NoError(t, err)
+	assert.True(t, base.Add(2*time.Hour).Equal(latest), "Got %v", latest)
+
+	kinds, err := repo.Table("repo_events").Order("id").PluckStrings("kind")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"login", "login", "purchase"}, kinds)
+}
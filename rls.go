@@ -0,0 +1,84 @@
+package gormext
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// rlsPluginName is the name of the row-level security plugin.
+const rlsPluginName = "gormext:row_level_security"
+
+// rowLevelSecurity is the plugin of Config.TenantSetting, handing the tenant of the context to
+// the Postgres row-level security policies at the start of every transaction.
+type rowLevelSecurity struct {
+	setting string
+	role    func(tenantID string) string
+}
+
+// Name returns the plugin name.
+func (s *rowLevelSecurity) Name() string {
+	return rlsPluginName
+}
+
+// Initialize registers the callbacks setting the tenant in the transactions gorm starts for
+// creates, updates and deletes.
+func (s *rowLevelSecurity) Initialize(db *gorm.DB) error {
+	const name = "gormext:rls_begin"
+	callbacks := db.Callback()
+	return errors.Join(
+		callbacks.Create().After("gorm:begin_transaction").Register(name, s.begun),
+		callbacks.Update().After("gorm:begin_transaction").Register(name, s.begun),
+		callbacks.Delete().After("gorm:begin_transaction").Register(name, s.begun),
+	)
+}
+
+// begun sets the tenant in the transaction the statement started, if any.
+func (s *rowLevelSecurity) begun(db *gorm.DB) {
+	if _, started := db.InstanceGet("gorm:started_transaction"); !started || db.Error != nil {
+		return
+	}
+	if err := s.apply(db.Statement.Context, db.Statement.ConnPool); err != nil {
+		db.AddError(err)
+	}
+}
+
+// apply sets the tenant of ctx, and its role if any, for the rest of the transaction of pool.
+// Without tenant, the setting is left empty, so that the policies let no rows through.
+func (s *rowLevelSecurity) apply(ctx context.Context, pool gorm.ConnPool) error {
+	tenantID, ok := TenantFromContext(ctx)
+	if !ok {
+		return nil
+	}
+
+	if _, err := pool.ExecContext(ctx, "SELECT set_config($1, $2, true)", s.setting, tenantID); err != nil {
+		return fmt.Errorf("failed to set '%s' of tenant '%s': %w", s.setting, tenantID, err)
+	}
+	if s.role == nil {
+		return nil
+	}
+	if role := s.role(tenantID); role != "" {
+		if _, err := pool.ExecContext(ctx, `SET LOCAL ROLE "`+strings.ReplaceAll(role, `"`, `""`)+`"`); err != nil {
+			return fmt.Errorf("failed to set role '%s' of tenant '%s': %w", role, tenantID, err)
+		}
+	}
+	return nil
+}
+
+// rowLevelSecurity returns the row-level security plugin of the connection, nil when disabled.
+func (r *gormRepository) rowLevelSecurity() *rowLevelSecurity {
+	s, _ := r.db.Config.Plugins[rlsPluginName].(*rowLevelSecurity)
+	return s
+}
+
+// beginTenant hands the tenant of tx to the row-level security policies, when enabled, at the
+// start of the transaction.
+func (r *gormRepository) beginTenant(tx *gorm.DB) error {
+	if s := r.rowLevelSecurity(); s != nil {
+		return s.apply(tx.Statement.Context, tx.Statement.ConnPool)
+	}
+	return nil
+}
@@ -0,0 +1,92 @@
+package gormext
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// rlsSettings records the set_config calls of the sqlite3_rls driver.
+var (
+	rlsSettingsMu sync.Mutex
+	rlsSettings   []string
+)
+
+func init() {
+	// set_config stands in for the Postgres function of the same name.
+	sql.Register("sqlite3_rls", &sqlite3.SQLiteDriver{ConnectHook: func(conn *sqlite3.SQLiteConn) error {
+		return conn.RegisterFunc("set_config", func(name, value string, local bool) string {
+			rlsSettingsMu.Lock()
+			defer rlsSettingsMu.Unlock()
+			rlsSettings = append(rlsSettings, name+"="+value)
+			return value
+		}, true)
+	}})
+}
+
+// TestRowLevelSecurity verifies that the context tenant is set at the start of the
+// transactions of WithTransaction, Begin and gorm writes, and not outside transactions.
+func TestRowLevelSecurity(t *testing.T) {
+	conn, err := gorm.Open(sqlite.Dialector{DriverName: "sqlite3_rls", DSN: filepath.Join(t.TempDir(), "rls.db")},
+		&gorm.Config{Logger: logger.Discard})
+	assert.NoError(t, err)
+	assert.NoError(t, conn.Use(&rowLevelSecurity{setting: "app.current_tenant"}))
+	assert.NoError(t, conn.AutoMigrate(&repoUser{}))
+	recorded := func() []string {
+		rlsSettingsMu.Lock()
+		defer rlsSettingsMu.Unlock()
+		settings := rlsSettings
+		rlsSettings = nil
+		return settings
+	}
+
+	repo := NewRepository(conn).WithContext(ContextWithTenant(context.Background(), "acme"))
+	assert.NoError(t, repo.WithTransaction(func(tx IRepository) error {
+		return tx.WithTransaction(func(tx IRepository) error {
+			return tx.Create(&repoUser{Name: "alice"})
+		})
+	}))
+	assert.Equal(t, []string{"app.current_tenant=acme"}, recorded())
+
+	tx, err := repo.Begin()
+	assert.NoError(t, err)
+	assert.NoError(t, tx.Commit())
+	assert.Equal(t, []string{"app.current_tenant=acme"}, recorded())
+
+	assert.NoError(t, repo.Create(&repoUser{Name: "bob"}))
+	assert.NoError(t, repo.Find(&[]repoUser{}))
+	assert.NoError(t, NewRepository(conn).Create(&repoUser{Name: "carol"}))
+	assert.Equal(t, []string{"app.current_tenant=acme"}, recorded())
+}
+
+// execRecorder is a connection pool recording the statements it executes, without running them.
+type execRecorder struct {
+	gorm.ConnPool
+	queries []string
+}
+
+func (p *execRecorder) ExecContext(_ context.Context, query string, _ ...any) (sql.Result, error) {
+	p.queries = append(p.queries, query)
+	return driver.RowsAffected(0), nil
+}
+
+// TestRowLevelSecurityRole verifies the role statement of tenants and the validation of the
+// options.
+func TestRowLevelSecurityRole(t *testing.T) {
+	pool := &execRecorder{}
+	s := &rowLevelSecurity{setting: "app.current_tenant", role: func(tenantID string) string { return `tenant"` + tenantID }}
+	assert.NoError(t, s.apply(ContextWithTenant(context.Background(), "acme"), pool))
+	assert.Equal(t, []string{"SELECT set_config($1, $2, true)", `SET LOCAL ROLE "tenant""acme"`}, pool.queries)
+
+	_, err := NewGorm(newTestDatabaseContext(), nil, nil, nil, Config{TenantSetting: "app.current_tenant"})
+	assert.ErrorContains(t, err, "TenantSetting needs row-level security, not supported on 'sqlite'")
+}
@@ -0,0 +1,136 @@
+package gormext
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// ErrUnsupportedDriver is returned when an operation is not available for the configured SQL driver.
+var ErrUnsupportedDriver = errors.New("operation not supported by the SQL database driver")
+
+// routineNamePattern matches optionally schema-qualified routine names.
+var routineNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*(\.[A-Za-z_][A-Za-z0-9_]*)?$`)
+
+// OutParam marks a procedure argument as an OUT parameter. Its value is returned in the
+// destination of CallProcedure under the given name.
+type OutParam struct {
+	Name string
+}
+
+// Out creates an OUT parameter placeholder for CallProcedure.
+func Out(name string) OutParam {
+	return OutParam{Name: name}
+}
+
+// CallFunction calls the database function name with args and scans its result into dest.
+// Set-returning Postgres functions fill slices; scalar functions fill single values.
+func (g *Gorm) CallFunction(ctx context.Context, name string, args []any, dest any) error {
+	query, vars, _, err := g.routineCall(false, name, args)
+	if err != nil {
+		return err
+	}
+
+	if err := g.connection.WithContext(ctx).Raw(query, vars...).Scan(dest).Error; err != nil {
+		return fmt.Errorf("failed to call function '%s': %w", name, err)
+	}
+	return nil
+}
+
+// CallProcedure calls the stored procedure name with args. Arguments created with Out are
+// OUT parameters: when present their values are scanned into dest by name, otherwise dest
+// (if not nil) receives the first result set returned by the procedure.
+func (g *Gorm) CallProcedure(ctx context.Context, name string, args []any, dest any) error {
+	query, vars, outs, err := g.routineCall(true, name, args)
+	if err != nil {
+		return err
+	}
+
+	err = g.connection.WithContext(ctx).Connection(func(conn *gorm.DB) error {
+		// Postgres returns OUT parameters as the result row of CALL.
+		if g.databaseCtx.driver == PostgreSQL || len(outs) == 0 {
+			if dest == nil {
+				return conn.Exec(query, vars...).Error
+			}
+			return conn.Raw(query, vars...).Scan(dest).Error
+		}
+
+		if err := conn.Exec(query, vars...).Error; err != nil {
+			return err
+		}
+		if dest == nil {
+			return nil
+		}
+
+		// MySQL returns OUT parameters through session variables.
+		columns := make([]string, len(outs))
+		for i, out := range outs {
+			columns[i] = fmt.Sprintf("@%s AS %s", mysqlOutVariable(out), conn.Statement.Quote(out))
+		}
+		return conn.Raw("SELECT " + strings.Join(columns, ", ")).Scan(dest).Error
+	})
+	if err != nil {
+		return fmt.Errorf("failed to call procedure '%s': %w", name, err)
+	}
+	return nil
+}
+
+// routineCall builds the statement calling a function or procedure for the configured driver,
+// returning the SQL, its bind values and the names of OUT parameters.
+func (g *Gorm) routineCall(procedure bool, name string, args []any) (string, []any, []string, error) {
+	if !routineNamePattern.MatchString(name) {
+		return "", nil, nil, fmt.Errorf("invalid routine name '%s'", name)
+	}
+
+	driver := g.databaseCtx.driver
+	if procedure && driver == SQLite {
+		return "", nil, nil, fmt.Errorf("%w: stored procedures on '%s'", ErrUnsupportedDriver, g.databaseCtx.GetDriverAlias())
+	}
+
+	var (
+		placeholders = make([]string, len(args))
+		vars         []any
+		outs         []string
+	)
+	for i, arg := range args {
+		out, ok := arg.(OutParam)
+		if !ok {
+			placeholders[i] = "?"
+			vars = append(vars, arg)
+			continue
+		}
+
+		if !procedure {
+			return "", nil, nil, fmt.Errorf("OUT parameter '%s' is only supported by procedures", out.Name)
+		}
+		if !routineNamePattern.MatchString(out.Name) || strings.Contains(out.Name, ".") {
+			return "", nil, nil, fmt.Errorf("invalid OUT parameter name '%s'", out.Name)
+		}
+
+		outs = append(outs, out.Name)
+		if driver == PostgreSQL {
+			placeholders[i] = "NULL"
+		} else {
+			placeholders[i] = "@" + mysqlOutVariable(out.Name)
+		}
+	}
+
+	call := fmt.Sprintf("%s(%s)", name, strings.Join(placeholders, ", "))
+	switch {
+	case procedure:
+		return "CALL " + call, vars, outs, nil
+	case driver == PostgreSQL:
+		return "SELECT * FROM " + call, vars, outs, nil
+	default:
+		return "SELECT " + call, vars, outs, nil
+	}
+}
+
+// mysqlOutVariable returns the session variable holding a MySQL OUT parameter.
+func mysqlOutVariable(name string) string {
+	return "gormext_out_" + name
+}
@@ -0,0 +1,54 @@
+package gormext
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestRoutineCallPerDriver verifies the statements generated for each driver.
+func TestRoutineCallPerDriver(t *testing.T) {
+	g := &Gorm{databaseCtx: DatabaseContext{driver: PostgreSQL}}
+
+	query, vars, _, err := g.routineCall(false, "billing.totals", []any{1, "x"})
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM billing.totals(?, ?)", query)
+	assert.Equal(t, []any{1, "x"}, vars)
+
+	query, vars, outs, err := g.routineCall(true, "transfer", []any{10, Out("balance")})
+	assert.NoError(t, err)
+	assert.Equal(t, "CALL transfer(?, NULL)", query)
+	assert.Equal(t, []any{10}, vars)
+	assert.Equal(t, []string{"balance"}, outs)
+
+	g.databaseCtx.driver = MySQL
+	query, _, _, err = g.routineCall(true, "transfer", []any{10, Out("balance")})
+	assert.NoError(t, err)
+	assert.Equal(t, "CALL transfer(?, @gormext_out_balance)", query)
+}
+
+// TestRoutineCallValidation verifies invalid names and unsupported calls are rejected.
+func TestRoutineCallValidation(t *testing.T) {
+	g := &Gorm{databaseCtx: DatabaseContext{driver: PostgreSQL}}
+
+	_, _, _, err := g.routineCall(false, "drop table users; --", nil)
+	assert.ErrorContains(t, err, "invalid routine name")
+
+	_, _, _, err = g.routineCall(false, "fn", []any{Out("x")})
+	assert.ErrorContains(t, err, "only supported by procedures")
+
+	g.databaseCtx.driver = SQLite
+	_, _, _, err = g.routineCall(true, "proc", nil)
+	assert.ErrorIs(t, err, ErrUnsupportedDriver)
+}
+
+// TestCallFunctionSQLite verifies scalar functions are called and scanned.
+func TestCallFunctionSQLite(t *testing.T) {
+	g, _ := newTestRepository(t)
+
+	var result string
+	err := g.CallFunction(context.Background(), "upper", []any{"gormext"}, &result)
+	assert.NoError(t, err)
+	assert.Equal(t, "GORMEXT", result)
+}
@@ -0,0 +1,52 @@
+package gormext
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+const (
+	// RunSeed, RunMigration and RunTenantMigration are the kinds of RunEvent.
+	RunSeed            = "seed"
+	RunMigration       = "migration"
+	RunTenantMigration = "tenant_migration"
+)
+
+// RunEvent reports a completed seed, versioned migration or tenant migration run, such as to
+// export its duration as a metric.
+type RunEvent struct {
+	Kind      string        // RunSeed, RunMigration or RunTenantMigration.
+	Name      string        // Seed name, migration <version>_<name> or tenant ID.
+	Direction string        // "up" or "down" for migrations, empty for seeds.
+	Duration  time.Duration // Duration of the run.
+	Err       error         // Error the run failed with, if any.
+}
+
+// OnRun registers hook to receive every completed seed and migration run. Seeds may run in
+// parallel, so hook must be safe for concurrent use.
+func (g *Gorm) OnRun(hook func(RunEvent)) {
+	g.runHooks = append(g.runHooks, hook)
+}
+
+// PoolStats returns the statistics of the connection pool.
+func (g *Gorm) PoolStats() (sql.DBStats, error) {
+	sqlDB, err := g.connection.DB()
+	if err != nil {
+		return sql.DBStats{}, fmt.Errorf("failed to get database connection: %w", err)
+	}
+	return sqlDB.Stats(), nil
+}
+
+// reportRun completes event with the time elapsed since start and the error *errp points to,
+// and sends it to the run hooks. It is meant to be deferred.
+func (g *Gorm) reportRun(event RunEvent, start time.Time, errp *error) {
+	if len(g.runHooks) == 0 {
+		return
+	}
+
+	event.Duration, event.Err = time.Since(start), *errp
+	for _, hook := range g.runHooks {
+		hook(event)
+	}
+}
@@ -0,0 +1,39 @@
+package gormext
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+// TestOnRun verifies that seed and migration runs are reported with their outcome.
+func TestOnRun(t *testing.T) {
+	g, err := NewGorm(newTestDatabaseContext(), nil, nil, nil)
+	assert.NoError(t, err)
+
+	var events []RunEvent
+	g.OnRun(func(event RunEvent) { events = append(events, event) })
+
+	assert.NoError(t, g.RegisterMigration(Migration{Version: 7, Name: "noop", Up: func(*gorm.DB) error { return nil }}))
+	assert.NoError(t, g.MigrateUp(context.Background()))
+	g.RegisterSeeder(seedFunc{name: "broken", run: func(IRepository) error { return errors.New("boom") }})
+	assert.Error(t, g.Seed())
+
+	if assert.Len(t, events, 2) {
+		assert.Equal(t, RunMigration, events[0].Kind)
+		assert.Equal(t, "7_noop", events[0].Name)
+		assert.Equal(t, "up", events[0].Direction)
+		assert.NoError(t, events[0].Err)
+
+		assert.Equal(t, RunSeed, events[1].Kind)
+		assert.Equal(t, "broken", events[1].Name)
+		assert.ErrorContains(t, events[1].Err, "boom")
+	}
+
+	stats, err := g.PoolStats()
+	assert.NoError(t, err)
+	assert.Positive(t, stats.OpenConnections)
+}
@@ -0,0 +1,119 @@
+package gormext
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// timeType is scanned as a single value, although it is a struct.
+var timeType = reflect.TypeOf(time.Time{})
+
+// SelectInto runs the cached query queryName and scans its rows into dest, a pointer to a
+// struct or to a slice of structs that need not be models, anonymous structs included.
+// Columns map to fields by column name (the snake_case field name or its gorm column tag) or
+// field name; unmatched columns are ignored. A single map[string]any param binds the :name
+// placeholders of the query, other params bind its ? placeholders.
+func (g *Gorm) SelectInto(dest any, queryName string, params ...any) error {
+	return g.selectInto(dest, queryName, false, params)
+}
+
+// SelectIntoStrict is SelectInto failing when a column of the result matches no field of
+// dest, so report queries and their result structs cannot silently drift apart.
+func (g *Gorm) SelectIntoStrict(dest any, queryName string, params ...any) error {
+	return g.selectInto(dest, queryName, true, params)
+}
+
+// selectInto implements SelectInto and SelectIntoStrict.
+func (g *Gorm) selectInto(dest any, queryName string, strict bool, params []any) error {
+	rv := reflect.ValueOf(dest)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return fmt.Errorf("invalid destination %T for sql query '%s': expected a non-nil pointer", dest, queryName)
+	}
+
+	var (
+		query string
+		args  = params
+		err   error
+	)
+	if named, ok := singleNamedParams(params); ok {
+		query, args, err = g.bindNamedQuery(queryName, named)
+	} else {
+		query, err = g.GetQuery(queryName)
+	}
+	if err != nil {
+		return err
+	}
+
+	rows, err := g.queryConn(context.Background(), queryName).Raw(query, args...).Rows()
+	if err != nil {
+		return fmt.Errorf("failed to run sql query '%s': %w", queryName, err)
+	}
+	defer rows.Close()
+
+	if strict {
+		columns, err := rows.Columns()
+		if err != nil {
+			return fmt.Errorf("failed to read columns of sql query '%s': %w", queryName, err)
+		}
+		if err := g.checkColumns(rv.Elem().Type(), columns); err != nil {
+			return fmt.Errorf("sql query '%s' does not match %T: %w", queryName, dest, err)
+		}
+	}
+
+	// Slices are reset like gorm's Scan; a struct is left untouched when no row is returned.
+	if rv.Elem().Kind() == reflect.Slice {
+		rv.Elem().SetLen(0)
+	}
+	if rows.Next() {
+		if err := g.connection.ScanRows(rows, dest); err != nil {
+			return fmt.Errorf("failed to scan sql query '%s': %w", queryName, err)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to run sql query '%s': %w", queryName, err)
+	}
+	return nil
+}
+
+// checkColumns reports the columns that match no field of the struct (or slice element)
+// typ. Destinations that are not structs must receive a single column.
+func (g *Gorm) checkColumns(typ reflect.Type, columns []string) error {
+	for typ.Kind() == reflect.Slice || typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+
+	if typ.Kind() != reflect.Struct || typ.ConvertibleTo(timeType) {
+		if len(columns) != 1 {
+			return fmt.Errorf("%d columns returned for a single value", len(columns))
+		}
+		return nil
+	}
+
+	table, err := g.parseModel(reflect.New(typ).Interface())
+	if err != nil {
+		return err
+	}
+
+	var unmatched []string
+	for _, column := range columns {
+		if field := table.LookUpField(column); field == nil || !field.Readable {
+			unmatched = append(unmatched, column)
+		}
+	}
+	if len(unmatched) > 0 {
+		return fmt.Errorf("unmatched columns '%s'", strings.Join(unmatched, "', '"))
+	}
+	return nil
+}
+
+// singleNamedParams returns the named parameters when params holds only them.
+func singleNamedParams(params []any) (map[string]any, bool) {
+	if len(params) != 1 {
+		return nil, false
+	}
+	named, ok := params[0].(map[string]any)
+	return named, ok
+}
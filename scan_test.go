@@ -0,0 +1,46 @@
+package gormext
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestSelectInto verifies that rows scan into anonymous and non-model structs, and that strict
+// mode reports unmatched columns.
+func TestSelectInto(t *testing.T) {
+	g := newFileSeedGorm(t, t.TempDir())
+	assert.NoError(t, g.GetDB().Exec("CREATE TABLE sales (region TEXT, amount INTEGER)"))
+	assert.NoError(t, g.GetDB().Exec("INSERT INTO sales VALUES ('north', 10), ('north', 5), ('south', 7)"))
+	assert.NoError(t, g.RegisterQuery("report.by_region", "SELECT region, SUM(amount) AS total_amount FROM sales GROUP BY region ORDER BY region"))
+	assert.NoError(t, g.RegisterQuery("report.region", "SELECT region, SUM(amount) AS total_amount, COUNT(*) AS sales FROM sales WHERE region = :region GROUP BY region"))
+	assert.NoError(t, g.RegisterQuery("report.regions", "SELECT region FROM sales WHERE amount > ? ORDER BY region"))
+
+	var totals []struct {
+		Region      string
+		TotalAmount int64
+	}
+	assert.NoError(t, g.SelectIntoStrict(&totals, "report.by_region"))
+	if assert.Len(t, totals, 2) {
+		assert.Equal(t, "north", totals[0].Region)
+		assert.EqualValues(t, 15, totals[0].TotalAmount)
+	}
+
+	type regionTotal struct {
+		Name  string `gorm:"column:region"`
+		Total int64  `gorm:"column:total_amount"`
+	}
+	var total regionTotal
+	assert.NoError(t, g.SelectInto(&total, "report.region", map[string]any{"region": "south"}))
+	assert.Equal(t, regionTotal{Name: "south", Total: 7}, total, "Unmatched columns should be ignored")
+
+	err := g.SelectIntoStrict(&total, "report.region", map[string]any{"region": "south"})
+	assert.ErrorContains(t, err, "unmatched columns 'sales'")
+
+	var regions []string
+	assert.NoError(t, g.SelectIntoStrict(&regions, "report.regions", 6))
+	assert.Equal(t, []string{"north", "south"}, regions)
+
+	assert.ErrorContains(t, g.SelectIntoStrict(&regions, "report.by_region"), "2 columns returned for a single value")
+	assert.ErrorContains(t, g.SelectInto(total, "report.region"), "expected a non-nil pointer")
+}
@@ -0,0 +1,239 @@
+package gormext
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+	"gorm.io/gorm/schema"
+)
+
+// ErrSchemaDrift is returned by Diff.Err when the database schema differs from the models.
+var ErrSchemaDrift = errors.New("database schema differs from the models")
+
+type (
+	// Diff lists the differences between models and the live database schema, as reported by
+	// SchemaDiff.
+	Diff struct {
+		Tables []TableDiff
+	}
+
+	// TableDiff lists the differences between a model and its table.
+	TableDiff struct {
+		Table          string
+		Missing        bool             // The table does not exist; other differences are not reported.
+		MissingColumns []string         // Model columns the table lacks.
+		ExtraColumns   []string         // Table columns no model field maps to.
+		TypeMismatches []ColumnMismatch // Columns whose type differs from the one of the model.
+		MissingIndexes []string         // Model indexes the table lacks.
+		ExtraIndexes   []string         // Table indexes the model does not declare.
+		IndexMismatch  []string         // Indexes whose columns or uniqueness differ from the model.
+	}
+
+	// ColumnMismatch is a column whose database type differs from its model type.
+	ColumnMismatch struct {
+		Column       string
+		ModelType    string
+		DatabaseType string
+	}
+)
+
+// SchemaDiff compares models against the live database schema and reports missing and extra
+// columns, type differences and index mismatches, without changing anything. Types are
+// compared by family and size, so that dialect aliases such as int8 and bigint match.
+//
+// Use it as a startup assertion:
+//
+//	diff, err := g.SchemaDiff(&User{}, &Order{})
+//	if err == nil {
+//		err = diff.Err()
+//	}
+func (g *Gorm) SchemaDiff(models ...any) (*Diff, error) {
+	conn := g.connection.WithContext(context.Background())
+	migrator := conn.Migrator()
+	quiet := conn.Session(&gorm.Session{Logger: logger.Discard}).Migrator()
+
+	diff := &Diff{}
+	for _, model := range models {
+		table, err := g.parseModel(model)
+		if err != nil {
+			return nil, err
+		}
+
+		tableDiff := TableDiff{Table: table.Table}
+		if !migrator.HasTable(model) {
+			tableDiff.Missing = true
+			diff.Tables = append(diff.Tables, tableDiff)
+			continue
+		}
+
+		if err := g.diffColumns(migrator, model, table, &tableDiff); err != nil {
+			return nil, err
+		}
+		if err := g.diffIndexes(quiet, model, table, &tableDiff); err != nil {
+			return nil, err
+		}
+		if !tableDiff.empty() {
+			diff.Tables = append(diff.Tables, tableDiff)
+		}
+	}
+	return diff, nil
+}
+
+// Empty reports whether the models match the database schema.
+func (d *Diff) Empty() bool {
+	return len(d.Tables) == 0
+}
+
+// Err returns nil when the models match the database schema, or an error wrapping
+// ErrSchemaDrift and listing every difference.
+func (d *Diff) Err() error {
+	var problems []error
+	for _, t := range d.Tables {
+		if t.Missing {
+			problems = append(problems, fmt.Errorf("table '%s' is missing", t.Table))
+			continue
+		}
+		for _, column := range t.MissingColumns {
+			problems = append(problems, fmt.Errorf("column '%s.%s' is missing", t.Table, column))
+		}
+		for _, column := range t.ExtraColumns {
+			problems = append(problems, fmt.Errorf("column '%s.%s' is not mapped by the model", t.Table, column))
+		}
+		for _, m := range t.TypeMismatches {
+			problems = append(problems, fmt.Errorf("column '%s.%s' is %s instead of %s", t.Table, m.Column, m.DatabaseType, m.ModelType))
+		}
+		for _, index := range t.MissingIndexes {
+			problems = append(problems, fmt.Errorf("index '%s' of table '%s' is missing", index, t.Table))
+		}
+		for _, index := range t.ExtraIndexes {
+			problems = append(problems, fmt.Errorf("index '%s' of table '%s' is not declared by the model", index, t.Table))
+		}
+		for _, index := range t.IndexMismatch {
+			problems = append(problems, fmt.Errorf("index '%s' of table '%s' differs from the model", index, t.Table))
+		}
+	}
+
+	if err := errors.Join(problems...); err != nil {
+		return fmt.Errorf("%w:\n%w", ErrSchemaDrift, err)
+	}
+	return nil
+}
+
+// empty reports whether the table matches its model.
+func (t *TableDiff) empty() bool {
+	return !t.Missing && len(t.MissingColumns) == 0 && len(t.ExtraColumns) == 0 && len(t.TypeMismatches) == 0 &&
+		len(t.MissingIndexes) == 0 && len(t.ExtraIndexes) == 0 && len(t.IndexMismatch) == 0
+}
+
+// diffColumns compares the columns of table with the fields of its model.
+func (g *Gorm) diffColumns(migrator gorm.Migrator, model any, table *schema.Schema, diff *TableDiff) error {
+	columnTypes, err := migrator.ColumnTypes(model)
+	if err != nil {
+		return fmt.Errorf("failed to read columns of table '%s': %w", table.Table, err)
+	}
+
+	columns := make(map[string]gorm.ColumnType, len(columnTypes))
+	for _, column := range columnTypes {
+		columns[column.Name()] = column
+		if table.LookUpField(column.Name()) == nil {
+			diff.ExtraColumns = append(diff.ExtraColumns, column.Name())
+		}
+	}
+
+	for _, name := range table.DBNames {
+		field := table.FieldsByDBName[name]
+		if field.IgnoreMigration {
+			continue
+		}
+
+		column, ok := columns[name]
+		if !ok {
+			diff.MissingColumns = append(diff.MissingColumns, name)
+			continue
+		}
+
+		modelType := strings.ToLower(g.connection.Dialector.DataTypeOf(field))
+		databaseType := strings.ToLower(column.DatabaseTypeName())
+		if g.typeDiffers(field, column, modelType, databaseType) {
+			diff.TypeMismatches = append(diff.TypeMismatches, ColumnMismatch{Column: name, ModelType: modelType, DatabaseType: databaseType})
+		}
+	}
+	return nil
+}
+
+// typeDiffers reports whether the type of column differs from the one of field by family,
+// integer size or string length.
+func (g *Gorm) typeDiffers(field *schema.Field, column gorm.ColumnType, modelType, databaseType string) bool {
+	modelKind, databaseKind := g.columnKind(modelType), g.columnKind(databaseType)
+	switch {
+	case modelKind.family != databaseKind.family:
+		// MySQL stores booleans as tinyint.
+		return !(modelKind.family == "bool" && databaseType == "tinyint")
+	case modelKind.family == "integer":
+		return modelKind.bits != databaseKind.bits
+	case modelKind.family == "string" && field.Size > 0 && strings.Contains(modelType, "("):
+		length, ok := column.Length()
+		return ok && length > 0 && length != int64(field.Size)
+	}
+	return false
+}
+
+// diffIndexes compares the indexes of table with the ones its model declares. Primary keys and
+// the indexes backing unique constraints are not compared. migrator should discard its logs,
+// since the SQLite driver reads indexes in debug mode.
+func (g *Gorm) diffIndexes(migrator gorm.Migrator, model any, table *schema.Schema, diff *TableDiff) error {
+	indexes, err := migrator.GetIndexes(model)
+	if err != nil {
+		return fmt.Errorf("failed to read indexes of table '%s': %w", table.Table, err)
+	}
+
+	declared := table.ParseIndexes()
+	constraints := make(map[string]bool)
+	for _, field := range table.Fields {
+		if field.Unique {
+			constraints[g.connection.NamingStrategy.UniqueName(table.Table, field.DBName)] = true
+		}
+	}
+
+	found := make(map[string]bool, len(indexes))
+	for _, index := range indexes {
+		name := index.Name()
+		if primary, _ := index.PrimaryKey(); primary || constraints[name] || strings.HasPrefix(name, "sqlite_autoindex_") {
+			continue
+		}
+		found[name] = true
+
+		want, ok := declared[name]
+		switch {
+		case !ok:
+			diff.ExtraIndexes = append(diff.ExtraIndexes, name)
+		case !indexMatches(want, index):
+			diff.IndexMismatch = append(diff.IndexMismatch, name)
+		}
+	}
+
+	for name := range declared {
+		if !found[name] {
+			diff.MissingIndexes = append(diff.MissingIndexes, name)
+		}
+	}
+	slices.Sort(diff.MissingIndexes)
+	return nil
+}
+
+// indexMatches reports whether index has the columns and uniqueness the model declares.
+func indexMatches(want schema.Index, index gorm.Index) bool {
+	columns := make([]string, len(want.Fields))
+	for i, option := range want.Fields {
+		columns[i] = option.DBName
+	}
+	if unique, ok := index.Unique(); ok && unique != (want.Class == "UNIQUE") {
+		return false
+	}
+	return slices.Equal(columns, index.Columns())
+}
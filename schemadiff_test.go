@@ -0,0 +1,66 @@
+package gormext
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type (
+	driftAccount struct {
+		ID      uint
+		Email   string `gorm:"index"`
+		Balance int64
+	}
+
+	driftAccountV2 struct {
+		ID      uint
+		Email   string `gorm:"index:idx_drift_accounts_email,unique"`
+		Balance string
+		Country string `gorm:"index"`
+	}
+)
+
+func (driftAccount) TableName() string   { return "drift_accounts" }
+func (driftAccountV2) TableName() string { return "drift_accounts" }
+
+// TestSchemaDiff verifies that SchemaDiff reports missing tables, columns, type differences and
+// index mismatches.
+func TestSchemaDiff(t *testing.T) {
+	g, err := NewGorm(newTestDatabaseContext(), nil, nil, nil)
+	assert.NoError(t, err)
+
+	diff, err := g.SchemaDiff(&driftAccount{})
+	assert.NoError(t, err)
+	assert.Equal(t, []TableDiff{{Table: "drift_accounts", Missing: true}}, diff.Tables)
+	assert.ErrorContains(t, diff.Err(), "table 'drift_accounts' is missing")
+
+	assert.NoError(t, g.Migrate(&driftAccount{}))
+	assert.NoError(t, g.connection.Exec("ALTER TABLE drift_accounts ADD COLUMN legacy TEXT").Error)
+	assert.NoError(t, g.connection.Exec("CREATE INDEX idx_legacy ON drift_accounts (legacy)").Error)
+
+	diff, err = g.SchemaDiff(&driftAccountV2{})
+	assert.NoError(t, err)
+	assert.False(t, diff.Empty())
+	assert.Equal(t, []TableDiff{{
+		Table:          "drift_accounts",
+		MissingColumns: []string{"country"},
+		ExtraColumns:   []string{"legacy"},
+		TypeMismatches: []ColumnMismatch{{Column: "balance", ModelType: "text", DatabaseType: "integer"}},
+		MissingIndexes: []string{"idx_drift_accounts_country"},
+		ExtraIndexes:   []string{"idx_legacy"},
+		IndexMismatch:  []string{"idx_drift_accounts_email"},
+	}}, diff.Tables)
+
+	err = diff.Err()
+	assert.True(t, errors.Is(err, ErrSchemaDrift))
+	assert.ErrorContains(t, err, "column 'drift_accounts.balance' is integer instead of text")
+
+	assert.NoError(t, g.connection.Exec("DROP INDEX idx_legacy").Error)
+	assert.NoError(t, g.connection.Exec("ALTER TABLE drift_accounts DROP COLUMN legacy").Error)
+	diff, err = g.SchemaDiff(&driftAccount{})
+	assert.NoError(t, err)
+	assert.True(t, diff.Empty())
+	assert.NoError(t, diff.Err())
+}
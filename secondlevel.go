@@ -0,0 +1,236 @@
+package gormext
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/callbacks"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+// primaryKeyCondition matches the SQL conditions of primary key lookups, such as the ones of
+// IDEqual and IDIn: the column, then = or IN.
+var primaryKeyCondition = regexp.MustCompile("(?i)^\\s*[`\"]?(\\w+)[`\"]?\\s*(=|IN)\\s*\\(?\\s*\\?\\s*\\)?\\s*$")
+
+// entityLookup is a primary key lookup of cached entities.
+type entityLookup struct {
+	table string
+	ttl   time.Duration
+	ids   []any
+}
+
+// queryEntities replaces the gorm:query callback with the second-level cache: lookups of cached
+// entities by primary key, such as Find(&users, ids) or IDIn(ids).Find(&users), read the cached
+// entities and query the database for the missing ones only, caching them.
+func (c *resultCache) queryEntities(db *gorm.DB) {
+	lookup, ok := c.entityLookup(db)
+	if !ok {
+		callbacks.Query(db)
+		return
+	}
+
+	stmt := db.Statement
+	ctx := stmt.Context
+	version, err := c.version(ctx, entityTagPrefix+lookup.table)
+	if err != nil {
+		db.Logger.Warn(ctx, "entity cache read: %v", err)
+		callbacks.Query(db)
+		return
+	}
+
+	tenant, _ := TenantFromContext(ctx)
+	key := func(id any) string {
+		return entityKey(lookup.table, version, tenant, fmt.Sprint(id))
+	}
+
+	var hits []reflect.Value
+	var missing []any
+	refresh := cacheModeOf(db) == cacheRefresh
+	for _, id := range lookup.ids {
+		if !refresh {
+			if entity, ok := c.cachedEntity(db, key(id)); ok {
+				hits = append(hits, entity)
+				continue
+			}
+		}
+		missing = append(missing, id)
+	}
+	c.entityCounters.hits.Add(uint64(len(hits)))
+	c.entityCounters.misses.Add(uint64(len(missing)))
+
+	if len(missing) > 0 {
+		if len(hits) > 0 {
+			stmt.Clauses["WHERE"] = clause.Clause{Name: "WHERE", Expression: clause.Where{
+				Exprs: []clause.Expression{clause.IN{Column: clause.PrimaryColumn, Values: missing}},
+			}}
+		}
+		callbacks.Query(db)
+		if db.Error != nil {
+			return
+		}
+
+		// The mask callback runs after this one: cache the entities as masked readers get them.
+		for _, entity := range auditEntities(stmt) {
+			id, _ := stmt.Schema.PrioritizedPrimaryField.ValueOf(ctx, entity)
+			if cached, ok := maskedEntity(db, entity); ok {
+				c.write(db, key(id), cached, lookup.ttl, 0)
+			}
+		}
+	} else if stmt.ReflectValue.Kind() == reflect.Slice {
+		stmt.ReflectValue.SetLen(0)
+	}
+
+	value := stmt.ReflectValue
+	if value.Kind() == reflect.Struct {
+		if len(hits) > 0 {
+			value.Set(hits[0].Elem())
+			stmt.RowsAffected = 1
+		}
+		return
+	}
+	for _, entity := range hits {
+		if value.Type().Elem().Kind() != reflect.Pointer {
+			entity = entity.Elem()
+		}
+		value.Set(reflect.Append(value, entity))
+	}
+	stmt.RowsAffected += int64(len(hits))
+}
+
+// cachedEntity returns the entity cached under key, as a pointer.
+func (c *resultCache) cachedEntity(db *gorm.DB, key string) (reflect.Value, bool) {
+	ctx := db.Statement.Context
+	value, ok, err := c.store.Get(ctx, key)
+	if err != nil {
+		db.Logger.Warn(ctx, "entity cache read: %v", err)
+	}
+	if !ok {
+		return reflect.Value{}, false
+	}
+
+	var entry cacheEntry
+	entity := reflect.New(db.Statement.Schema.ModelType)
+	if json.Unmarshal(value, &entry) != nil || entry.NotFound || decodeResult(entry.Value, entity.Interface()) != nil {
+		return reflect.Value{}, false
+	}
+	return entity, true
+}
+
+// entityLookup returns the lookup of a statement selecting cached entities by primary key only,
+// false for other statements, which read the database.
+func (c *resultCache) entityLookup(db *gorm.DB) (entityLookup, bool) {
+	stmt := db.Statement
+	if db.Error != nil || db.DryRun || stmt.Schema == nil || inTransaction(db) || stmt.SQL.Len() > 0 ||
+		cacheModeOf(db) == cacheBypass || unmaskedRead(db) {
+		return entityLookup{}, false
+	}
+	ttl, ok := c.entities.Load(stmt.Table)
+	if !ok || len(stmt.Schema.PrimaryFields) != 1 || stmt.Table != stmt.Schema.Table {
+		return entityLookup{}, false
+	}
+	if len(stmt.Joins) > 0 || len(stmt.Preloads) > 0 || len(stmt.Selects) > 0 || len(stmt.Omits) > 0 ||
+		stmt.Distinct || stmt.Unscoped {
+		return entityLookup{}, false
+	}
+
+	value := stmt.ReflectValue
+	switch {
+	case value.Kind() == reflect.Struct && value.Type() == stmt.Schema.ModelType:
+		if _, zero := stmt.Schema.PrioritizedPrimaryField.ValueOf(stmt.Context, value); !zero {
+			return entityLookup{}, false
+		}
+	case value.Kind() == reflect.Slice && value.Type().Elem() == stmt.Schema.ModelType:
+	case value.Kind() == reflect.Slice && value.Type().Elem().Kind() == reflect.Pointer &&
+		value.Type().Elem().Elem() == stmt.Schema.ModelType:
+	default:
+		return entityLookup{}, false
+	}
+
+	for name := range stmt.Clauses {
+		switch name {
+		case "WHERE":
+		case "ORDER BY", "LIMIT":
+			// First and Take of a single entity order and limit by primary key.
+			if value.Kind() != reflect.Struct {
+				return entityLookup{}, false
+			}
+		default:
+			return entityLookup{}, false
+		}
+	}
+
+	where, ok := stmt.Clauses["WHERE"].Expression.(clause.Where)
+	if !ok || len(where.Exprs) != 1 {
+		return entityLookup{}, false
+	}
+	ids, ok := primaryKeyValues(stmt.Schema, where.Exprs[0])
+	if !ok || len(ids) == 0 || (value.Kind() == reflect.Struct && len(ids) != 1) {
+		return entityLookup{}, false
+	}
+	return entityLookup{table: stmt.Table, ttl: ttl.(time.Duration), ids: ids}, true
+}
+
+// primaryKeyValues returns the distinct primary keys of a condition on the primary key, false
+// for other conditions.
+func primaryKeyValues(s *schema.Schema, condition clause.Expression) ([]any, bool) {
+	primaryKey := func(column any) bool {
+		switch column := column.(type) {
+		case clause.Column:
+			return column.Name == clause.PrimaryKey || column.Name == s.PrioritizedPrimaryField.DBName
+		case string:
+			return column == s.PrioritizedPrimaryField.DBName
+		}
+		return false
+	}
+
+	var values []any
+	switch condition := condition.(type) {
+	case clause.Eq:
+		if !primaryKey(condition.Column) {
+			return nil, false
+		}
+		values = []any{condition.Value}
+	case clause.IN:
+		if !primaryKey(condition.Column) {
+			return nil, false
+		}
+		values = condition.Values
+	case clause.Expr:
+		match := primaryKeyCondition.FindStringSubmatch(condition.SQL)
+		if match == nil || !primaryKey(match[1]) || len(condition.Vars) != 1 {
+			return nil, false
+		}
+		values = []any{condition.Vars[0]}
+		if strings.EqualFold(match[2], "IN") {
+			list := reflect.ValueOf(condition.Vars[0])
+			if list.Kind() != reflect.Slice {
+				return nil, false
+			}
+			values = make([]any, list.Len())
+			for i := range values {
+				values[i] = list.Index(i).Interface()
+			}
+		}
+	default:
+		return nil, false
+	}
+
+	seen := make(map[string]bool, len(values))
+	ids := make([]any, 0, len(values))
+	for _, value := range values {
+		if reflect.ValueOf(value).Kind() == reflect.Slice || value == nil {
+			return nil, false
+		}
+		if key := fmt.Sprint(value); !seen[key] {
+			seen[key] = true
+			ids = append(ids, value)
+		}
+	}
+	return ids, true
+}
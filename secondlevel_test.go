@@ -0,0 +1,73 @@
+package gormext
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// TestSecondLevelCache verifies that primary key lookups of cached entities are served from the
+// cache, querying the database for the missing entities only.
+func TestSecondLevelCache(t *testing.T) {
+	g, err := NewGorm(newTestDatabaseContext(), nil, nil, nil, Config{
+		Config:           gorm.Config{Logger: logger.Discard},
+		Cache:            NewMemoryCache(100),
+		SecondLevelCache: true,
+	})
+	assert.NoError(t, err)
+	assert.NoError(t, g.Migrate(&repoUser{}))
+	assert.NoError(t, g.CacheEntity(&repoUser{}, time.Minute))
+	repo := g.GetDB()
+	assert.NoError(t, repo.Create(&[]repoUser{{Name: "alice"}, {Name: "bob"}, {Name: "carol"}}))
+
+	// Statements reaching the database, with their arguments.
+	var queries [][]any
+	assert.NoError(t, g.connection.Callback().Query().After("gorm:query").Register("test:queries", func(db *gorm.DB) {
+		if db.Statement.SQL.Len() > 0 {
+			queries = append(queries, db.Statement.Vars)
+		}
+	}))
+
+	var users []repoUser
+	assert.NoError(t, repo.IDIn([]any{1, 2}).Find(&users))
+	assert.Len(t, users, 2)
+	assert.Len(t, queries, 1)
+
+	users = nil
+	assert.NoError(t, repo.IDIn([]any{1, 2}).Find(&users))
+	assert.Len(t, users, 2)
+	assert.Len(t, queries, 1, "cached entities are not queried")
+
+	var pointers []*repoUser
+	assert.NoError(t, g.connection.Find(&pointers, []uint{1, 2, 3}).Error)
+	assert.Len(t, pointers, 3)
+	assert.Len(t, queries, 2)
+	assert.Equal(t, []any{uint(3)}, queries[1], "missing entities only are queried")
+
+	assert.NoError(t, repo.Exec("UPDATE repo_users SET name = 'raw'"))
+	var user repoUser
+	assert.NoError(t, repo.IDEqual(2).First(&user))
+	assert.Equal(t, "bob", user.Name)
+	assert.Len(t, queries, 2)
+
+	assert.NoError(t, repo.Update(&repoUser{ID: 2, Name: "robert"}))
+	user = repoUser{}
+	assert.NoError(t, g.connection.First(&user, 2).Error)
+	assert.Equal(t, "robert", user.Name, "writes invalidate their entities")
+	assert.Len(t, queries, 3)
+
+	assert.NoError(t, repo.Where("name = ?", "robert").Find(&users))
+	assert.ErrorIs(t, repo.IDEqual(9).First(&user), gorm.ErrRecordNotFound)
+	assert.Len(t, queries, 5, "other queries and misses read the database")
+
+	var bypassed, refreshed, cached repoUser
+	assert.NoError(t, repo.NoCache().IDEqual(1).First(&bypassed))
+	assert.NoError(t, repo.RefreshCache().IDEqual(1).First(&refreshed))
+	assert.Len(t, queries, 7, "bypassed and refreshed lookups read the database")
+	assert.NoError(t, repo.IDEqual(1).First(&cached))
+	assert.Equal(t, "raw", cached.Name)
+	assert.Len(t, queries, 7, "refreshed lookups replace the cached entities")
+}
@@ -0,0 +1,613 @@
+package gormext
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/logger"
+	"gorm.io/gorm/schema"
+)
+
+// seedDryRunPreview is the number of characters of each statement logged by a dry run.
+const seedDryRunPreview = 200
+
+// Seed transaction modes.
+const (
+	SeedTxPerFile SeedTransactionMode = iota // Each seed file runs in its own transaction (default).
+	SeedTxRun                                // The whole run is one transaction; files run serially.
+	SeedTxNone                               // Statements run without an explicit transaction.
+)
+
+type (
+	// SeedOption configures a Seed run.
+	SeedOption func(*seedOptions)
+
+	// SeedTransactionMode selects how a Seed run is wrapped in transactions.
+	SeedTransactionMode int
+
+	// seedOptions holds the settings of a Seed run.
+	seedOptions struct {
+		parallelism  int
+		dependencies map[string][]string
+		force        bool
+		dryRun       bool
+		txMode       SeedTransactionMode
+		tenants      []string
+		perTenant    bool
+	}
+
+	// Seeder is a seed implemented in Go, for seeds needing logic such as hashing passwords.
+	// Seeders are tracked by name, so each runs once unless reseeding is forced.
+	Seeder interface {
+		Name() string               // Unique name, used for tracking and dependencies.
+		Run(repo IRepository) error // Insert the seed data.
+	}
+
+	// seedUnit is a seed file, data file or Go seeder, in declared order.
+	seedUnit struct {
+		name   string // File path or seeder name.
+		seeder Seeder // Nil for seed files.
+		model  any    // Model or table name of data files.
+	}
+
+	// SeedError reports the seed file and statement that failed.
+	SeedError struct {
+		File      string // Path of the seed file.
+		Statement int    // 1-based index of the failed statement in the file.
+		SQL       string // Failed statement.
+		Err       error  // Database error.
+	}
+
+	// seedRecord tracks an applied seed file by the checksum of its content.
+	seedRecord struct {
+		Checksum  string `gorm:"primaryKey;size:64"`
+		Path      string
+		AppliedAt time.Time
+	}
+)
+
+// TableName returns the seed tracking table name.
+func (seedRecord) TableName() string {
+	return "gormext_seeds"
+}
+
+// WithSeedParallelism runs up to n independent seed files concurrently. Files are independent
+// only when a dependency graph is declared with WithSeedDependencies; otherwise each file
+// depends on the previous one and the run stays serial.
+func WithSeedParallelism(n int) SeedOption {
+	return func(o *seedOptions) { o.parallelism = n }
+}
+
+// WithSeedDependencies declares which seeds each seed depends on, by file path or seeder name.
+// Seeds run only after all their dependencies succeeded; seeds without declared dependencies
+// are independent.
+func WithSeedDependencies(dependencies map[string][]string) SeedOption {
+	return func(o *seedOptions) { o.dependencies = dependencies }
+}
+
+// WithSeedTransaction selects how the run is wrapped in transactions. Note that MySQL commits
+// DDL statements implicitly, so they cannot be rolled back.
+func WithSeedTransaction(mode SeedTransactionMode) SeedOption {
+	return func(o *seedOptions) { o.txMode = mode }
+}
+
+// Error describes the failed statement.
+func (e *SeedError) Error() string {
+	return fmt.Sprintf("failed to execute seed query from file '%s' (statement %d): %v", e.File, e.Statement, e.Err)
+}
+
+// Unwrap returns the database error.
+func (e *SeedError) Unwrap() error {
+	return e.Err
+}
+
+// RegisterSeeder appends a Go seeder to the seeds run by Seed, after the seeds declared so far.
+// It must not be called concurrently with Seed.
+func (g *Gorm) RegisterSeeder(seeder Seeder) {
+	g.seeds = append(g.seeds, seedUnit{name: seeder.Name(), seeder: seeder})
+}
+
+// RegisterSeedFile appends a seed file to the seeds run by Seed, after the seeds declared so
+// far, to interleave files with seeders. It must not be called concurrently with Seed.
+func (g *Gorm) RegisterSeedFile(path string) {
+	g.seeds = append(g.seeds, seedUnit{name: path})
+}
+
+// WithForceReseed executes every seed, including those already applied.
+func WithForceReseed() SeedOption {
+	return func(o *seedOptions) { o.force = true }
+}
+
+// WithDryRun logs every statement that would run, with its file, index and first characters,
+// and the seeders and data files that would run, without executing or recording anything.
+// Seeds run serially in a dry run.
+func WithDryRun() SeedOption {
+	return func(o *seedOptions) { o.dryRun = true }
+}
+
+// Seed executes seed queries to initialize the database. It is allowed during maintenance mode.
+// Seed files may be compressed (.sql.gz) or encrypted (.sql.enc, .sql.gz.enc). Applied files
+// are tracked by content checksum in the gormext_seeds table and skipped on later runs, unless
+// WithForceReseed is given; changing a file makes it run again. Each file runs in its own
+// transaction unless configured with WithSeedTransaction, and failures are reported as a
+// *SeedError naming the file and statement. Seeders registered with RegisterSeeder run
+// interleaved with the files, in declared order. Seeding holds the migration lock, so that
+// instances starting together do not seed twice. WithSeedTenants runs the seeds per tenant.
+//
+// Seed files may declare their dependencies in leading comments, by path relative to the
+// file or seeder name, adding to those given with WithSeedDependencies:
+//
+//	-- depends: roles.sql, permissions.sql
+func (g *Gorm) Seed(opts ...SeedOption) error {
+	return g.seedContext(context.Background(), opts...)
+}
+
+// seedContext runs Seed with the statements in ctx.
+func (g *Gorm) seedContext(ctx context.Context, opts ...SeedOption) error {
+	options := seedOptions{parallelism: 1}
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	return g.withMigrationLock(ctx, func() error {
+		return g.seed(ctx, options)
+	})
+}
+
+// seed implements Seed, holding the migration lock.
+func (g *Gorm) seed(ctx context.Context, options seedOptions) error {
+	names := make([]string, len(g.seeds))
+	for i, unit := range g.seeds {
+		names[i] = unit.name
+	}
+
+	declared, err := g.seedDependencies(names, options.dependencies)
+	if err != nil {
+		return err
+	}
+	dependencies, err := seedGraph(names, declared)
+	if err != nil {
+		return err
+	}
+	order, err := seedOrder(names, dependencies)
+	if err != nil {
+		return err
+	}
+
+	ctx = WithMaintenanceBypass(ctx)
+	if options.perTenant {
+		return g.seedTenants(ctx, order, dependencies, options)
+	}
+	return g.seedWith(g.connection.WithContext(ctx), order, dependencies, options)
+}
+
+// seedWith runs the seeds in order, or by dependencies in parallel, on conn.
+func (g *Gorm) seedWith(conn *gorm.DB, order []int, dependencies [][]int, options seedOptions) error {
+	if options.dryRun {
+		return g.seedSerial(conn, order, options)
+	}
+	if err := conn.AutoMigrate(&seedRecord{}); err != nil {
+		return fmt.Errorf("failed to create seed tracking table: %w", err)
+	}
+
+	if options.txMode == SeedTxRun {
+		return conn.Transaction(func(tx *gorm.DB) error {
+			return g.seedSerial(tx, order, options)
+		})
+	}
+	if options.parallelism > 1 {
+		return g.seedParallel(conn, dependencies, options)
+	}
+	return g.seedSerial(conn, order, options)
+}
+
+// seedSerial runs the seeds one at a time in the given order.
+func (g *Gorm) seedSerial(conn *gorm.DB, order []int, options seedOptions) error {
+	for _, i := range order {
+		if err := g.runSeed(conn, g.seeds[i], options); err != nil {
+			return err
+		}
+
+		time.Sleep(10 * time.Millisecond) // Throttle to avoid overwhelming the database.
+	}
+	return nil
+}
+
+// seedParallel runs the seeds concurrently, bounded by parallelism, each after its
+// dependencies. Seeds whose dependencies failed are skipped.
+func (g *Gorm) seedParallel(conn *gorm.DB, dependencies [][]int, options seedOptions) error {
+	var (
+		wg     sync.WaitGroup
+		slots  = make(chan struct{}, options.parallelism)
+		done   = make([]chan struct{}, len(dependencies))
+		failed = make([]bool, len(dependencies))
+		errs   = make([]error, len(dependencies))
+	)
+	for i := range done {
+		done[i] = make(chan struct{})
+	}
+
+	// Each seed only writes its own result before closing done, which publishes it to dependents.
+	for i := range dependencies {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer close(done[i])
+
+			for _, dep := range dependencies[i] {
+				if <-done[dep]; failed[dep] {
+					failed[i] = true
+					return
+				}
+			}
+
+			slots <- struct{}{}
+			errs[i] = g.runSeed(conn, g.seeds[i], options)
+			failed[i] = errs[i] != nil
+			<-slots
+		}()
+	}
+
+	wg.Wait()
+	return errors.Join(errs...)
+}
+
+// runSeed runs a seed file, data file or seeder.
+func (g *Gorm) runSeed(conn *gorm.DB, unit seedUnit, options seedOptions) (err error) {
+	defer g.reportRun(RunEvent{Kind: RunSeed, Name: unit.name}, time.Now(), &err)
+
+	switch {
+	case unit.model != nil:
+		return g.seedData(conn, unit, options)
+	case unit.seeder == nil:
+		return g.seedFile(conn, unit.name, options)
+	}
+
+	sum := sha256.Sum256([]byte("seeder:" + unit.name))
+	return g.applySeed(conn, unit.name, hex.EncodeToString(sum[:]), options, func(tx *gorm.DB) error {
+		if options.dryRun {
+			g.seedLog("seeder '%s' would run", unit.name)
+			return nil
+		}
+		if err := unit.seeder.Run(g.repository(tx)); err != nil {
+			return fmt.Errorf("failed to run seeder '%s': %w", unit.name, err)
+		}
+		return nil
+	})
+}
+
+// seedFile reads and executes a single seed file.
+func (g *Gorm) seedFile(conn *gorm.DB, queryPath string, options seedOptions) error {
+	content, err := g.readFile(queryPath)
+	if err != nil {
+		return fmt.Errorf("failed to read seed file '%s': %w", queryPath, err)
+	}
+
+	sum := sha256.Sum256(content)
+	if tenantID, ok := TenantFromContext(conn.Statement.Context); ok {
+		if content, err = g.renderTenantSeed(queryPath, content, tenantID); err != nil {
+			return err
+		}
+	}
+
+	return g.applySeed(conn, queryPath, hex.EncodeToString(sum[:]), options, func(tx *gorm.DB) error {
+		for i, statement := range splitStatements(string(content), g.databaseCtx.driver) {
+			if options.dryRun {
+				g.seedLog("seed file '%s' statement %d would run: %s", queryPath, i+1, truncateSQL(statement, seedDryRunPreview))
+				continue
+			}
+			if err := tx.Exec(statement).Error; err != nil {
+				return &SeedError{File: queryPath, Statement: i + 1, SQL: statement, Err: err}
+			}
+		}
+		return nil
+	})
+}
+
+// applySeed runs apply unless the seed identified by checksum was already applied, then
+// records it, within a transaction when the mode asks for one per seed. In a dry run, apply
+// runs without a transaction and nothing is recorded.
+func (g *Gorm) applySeed(conn *gorm.DB, name, checksum string, options seedOptions, apply func(tx *gorm.DB) error) error {
+	checksum = tenantChecksum(conn, checksum)
+	if !options.force && (!options.dryRun || conn.Migrator().HasTable(&seedRecord{})) {
+		var applied int64
+		if err := conn.Model(&seedRecord{}).Where("checksum = ?", checksum).Count(&applied).Error; err != nil {
+			return fmt.Errorf("failed to check seed '%s': %w", name, err)
+		}
+		if applied > 0 {
+			return nil
+		}
+	}
+
+	if options.dryRun {
+		return apply(conn)
+	}
+
+	run := func(tx *gorm.DB) error {
+		if err := apply(tx); err != nil {
+			return err
+		}
+
+		record := seedRecord{Checksum: checksum, Path: name, AppliedAt: time.Now().UTC()}
+		if err := tx.Clauses(clause.OnConflict{UpdateAll: true}).Create(&record).Error; err != nil {
+			return fmt.Errorf("failed to record seed '%s': %w", name, err)
+		}
+		return nil
+	}
+
+	if options.txMode == SeedTxPerFile {
+		return conn.Transaction(run)
+	}
+	return run(conn)
+}
+
+// seedLog logs a dry run message, whatever the log level of the connection.
+func (g *Gorm) seedLog(format string, args ...any) {
+	g.connection.Logger.LogMode(logger.Info).Info(context.Background(), format, args...)
+}
+
+// truncateSQL returns the first n characters of statement on a single line, marking cuts.
+func truncateSQL(statement string, n int) string {
+	statement = strings.Join(strings.Fields(statement), " ")
+	if runes := []rune(statement); len(runes) > n {
+		return string(runes[:n]) + "..."
+	}
+	return statement
+}
+
+// seedDependencies merges the dependencies declared in the headers of seed files with the
+// given ones. It returns nil when none are declared.
+func (g *Gorm) seedDependencies(names []string, given map[string][]string) (map[string][]string, error) {
+	known := make(map[string]bool, len(names))
+	for _, name := range names {
+		known[name] = true
+	}
+
+	declared := make(map[string][]string, len(given))
+	for name, deps := range given {
+		declared[name] = append(declared[name], deps...)
+	}
+
+	for _, unit := range g.seeds {
+		if unit.seeder != nil || unit.model != nil {
+			continue
+		}
+
+		content, err := g.readFile(unit.name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read seed file '%s': %w", unit.name, err)
+		}
+		for _, dep := range seedHeaderDependencies(string(content)) {
+			// Paths are relative to the declaring file, unless they name a seed as is.
+			if relative := filepath.Join(filepath.Dir(unit.name), dep); !known[dep] && known[relative] {
+				dep = relative
+			}
+			declared[unit.name] = append(declared[unit.name], dep)
+		}
+	}
+
+	if len(declared) == 0 {
+		return nil, nil
+	}
+	return declared, nil
+}
+
+// seedHeaderDependencies returns the dependencies declared by "-- depends:" lines among the
+// comments leading a seed file.
+func seedHeaderDependencies(content string) []string {
+	var deps []string
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		comment, ok := strings.CutPrefix(line, "--")
+		if !ok {
+			break
+		}
+
+		value, ok := strings.CutPrefix(strings.TrimSpace(comment), "depends:")
+		if !ok {
+			continue
+		}
+		for _, dep := range strings.Split(value, ",") {
+			if dep = strings.TrimSpace(dep); dep != "" {
+				deps = append(deps, dep)
+			}
+		}
+	}
+	return deps
+}
+
+// seedGraph resolves declared dependencies between seed names into indexes of names. Without
+// declarations every seed depends on the one before it.
+func seedGraph(names []string, declared map[string][]string) ([][]int, error) {
+	dependencies := make([][]int, len(names))
+	if declared == nil {
+		for i := 1; i < len(names); i++ {
+			dependencies[i] = []int{i - 1}
+		}
+		return dependencies, nil
+	}
+
+	index := make(map[string]int, len(names))
+	for i, name := range names {
+		index[name] = i
+	}
+
+	for name, deps := range declared {
+		i, ok := index[name]
+		if !ok {
+			return nil, fmt.Errorf("seed dependencies declared for unknown seed '%s'", name)
+		}
+		for _, dep := range deps {
+			j, ok := index[dep]
+			if !ok {
+				return nil, fmt.Errorf("seed '%s' depends on unknown seed '%s'", name, dep)
+			}
+			dependencies[i] = append(dependencies[i], j)
+		}
+	}
+	return dependencies, nil
+}
+
+// seedOrder sorts the seed graph topologically, keeping the declared order among independent
+// seeds, and fails on dependency cycles.
+func seedOrder(names []string, dependencies [][]int) ([]int, error) {
+	visited := make([]int, len(dependencies)) // 0: new, 1: in progress, 2: done.
+	order := make([]int, 0, len(dependencies))
+
+	var visit func(i int) error
+	visit = func(i int) error {
+		switch visited[i] {
+		case 1:
+			return fmt.Errorf("seed '%s' is part of a dependency cycle", names[i])
+		case 2:
+			return nil
+		}
+
+		visited[i] = 1
+		for _, dep := range dependencies[i] {
+			if err := visit(dep); err != nil {
+				return err
+			}
+		}
+		visited[i] = 2
+		order = append(order, i)
+		return nil
+	}
+
+	for i := range dependencies {
+		if err := visit(i); err != nil {
+			return nil, err
+		}
+	}
+	return order, nil
+}
+
+// SeedEntities inserts seed entities (struct pointers or slices of structs) through the
+// repository, so hooks and ID generation apply. Entities whose natural key, the fields tagged
+// `gormext:"naturalKey"` (or the primary key when set and no field is tagged), already matches
+// a row are skipped. It is allowed during maintenance mode.
+func (g *Gorm) SeedEntities(ctx context.Context, entities ...any) error {
+	repo := g.GetDB().WithContext(WithMaintenanceBypass(ctx))
+	for _, entity := range entities {
+		for _, row := range seedRows(entity) {
+			if err := g.seedEntity(repo, row); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// SeedIfEmpty inserts rows with SeedEntities only when the table of model has no rows.
+func (g *Gorm) SeedIfEmpty(model any, rows any) error {
+	table, err := g.parseModel(model)
+	if err != nil {
+		return err
+	}
+
+	var count int64
+	if err := g.GetDB().Table(table.Table).Count(&count); err != nil {
+		return fmt.Errorf("failed to count rows of '%s': %w", table.Table, err)
+	}
+	if count > 0 {
+		return nil
+	}
+	return g.SeedEntities(context.Background(), rows)
+}
+
+// seedEntity creates row unless a row with the same natural key exists.
+func (g *Gorm) seedEntity(repo IRepository, row any) error {
+	table, err := g.parseModel(row)
+	if err != nil {
+		return err
+	}
+
+	key := naturalKey(table, reflect.Indirect(reflect.ValueOf(row)))
+	if len(key) > 0 {
+		var count int64
+		if err := repo.Table(table.Table).Where(key).Count(&count); err != nil {
+			return fmt.Errorf("failed to look up seed entity of '%s': %w", table.Table, err)
+		}
+		if count > 0 {
+			return nil
+		}
+	}
+
+	if err := repo.Create(row); err != nil {
+		return fmt.Errorf("failed to create seed entity of '%s': %w", table.Table, err)
+	}
+	return nil
+}
+
+// parseModel returns the schema of model.
+func (g *Gorm) parseModel(model any) (*schema.Schema, error) {
+	stmt := &gorm.Statement{DB: g.connection}
+	if err := stmt.Parse(model); err != nil {
+		return nil, fmt.Errorf("failed to parse model %T: %w", model, err)
+	}
+	return stmt.Schema, nil
+}
+
+// naturalKey returns the column values identifying value: its fields tagged as natural key,
+// or its primary key when none is tagged and it is set.
+func naturalKey(table *schema.Schema, value reflect.Value) map[string]any {
+	key := make(map[string]any)
+	for _, field := range table.Fields {
+		if _, ok := schema.ParseTagSetting(field.Tag.Get("gormext"), ";")["NATURALKEY"]; ok {
+			fieldValue, _ := field.ValueOf(context.Background(), value)
+			key[field.DBName] = fieldValue
+		}
+	}
+	if len(key) > 0 {
+		return key
+	}
+
+	for _, field := range table.PrimaryFields {
+		fieldValue, zero := field.ValueOf(context.Background(), value)
+		if zero {
+			return nil
+		}
+		key[field.DBName] = fieldValue
+	}
+	return key
+}
+
+// seedRows expands entity into pointers to its rows when it is a slice or array.
+func seedRows(entity any) []any {
+	value := reflect.ValueOf(entity)
+	if value.Kind() == reflect.Ptr && (value.Elem().Kind() == reflect.Slice || value.Elem().Kind() == reflect.Array) {
+		value = value.Elem()
+	}
+	if value.Kind() != reflect.Slice && value.Kind() != reflect.Array {
+		return []any{entity}
+	}
+
+	rows := make([]any, value.Len())
+	for i := range rows {
+		row := value.Index(i)
+		if row.Kind() == reflect.Ptr {
+			rows[i] = row.Interface()
+			continue
+		}
+		if !row.CanAddr() {
+			copied := reflect.New(row.Type())
+			copied.Elem().Set(row)
+			row = copied.Elem()
+		}
+		rows[i] = row.Addr().Interface()
+	}
+	return rows
+}
@@ -0,0 +1,284 @@
+package gormext
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// newFileSeedGorm creates a Gorm instance over a SQLite file, shared by concurrent connections,
+// seeding the given files of dir.
+func newFileSeedGorm(t *testing.T, dir string, files ...string) *Gorm {
+	dbCtx, err := NewDatabaseContext(filepath.Join(t.TempDir(), "seed.db")+"?_busy_timeout=5000", "sqlite", "silent")
+	assert.NoError(t, err)
+
+	paths := make([]string, len(files))
+	for i, file := range files {
+		paths[i] = filepath.Join(dir, file)
+	}
+
+	g, err := NewGorm(*dbCtx, nil, paths, map[string]string{})
+	assert.NoError(t, err, "Unexpected error from NewGorm")
+	return g
+}
+
+// TestSeedParallelDependencies verifies dependent seed files run after their dependencies.
+func TestSeedParallelDependencies(t *testing.T) {
+	dir := t.TempDir()
+	writeSQLFiles(t, dir, map[string]string{
+		"users.sql":  "INSERT INTO users (id, role_id) VALUES (1, 1), (2, 2);",
+		"roles.sql":  "INSERT INTO roles (id) VALUES (1), (2);",
+		"schema.sql": "CREATE TABLE roles (id INTEGER PRIMARY KEY); CREATE TABLE users (id INTEGER PRIMARY KEY, role_id INTEGER REFERENCES roles(id)); CREATE TABLE tags (id INTEGER);",
+		"tags.sql":   "INSERT INTO tags (id) VALUES (1);",
+	})
+	g := newFileSeedGorm(t, dir, "users.sql", "roles.sql", "schema.sql", "tags.sql")
+
+	path := func(name string) string { return filepath.Join(dir, name) }
+	err := g.Seed(WithSeedParallelism(4), WithSeedDependencies(map[string][]string{
+		path("users.sql"): {path("roles.sql")},
+		path("roles.sql"): {path("schema.sql")},
+		path("tags.sql"):  {path("schema.sql")},
+	}))
+	assert.NoError(t, err)
+
+	var users int64
+	assert.NoError(t, g.GetDB().Table("users").Count(&users))
+	assert.EqualValues(t, 2, users)
+}
+
+// TestSeedDependencyErrors verifies failures skip dependents and invalid graphs are rejected.
+func TestSeedDependencyErrors(t *testing.T) {
+	dir := t.TempDir()
+	writeSQLFiles(t, dir, map[string]string{
+		"a.sql": "INSERT INTO missing VALUES (1);",
+		"b.sql": "CREATE TABLE b (id INTEGER);",
+	})
+	g := newFileSeedGorm(t, dir, "a.sql", "b.sql")
+	a, b := filepath.Join(dir, "a.sql"), filepath.Join(dir, "b.sql")
+
+	err := g.Seed(WithSeedParallelism(2), WithSeedDependencies(map[string][]string{b: {a}}))
+	assert.ErrorContains(t, err, "a.sql")
+	assert.False(t, g.connection.Migrator().HasTable("b"), "Dependents of a failed file should be skipped")
+
+	err = g.Seed(WithSeedDependencies(map[string][]string{a: {b}, b: {a}}))
+	assert.ErrorContains(t, err, "dependency cycle")
+
+	err = g.Seed(WithSeedDependencies(map[string][]string{a: {"unknown.sql"}}))
+	assert.ErrorContains(t, err, "unknown seed 'unknown.sql'")
+}
+
+// seedRole is a seed model identified by its natural key.
+type seedRole struct {
+	ID   uint   `gorm:"primaryKey"`
+	Code string `gormext:"naturalKey"`
+	Name string
+	Slug string
+}
+
+// BeforeCreate derives the slug, checking seed entities go through hooks.
+func (r *seedRole) BeforeCreate(*gorm.DB) error {
+	r.Slug = strings.ToLower(r.Code)
+	return nil
+}
+
+// TestSeedEntities verifies entities are created through hooks and duplicates by natural key are skipped.
+func TestSeedEntities(t *testing.T) {
+	g, _ := newTestRepository(t)
+	assert.NoError(t, g.Migrate(&seedRole{}))
+
+	roles := []seedRole{{Code: "ADMIN", Name: "Administrator"}, {Code: "USER", Name: "User"}}
+	assert.NoError(t, g.SeedEntities(context.Background(), roles, &seedRole{Code: "GUEST"}))
+	assert.NoError(t, g.SeedEntities(context.Background(), &roles, &seedRole{Code: "GUEST", Name: "changed"}))
+
+	var stored []seedRole
+	assert.NoError(t, g.GetDB().Order("id").Find(&stored))
+	if assert.Len(t, stored, 3, "Seeding twice should not duplicate rows") {
+		assert.Equal(t, "admin", stored[0].Slug)
+		assert.Empty(t, stored[2].Name)
+	}
+
+	assert.NoError(t, g.SeedIfEmpty(&seedRole{}, []seedRole{{Code: "OTHER"}}))
+	assert.NoError(t, g.SeedIfEmpty(&repoUser{}, []repoUser{{Name: "ann"}, {Name: "ann"}}))
+
+	var roleCount, userCount int64
+	assert.NoError(t, g.GetDB().Table("seed_roles").Count(&roleCount))
+	assert.NoError(t, g.GetDB().Table("repo_users").Count(&userCount))
+	assert.EqualValues(t, 3, roleCount, "Non-empty tables should not be seeded")
+	assert.EqualValues(t, 2, userCount, "Rows without natural key should all be created")
+}
+
+// TestSeedTracking verifies applied seed files are skipped unless forced or changed.
+func TestSeedTracking(t *testing.T) {
+	dir := t.TempDir()
+	writeSQLFiles(t, dir, map[string]string{
+		"schema.sql": "CREATE TABLE IF NOT EXISTS events (name TEXT);",
+		"events.sql": "INSERT INTO events (name) VALUES ('boot');",
+	})
+	g := newFileSeedGorm(t, dir, "schema.sql", "events.sql")
+
+	count := func() int64 {
+		var n int64
+		assert.NoError(t, g.GetDB().Table("events").Count(&n))
+		return n
+	}
+
+	assert.NoError(t, g.Seed())
+	assert.NoError(t, g.Seed())
+	assert.EqualValues(t, 1, count(), "Applied seed files should be skipped")
+
+	assert.NoError(t, g.Seed(WithForceReseed()))
+	assert.EqualValues(t, 2, count(), "Forced runs should execute every file")
+
+	writeSQLFiles(t, dir, map[string]string{"events.sql": "INSERT INTO events (name) VALUES ('changed');"})
+	assert.NoError(t, g.Seed())
+	assert.EqualValues(t, 3, count(), "Changed seed files should run again")
+
+	var records int64
+	assert.NoError(t, g.GetDB().Table("gormext_seeds").Count(&records))
+	assert.EqualValues(t, 3, records)
+}
+
+// TestSeedTransactions verifies failed files roll back and errors name the file and statement.
+func TestSeedTransactions(t *testing.T) {
+	dir := t.TempDir()
+	writeSQLFiles(t, dir, map[string]string{
+		"schema.sql": "CREATE TABLE items (name TEXT UNIQUE);",
+		"items.sql":  "INSERT INTO items VALUES ('a');\nINSERT INTO items VALUES ('b');\nINSERT INTO items VALUES ('a');",
+	})
+	g := newFileSeedGorm(t, dir, "schema.sql", "items.sql")
+
+	count := func() int64 {
+		var n int64
+		assert.NoError(t, g.GetDB().Table("items").Count(&n))
+		return n
+	}
+
+	err := g.Seed()
+	var seedErr *SeedError
+	if assert.ErrorAs(t, err, &seedErr) {
+		assert.Equal(t, filepath.Join(dir, "items.sql"), seedErr.File)
+		assert.Equal(t, 3, seedErr.Statement)
+		assert.Equal(t, "INSERT INTO items VALUES ('a')", seedErr.SQL)
+	}
+	assert.EqualValues(t, 0, count(), "The failed file should be rolled back")
+
+	assert.Error(t, g.Seed(WithSeedTransaction(SeedTxNone)))
+	assert.EqualValues(t, 2, count(), "Without transactions earlier statements stay applied")
+
+	writeSQLFiles(t, dir, map[string]string{
+		"more.sql":   "INSERT INTO items VALUES ('c');",
+		"broken.sql": "INSERT INTO missing VALUES (1);",
+	})
+	g = newFileSeedGorm(t, dir, "schema.sql", "more.sql", "broken.sql")
+	assert.Error(t, g.Seed(WithSeedTransaction(SeedTxRun)))
+	assert.False(t, g.connection.Migrator().HasTable("items"), "The whole run should be rolled back")
+}
+
+// seedFunc adapts a function to the Seeder interface.
+type seedFunc struct {
+	name string
+	run  func(repo IRepository) error
+}
+
+func (s seedFunc) Name() string               { return s.name }
+func (s seedFunc) Run(repo IRepository) error { return s.run(repo) }
+
+// TestSeeders verifies that Go seeders run interleaved with seed files, once unless forced.
+func TestSeeders(t *testing.T) {
+	dir := t.TempDir()
+	writeSQLFiles(t, dir, map[string]string{
+		"schema.sql": "CREATE TABLE IF NOT EXISTS accounts (name TEXT, hash TEXT);",
+		"admins.sql": "UPDATE accounts SET name = 'admin:' || name;",
+	})
+	g := newFileSeedGorm(t, dir, "schema.sql")
+
+	runs := 0
+	g.RegisterSeeder(seedFunc{name: "accounts", run: func(repo IRepository) error {
+		runs++
+		return repo.Exec("INSERT INTO accounts (name, hash) VALUES (?, ?)", "root", "hashed")
+	}})
+	g.RegisterSeedFile(filepath.Join(dir, "admins.sql"))
+
+	assert.NoError(t, g.Seed())
+	assert.NoError(t, g.Seed())
+	assert.Equal(t, 1, runs, "Applied seeders should be skipped")
+
+	names, err := g.GetDB().Table("accounts").PluckStrings("name")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"admin:root"}, names, "Seeders should run in declared order")
+
+	assert.NoError(t, g.Seed(WithForceReseed()))
+	assert.Equal(t, 2, runs, "Forced runs should execute every seeder")
+
+	g.RegisterSeeder(seedFunc{name: "broken", run: func(IRepository) error { return errors.New("boom") }})
+	err = g.Seed()
+	assert.ErrorContains(t, err, "failed to run seeder 'broken': boom")
+}
+
+// TestSeedHeaderDependencies verifies that dependencies declared in seed file headers order
+// the run and that cycles are detected.
+func TestSeedHeaderDependencies(t *testing.T) {
+	dir := t.TempDir()
+	writeSQLFiles(t, dir, map[string]string{
+		"users.sql":  "-- Users and their roles.\n-- depends: roles.sql\nINSERT INTO users SELECT 'ana', id FROM roles;",
+		"roles.sql":  "-- depends: schema.sql\n\nINSERT INTO roles VALUES (1);",
+		"schema.sql": "CREATE TABLE roles (id INTEGER);\nCREATE TABLE users (name TEXT, role_id INTEGER);",
+	})
+	g := newFileSeedGorm(t, dir, "users.sql", "roles.sql", "schema.sql")
+
+	assert.NoError(t, g.Seed())
+	var roleID int64
+	assert.NoError(t, g.connection.Raw("SELECT role_id FROM users").Scan(&roleID).Error)
+	assert.EqualValues(t, 1, roleID, "Dependencies should run first")
+
+	assert.Equal(t, []string{"a.sql", "b.sql"}, seedHeaderDependencies("-- depends: a.sql,  b.sql\nSELECT 1;\n-- depends: c.sql"))
+
+	writeSQLFiles(t, dir, map[string]string{"schema.sql": "-- depends: users.sql\nSELECT 1;"})
+	assert.ErrorContains(t, g.Seed(), "dependency cycle")
+}
+
+// TestSeedDryRun verifies that a dry run logs the statements that would run without executing
+// or recording them.
+func TestSeedDryRun(t *testing.T) {
+	dir := t.TempDir()
+	writeSQLFiles(t, dir, map[string]string{
+		"schema.sql": "CREATE TABLE notes (body TEXT);\nINSERT INTO notes VALUES ('" + strings.Repeat("x", 300) + "');",
+	})
+	g := newFileSeedGorm(t, dir, "schema.sql")
+	g.RegisterSeeder(seedFunc{name: "notes", run: func(IRepository) error { return errors.New("should not run") }})
+
+	var logs []string
+	g.connection.Logger = recordLogger{logs: &logs}
+
+	assert.NoError(t, g.Seed(WithDryRun()))
+	assert.False(t, g.connection.Migrator().HasTable("notes"), "Statements should not run")
+	assert.False(t, g.connection.Migrator().HasTable(&seedRecord{}), "Nothing should be recorded")
+
+	if assert.Len(t, logs, 3) {
+		assert.Contains(t, logs[0], "statement 1 would run: CREATE TABLE notes (body TEXT)")
+		assert.Contains(t, logs[1], "statement 2 would run: INSERT INTO notes VALUES ('xxx")
+		assert.True(t, strings.HasSuffix(logs[1], "x..."), "Long statements should be truncated")
+		assert.Contains(t, logs[2], "seeder 'notes' would run")
+	}
+}
+
+// recordLogger records the info messages logged through it.
+type recordLogger struct {
+	logs *[]string
+}
+
+func (l recordLogger) LogMode(logger.LogLevel) logger.Interface { return l }
+func (l recordLogger) Info(_ context.Context, format string, args ...any) {
+	*l.logs = append(*l.logs, fmt.Sprintf(format, args...))
+}
+func (l recordLogger) Warn(context.Context, string, ...any)                            {}
+func (l recordLogger) Error(context.Context, string, ...any)                           {}
+func (l recordLogger) Trace(context.Context, time.Time, func() (string, int64), error) {}
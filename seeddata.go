@@ -0,0 +1,184 @@
+package gormext
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"path"
+	"reflect"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+	"gorm.io/gorm"
+)
+
+// seedDataBatchSize is the number of rows inserted per statement by data seeds.
+const seedDataBatchSize = 100
+
+// RegisterSeedData appends a data file to the seeds run by Seed, after the seeds declared so
+// far. The file holds rows for model as CSV (with a header row), a JSON array of objects or a
+// YAML list of mappings, chosen by its extension (.csv, .json, .yaml or .yml, optionally
+// compressed or encrypted). Keys are column or field names.
+//
+// When model is a struct, values are converted to the field types and hooks run. When model is
+// a table name, rows are inserted as given. Empty CSV cells are NULL, or leave the field at its
+// zero value. It must not be called concurrently with Seed.
+func (g *Gorm) RegisterSeedData(path string, model any) error {
+	if _, err := seedDataFormat(path); err != nil {
+		return err
+	}
+	if _, ok := model.(string); !ok {
+		if _, err := g.parseModel(model); err != nil {
+			return err
+		}
+	}
+
+	g.seeds = append(g.seeds, seedUnit{name: path, model: model})
+	return nil
+}
+
+// seedData reads a data file and bulk-inserts its rows.
+func (g *Gorm) seedData(conn *gorm.DB, unit seedUnit, options seedOptions) error {
+	content, err := g.readFile(unit.name)
+	if err != nil {
+		return fmt.Errorf("failed to read seed file '%s': %w", unit.name, err)
+	}
+
+	rows, err := parseSeedData(unit.name, content)
+	if err != nil {
+		return err
+	}
+
+	sum := sha256.Sum256(content)
+	return g.applySeed(conn, unit.name, hex.EncodeToString(sum[:]), options, func(tx *gorm.DB) error {
+		if options.dryRun {
+			g.seedLog("seed file '%s' would insert %d rows", unit.name, len(rows))
+			return nil
+		}
+		if len(rows) == 0 {
+			return nil
+		}
+
+		var err error
+		if table, ok := unit.model.(string); ok {
+			err = tx.Table(table).CreateInBatches(rows, seedDataBatchSize).Error
+		} else {
+			var values any
+			if values, err = g.seedDataValues(tx, unit.model, rows); err == nil {
+				err = tx.CreateInBatches(values, seedDataBatchSize).Error
+			}
+		}
+		if err != nil {
+			return fmt.Errorf("failed to insert rows from seed file '%s': %w", unit.name, err)
+		}
+		return nil
+	})
+}
+
+// seedDataValues converts rows into a pointer to a slice of model, setting each key on the
+// field with that column or field name.
+func (g *Gorm) seedDataValues(tx *gorm.DB, model any, rows []map[string]any) (any, error) {
+	table, err := g.parseModel(model)
+	if err != nil {
+		return nil, err
+	}
+
+	values := reflect.MakeSlice(reflect.SliceOf(table.ModelType), len(rows), len(rows))
+	for i, row := range rows {
+		value := values.Index(i)
+		for key, v := range row {
+			field := table.LookUpField(key)
+			if field == nil {
+				return nil, fmt.Errorf("unknown column '%s' for model %s (row %d)", key, table.Name, i+1)
+			}
+			if v == nil {
+				continue
+			}
+			if err := field.Set(tx.Statement.Context, value, v); err != nil {
+				return nil, fmt.Errorf("invalid value for column '%s' (row %d): %w", key, i+1, err)
+			}
+		}
+	}
+
+	ptr := reflect.New(values.Type())
+	ptr.Elem().Set(values)
+	return ptr.Interface(), nil
+}
+
+// seedDataFormat returns the format of a data file from its extension.
+func seedDataFormat(filePath string) (string, error) {
+	switch ext := path.Ext(trimEncodingExt(filePath)); ext {
+	case ".csv", ".json", ".yaml", ".yml":
+		return ext, nil
+	default:
+		return "", fmt.Errorf("unsupported seed data file '%s': expected .csv, .json, .yaml or .yml", filePath)
+	}
+}
+
+// parseSeedData decodes the rows of a data file.
+func parseSeedData(filePath string, content []byte) ([]map[string]any, error) {
+	format, err := seedDataFormat(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []map[string]any
+	switch format {
+	case ".csv":
+		rows, err = parseSeedCSV(content)
+	case ".json":
+		decoder := json.NewDecoder(bytes.NewReader(content))
+		decoder.UseNumber()
+		if err = decoder.Decode(&rows); err == nil {
+			normalizeJSONNumbers(rows)
+		}
+	default:
+		err = yaml.Unmarshal(content, &rows)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse seed file '%s': %w", filePath, err)
+	}
+	return rows, nil
+}
+
+// parseSeedCSV decodes CSV content whose first record names the columns. Empty cells are nil.
+func parseSeedCSV(content []byte) ([]map[string]any, error) {
+	records, err := csv.NewReader(bytes.NewReader(content)).ReadAll()
+	if err != nil || len(records) == 0 {
+		return nil, err
+	}
+
+	header := records[0]
+	for i := range header {
+		header[i] = strings.TrimSpace(header[i])
+	}
+
+	rows := make([]map[string]any, 0, len(records)-1)
+	for _, record := range records[1:] {
+		row := make(map[string]any, len(header))
+		for i, column := range header {
+			if record[i] == "" {
+				row[column] = nil
+				continue
+			}
+			row[column] = record[i]
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// normalizeJSONNumbers replaces json.Number values with their text, which keeps integer
+// precision and converts to any numeric field.
+func normalizeJSONNumbers(rows []map[string]any) {
+	for _, row := range rows {
+		for key, v := range row {
+			if number, ok := v.(json.Number); ok {
+				row[key] = number.String()
+			}
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package gormext
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// seedProduct is the model filled from data files.
+type seedProduct struct {
+	ID        uint
+	Name      string
+	Price     int64
+	Active    bool
+	ReleaseAt time.Time
+}
+
+// TestSeedData verifies that CSV, JSON and YAML files are converted to the model and inserted.
+func TestSeedData(t *testing.T) {
+	dir := t.TempDir()
+	writeSQLFiles(t, dir, map[string]string{
+		"products.csv":  "name,price,active,release_at\nkeyboard,4500,true,2024-01-02 03:04:05\nmouse,,false,2024-02-01\n",
+		"products.json": `[{"name": "monitor", "price": 9007199254740993, "active": true}]`,
+		"products.yaml": "- name: cable\n  Price: 300\n  active: true\n",
+		"tags.yml":      "- label: new\n- label: sale\n",
+	})
+	g := newFileSeedGorm(t, dir)
+	assert.NoError(t, g.Migrate(&seedProduct{}))
+	assert.NoError(t, g.GetDB().Exec("CREATE TABLE tags (label TEXT)"))
+
+	for _, file := range []string{"products.csv", "products.json", "products.yaml"} {
+		assert.NoError(t, g.RegisterSeedData(filepath.Join(dir, file), &seedProduct{}))
+	}
+	assert.NoError(t, g.RegisterSeedData(filepath.Join(dir, "tags.yml"), "tags"))
+	assert.NoError(t, g.Seed())
+
+	var products []seedProduct
+	assert.NoError(t, g.GetDB().Order("id").Find(&products))
+	if assert.Len(t, products, 4) {
+		assert.Equal(t, "keyboard", products[0].Name)
+		assert.EqualValues(t, 4500, products[0].Price)
+		assert.True(t, products[0].Active)
+		assert.Equal(t, 2024, products[0].ReleaseAt.Year())
+		assert.EqualValues(t, 0, products[1].Price, "Empty cells should leave the zero value")
+		assert.EqualValues(t, 9007199254740993, products[2].Price, "JSON integers should keep their precision")
+		assert.True(t, products[3].Active)
+	}
+
+	labels, err := g.GetDB().Table("tags").PluckStrings("label")
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []string{"new", "sale"}, labels)
+}
+
+// TestSeedDataErrors verifies that unsupported files and unknown columns are reported.
+func TestSeedDataErrors(t *testing.T) {
+	dir := t.TempDir()
+	writeSQLFiles(t, dir, map[string]string{"products.csv": "name,color\nlamp,red\n"})
+	g := newFileSeedGorm(t, dir)
+	assert.NoError(t, g.Migrate(&seedProduct{}))
+
+	assert.ErrorContains(t, g.RegisterSeedData(filepath.Join(dir, "products.xml"), &seedProduct{}), "unsupported seed data file")
+
+	assert.NoError(t, g.RegisterSeedData(filepath.Join(dir, "products.csv"), &seedProduct{}))
+	assert.ErrorContains(t, g.Seed(), "unknown column 'color'")
+}
@@ -0,0 +1,84 @@
+package gormext
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"text/template"
+
+	"gorm.io/gorm"
+)
+
+// tenantSeedData is the data of the seed file templates of tenant runs.
+type tenantSeedData struct {
+	TenantID string
+}
+
+// WithSeedTenants runs the seed set once for each of tenantIDs, or for every tenant
+// registered with RegisterTenant, or in the catalog with Config.TenantCatalog, when none is
+// given, in the schema, database or rows of the tenant. Each tenant tracks its applied seeds
+// apart, so that a tenant provisioned later is seeded like the existing ones. A failing tenant
+// does not stop the others.
+//
+// In tenant runs, seed files are text/template templates given the tenant as .TenantID,
+// with the identifier helpers of GetQueryTemplated and literal, quoting a string literal for
+// the driver of the connection:
+//
+//	INSERT INTO settings (tenant_id, name) VALUES ({{ literal .TenantID }}, 'theme');
+func WithSeedTenants(tenantIDs ...string) SeedOption {
+	return func(o *seedOptions) {
+		o.tenants = tenantIDs
+		o.perTenant = true
+	}
+}
+
+// seedTenants runs the seeds for each tenant of options, with the context of the tenant.
+func (g *Gorm) seedTenants(ctx context.Context, order []int, dependencies [][]int, options seedOptions) error {
+	tenants := options.tenants
+	if len(tenants) == 0 {
+		var err error
+		if tenants, err = g.tenantIDs(ctx); err != nil {
+			return err
+		}
+	}
+
+	var errs []error
+	for _, tenantID := range tenants {
+		conn := g.connection.WithContext(ContextWithTenant(ctx, tenantID))
+		if err := g.seedWith(conn, order, dependencies, options); err != nil {
+			errs = append(errs, fmt.Errorf("failed to seed tenant '%s': %w", tenantID, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// renderTenantSeed renders the seed file template content for tenantID.
+func (g *Gorm) renderTenantSeed(path string, content []byte, tenantID string) ([]byte, error) {
+	funcs := g.templateFuncs()
+	funcs["literal"] = g.Admin().literal
+
+	tmpl, err := template.New(path).Option("missingkey=error").Funcs(funcs).Parse(string(content))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse seed file template '%s': %w", path, err)
+	}
+
+	var rendered strings.Builder
+	if err := tmpl.Execute(&rendered, tenantSeedData{TenantID: tenantID}); err != nil {
+		return nil, fmt.Errorf("failed to render seed file template '%s' for tenant '%s': %w", path, tenantID, err)
+	}
+	return []byte(rendered.String()), nil
+}
+
+// tenantChecksum returns the tracking checksum of a seed run with conn: checksum itself
+// without tenant, or one of its own for each tenant, whose tracking table may be shared.
+func tenantChecksum(conn *gorm.DB, checksum string) string {
+	tenantID, ok := TenantFromContext(conn.Statement.Context)
+	if !ok {
+		return checksum
+	}
+	sum := sha256.Sum256([]byte("tenant:" + tenantID + ":" + checksum))
+	return hex.EncodeToString(sum[:])
+}
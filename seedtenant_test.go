@@ -0,0 +1,58 @@
+package gormext
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestSeedTenants verifies that seed sets run once per tenant, with templates rendered for the
+// tenant, tenants registered later being seeded like the existing ones.
+func TestSeedTenants(t *testing.T) {
+	dir := t.TempDir()
+	writeSQLFiles(t, dir, map[string]string{
+		"welcome.sql": "INSERT INTO tenant_notes (tenant_id, body) VALUES ({{ literal .TenantID }}, 'welcome');",
+	})
+	g := newTenantScopedGorm(t)
+	g.RegisterSeedFile(filepath.Join(dir, "welcome.sql"))
+	g.RegisterSeeder(seedFunc{name: "tips", run: func(repo IRepository) error {
+		return repo.Create(&tenantNote{Body: "tip"})
+	}})
+
+	notes := func(tenantID string) []string {
+		var notes []tenantNote
+		assert.NoError(t, g.ForTenant(tenantID).Order("id").Find(&notes))
+		bodies := make([]string, len(notes))
+		for i, note := range notes {
+			bodies[i] = note.Body
+		}
+		return bodies
+	}
+
+	assert.NoError(t, g.Seed(WithSeedTenants("o'brien", "globex")))
+	assert.NoError(t, g.Seed(WithSeedTenants("o'brien", "globex")))
+	assert.Equal(t, []string{"welcome", "tip"}, notes("o'brien"))
+	assert.Equal(t, []string{"welcome", "tip"}, notes("globex"))
+
+	g.RegisterTenant("o'brien", "globex", "initech")
+	assert.NoError(t, g.Seed(WithSeedTenants()))
+	assert.Equal(t, []string{"welcome", "tip"}, notes("initech"))
+	assert.Equal(t, []string{"welcome", "tip"}, notes("globex"))
+
+	writeSQLFiles(t, dir, map[string]string{"welcome.sql": "INSERT INTO tenant_notes (body) VALUES ({{ .Missing }});"})
+	assert.ErrorContains(t, g.Seed(WithSeedTenants("umbrella")), "failed to seed tenant 'umbrella'")
+}
+
+// TestRenderTenantSeed verifies that tenant IDs are quoted as literals of the driver.
+func TestRenderTenantSeed(t *testing.T) {
+	content := []byte("INSERT INTO settings (tenant_id) VALUES ({{ literal .TenantID }});")
+
+	rendered, err := newDryRunGorm(t, PostgreSQL).renderTenantSeed("seed.sql", content, `o'brien\`)
+	assert.NoError(t, err)
+	assert.Equal(t, `INSERT INTO settings (tenant_id) VALUES ('o''brien\');`, string(rendered))
+
+	rendered, err = newDryRunGorm(t, MySQL).renderTenantSeed("seed.sql", content, `x\'); DROP TABLE settings; --`)
+	assert.NoError(t, err)
+	assert.Equal(t, `INSERT INTO settings (tenant_id) VALUES ('x\\''); DROP TABLE settings; --');`, string(rendered))
+}
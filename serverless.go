@@ -0,0 +1,90 @@
+package gormext
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net"
+	"syscall"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// retryConnPool retries statements whose connection could not be established. Only dial
+// failures are retried, since the statement never reached the database.
+type retryConnPool struct {
+	gorm.ConnPool
+	db      *sql.DB
+	retries int
+	delay   time.Duration
+}
+
+// ExecContext executes a statement, retrying connection failures.
+func (p *retryConnPool) ExecContext(ctx context.Context, query string, args ...any) (result sql.Result, err error) {
+	err = p.retry(ctx, func() error {
+		result, err = p.ConnPool.ExecContext(ctx, query, args...)
+		return err
+	})
+	return result, err
+}
+
+// QueryContext runs a query, retrying connection failures.
+func (p *retryConnPool) QueryContext(ctx context.Context, query string, args ...any) (rows *sql.Rows, err error) {
+	err = p.retry(ctx, func() error {
+		rows, err = p.ConnPool.QueryContext(ctx, query, args...)
+		return err
+	})
+	return rows, err
+}
+
+// PrepareContext prepares a statement, retrying connection failures.
+func (p *retryConnPool) PrepareContext(ctx context.Context, query string) (stmt *sql.Stmt, err error) {
+	err = p.retry(ctx, func() error {
+		stmt, err = p.ConnPool.PrepareContext(ctx, query)
+		return err
+	})
+	return stmt, err
+}
+
+// BeginTx starts a transaction, retrying connection failures.
+func (p *retryConnPool) BeginTx(ctx context.Context, opts *sql.TxOptions) (tx *sql.Tx, err error) {
+	err = p.retry(ctx, func() error {
+		tx, err = p.db.BeginTx(ctx, opts)
+		return err
+	})
+	return tx, err
+}
+
+// GetDBConn returns the underlying *sql.DB.
+func (p *retryConnPool) GetDBConn() (*sql.DB, error) {
+	return p.db, nil
+}
+
+// retry runs fn until it succeeds, fails with another error than a connection failure, or
+// the retries are exhausted.
+func (p *retryConnPool) retry(ctx context.Context, fn func() error) error {
+	delay := p.delay
+	for attempt := 0; ; attempt++ {
+		err := fn()
+		if err == nil || attempt >= p.retries || !isConnectError(err) {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(delay):
+		}
+		delay *= 2
+	}
+}
+
+// isConnectError reports whether err is a failure to establish a connection.
+func isConnectError(err error) bool {
+	var opErr *net.OpError
+	if errors.As(err, &opErr) && opErr.Op == "dial" {
+		return true
+	}
+	return errors.Is(err, syscall.ECONNREFUSED)
+}
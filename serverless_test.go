@@ -0,0 +1,69 @@
+package gormext
+
+import (
+	"context"
+	"database/sql"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+// flakyConnPool fails the first statements with a dial error.
+type flakyConnPool struct {
+	gorm.ConnPool
+	failures int
+	calls    int
+}
+
+// ExecContext fails while failures remain.
+func (p *flakyConnPool) ExecContext(context.Context, string, ...any) (sql.Result, error) {
+	p.calls++
+	if p.calls <= p.failures {
+		return nil, &net.OpError{Op: "dial", Net: "tcp", Err: &net.AddrError{Err: "refused"}}
+	}
+	return nil, nil
+}
+
+// TestRetryConnPool verifies only connection failures are retried, up to the limit.
+func TestRetryConnPool(t *testing.T) {
+	flaky := &flakyConnPool{failures: 2}
+	pool := &retryConnPool{ConnPool: flaky, retries: 3, delay: time.Millisecond}
+	_, err := pool.ExecContext(context.Background(), "SELECT 1")
+	assert.NoError(t, err)
+	assert.Equal(t, 3, flaky.calls)
+
+	flaky = &flakyConnPool{failures: 5}
+	pool.ConnPool = flaky
+	_, err = pool.ExecContext(context.Background(), "SELECT 1")
+	assert.True(t, isConnectError(err))
+	assert.Equal(t, 4, flaky.calls, "Retries should stop at the limit")
+}
+
+// TestServerlessProfile verifies lazy connections and that the wrapped pool serves statements and transactions.
+func TestServerlessProfile(t *testing.T) {
+	profile := ProfileServerless
+	profile.ConnectRetryDelay = time.Millisecond
+
+	dbCtx, err := NewDatabaseContext("host=127.0.0.1 port=1 user=app dbname=app sslmode=disable", "postgres", "silent")
+	assert.NoError(t, err)
+	g, err := NewGorm(*dbCtx, nil, []string{}, map[string]string{}, Config{Profile: &profile})
+	assert.NoError(t, err, "Lazy connections should not connect while opening")
+	assert.True(t, isConnectError(g.GetDB().Exec("SELECT 1")))
+
+	g, err = NewGorm(newTestDatabaseContext(), nil, []string{}, map[string]string{}, Config{Profile: &profile})
+	assert.NoError(t, err, "Unexpected error from NewGorm")
+	assert.NoError(t, g.Migrate(&repoUser{}))
+
+	repo := g.GetDB()
+	assert.NoError(t, repo.WithTransaction(func(tx IRepository) error {
+		return tx.Create(&repoUser{Name: "ann"})
+	}))
+
+	var count int64
+	assert.NoError(t, repo.Table("repo_users").Count(&count))
+	assert.EqualValues(t, 1, count)
+	assert.True(t, g.HealthCheck(context.Background()).Healthy)
+}
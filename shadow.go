@@ -0,0 +1,214 @@
+package gormext
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"reflect"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	// maxShadowDivergences bounds the number of divergences kept in memory.
+	maxShadowDivergences = 1000
+
+	// shadowSetKey stores the SET clause of an update in the statement settings, since gorm
+	// removes it from the statement once the update has run.
+	shadowSetKey = "gormext:shadow_set"
+)
+
+type (
+	// ShadowDivergence records a write that was not mirrored identically on the shadow database.
+	ShadowDivergence struct {
+		Table     string    // Table written.
+		Operation string    // create, update or delete.
+		Primary   int64     // Rows affected on the primary database.
+		Shadow    int64     // Rows affected on the shadow database.
+		Err       error     // Error returned by the shadow database, if any.
+		At        time.Time // When the divergence was recorded.
+	}
+
+	// shadowWriter mirrors writes of selected tables to a shadow database.
+	shadowWriter struct {
+		target      *gorm.DB
+		tables      map[string]bool
+		async       bool
+		wg          sync.WaitGroup
+		mu          sync.Mutex
+		divergences []ShadowDivergence
+	}
+)
+
+// WithShadow mirrors every successful create, update and delete on the given models to target,
+// recording divergences (errors or different affected row counts) for later inspection. With
+// async set, mirrored writes run in the background and ShadowWait waits for them.
+//
+// Creates are replayed from the written entities (keeping generated primary keys), updates and
+// deletes by rebuilding the statement for the target dialect. Writes inside a transaction are
+// mirrored when their statement succeeds, even if the transaction is later rolled back.
+func (g *Gorm) WithShadow(target *Gorm, models []any, async bool) error {
+	if g.shadow != nil {
+		return errors.New("shadow database already configured")
+	}
+
+	shadow := &shadowWriter{target: target.connection, tables: make(map[string]bool), async: async}
+	for _, model := range models {
+		stmt := &gorm.Statement{DB: g.connection}
+		if err := stmt.Parse(model); err != nil {
+			return fmt.Errorf("failed to parse shadow model %T: %w", model, err)
+		}
+		shadow.tables[stmt.Schema.Table] = true
+	}
+
+	// Capture SET clauses as they are built, wrapping any dialect builder. The builders are
+	// copied first, as connections opened with the same gorm.Config share them.
+	builders := maps.Clone(g.connection.ClauseBuilders)
+	buildSet := builders["SET"]
+	builders["SET"] = func(c clause.Clause, builder clause.Builder) {
+		if stmt, ok := builder.(*gorm.Statement); ok {
+			stmt.Settings.Store(shadowSetKey, c)
+		}
+		if buildSet != nil {
+			buildSet(c, builder)
+			return
+		}
+		c.Build(builder)
+	}
+	g.connection.ClauseBuilders = builders
+
+	const name = "gormext:shadow"
+	callbacks := g.connection.Callback()
+	err := errors.Join(
+		callbacks.Create().After("gorm:commit_or_rollback_transaction").Register(name, shadow.mirror("create")),
+		callbacks.Update().After("gorm:commit_or_rollback_transaction").Register(name, shadow.mirror("update")),
+		callbacks.Delete().After("gorm:commit_or_rollback_transaction").Register(name, shadow.mirror("delete")),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to register shadow callbacks: %w", err)
+	}
+
+	g.shadow = shadow
+	return nil
+}
+
+// ShadowDivergences returns the divergences recorded since the shadow was configured.
+func (g *Gorm) ShadowDivergences() []ShadowDivergence {
+	if g.shadow == nil {
+		return nil
+	}
+
+	g.shadow.mu.Lock()
+	defer g.shadow.mu.Unlock()
+	return append([]ShadowDivergence(nil), g.shadow.divergences...)
+}
+
+// ShadowWait blocks until all asynchronous shadow writes have completed.
+func (g *Gorm) ShadowWait() {
+	if g.shadow != nil {
+		g.shadow.wg.Wait()
+	}
+}
+
+// mirror returns the callback replaying successful writes of operation on the shadow database.
+func (s *shadowWriter) mirror(operation string) func(*gorm.DB) {
+	return func(db *gorm.DB) {
+		stmt := db.Statement
+		if db.Error != nil || stmt.Schema == nil || !s.tables[stmt.Table] {
+			return
+		}
+
+		ctx := context.WithoutCancel(stmt.Context)
+		write := s.replayer(ctx, operation, stmt)
+		primary := db.RowsAffected
+
+		if !s.async {
+			s.record(stmt.Table, operation, primary, write)
+			return
+		}
+
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			s.record(stmt.Table, operation, primary, write)
+		}()
+	}
+}
+
+// replayer prepares the shadow write for stmt, capturing everything it needs up front so it
+// can run after the primary statement has been reused.
+func (s *shadowWriter) replayer(ctx context.Context, operation string, stmt *gorm.Statement) func() (int64, error) {
+	if operation == "create" {
+		entity := snapshot(stmt.Dest)
+		return func() (int64, error) {
+			tx := s.target.Session(&gorm.Session{NewDB: true, SkipHooks: true}).WithContext(ctx).Omit(clause.Associations).Create(entity)
+			return tx.RowsAffected, tx.Error
+		}
+	}
+
+	// Soft deletes are built as updates.
+	set, hasSet := stmt.Settings.LoadAndDelete(shadowSetKey)
+	clauses := s.target.Callback().Delete().Clauses
+	if operation == "update" || hasSet {
+		clauses = s.target.Callback().Update().Clauses
+	}
+
+	rebuilt := s.target.Session(&gorm.Session{NewDB: true}).WithContext(ctx).Statement
+	rebuilt.Table, rebuilt.TableExpr, rebuilt.Schema = stmt.Table, stmt.TableExpr, stmt.Schema
+	for name, c := range stmt.Clauses {
+		rebuilt.Clauses[name] = c
+	}
+	if hasSet {
+		rebuilt.Clauses["SET"] = set.(clause.Clause)
+	}
+	rebuilt.Build(clauses...)
+
+	query, vars := rebuilt.SQL.String(), rebuilt.Vars
+	return func() (int64, error) {
+		result, err := s.target.Statement.ConnPool.ExecContext(ctx, query, vars...)
+		if err != nil {
+			return 0, err
+		}
+		return result.RowsAffected()
+	}
+}
+
+// record runs the shadow write and stores a divergence when it does not match the primary.
+func (s *shadowWriter) record(table, operation string, primary int64, write func() (int64, error)) {
+	shadow, err := write()
+	if err == nil && shadow == primary {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(s.divergences) >= maxShadowDivergences {
+		s.divergences = s.divergences[1:]
+	}
+	s.divergences = append(s.divergences, ShadowDivergence{
+		Table:     table,
+		Operation: operation,
+		Primary:   primary,
+		Shadow:    shadow,
+		Err:       err,
+		At:        time.Now(),
+	})
+}
+
+// snapshot returns a shallow copy of the value dest points to, so asynchronous writes are not
+// affected by later changes made by the caller.
+func snapshot(dest any) any {
+	rv := reflect.ValueOf(dest)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return dest
+	}
+
+	copied := reflect.New(rv.Elem().Type())
+	copied.Elem().Set(rv.Elem())
+	return copied.Interface()
+}
@@ -0,0 +1,99 @@
+package gormext
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// newShadowPair creates a primary repository mirroring repoUser writes to a second database.
+func newShadowPair(t *testing.T, async bool) (*Gorm, IRepository, *Gorm) {
+	g, repo := newTestRepository(t)
+	target, _ := newTestRepository(t)
+
+	// Keep the in-memory target on a single connection so background writes see the same database.
+	sqlDB, err := target.connection.DB()
+	assert.NoError(t, err)
+	sqlDB.SetMaxOpenConns(1)
+
+	assert.NoError(t, g.WithShadow(target, []any{&repoUser{}}, async))
+	return g, repo, target
+}
+
+// TestShadowMirrorsWrites verifies creates, updates and deletes are replayed on the shadow database.
+func TestShadowMirrorsWrites(t *testing.T) {
+	g, repo, target := newShadowPair(t, false)
+
+	ann := &repoUser{Name: "ann", Age: 30}
+	assert.NoError(t, repo.Create(ann))
+	assert.NoError(t, repo.Create(&repoUser{Name: "bob", Age: 40}))
+
+	ann.Age = 31
+	assert.NoError(t, repo.Update(ann))
+	assert.NoError(t, repo.Where("name = ?", "bob").Delete(&repoUser{}))
+
+	var users []repoUser
+	assert.NoError(t, target.GetDB().Find(&users))
+	assert.Equal(t, []repoUser{{ID: ann.ID, Name: "ann", Age: 31}}, users)
+	assert.Empty(t, g.ShadowDivergences())
+}
+
+// TestShadowAsync verifies asynchronous mirroring completes by ShadowWait.
+func TestShadowAsync(t *testing.T) {
+	g, repo, target := newShadowPair(t, true)
+
+	for _, name := range []string{"ann", "bob", "cid"} {
+		assert.NoError(t, repo.Create(&repoUser{Name: name}))
+	}
+	g.ShadowWait()
+
+	var count int64
+	assert.NoError(t, target.GetDB().Table("repo_users").Count(&count))
+	assert.EqualValues(t, 3, count)
+	assert.Empty(t, g.ShadowDivergences())
+}
+
+// TestShadowRecordsDivergences verifies failed and mismatched shadow writes are recorded.
+func TestShadowRecordsDivergences(t *testing.T) {
+	g, repo, target := newShadowPair(t, false)
+
+	assert.NoError(t, repo.Create(&repoUser{Name: "ann"}))
+	assert.NoError(t, target.GetDB().Exec("DELETE FROM repo_users"))
+	assert.NoError(t, repo.Where("name = ?", "ann").Delete(&repoUser{}))
+
+	assert.NoError(t, target.GetDB().Exec("DROP TABLE repo_users"))
+	assert.NoError(t, repo.Create(&repoUser{Name: "bob"}), "Shadow failures should not fail the primary write")
+
+	divergences := g.ShadowDivergences()
+	if assert.Len(t, divergences, 2) {
+		assert.Equal(t, "delete", divergences[0].Operation)
+		assert.EqualValues(t, 1, divergences[0].Primary)
+		assert.EqualValues(t, 0, divergences[0].Shadow)
+		assert.NoError(t, divergences[0].Err)
+
+		assert.Equal(t, "create", divergences[1].Operation)
+		assert.Equal(t, "repo_users", divergences[1].Table)
+		assert.Error(t, divergences[1].Err)
+	}
+}
+
+// TestShadowClauseBuilders verifies that the shadow wraps the SET builder of its own connection
+// only, leaving the builders shared through gorm.Config alone.
+func TestShadowClauseBuilders(t *testing.T) {
+	shared := map[string]clause.ClauseBuilder{}
+	config := Config{Config: gorm.Config{ClauseBuilders: shared}}
+	g, err := NewGorm(newTestDatabaseContext(), nil, nil, nil, config)
+	assert.NoError(t, err)
+	other, err := NewGorm(newTestDatabaseContext(), nil, nil, nil, config)
+	assert.NoError(t, err)
+	target, _ := newTestRepository(t)
+	builders := len(shared)
+
+	assert.NoError(t, g.WithShadow(target, []any{&repoUser{}}, false))
+	assert.Len(t, shared, builders)
+	assert.NotContains(t, shared, "SET")
+	assert.NotContains(t, other.connection.ClauseBuilders, "SET")
+	assert.Contains(t, g.connection.ClauseBuilders, "SET")
+}
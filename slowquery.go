@@ -0,0 +1,132 @@
+package gormext
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// slowQueryStartKey is the statement instance setting holding the start time of the statement.
+const slowQueryStartKey = "gormext:slow_query_start"
+
+// sourceDir is the directory of the gormext sources, skipped when looking for the caller of a
+// statement.
+var sourceDir = func() string {
+	_, file, _, _ := runtime.Caller(0)
+	return filepath.Dir(file)
+}()
+
+// SlowQuery describes a statement that ran longer than the slow query threshold of the
+// database context.
+type SlowQuery struct {
+	SQL       string        // Statement, with placeholders.
+	Args      []any         // Statement arguments, redacted to their type such as "<string>".
+	Duration  time.Duration // Duration of the statement.
+	QueryName string        // Cached query name, empty for other statements.
+	Caller    string        // file:line of the application code that ran the statement.
+	Err       error         // Error the statement failed with, if any.
+}
+
+// OnSlowQuery registers hook to receive the statements running longer than the threshold set
+// with (*DatabaseContext).SetSlowQueryThreshold. Statements may run concurrently, so hook must
+// be safe for concurrent use.
+func (g *Gorm) OnSlowQuery(hook func(SlowQuery)) {
+	g.slowQueryHooks = append(g.slowQueryHooks, hook)
+}
+
+// registerSlowQueryCallbacks registers the callbacks timing every statement against the slow
+// query threshold.
+func (g *Gorm) registerSlowQueryCallbacks() error {
+	const (
+		startName  = "gormext:slow_query_start"
+		reportName = "gormext:slow_query"
+	)
+
+	callbacks := g.connection.Callback()
+	return errors.Join(
+		callbacks.Create().Before("gorm:create").Register(startName, g.startSlowQuery),
+		callbacks.Create().After("gorm:create").Register(reportName, g.reportSlowQuery),
+		callbacks.Query().Before("gorm:query").Register(startName, g.startSlowQuery),
+		callbacks.Query().After("gorm:query").Register(reportName, g.reportSlowQuery),
+		callbacks.Update().Before("gorm:update").Register(startName, g.startSlowQuery),
+		callbacks.Update().After("gorm:update").Register(reportName, g.reportSlowQuery),
+		callbacks.Delete().Before("gorm:delete").Register(startName, g.startSlowQuery),
+		callbacks.Delete().After("gorm:delete").Register(reportName, g.reportSlowQuery),
+		callbacks.Row().Before("gorm:row").Register(startName, g.startSlowQuery),
+		callbacks.Row().After("gorm:row").Register(reportName, g.reportSlowQuery),
+		callbacks.Raw().Before("gorm:raw").Register(startName, g.startSlowQuery),
+		callbacks.Raw().After("gorm:raw").Register(reportName, g.reportSlowQuery),
+	)
+}
+
+// startSlowQuery records the start time of a statement when slow queries are detected.
+func (g *Gorm) startSlowQuery(db *gorm.DB) {
+	if g.databaseCtx.slowQueryThreshold <= 0 || len(g.slowQueryHooks) == 0 {
+		return
+	}
+	db.InstanceSet(slowQueryStartKey, time.Now())
+}
+
+// reportSlowQuery sends a statement to the slow query hooks when it exceeded the threshold.
+func (g *Gorm) reportSlowQuery(db *gorm.DB) {
+	start, ok := db.InstanceGet(slowQueryStartKey)
+	if !ok {
+		return
+	}
+
+	duration := time.Since(start.(time.Time))
+	if duration < g.databaseCtx.slowQueryThreshold {
+		return
+	}
+
+	query := SlowQuery{
+		SQL:       db.Statement.SQL.String(),
+		Args:      redactArgs(db.Statement.Vars),
+		Duration:  duration,
+		QueryName: QueryName(db),
+		Caller:    caller(),
+		Err:       db.Error,
+	}
+	for _, hook := range g.slowQueryHooks {
+		hook(query)
+	}
+}
+
+// redactArgs replaces the statement arguments with their type, keeping nil arguments.
+func redactArgs(args []any) []any {
+	redacted := make([]any, len(args))
+	for i, arg := range args {
+		if arg != nil {
+			redacted[i] = fmt.Sprintf("<%T>", arg)
+		}
+	}
+	return redacted
+}
+
+// caller returns the file:line of the first frame outside gorm and gormext, test files aside.
+func caller() string {
+	pcs := make([]uintptr, 32)
+	frames := runtime.CallersFrames(pcs[:runtime.Callers(3, pcs)])
+	for {
+		frame, more := frames.Next()
+		if !internalFrame(frame.File) {
+			return fmt.Sprintf("%s:%d", frame.File, frame.Line)
+		}
+		if !more {
+			return ""
+		}
+	}
+}
+
+// internalFrame reports whether file belongs to gorm or to the gormext package.
+func internalFrame(file string) bool {
+	if strings.HasSuffix(file, "_test.go") {
+		return false
+	}
+	return filepath.Dir(file) == sourceDir || strings.Contains(file, "/gorm.io/")
+}
@@ -0,0 +1,45 @@
+package gormext
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestSlowQuery verifies that statements above the threshold are reported with redacted
+// arguments and the caller location.
+func TestSlowQuery(t *testing.T) {
+	dbCtx := newTestDatabaseContext()
+	dbCtx.SetSlowQueryThreshold(time.Nanosecond)
+	assert.Equal(t, time.Nanosecond, dbCtx.GetSlowQueryThreshold())
+	g, err := NewGorm(dbCtx, nil, nil, nil)
+	assert.NoError(t, err)
+
+	var queries []SlowQuery
+	g.OnSlowQuery(func(q SlowQuery) { queries = append(queries, q) })
+	assert.NoError(t, g.connection.Exec("CREATE TABLE accounts (id INTEGER, email TEXT, note TEXT)").Error)
+	assert.NoError(t, g.RegisterQuery("accounts.add", "INSERT INTO accounts (id, email, note) VALUES (?, ?, ?)"))
+	assert.NoError(t, g.ExecQuery(context.Background(), "accounts.add", 1, "ana@example.com", nil))
+
+	if !assert.Len(t, queries, 2) {
+		return
+	}
+	query := queries[1]
+	assert.Equal(t, "INSERT INTO accounts (id, email, note) VALUES (?, ?, ?)", query.SQL)
+	assert.Equal(t, []any{"<int>", "<string>", nil}, query.Args, "Arguments should be redacted")
+	assert.Equal(t, "accounts.add", query.QueryName)
+	assert.Positive(t, query.Duration)
+	assert.Equal(t, "slowquery_test.go", filepath.Base(strings.Split(query.Caller, ":")[0]), "Caller should skip gorm and gormext frames")
+
+	dbCtx.SetSlowQueryThreshold(time.Hour)
+	g, err = NewGorm(dbCtx, nil, nil, nil)
+	assert.NoError(t, err)
+	queries = nil
+	g.OnSlowQuery(func(q SlowQuery) { queries = append(queries, q) })
+	assert.NoError(t, g.connection.Exec("SELECT 1").Error)
+	assert.Empty(t, queries, "Statements under the threshold should not be reported")
+}
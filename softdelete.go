@@ -0,0 +1,82 @@
+package gormext
+
+import (
+	"fmt"
+	"reflect"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+// deletedAtType is the type of the soft delete fields of gorm.
+var deletedAtType = reflect.TypeOf(gorm.DeletedAt{})
+
+// trashedExpr is the condition of OnlyTrashed, resolved for the model of the statement when it
+// is built.
+type trashedExpr struct{}
+
+// Build writes the condition matching the soft-deleted records, on "deleted_at" for models
+// without soft delete field.
+func (trashedExpr) Build(builder clause.Builder) {
+	column := clause.Column{Name: "deleted_at"}
+	if stmt, ok := builder.(*gorm.Statement); ok && stmt.Schema != nil {
+		if field := softDeleteField(stmt.Schema); field != nil {
+			column = clause.Column{Table: clause.CurrentTable, Name: field.DBName}
+		}
+	}
+	clause.Expr{SQL: "? IS NOT NULL", Vars: []any{column}}.Build(builder)
+}
+
+// softDeleteField returns the gorm.DeletedAt field of table, nil without soft delete.
+func softDeleteField(table *schema.Schema) *schema.Field {
+	for _, field := range table.Fields {
+		if field.FieldType == deletedAtType {
+			return field
+		}
+	}
+	return nil
+}
+
+// OnlyTrashed filters the soft-deleted records, which the queries of soft-deleting models
+// leave out otherwise.
+func (r *gormRepository) OnlyTrashed() IRepository {
+	return r.with(r.db.Unscoped().Where(trashedExpr{}))
+}
+
+// Restore restores the soft-deleted entity, clearing its deleted_at column, and its deleted_by
+// one with Config.StampActor. It returns gorm.ErrRecordNotFound when the entity is not
+// soft-deleted.
+func (r *gormRepository) Restore(entity any) error {
+	return r.restore(r.db.Model(entity), entity)
+}
+
+// RestoreByID restores the soft-deleted record of model with primary key id, like Restore.
+func (r *gormRepository) RestoreByID(model any, id any) error {
+	return r.restore(r.db.Model(model).Where(clause.Eq{Column: clause.PrimaryColumn, Value: id}), model)
+}
+
+// restore clears the soft delete columns of the soft-deleted records of db.
+func (r *gormRepository) restore(db *gorm.DB, model any) error {
+	stmt := &gorm.Statement{DB: r.db}
+	if err := stmt.Parse(model); err != nil {
+		return fmt.Errorf("failed to parse model %T: %w", model, err)
+	}
+	field := softDeleteField(stmt.Schema)
+	if field == nil {
+		return fmt.Errorf("failed to restore %T: model has no soft delete field", model)
+	}
+
+	values := map[string]any{field.DBName: nil}
+	if deletedBy := actorField(stmt.Schema, "DeletedBy"); deletedBy != nil {
+		values[deletedBy.DBName] = nil
+	}
+	result := db.Unscoped().Where(trashedExpr{}).Updates(values)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
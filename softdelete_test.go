@@ -0,0 +1,77 @@
+package gormext
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// trashableNote is a soft-deleting model recording its deleter.
+type trashableNote struct {
+	ID        uint
+	Body      string
+	DeletedAt gorm.DeletedAt
+	DeletedBy *string
+}
+
+// TestSoftDeleteRestore verifies that soft deletes record the actor, and that trashed records
+// are listed and restored.
+func TestSoftDeleteRestore(t *testing.T) {
+	g, err := NewGorm(newTestDatabaseContext(), nil, nil, nil, Config{StampActor: true})
+	assert.NoError(t, err)
+	assert.NoError(t, g.Migrate(&trashableNote{}))
+	repo := g.GetDB().WithContext(ContextWithActor(context.Background(), "alice"))
+
+	notes := []trashableNote{{Body: "kept"}, {Body: "trashed"}, {Body: "purged"}}
+	assert.NoError(t, repo.Create(&notes))
+	assert.NoError(t, repo.Delete(&notes[1]))
+	assert.NoError(t, repo.Delete(&notes[2]))
+	assert.Equal(t, "alice", *notes[1].DeletedBy)
+
+	var found []trashableNote
+	assert.NoError(t, repo.Find(&found))
+	assert.Len(t, found, 1)
+	assert.NoError(t, repo.OnlyTrashed().Order("id").Find(&found))
+	assert.Len(t, found, 2)
+	assert.Equal(t, "alice", *found[0].DeletedBy)
+	assert.True(t, found[0].DeletedAt.Valid)
+
+	assert.NoError(t, repo.Restore(&notes[1]))
+	assert.Nil(t, notes[1].DeletedBy)
+	assert.ErrorIs(t, repo.Restore(&notes[1]), gorm.ErrRecordNotFound, "restored records are not trashed")
+	assert.NoError(t, repo.RestoreByID(&trashableNote{}, notes[2].ID))
+	assert.ErrorIs(t, repo.RestoreByID(&trashableNote{}, 99), gorm.ErrRecordNotFound)
+	assert.ErrorContains(t, repo.Restore(&repoUser{ID: 1}), "model has no soft delete field")
+
+	assert.NoError(t, repo.Find(&found))
+	assert.Len(t, found, 3)
+	assert.Nil(t, found[1].DeletedBy)
+	assert.False(t, found[1].DeletedAt.Valid)
+}
+
+// TestSoftDeleteCallbackClauses verifies that the clauses added by callbacks registered before
+// gorm:delete apply to soft deletes recording their deleter.
+func TestSoftDeleteCallbackClauses(t *testing.T) {
+	g, err := NewGorm(newTestDatabaseContext(), nil, nil, nil, Config{StampActor: true})
+	assert.NoError(t, err)
+	assert.NoError(t, g.Migrate(&trashableNote{}))
+	assert.NoError(t, g.connection.Callback().Delete().Before("gorm:delete").Register("test:guard", func(db *gorm.DB) {
+		db.Statement.AddClause(clause.Where{Exprs: []clause.Expression{clause.Neq{Column: "body", Value: "pinned"}}})
+	}))
+	repo := g.GetDB().WithContext(ContextWithActor(context.Background(), "alice"))
+
+	notes := []trashableNote{{Body: "pinned"}, {Body: "draft"}}
+	assert.NoError(t, repo.Create(&notes))
+	assert.NoError(t, repo.Where("id > ?", 0).Delete(&trashableNote{}))
+
+	var found []trashableNote
+	assert.NoError(t, repo.Find(&found))
+	assert.Len(t, found, 1)
+	assert.Equal(t, "pinned", found[0].Body)
+	assert.NoError(t, repo.OnlyTrashed().Find(&found))
+	assert.Len(t, found, 1)
+	assert.Equal(t, "alice", *found[0].DeletedBy)
+}
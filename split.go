@@ -0,0 +1,181 @@
+package gormext
+
+import (
+	"regexp"
+	"strings"
+)
+
+const (
+	// defaultDelimiter ends statements unless a MySQL DELIMITER command changes it.
+	defaultDelimiter = ";"
+
+	// delimiterCommand is the MySQL client command changing the statement delimiter.
+	delimiterCommand = "DELIMITER"
+)
+
+// sqliteTriggerStart matches the start of a SQLite CREATE TRIGGER statement, after comments.
+var sqliteTriggerStart = regexp.MustCompile(`(?is)^\s*(?:(?:--[^\n]*(?:\n|$)|/\*.*?\*/)\s*)*CREATE\s+(?:TEMP\s+|TEMPORARY\s+)?TRIGGER\b`)
+
+// splitStatements splits a SQL script into the statements of driver, so they can be executed
+// one at a time. Statements end on semicolons outside string literals, quoted identifiers and
+// comments, as well as:
+//   - Postgres dollar-quoted bodies ($$ ... $$ or $tag$ ... $tag$) and escape strings (E'...'),
+//     whose backslashes escape quotes;
+//   - MySQL DELIMITER commands, which change the delimiter and are not statements themselves,
+//     backslash escapes in literals and # comments;
+//   - SQLite trigger bodies, up to their END.
+//
+// Statements holding only whitespace and comments are dropped.
+func splitStatements(script string, driver SQLDriver) []string {
+	var (
+		statements []string
+		start      int
+		blank      = true
+		delimiter  = defaultDelimiter
+	)
+	flush := func(end, next int) {
+		if !blank {
+			statements = append(statements, strings.TrimSpace(script[start:end]))
+		}
+		start, blank = next, true
+	}
+
+	for i := 0; i < len(script); {
+		c := script[i]
+		switch {
+		case driver == MySQL && blank && isDelimiterCommand(script[i:]):
+			end := commentEnd(script, i, "\n")
+			if next := strings.TrimSpace(script[i+len(delimiterCommand) : end]); next != "" {
+				delimiter = next
+			}
+			start, i = end, end
+		case c == '\'' || c == '"' || c == '`':
+			if driver == MySQL || driver == PostgreSQL && isEscapeString(script, i) {
+				i = skipQuotedEscaped(script, i, c)
+			} else {
+				i = skipQuoted(script, i, c)
+			}
+			blank = false
+		case c == '-' && strings.HasPrefix(script[i:], "--"):
+			i = commentEnd(script, i, "\n")
+		case c == '#' && driver == MySQL:
+			i = commentEnd(script, i, "\n")
+		case c == '/' && strings.HasPrefix(script[i:], "/*"):
+			i = commentEnd(script, i+2, "*/")
+		case c == '$' && driver == PostgreSQL:
+			if tag := dollarTag(script[i:]); tag != "" {
+				i = commentEnd(script, i+len(tag), tag)
+			} else {
+				i++
+			}
+			blank = false
+		case strings.HasPrefix(script[i:], delimiter):
+			if driver == SQLite && inTriggerBody(script[start:i]) {
+				i, blank = i+len(delimiter), false
+				continue
+			}
+			flush(i, i+len(delimiter))
+			i += len(delimiter)
+		default:
+			if c != ' ' && c != '\t' && c != '\n' && c != '\r' {
+				blank = false
+			}
+			i++
+		}
+	}
+	flush(len(script), len(script))
+	return statements
+}
+
+// commentEnd returns the index just past the terminator of the comment starting at start,
+// or the end of script for unterminated comments.
+func commentEnd(script string, start int, terminator string) int {
+	end := strings.Index(script[start:], terminator)
+	if end < 0 {
+		return len(script)
+	}
+	return start + end + len(terminator)
+}
+
+// skipQuotedEscaped is skipQuoted for dialects also escaping quotes with a backslash.
+func skipQuotedEscaped(query string, start int, quote byte) int {
+	for i := start + 1; i < len(query); i++ {
+		switch {
+		case query[i] == '\\' && quote != '`':
+			i++
+		case query[i] != quote:
+		case i+1 < len(query) && query[i+1] == quote:
+			i++
+		default:
+			return i + 1
+		}
+	}
+	return len(query)
+}
+
+// isDelimiterCommand reports whether script starts with a MySQL DELIMITER command.
+func isDelimiterCommand(script string) bool {
+	return len(script) > len(delimiterCommand) &&
+		strings.EqualFold(script[:len(delimiterCommand)], delimiterCommand) &&
+		(script[len(delimiterCommand)] == ' ' || script[len(delimiterCommand)] == '\t')
+}
+
+// dollarTag returns the Postgres dollar quote ($$ or $tag$) script starts with, if any.
+func dollarTag(script string) string {
+	for i := 1; i < len(script); i++ {
+		switch {
+		case script[i] == '$':
+			return script[:i+1]
+		case i == 1 && !isNameStart(script[i]), !isNamePart(script[i]):
+			return ""
+		}
+	}
+	return ""
+}
+
+// isEscapeString reports whether the quote at index i of script opens a Postgres escape
+// string, prefixed with E.
+func isEscapeString(script string, i int) bool {
+	return script[i] == '\'' && i > 0 && (script[i-1] == 'E' || script[i-1] == 'e') &&
+		(i == 1 || !isNamePart(script[i-2]))
+}
+
+// inTriggerBody reports whether statement is a SQLite CREATE TRIGGER whose body is not closed
+// yet: its BEGIN, and the CASE expressions inside, are not all matched by an END.
+func inTriggerBody(statement string) bool {
+	if !sqliteTriggerStart.MatchString(statement) {
+		return false
+	}
+
+	depth, opened := 0, false
+	for i := 0; i < len(statement); {
+		c := statement[i]
+		switch {
+		case c == '\'' || c == '"' || c == '`' || c == '[':
+			if c == '[' {
+				i = commentEnd(statement, i+1, "]")
+			} else {
+				i = skipQuoted(statement, i, c)
+			}
+		case c == '-' && strings.HasPrefix(statement[i:], "--"):
+			i = commentEnd(statement, i, "\n")
+		case c == '/' && strings.HasPrefix(statement[i:], "/*"):
+			i = commentEnd(statement, i+2, "*/")
+		case isNameStart(c):
+			end := i + 1
+			for end < len(statement) && isNamePart(statement[end]) {
+				end++
+			}
+			switch word := statement[i:end]; {
+			case strings.EqualFold(word, "BEGIN"), strings.EqualFold(word, "CASE"):
+				depth, opened = depth+1, true
+			case strings.EqualFold(word, "END"):
+				depth--
+			}
+			i = end
+		default:
+			i++
+		}
+	}
+	return !opened || depth > 0
+}
@@ -0,0 +1,75 @@
+package gormext
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestSplitStatements verifies scripts split on semicolons outside literals and comments.
+func TestSplitStatements(t *testing.T) {
+	script := `-- header; not a statement
+INSERT INTO t VALUES ('a;b', "c;d");
+/* block; comment */ UPDATE t SET v = 1;
+
+-- trailing comment;
+`
+	assert.Equal(t, []string{
+		"-- header; not a statement\nINSERT INTO t VALUES ('a;b', \"c;d\")",
+		"/* block; comment */ UPDATE t SET v = 1",
+	}, splitStatements(script, SQLite))
+	assert.Empty(t, splitStatements("  ;\n-- nothing\n", SQLite))
+}
+
+// TestSplitStatementsDialects verifies the delimiters and quoting specific to each driver.
+func TestSplitStatementsDialects(t *testing.T) {
+	postgres := `CREATE FUNCTION touch() RETURNS trigger AS $$
+BEGIN NEW.updated_at = now(); RETURN NEW; END;
+$$ LANGUAGE plpgsql;
+DO $body$ BEGIN PERFORM 1; END $body$;
+SELECT $1::int;`
+	assert.Equal(t, []string{
+		"CREATE FUNCTION touch() RETURNS trigger AS $$\nBEGIN NEW.updated_at = now(); RETURN NEW; END;\n$$ LANGUAGE plpgsql",
+		"DO $body$ BEGIN PERFORM 1; END $body$",
+		"SELECT $1::int",
+	}, splitStatements(postgres, PostgreSQL))
+	assert.Equal(t, []string{
+		`INSERT INTO t VALUES (E'it\'s; fine', 'C:\')`,
+		"SELECT 1",
+	}, splitStatements(`INSERT INTO t VALUES (E'it\'s; fine', 'C:\'); SELECT 1;`, PostgreSQL))
+
+	mysql := `INSERT INTO t VALUES ('it\'s; fine'); # comment; here
+DELIMITER //
+CREATE PROCEDURE p() BEGIN SELECT 1; SELECT 2; END//
+DELIMITER ;
+SELECT 3;`
+	assert.Equal(t, []string{
+		`INSERT INTO t VALUES ('it\'s; fine')`,
+		"CREATE PROCEDURE p() BEGIN SELECT 1; SELECT 2; END",
+		"SELECT 3",
+	}, splitStatements(mysql, MySQL))
+
+	sqlite := `-- audit
+CREATE TRIGGER trg AFTER INSERT ON t BEGIN
+  INSERT INTO audit VALUES (NEW.id);
+  UPDATE t SET seen = 1 WHERE id = NEW.id;
+END;
+INSERT INTO t VALUES (1);`
+	assert.Equal(t, []string{
+		"-- audit\nCREATE TRIGGER trg AFTER INSERT ON t BEGIN\n  INSERT INTO audit VALUES (NEW.id);\n  UPDATE t SET seen = 1 WHERE id = NEW.id;\nEND",
+		"INSERT INTO t VALUES (1)",
+	}, splitStatements(sqlite, SQLite))
+
+	// END closes the CASE expressions of trigger bodies as well as the bodies themselves.
+	sqlite = `CREATE TRIGGER grade AFTER INSERT ON t BEGIN
+  INSERT INTO audit VALUES ('end;');
+  UPDATE t SET grade = CASE
+    WHEN NEW.score > 90 THEN 'A'
+    ELSE 'B' END;
+END;
+INSERT INTO t VALUES (1);`
+	assert.Equal(t, []string{
+		"CREATE TRIGGER grade AFTER INSERT ON t BEGIN\n  INSERT INTO audit VALUES ('end;');\n  UPDATE t SET grade = CASE\n    WHEN NEW.score > 90 THEN 'A'\n    ELSE 'B' END;\nEND",
+		"INSERT INTO t VALUES (1)",
+	}, splitStatements(sqlite, SQLite))
+}
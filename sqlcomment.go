@@ -0,0 +1,130 @@
+package gormext
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/gorm"
+)
+
+// commentPoolKey is the statement instance setting holding the connection pool replaced by the
+// commenting one.
+const commentPoolKey = "gormext:comment_pool"
+
+type (
+	// routeKey is the context key of the current route.
+	routeKey struct{}
+
+	// commentedPool is a connection pool appending a SQL comment to every statement.
+	commentedPool struct {
+		gorm.ConnPool
+		comment string
+	}
+)
+
+// WithRoute returns a context carrying route, the route or handler running the statements,
+// such as "GET /users/:id", added to SQL comments when Config.SQLComments is set.
+func WithRoute(ctx context.Context, route string) context.Context {
+	return context.WithValue(ctx, routeKey{}, route)
+}
+
+// registerCommentCallbacks registers the callbacks commenting every statement.
+func (g *Gorm) registerCommentCallbacks(application string) error {
+	const (
+		startName = "gormext:comment"
+		endName   = "gormext:comment_end"
+	)
+
+	start := func(db *gorm.DB) {
+		comment := sqlComment(db.Statement.Context, application)
+		if comment == "" || db.Statement.ConnPool == nil {
+			return
+		}
+		db.InstanceSet(commentPoolKey, db.Statement.ConnPool)
+		db.Statement.ConnPool = &commentedPool{ConnPool: db.Statement.ConnPool, comment: comment}
+	}
+	end := func(db *gorm.DB) {
+		if pool, ok := db.InstanceGet(commentPoolKey); ok {
+			db.Statement.ConnPool = pool.(gorm.ConnPool)
+		}
+	}
+
+	callbacks := g.connection.Callback()
+	return errors.Join(
+		callbacks.Create().Before("gorm:create").Register(startName, start),
+		callbacks.Create().After("gorm:create").Register(endName, end),
+		callbacks.Query().Before("gorm:query").Register(startName, start),
+		callbacks.Query().After("gorm:query").Register(endName, end),
+		callbacks.Update().Before("gorm:update").Register(startName, start),
+		callbacks.Update().After("gorm:update").Register(endName, end),
+		callbacks.Delete().Before("gorm:delete").Register(startName, start),
+		callbacks.Delete().After("gorm:delete").Register(endName, end),
+		callbacks.Row().Before("gorm:row").Register(startName, start),
+		callbacks.Row().After("gorm:row").Register(endName, end),
+		callbacks.Raw().Before("gorm:raw").Register(startName, start),
+		callbacks.Raw().After("gorm:raw").Register(endName, end),
+	)
+}
+
+// sqlComment returns the sqlcommenter comment of the statements run with ctx: sorted
+// key='value' pairs with URL-encoded values, empty when there is nothing to add.
+func sqlComment(ctx context.Context, application string) string {
+	tags := make(map[string]string)
+	if application != "" {
+		tags["application"] = application
+	}
+	if ctx != nil {
+		if route, ok := ctx.Value(routeKey{}).(string); ok && route != "" {
+			tags["route"] = route
+		}
+		if span := trace.SpanContextFromContext(ctx); span.IsValid() {
+			tags["traceparent"] = fmt.Sprintf("00-%s-%s-%s", span.TraceID(), span.SpanID(), span.TraceFlags())
+		}
+	}
+	if len(tags) == 0 {
+		return ""
+	}
+
+	pairs := make([]string, 0, len(tags))
+	for key, value := range tags {
+		value = strings.ReplaceAll(url.QueryEscape(value), "+", "%20")
+		pairs = append(pairs, fmt.Sprintf("%s='%s'", key, value))
+	}
+	sort.Strings(pairs)
+	return "/*" + strings.Join(pairs, ",") + "*/"
+}
+
+// annotate appends the comment to query, before a trailing semicolon.
+func (p *commentedPool) annotate(query string) string {
+	query = strings.TrimRight(query, " \t\r\n")
+	if trimmed, ok := strings.CutSuffix(query, ";"); ok {
+		return trimmed + " " + p.comment + ";"
+	}
+	return query + " " + p.comment
+}
+
+// PrepareContext prepares the commented query.
+func (p *commentedPool) PrepareContext(ctx context.Context, query string) (*sql.Stmt, error) {
+	return p.ConnPool.PrepareContext(ctx, p.annotate(query))
+}
+
+// ExecContext executes the commented query.
+func (p *commentedPool) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	return p.ConnPool.ExecContext(ctx, p.annotate(query), args...)
+}
+
+// QueryContext runs the commented query.
+func (p *commentedPool) QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	return p.ConnPool.QueryContext(ctx, p.annotate(query), args...)
+}
+
+// QueryRowContext runs the commented query, returning at most one row.
+func (p *commentedPool) QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row {
+	return p.ConnPool.QueryRowContext(ctx, p.annotate(query), args...)
+}
@@ -0,0 +1,70 @@
+package gormext
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// recordingPool is a connection pool recording the statements it runs.
+type recordingPool struct {
+	*sql.DB
+	statements []string
+}
+
+func (p *recordingPool) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	p.statements = append(p.statements, query)
+	return p.DB.ExecContext(ctx, query, args...)
+}
+
+func (p *recordingPool) QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	p.statements = append(p.statements, query)
+	return p.DB.QueryContext(ctx, query, args...)
+}
+
+// TestSQLComments verifies that statements are sent with the application, route and
+// traceparent of their context, and left unchanged without SQL comments.
+func TestSQLComments(t *testing.T) {
+	sqlDB, err := sql.Open("sqlite3", ":memory:")
+	assert.NoError(t, err)
+	defer sqlDB.Close()
+
+	pool := &recordingPool{DB: sqlDB}
+	g, err := NewGorm(newTestDatabaseContext(), nil, nil, nil, Config{
+		Config:      gorm.Config{SkipDefaultTransaction: true},
+		Dialector:   sqlite.Dialector{Conn: pool},
+		SQLComments: true,
+		Application: "billing api",
+	})
+	assert.NoError(t, err)
+
+	traceID, _ := trace.TraceIDFromHex("4bf92f3577b34da6a3ce929d0e0e4736")
+	spanID, _ := trace.SpanIDFromHex("00f067aa0ba902b7")
+	ctx := trace.ContextWithSpanContext(context.Background(), trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID: traceID, SpanID: spanID, TraceFlags: trace.FlagsSampled,
+	}))
+	ctx = WithRoute(ctx, "GET /users/:id")
+
+	pool.statements = nil
+	assert.NoError(t, g.connection.WithContext(ctx).Exec("CREATE TABLE users (id INTEGER);").Error)
+	var count int64
+	assert.NoError(t, g.connection.Table("users").Count(&count).Error)
+	assert.Equal(t, []string{
+		"CREATE TABLE users (id INTEGER) /*application='billing%20api',route='GET%20%2Fusers%2F%3Aid',traceparent='00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01'*/;",
+		"SELECT count(*) FROM `users` /*application='billing%20api'*/",
+	}, pool.statements)
+
+	g, err = NewGorm(newTestDatabaseContext(), nil, nil, nil, Config{
+		Config:    gorm.Config{SkipDefaultTransaction: true},
+		Dialector: sqlite.Dialector{Conn: pool},
+	})
+	assert.NoError(t, err)
+	pool.statements = nil
+	assert.NoError(t, g.connection.WithContext(ctx).Exec("SELECT 1").Error)
+	assert.Equal(t, []string{"SELECT 1"}, pool.statements, "Statements should not be commented by default")
+}
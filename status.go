@@ -0,0 +1,130 @@
+package gormext
+
+import (
+	"strings"
+	"sync"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+// statusPluginName is the name of the plugin holding the registered active conditions.
+const statusPluginName = "gormext:status"
+
+type (
+	// statusConditions is the plugin holding the active conditions of models, registered with
+	// RegisterActive or read from their tags, so that the conditions built by IsActive and
+	// WithStatus reach them.
+	statusConditions struct {
+		active sync.Map // reflect.Type of the model -> clause.Expression of IsActive
+	}
+
+	// activeExpr is the condition of IsActive, resolved for the model of the statement when it
+	// is built, since the model is usually set after IsActive is called.
+	activeExpr struct{}
+
+	// statusExpr is the condition of WithStatus, resolved like activeExpr.
+	statusExpr struct {
+		values []string
+	}
+)
+
+// Name returns the plugin name.
+func (c *statusConditions) Name() string {
+	return statusPluginName
+}
+
+// Initialize does nothing: the conditions are resolved by the statements.
+func (c *statusConditions) Initialize(*gorm.DB) error {
+	return nil
+}
+
+// RegisterActive registers the condition IsActive filters the records of model with, such as
+// "status = ?" with "enabled", or "deleted_at IS NULL". It takes precedence over the tags of
+// the model, see IsActive.
+func (g *Gorm) RegisterActive(model any, query string, args ...any) error {
+	table, err := g.parseModel(model)
+	if err != nil {
+		return err
+	}
+	g.statuses.active.Store(table.ModelType, clause.Expr{SQL: query, Vars: args})
+	return nil
+}
+
+// activeCondition returns the IsActive condition of table.
+func (c *statusConditions) activeCondition(table *schema.Schema) clause.Expression {
+	condition, ok := c.active.Load(table.ModelType)
+	if !ok {
+		condition, _ = c.active.LoadOrStore(table.ModelType, taggedActiveCondition(table))
+	}
+	return condition.(clause.Expression)
+}
+
+// taggedActiveCondition returns the IsActive condition of the field of table tagged
+// `gormext:"active"`, "active IS TRUE" without one.
+func taggedActiveCondition(table *schema.Schema) clause.Expression {
+	for _, field := range table.Fields {
+		value, ok := schema.ParseTagSetting(field.Tag.Get("gormext"), ";")["ACTIVE"]
+		if !ok {
+			continue
+		}
+
+		column := clause.Column{Table: clause.CurrentTable, Name: field.DBName}
+		switch {
+		case value == "ACTIVE":
+			return clause.Expr{SQL: "? IS TRUE", Vars: []any{column}}
+		case strings.EqualFold(value, "null"):
+			return clause.Expr{SQL: "? IS NULL", Vars: []any{column}}
+		default:
+			return statusIn(column, strings.Split(value, ","))
+		}
+	}
+	return defaultActiveCondition
+}
+
+// statusColumn returns the status column of table: its field tagged `gormext:"status"`, or
+// "status".
+func statusColumn(table *schema.Schema) clause.Column {
+	if table != nil {
+		for _, field := range table.Fields {
+			if _, ok := schema.ParseTagSetting(field.Tag.Get("gormext"), ";")["STATUS"]; ok {
+				return clause.Column{Table: clause.CurrentTable, Name: field.DBName}
+			}
+		}
+	}
+	return clause.Column{Name: "status"}
+}
+
+// statusIn returns the condition matching column against values.
+func statusIn(column clause.Column, values []string) clause.Expression {
+	if len(values) == 1 {
+		return clause.Eq{Column: column, Value: values[0]}
+	}
+
+	in := make([]any, len(values))
+	for i, value := range values {
+		in[i] = value
+	}
+	return clause.IN{Column: column, Values: in}
+}
+
+// Build writes the active condition of the statement model, "active IS TRUE" by default.
+func (activeExpr) Build(builder clause.Builder) {
+	condition := clause.Expression(defaultActiveCondition)
+	if stmt, ok := builder.(*gorm.Statement); ok && stmt.Schema != nil {
+		if c, ok := stmt.DB.Config.Plugins[statusPluginName].(*statusConditions); ok {
+			condition = c.activeCondition(stmt.Schema)
+		}
+	}
+	condition.Build(builder)
+}
+
+// Build writes the condition on the status column of the statement model.
+func (e statusExpr) Build(builder clause.Builder) {
+	var table *schema.Schema
+	if stmt, ok := builder.(*gorm.Statement); ok {
+		table = stmt.Schema
+	}
+	statusIn(statusColumn(table), e.values).Build(builder)
+}
@@ -0,0 +1,60 @@
+package gormext
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+type (
+	// statusAccount is a model whose active records have an enabled status.
+	statusAccount struct {
+		ID    uint
+		State string `gormext:"status;active:enabled,trial"`
+	}
+
+	// statusDocument is a model whose active records are not deleted.
+	statusDocument struct {
+		ID        uint
+		DeletedAt *time.Time `gormext:"active:null"`
+	}
+)
+
+// TestIsActiveConditions verifies the IsActive condition of tagged, registered and default
+// models, and the WithStatus column.
+func TestIsActiveConditions(t *testing.T) {
+	g, repo := newTestRepository(t)
+	render := func(repo IRepository, dest any) string {
+		return repo.(*gormRepository).db.ToSQL(func(tx *gorm.DB) *gorm.DB { return tx.Find(dest) })
+	}
+
+	assert.Equal(t, "SELECT * FROM `repo_users` WHERE active IS TRUE", render(repo.IsActive(), &[]repoUser{}))
+	assert.Equal(t, "SELECT * FROM `status_accounts` WHERE `status_accounts`.`state` IN (\"enabled\",\"trial\")",
+		render(repo.IsActive(), &[]statusAccount{}))
+	assert.Equal(t, "SELECT * FROM `status_documents` WHERE `status_documents`.`deleted_at` IS NULL",
+		render(repo.IsActive(), &[]statusDocument{}))
+
+	assert.NoError(t, g.RegisterActive(&repoUser{}, "age >= ? AND active IS TRUE", 18))
+	assert.Equal(t, "SELECT * FROM `repo_users` WHERE age >= 18 AND active IS TRUE", render(repo.IsActive(), &[]repoUser{}))
+
+	assert.Equal(t, "SELECT * FROM `status_accounts` WHERE `status_accounts`.`state` = \"closed\"",
+		render(repo.WithStatus("closed"), &[]statusAccount{}))
+	assert.Equal(t, "SELECT * FROM `repo_users` WHERE `status` IN (\"a\",\"b\")", render(repo.WithStatus("a", "b"), &[]repoUser{}))
+	assert.Equal(t, "SELECT * FROM `repo_users`", render(repo.WithStatus(), &[]repoUser{}))
+}
+
+// TestIsActiveQuery verifies that IsActive filters the rows of a tagged model.
+func TestIsActiveQuery(t *testing.T) {
+	g, repo := newTestRepository(t)
+	assert.NoError(t, g.Migrate(&statusAccount{}))
+	assert.NoError(t, repo.Create(&[]statusAccount{{State: "enabled"}, {State: "trial"}, {State: "closed"}}))
+
+	var accounts []statusAccount
+	assert.NoError(t, repo.IsActive().Order("id").Find(&accounts))
+	assert.Len(t, accounts, 2)
+
+	assert.NoError(t, repo.WithStatus("closed").Find(&accounts))
+	assert.Len(t, accounts, 1)
+}
@@ -0,0 +1,185 @@
+package gormext
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// defaultStreamFlushEvery is the number of rows written between flushes by default.
+const defaultStreamFlushEvery = 100
+
+type (
+	// StreamOption configures a StreamJSON run.
+	StreamOption func(*streamOptions)
+
+	// streamOptions holds the settings of a StreamJSON run.
+	streamOptions struct {
+		args       []any
+		flushEvery int
+	}
+)
+
+// WithStreamArgs binds the placeholders of a cached query streamed by StreamJSON. A single
+// map[string]any binds its :name placeholders.
+func WithStreamArgs(args ...any) StreamOption {
+	return func(o *streamOptions) {
+		o.args = args
+	}
+}
+
+// WithFlushEvery flushes the writer every n rows, when it has a Flush method such as an
+// http.ResponseWriter or a *bufio.Writer. Values below 1 flush after every row.
+func WithFlushEvery(n int) StreamOption {
+	return func(o *streamOptions) {
+		o.flushEvery = max(n, 1)
+	}
+}
+
+// StreamJSON writes the rows of source to w as a JSON array of objects keyed by column name,
+// one row at a time, so large results are never held in memory. source is the name of a
+// cached query or an IRepository chain such as GetDB().Table("orders").Where(...).
+//
+// The query runs with ctx and stops when it is cancelled, returning its error. When an error
+// occurs after the first bytes were written, the array is left unterminated so the output
+// cannot be mistaken for a complete result.
+func (g *Gorm) StreamJSON(ctx context.Context, w io.Writer, source any, opts ...StreamOption) error {
+	options := streamOptions{flushEvery: defaultStreamFlushEvery}
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	rows, name, err := g.streamRows(ctx, source, options.args)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	if err := writeJSONRows(ctx, w, rows, options.flushEvery); err != nil {
+		return fmt.Errorf("failed to stream %s: %w", name, err)
+	}
+	return nil
+}
+
+// streamRows runs source with ctx, returning its rows and a description for errors.
+func (g *Gorm) streamRows(ctx context.Context, source any, args []any) (*sql.Rows, string, error) {
+	switch source := source.(type) {
+	case string:
+		name := fmt.Sprintf("sql query '%s'", source)
+
+		var (
+			query string
+			err   error
+		)
+		if named, ok := singleNamedParams(args); ok {
+			query, args, err = g.bindNamedQuery(source, named)
+		} else {
+			query, err = g.GetQuery(source)
+		}
+		if err != nil {
+			return nil, name, err
+		}
+
+		rows, err := g.queryConn(ctx, source).Raw(query, args...).Rows()
+		if err != nil {
+			return nil, name, fmt.Errorf("failed to run %s: %w", name, err)
+		}
+		return rows, name, nil
+	case IRepository:
+		rows, err := source.WithContext(ctx).Rows()
+		if err != nil {
+			return nil, "query", fmt.Errorf("failed to run query: %w", err)
+		}
+		return rows, "query", nil
+	default:
+		return nil, "", fmt.Errorf("invalid stream source %T: expected a query name or an IRepository", source)
+	}
+}
+
+// writeJSONRows encodes rows as a JSON array, keeping the column order in each object.
+func writeJSONRows(ctx context.Context, w io.Writer, rows *sql.Rows, flushEvery int) error {
+	columns, err := rows.Columns()
+	if err != nil {
+		return err
+	}
+
+	// Column names are encoded once, as the key prefix of their value.
+	keys := make([][]byte, len(columns))
+	for i, column := range columns {
+		key, err := json.Marshal(column)
+		if err != nil {
+			return err
+		}
+		keys[i] = append(key, ':')
+	}
+
+	values := make([]any, len(columns))
+	targets := make([]any, len(columns))
+	for i := range values {
+		targets[i] = &values[i]
+	}
+
+	var buf bytes.Buffer
+	buf.WriteByte('[')
+	for n := 0; rows.Next(); n++ {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := rows.Scan(targets...); err != nil {
+			return err
+		}
+
+		if n > 0 {
+			buf.WriteByte(',')
+		}
+		buf.WriteByte('{')
+		for i, value := range values {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			buf.Write(keys[i])
+
+			// Text columns are returned as bytes by some drivers.
+			if raw, ok := value.([]byte); ok {
+				value = string(raw)
+			}
+			encoded, err := json.Marshal(value)
+			if err != nil {
+				return fmt.Errorf("failed to encode column '%s': %w", columns[i], err)
+			}
+			buf.Write(encoded)
+		}
+		buf.WriteByte('}')
+
+		if (n+1)%flushEvery == 0 {
+			if err := flushJSON(w, &buf); err != nil {
+				return err
+			}
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	buf.WriteByte(']')
+	return flushJSON(w, &buf)
+}
+
+// flushJSON writes the buffered output to w and flushes w when it supports it.
+func flushJSON(w io.Writer, buf *bytes.Buffer) error {
+	if _, err := w.Write(buf.Bytes()); err != nil {
+		return err
+	}
+	buf.Reset()
+
+	switch f := w.(type) {
+	case interface{ Flush() error }:
+		return f.Flush()
+	case interface{ Flush() }:
+		f.Flush()
+	}
+	return nil
+}
@@ -0,0 +1,52 @@
+package gormext
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// flushBuffer counts the flushes of a buffer.
+type flushBuffer struct {
+	bytes.Buffer
+	flushes int
+}
+
+func (b *flushBuffer) Flush() { b.flushes++ }
+
+// TestStreamJSON verifies that cached queries and repository chains stream as JSON arrays.
+func TestStreamJSON(t *testing.T) {
+	g := newFileSeedGorm(t, t.TempDir())
+	assert.NoError(t, g.GetDB().Exec("CREATE TABLE exports (id INTEGER, label TEXT, price REAL)"))
+	for i := 1; i <= 5; i++ {
+		assert.NoError(t, g.GetDB().Exec("INSERT INTO exports VALUES (?, ?, ?)", i, fmt.Sprintf("item %d", i), float64(i)/2))
+	}
+	assert.NoError(t, g.RegisterQuery("exports.above", "SELECT id, label FROM exports WHERE id > :id ORDER BY id"))
+
+	var out flushBuffer
+	assert.NoError(t, g.StreamJSON(context.Background(), &out, "exports.above",
+		WithStreamArgs(map[string]any{"id": 3}), WithFlushEvery(1)))
+	assert.Equal(t, `[{"id":4,"label":"item 4"},{"id":5,"label":"item 5"}]`, out.String())
+	assert.Equal(t, 3, out.flushes, "Rows should be flushed as configured")
+
+	var chain bytes.Buffer
+	repo := g.GetDB().Table("exports").Where("price >= ?", 2).Order("id DESC")
+	assert.NoError(t, g.StreamJSON(context.Background(), &chain, repo))
+	var rows []map[string]any
+	assert.NoError(t, json.Unmarshal(chain.Bytes(), &rows))
+	assert.Len(t, rows, 2)
+	assert.EqualValues(t, 2.5, rows[0]["price"])
+
+	var empty bytes.Buffer
+	assert.NoError(t, g.StreamJSON(context.Background(), &empty, g.GetDB().Table("exports").Where("id < 0")))
+	assert.Equal(t, "[]", empty.String())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.ErrorIs(t, g.StreamJSON(ctx, &bytes.Buffer{}, repo), context.Canceled)
+	assert.ErrorContains(t, g.StreamJSON(context.Background(), &empty, 42), "invalid stream source int")
+}
@@ -0,0 +1,72 @@
+package gormext
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+)
+
+// queryTemplate is a parsed cached query, kept with the text it was parsed from.
+type queryTemplate struct {
+	text string
+	tmpl *template.Template
+}
+
+// GetQueryTemplated renders the cached query name through text/template with data, for
+// structural variations such as optional JOINs or dynamic column lists. Values should still
+// be passed as query arguments; the template only has access to identifier helpers:
+//
+//	ident "name"         quotes a (possibly qualified) identifier
+//	idents .Columns      quotes and comma-separates a list of identifiers
+//	join .Parts ", "     joins strings (use only for trusted fragments)
+//
+// Parsed templates are cached and reparsed when the underlying query changes.
+func (g *Gorm) GetQueryTemplated(name string, data any) (string, error) {
+	query, err := g.GetQuery(name)
+	if err != nil {
+		return "", err
+	}
+
+	var tmpl *template.Template
+	if cached, ok := g.templates.Load(name); ok && cached.(*queryTemplate).text == query {
+		tmpl = cached.(*queryTemplate).tmpl
+	} else {
+		tmpl, err = template.New(name).Option("missingkey=error").Funcs(g.templateFuncs()).Parse(query)
+		if err != nil {
+			return "", fmt.Errorf("failed to parse sql query template '%s': %w", name, err)
+		}
+		g.templates.Store(name, &queryTemplate{text: query, tmpl: tmpl})
+	}
+
+	var rendered strings.Builder
+	if err := tmpl.Execute(&rendered, data); err != nil {
+		return "", fmt.Errorf("failed to render sql query template '%s': %w", name, err)
+	}
+	return rendered.String(), nil
+}
+
+// templateFuncs returns the function map available to query templates.
+func (g *Gorm) templateFuncs() template.FuncMap {
+	ident := func(name string) (string, error) {
+		if !routineNamePattern.MatchString(name) {
+			return "", fmt.Errorf("invalid identifier '%s'", name)
+		}
+		return g.connection.Statement.Quote(name), nil
+	}
+
+	return template.FuncMap{
+		"ident": ident,
+		"idents": func(names []string) (string, error) {
+			quoted := make([]string, len(names))
+			for i, name := range names {
+				q, err := ident(name)
+				if err != nil {
+					return "", err
+				}
+				quoted[i] = q
+			}
+			return strings.Join(quoted, ", "), nil
+		},
+		"join": strings.Join,
+	}
+}
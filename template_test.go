@@ -0,0 +1,32 @@
+package gormext
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestGetQueryTemplated verifies cached queries render with data and the identifier helpers.
+func TestGetQueryTemplated(t *testing.T) {
+	g, _ := newTestRepository(t)
+	g.sqlQueries.Store("users", "SELECT {{idents .Columns}} FROM {{ident .Table}}{{if .Join}} JOIN roles r ON r.id = u.role_id{{end}} WHERE age > ?")
+
+	query, err := g.GetQueryTemplated("users", map[string]any{"Columns": []string{"u.id", "name"}, "Table": "repo_users", "Join": true})
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT `u`.`id`, `name` FROM `repo_users` JOIN roles r ON r.id = u.role_id WHERE age > ?", query)
+
+	query, err = g.GetQueryTemplated("users", map[string]any{"Columns": []string{"id"}, "Table": "repo_users", "Join": false})
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT `id` FROM `repo_users` WHERE age > ?", query)
+
+	_, err = g.GetQueryTemplated("users", map[string]any{"Columns": []string{"id; DROP TABLE x"}, "Table": "repo_users", "Join": false})
+	assert.ErrorContains(t, err, "invalid identifier")
+
+	_, err = g.GetQueryTemplated("users", map[string]any{"Columns": []string{"id"}})
+	assert.Error(t, err, "Missing keys should fail rendering")
+
+	g.sqlQueries.Store("users", "SELECT 1 FROM {{ident .Table}}")
+	query, err = g.GetQueryTemplated("users", map[string]any{"Table": "t"})
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT 1 FROM `t`", query, "Changed queries should be reparsed")
+}
@@ -0,0 +1,252 @@
+package gormext
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	mysqldriver "github.com/go-sql-driver/mysql"
+	"github.com/jackc/pgx/v5"
+)
+
+// tenantPruneEvery is the number of connection switches between two removals of the closed
+// connections from the schemas tracked by a tenantPool.
+const tenantPruneEvery = 64
+
+type (
+	// tenantKey is the context key of the current tenant.
+	tenantKey struct{}
+
+	// tenantPool is the connection pool of Config.SchemaPerTenant: every statement runs on a
+	// connection switched to the schema of the tenant of its context, or back to the default
+	// one. The schema of each connection is tracked, so that connections are switched only
+	// when they served another tenant last.
+	tenantPool struct {
+		db       *sql.DB
+		schema   func(ctx context.Context, tenantID string) (string, error)
+		switchTo func(schema string) string
+		current  sync.Map // driver connection -> schema, "" for the default one
+		switches atomic.Uint64
+	}
+)
+
+// ContextWithTenant returns a context carrying the ID of the tenant operations run for, which
+// keeps apart the cached results of tenants and selects the schema (Config.SchemaPerTenant)
+// or rows (Config.TenantColumn) of statements. Middleware sets it once per request, and
+// repositories given the request context with WithContext run for its tenant.
+func ContextWithTenant(ctx context.Context, tenantID string) context.Context {
+	return context.WithValue(ctx, tenantKey{}, tenantID)
+}
+
+// TenantFromContext returns the tenant ID carried by ctx, if any.
+func TenantFromContext(ctx context.Context) (string, bool) {
+	if ctx == nil {
+		return "", false
+	}
+	tenantID, ok := ctx.Value(tenantKey{}).(string)
+	return tenantID, ok
+}
+
+// bindTenant returns ctx carrying the tenant of bound when it carries none.
+func bindTenant(ctx, bound context.Context) context.Context {
+	if _, ok := TenantFromContext(ctx); ok {
+		return ctx
+	}
+	if tenantID, ok := TenantFromContext(bound); ok {
+		return ContextWithTenant(ctx, tenantID)
+	}
+	return ctx
+}
+
+// ForTenant returns a repository running its operations for tenantID: with
+// Config.SchemaPerTenant, in the Postgres schema or MySQL database of the tenant, and with
+// Config.TenantColumn, on its rows only. WithContext keeps the tenant unless the new context
+// carries another one.
+func (g *Gorm) ForTenant(tenantID string) IRepository {
+	return g.repository(g.connection.WithContext(ContextWithTenant(context.Background(), tenantID)))
+}
+
+// useTenantPool replaces the connection pool by a tenantPool switching the schema of the
+// connections with the statement for the driver.
+func (g *Gorm) useTenantPool(schema func(tenantID string) string) error {
+	switchSchema, err := tenantSwitch(g.databaseCtx)
+	if err != nil {
+		return err
+	}
+
+	sqlDB, ok := g.connection.ConnPool.(*sql.DB)
+	if !ok {
+		return fmt.Errorf("schema per tenant needs a *sql.DB connection pool, got %T", g.connection.ConnPool)
+	}
+
+	if schema == nil {
+		schema = func(tenantID string) string { return tenantID }
+	}
+	g.tenantSchema = schema
+	pool := &tenantPool{db: sqlDB, schema: g.schemaOf, switchTo: switchSchema}
+	g.connection.ConnPool = pool
+	g.connection.Statement.ConnPool = pool
+	return nil
+}
+
+// schemaOf returns the schema of tenantID: the one of its catalog record with
+// Config.TenantCatalog, or Config.TenantSchema of the ID.
+func (g *Gorm) schemaOf(ctx context.Context, tenantID string) (string, error) {
+	if g.tenantCatalog {
+		record, err := g.catalog.Get(ctx, tenantID)
+		if err != nil {
+			return "", err
+		}
+		if record.Schema != "" {
+			return record.Schema, nil
+		}
+	}
+	return g.tenantSchema(tenantID), nil
+}
+
+// tenantSwitch returns the function building the statement that switches a connection to a
+// schema, or back to the default one for an empty schema.
+func tenantSwitch(databaseCtx DatabaseContext) (func(schema string) string, error) {
+	switch databaseCtx.driver {
+	case PostgreSQL:
+		return func(schema string) string {
+			if schema == "" {
+				return "RESET search_path"
+			}
+			return `SET search_path TO "` + strings.ReplaceAll(schema, `"`, `""`) + `"`
+		}, nil
+	case MySQL:
+		dsn, err := mysqldriver.ParseDSN(databaseCtx.dsn)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse mysql DSN: %w", err)
+		}
+		if dsn.DBName == "" {
+			return nil, errors.New("schema per tenant needs the default database in the mysql DSN")
+		}
+		return func(schema string) string {
+			if schema == "" {
+				schema = dsn.DBName
+			}
+			return "USE `" + strings.ReplaceAll(schema, "`", "``") + "`"
+		}, nil
+	}
+	return nil, fmt.Errorf("%w: schema per tenant on '%s'", ErrUnsupportedDriver, databaseCtx.GetDriverAlias())
+}
+
+// conn returns a connection switched to the schema of the tenant of ctx.
+func (p *tenantPool) conn(ctx context.Context) (*sql.Conn, error) {
+	schema := ""
+	if tenantID, ok := TenantFromContext(ctx); ok {
+		var err error
+		if schema, err = p.schema(ctx, tenantID); err != nil {
+			return nil, err
+		}
+	}
+
+	conn, err := p.db.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var driverConn any
+	_ = conn.Raw(func(dc any) error {
+		driverConn = dc
+		return nil
+	})
+
+	current, _ := p.current.Load(driverConn)
+	if current == nil {
+		current = ""
+	}
+	if current == schema {
+		return conn, nil
+	}
+
+	if _, err := conn.ExecContext(ctx, p.switchTo(schema)); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to switch to schema '%s': %w", schema, err)
+	}
+	p.current.Store(driverConn, schema)
+	if p.switches.Add(1)%tenantPruneEvery == 0 {
+		p.prune()
+	}
+	return conn, nil
+}
+
+// prune forgets the schemas of the connections closed by the pool.
+func (p *tenantPool) prune() {
+	p.current.Range(func(key, _ any) bool {
+		switch dc := key.(type) {
+		case driver.Validator:
+			if !dc.IsValid() {
+				p.current.Delete(key)
+			}
+		case interface{ Conn() *pgx.Conn }:
+			if dc.Conn().IsClosed() {
+				p.current.Delete(key)
+			}
+		}
+		return true
+	})
+}
+
+// releaseTenantConn returns conn to the pool once the rows, row or transaction using it are done.
+func releaseTenantConn(conn *sql.Conn) {
+	// Close waits for the operations running on the connection.
+	go conn.Close()
+}
+
+// PrepareContext fails: prepared statements outlive the connection of their tenant.
+func (p *tenantPool) PrepareContext(context.Context, string) (*sql.Stmt, error) {
+	return nil, errors.New("prepared statements are not supported with schema per tenant")
+}
+
+// ExecContext executes the query in the schema of the tenant of ctx.
+func (p *tenantPool) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	conn, err := p.conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	return conn.ExecContext(ctx, query, args...)
+}
+
+// QueryContext runs the query in the schema of the tenant of ctx.
+func (p *tenantPool) QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	conn, err := p.conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer releaseTenantConn(conn)
+	return conn.QueryContext(ctx, query, args...)
+}
+
+// QueryRowContext runs the query in the schema of the tenant of ctx, returning at most one row.
+func (p *tenantPool) QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row {
+	conn, err := p.conn(ctx)
+	if err != nil {
+		return failedRow(ctx, p.db, query, args, err)
+	}
+	defer releaseTenantConn(conn)
+	return conn.QueryRowContext(ctx, query, args...)
+}
+
+// BeginTx starts a transaction in the schema of the tenant of ctx, which its statements keep.
+func (p *tenantPool) BeginTx(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, error) {
+	conn, err := p.conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer releaseTenantConn(conn)
+	return conn.BeginTx(ctx, opts)
+}
+
+// GetDBConn returns the underlying pool, for (*gorm.DB).DB.
+func (p *tenantPool) GetDBConn() (*sql.DB, error) {
+	return p.db, nil
+}
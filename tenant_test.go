@@ -0,0 +1,98 @@
+package gormext
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestTenantContext verifies tenant IDs round-trip through contexts.
+func TestTenantContext(t *testing.T) {
+	_, ok := TenantFromContext(context.Background())
+	assert.False(t, ok)
+
+	tenantID, ok := TenantFromContext(ContextWithTenant(context.Background(), "acme"))
+	assert.True(t, ok)
+	assert.Equal(t, "acme", tenantID)
+}
+
+// TestTenantSwitch verifies the statements switching connections between schemas.
+func TestTenantSwitch(t *testing.T) {
+	postgres, err := NewDatabaseContext("postgres://app@localhost/app", "postgres", "silent")
+	assert.NoError(t, err)
+	switchTo, err := tenantSwitch(*postgres)
+	assert.NoError(t, err)
+	assert.Equal(t, `SET search_path TO "acme""x"`, switchTo(`acme"x`))
+	assert.Equal(t, "RESET search_path", switchTo(""))
+
+	mysql, err := NewDatabaseContext("app@tcp(localhost:3306)/app", "mysql", "silent")
+	assert.NoError(t, err)
+	switchTo, err = tenantSwitch(*mysql)
+	assert.NoError(t, err)
+	assert.Equal(t, "USE `acme``x`", switchTo("acme`x"))
+	assert.Equal(t, "USE `app`", switchTo(""))
+
+	_, err = NewGorm(newTestDatabaseContext(), nil, nil, nil, Config{SchemaPerTenant: true})
+	assert.ErrorContains(t, err, "SchemaPerTenant is not supported on sqlite")
+}
+
+// TestTenantPool verifies that connections are switched to the schema of the context tenant
+// only when they served another one last, including connections held by open rows.
+func TestTenantPool(t *testing.T) {
+	sqlDB, err := sql.Open("sqlite3", filepath.Join(t.TempDir(), "tenants.db"))
+	assert.NoError(t, err)
+	defer sqlDB.Close()
+	sqlDB.SetMaxOpenConns(1)
+
+	// The per-connection cache size of sqlite stands in for the schema.
+	var switches []string
+	schemas := map[string]string{"a": "-100", "b": "-200"}
+	pool := &tenantPool{
+		db:     sqlDB,
+		schema: func(_ context.Context, tenantID string) (string, error) { return schemas[tenantID], nil },
+		switchTo: func(schema string) string {
+			if schema == "" {
+				schema = "-2000"
+			}
+			switches = append(switches, schema)
+			return "PRAGMA cache_size = " + schema
+		},
+	}
+	cacheSize := func(ctx context.Context) int {
+		var size int
+		assert.NoError(t, pool.QueryRowContext(ctx, "PRAGMA cache_size").Scan(&size))
+		return size
+	}
+
+	ctxA := ContextWithTenant(context.Background(), "a")
+	assert.Equal(t, -2000, cacheSize(context.Background()))
+	assert.Equal(t, -100, cacheSize(ctxA))
+	assert.Equal(t, -100, cacheSize(ctxA))
+
+	rows, err := pool.QueryContext(ContextWithTenant(context.Background(), "b"), "PRAGMA cache_size")
+	assert.NoError(t, err)
+	assert.True(t, rows.Next())
+	assert.NoError(t, rows.Close())
+
+	assert.Equal(t, -2000, cacheSize(context.Background()))
+	assert.Equal(t, []string{"-100", "-200", "-2000"}, switches)
+}
+
+// TestWithContextBindsTenant verifies that WithContext keeps the bound tenant for contexts
+// without one and rebinds it otherwise.
+func TestWithContextBindsTenant(t *testing.T) {
+	g, _ := newTestRepository(t)
+	tenantOf := func(repo IRepository) string {
+		tenantID, _ := TenantFromContext(repo.(*gormRepository).db.Statement.Context)
+		return tenantID
+	}
+
+	repo := g.ForTenant("acme").WithContext(context.Background())
+	assert.Equal(t, "acme", tenantOf(repo))
+	assert.Equal(t, "globex", tenantOf(repo.WithContext(ContextWithTenant(context.Background(), "globex"))))
+	assert.Equal(t, "globex", tenantOf(g.GetDB().WithContext(ContextWithTenant(context.Background(), "globex"))))
+	assert.Empty(t, tenantOf(g.GetDB().WithContext(context.Background())))
+}
@@ -0,0 +1,218 @@
+package gormext
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// DefaultTenantCatalogTTL is the time catalog lookups are cached without
+// Config.TenantCatalogTTL.
+const DefaultTenantCatalogTTL = time.Minute
+
+// ErrTenantNotFound is returned by catalog lookups of tenants missing from the catalog.
+var ErrTenantNotFound = errors.New("tenant not found in catalog")
+
+type (
+	// TenantRecord is a row of the gormext_tenants catalog table, mapping a tenant to its
+	// schema or dedicated database.
+	TenantRecord struct {
+		ID            string `gorm:"primaryKey;size:128"`
+		Schema        string `gorm:"size:128"` // Schema or database of the tenant, Config.TenantSchema of the ID when empty.
+		DSN           string // DSN of the dedicated database of the tenant, empty for the default pool.
+		Pending       bool   // Provisioning started and has not succeeded yet, see ProvisionTenant.
+		ProvisionedAt time.Time
+	}
+
+	// TenantChange reports a change of the catalog made by this instance.
+	TenantChange struct {
+		TenantID string
+		Record   *TenantRecord // Record saved, nil when deleted.
+	}
+
+	// TenantCatalog is the tenant registry kept in the gormext_tenants table, whose lookups
+	// are cached for the TTL of Config.TenantCatalogTTL. With Config.TenantCatalog, it
+	// resolves the schemas and dedicated databases of tenants. Changes made by other
+	// instances are seen once the cached lookups expire.
+	TenantCatalog struct {
+		g   *Gorm
+		ttl time.Duration
+
+		mu       sync.Mutex
+		entries  map[string]catalogEntry
+		hooks    []func(TenantChange)
+		migrated bool
+	}
+
+	// catalogEntry is a cached catalog lookup.
+	catalogEntry struct {
+		record  *TenantRecord // Nil for tenants missing from the catalog.
+		expires time.Time
+	}
+
+	// withoutTenant is a context hiding the tenant of its parent, for statements of the
+	// default schema such as the ones of the catalog.
+	withoutTenant struct {
+		context.Context
+	}
+)
+
+// TableName returns the tenant catalog table name.
+func (TenantRecord) TableName() string {
+	return "gormext_tenants"
+}
+
+// Value returns the value of the parent context for key, except its tenant.
+func (c withoutTenant) Value(key any) any {
+	if key == (tenantKey{}) {
+		return nil
+	}
+	return c.Context.Value(key)
+}
+
+// newTenantCatalog returns the catalog of g caching lookups for ttl.
+func newTenantCatalog(g *Gorm, ttl time.Duration) *TenantCatalog {
+	if ttl <= 0 {
+		ttl = DefaultTenantCatalogTTL
+	}
+	return &TenantCatalog{g: g, ttl: ttl, entries: make(map[string]catalogEntry)}
+}
+
+// TenantCatalog returns the tenant catalog.
+func (g *Gorm) TenantCatalog() *TenantCatalog {
+	return g.catalog
+}
+
+// OnChange registers hook to receive the changes made to the catalog by Put and Delete.
+func (c *TenantCatalog) OnChange(hook func(TenantChange)) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.hooks = append(c.hooks, hook)
+}
+
+// Get returns the record of tenantID, from the cache while fresh, or ErrTenantNotFound.
+func (c *TenantCatalog) Get(ctx context.Context, tenantID string) (TenantRecord, error) {
+	c.mu.Lock()
+	entry, ok := c.entries[tenantID]
+	c.mu.Unlock()
+
+	if !ok || time.Now().After(entry.expires) {
+		conn, err := c.conn(ctx)
+		if err != nil {
+			return TenantRecord{}, err
+		}
+
+		var record TenantRecord
+		err = conn.Where("id = ?", tenantID).Take(&record).Error
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			entry = catalogEntry{}
+		case err != nil:
+			return TenantRecord{}, fmt.Errorf("failed to look up tenant '%s': %w", tenantID, err)
+		default:
+			entry = catalogEntry{record: &record}
+		}
+		entry.expires = time.Now().Add(c.ttl)
+
+		c.mu.Lock()
+		c.entries[tenantID] = entry
+		c.mu.Unlock()
+	}
+
+	if entry.record == nil {
+		return TenantRecord{}, fmt.Errorf("%w: '%s'", ErrTenantNotFound, tenantID)
+	}
+	return *entry.record, nil
+}
+
+// List returns the records of every tenant, sorted by ID, except the pending ones, which Get
+// still returns.
+func (c *TenantCatalog) List(ctx context.Context) ([]TenantRecord, error) {
+	conn, err := c.conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var records []TenantRecord
+	if err := conn.Where("pending = ?", false).Order("id").Find(&records).Error; err != nil {
+		return nil, fmt.Errorf("failed to list tenants: %w", err)
+	}
+	return records, nil
+}
+
+// Put creates or replaces the record of a tenant, setting the ProvisionedAt of records not
+// pending when zero.
+func (c *TenantCatalog) Put(ctx context.Context, record TenantRecord) error {
+	if record.ID == "" {
+		return errors.New("failed to save tenant: empty tenant ID")
+	}
+	if !record.Pending && record.ProvisionedAt.IsZero() {
+		record.ProvisionedAt = time.Now().UTC()
+	}
+
+	conn, err := c.conn(ctx)
+	if err != nil {
+		return err
+	}
+	if err := conn.Clauses(clause.OnConflict{UpdateAll: true}).Create(&record).Error; err != nil {
+		return fmt.Errorf("failed to save tenant '%s': %w", record.ID, err)
+	}
+	c.changed(TenantChange{TenantID: record.ID, Record: &record})
+	return nil
+}
+
+// Delete removes the record of tenantID, leaving its schema or database in place.
+func (c *TenantCatalog) Delete(ctx context.Context, tenantID string) error {
+	conn, err := c.conn(ctx)
+	if err != nil {
+		return err
+	}
+	if err := conn.Where("id = ?", tenantID).Delete(&TenantRecord{}).Error; err != nil {
+		return fmt.Errorf("failed to delete tenant '%s': %w", tenantID, err)
+	}
+	c.changed(TenantChange{TenantID: tenantID})
+	return nil
+}
+
+// conn returns the connection of the catalog, in the default schema, creating the catalog
+// table on first use.
+func (c *TenantCatalog) conn(ctx context.Context) (*gorm.DB, error) {
+	conn := c.g.connection.WithContext(withoutTenant{WithMaintenanceBypass(ctx)})
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.migrated {
+		if err := conn.AutoMigrate(&TenantRecord{}); err != nil {
+			return nil, fmt.Errorf("failed to create tenant catalog table: %w", err)
+		}
+		c.migrated = true
+	}
+	return conn, nil
+}
+
+// changed caches the changed record and sends the change to the hooks.
+func (c *TenantCatalog) changed(change TenantChange) {
+	c.mu.Lock()
+	c.entries[change.TenantID] = catalogEntry{record: change.Record, expires: time.Now().Add(c.ttl)}
+	hooks := c.hooks
+	c.mu.Unlock()
+
+	for _, hook := range hooks {
+		hook(change)
+	}
+}
+
+// TenantConnection returns the database of tenantID, for the tenants with a DSN, so that the
+// catalog is a TenantConnectionResolver.
+func (c *TenantCatalog) TenantConnection(ctx context.Context, tenantID string) (*DatabaseContext, error) {
+	record, err := c.Get(ctx, tenantID)
+	if err != nil || record.DSN == "" {
+		return nil, err
+	}
+	return NewDatabaseContext(record.DSN, c.g.databaseCtx.GetDriverAlias(), string(c.g.databaseCtx.loggerLevel))
+}
@@ -0,0 +1,92 @@
+package gormext
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestTenantCatalog verifies the catalog CRUD, the caching of lookups until their TTL and the
+// change notifications.
+func TestTenantCatalog(t *testing.T) {
+	dbCtx, err := NewDatabaseContext(filepath.Join(t.TempDir(), "catalog.db"), "sqlite", "silent")
+	assert.NoError(t, err)
+	g, err := NewGorm(*dbCtx, nil, nil, nil, Config{TenantCatalogTTL: 50 * time.Millisecond})
+	assert.NoError(t, err)
+	catalog := g.TenantCatalog()
+	ctx := context.Background()
+
+	var changes []TenantChange
+	catalog.OnChange(func(change TenantChange) {
+		changes = append(changes, change)
+	})
+
+	_, err = catalog.Get(ctx, "acme")
+	assert.ErrorIs(t, err, ErrTenantNotFound)
+
+	assert.NoError(t, catalog.Put(ctx, TenantRecord{ID: "acme", Schema: "tenant_acme"}))
+	assert.NoError(t, catalog.Put(ctx, TenantRecord{ID: "globex", DSN: "globex.db"}))
+	assert.ErrorContains(t, catalog.Put(ctx, TenantRecord{}), "empty tenant ID")
+
+	record, err := catalog.Get(ctx, "acme")
+	assert.NoError(t, err)
+	assert.Equal(t, "tenant_acme", record.Schema)
+	assert.False(t, record.ProvisionedAt.IsZero())
+
+	records, err := catalog.List(ctx)
+	assert.NoError(t, err)
+	assert.Len(t, records, 2)
+	assert.Equal(t, "globex", records[1].ID)
+
+	// Changes made behind the catalog are seen once the lookup expires.
+	assert.NoError(t, g.GetDB().Exec("UPDATE gormext_tenants SET schema = 'moved' WHERE id = 'acme'"))
+	record, _ = catalog.Get(ctx, "acme")
+	assert.Equal(t, "tenant_acme", record.Schema)
+	time.Sleep(60 * time.Millisecond)
+	record, _ = catalog.Get(ctx, "acme")
+	assert.Equal(t, "moved", record.Schema)
+
+	assert.NoError(t, catalog.Delete(ctx, "globex"))
+	_, err = catalog.Get(ctx, "globex")
+	assert.ErrorIs(t, err, ErrTenantNotFound)
+
+	assert.Len(t, changes, 3)
+	assert.Equal(t, "globex", changes[2].TenantID)
+	assert.Nil(t, changes[2].Record)
+}
+
+// TestTenantCatalogConnections verifies that with Config.TenantCatalog the tenants with a DSN
+// run on their database, the others on the default one, and that unknown tenants fail.
+func TestTenantCatalogConnections(t *testing.T) {
+	dir := t.TempDir()
+	dbCtx, err := NewDatabaseContext(filepath.Join(dir, "shared.db"), "sqlite", "silent")
+	assert.NoError(t, err)
+	g, err := NewGorm(*dbCtx, nil, nil, nil, Config{TenantCatalog: true})
+	assert.NoError(t, err)
+	ctx := context.Background()
+
+	assert.NoError(t, g.TenantCatalog().Put(ctx, TenantRecord{ID: "big", DSN: filepath.Join(dir, "big.db")}))
+	assert.NoError(t, g.TenantCatalog().Put(ctx, TenantRecord{ID: "small"}))
+	assert.NoError(t, g.Migrate(&repoUser{}))
+	assert.NoError(t, g.MigrateAllTenants(&repoUser{}))
+
+	assert.NoError(t, g.ForTenant("small").Create(&repoUser{Name: "shared"}))
+	assert.NoError(t, g.ForTenant("big").Create(&[]repoUser{{Name: "dedicated"}, {Name: "dedicated too"}}))
+
+	count := func(repo IRepository) int64 {
+		var n int64
+		assert.NoError(t, repo.Table("repo_users").Count(&n))
+		return n
+	}
+	assert.Equal(t, int64(1), count(g.GetDB()))
+	assert.Equal(t, int64(2), count(g.ForTenant("big")))
+	assert.ErrorIs(t, g.ForTenant("unknown").Find(&[]repoUser{}), ErrTenantNotFound)
+
+	_, err = NewGorm(*dbCtx, nil, nil, nil, Config{TenantCatalog: true, TenantConnections: g.TenantCatalog()})
+	assert.ErrorContains(t, err, "TenantCatalog is set together with TenantConnections")
+
+	assert.NoError(t, g.Close())
+}
@@ -0,0 +1,209 @@
+package gormext
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sync"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+type (
+	// TenantConnectionResolver maps the tenants having a dedicated database to its
+	// DatabaseContext, see Config.TenantConnections.
+	TenantConnectionResolver interface {
+		// TenantConnection returns the database of tenantID, or nil for the tenants of the
+		// default pool.
+		TenantConnection(ctx context.Context, tenantID string) (*DatabaseContext, error)
+	}
+
+	// TenantConnectionResolverFunc adapts a function to a TenantConnectionResolver.
+	TenantConnectionResolverFunc func(ctx context.Context, tenantID string) (*DatabaseContext, error)
+
+	// tenantRouter is the connection pool of Config.TenantConnections and Config.TenantCatalog:
+	// statements run on the pool of the tenant of their context when it has a dedicated
+	// database, or on the default one. Tenants are resolved once, their pools opened on first
+	// use.
+	tenantRouter struct {
+		gorm.ConnPool
+		databaseCtx DatabaseContext
+		resolver    TenantConnectionResolver
+		catalog     *TenantCatalog // Resolver when it is the catalog, whose lookups expire.
+		profile     *Profile
+
+		mu    sync.Mutex
+		conns sync.Map // tenant ID (DSN with a catalog) -> *gorm.DB of its pool, nil for the default one
+	}
+)
+
+// TenantConnection calls f.
+func (f TenantConnectionResolverFunc) TenantConnection(ctx context.Context, tenantID string) (*DatabaseContext, error) {
+	return f(ctx, tenantID)
+}
+
+// useTenantRouter routes the statements of the tenants with a dedicated database to its pool.
+func (g *Gorm) useTenantRouter(resolver TenantConnectionResolver, profile *Profile) {
+	router := &tenantRouter{ConnPool: g.connection.ConnPool, databaseCtx: g.databaseCtx, resolver: resolver, profile: profile}
+	router.catalog, _ = resolver.(*TenantCatalog)
+	g.connection.ConnPool = router
+	g.connection.Statement.ConnPool = router
+}
+
+// pool returns the pool of the tenant of ctx. With a catalog, tenants are resolved by every
+// statement from the cached catalog lookups, so that catalog changes apply, and pools are
+// shared by DSN.
+func (r *tenantRouter) pool(ctx context.Context) (gorm.ConnPool, error) {
+	tenantID, ok := TenantFromContext(ctx)
+	if !ok {
+		return r.ConnPool, nil
+	}
+
+	key := tenantID
+	if r.catalog != nil {
+		record, err := r.catalog.Get(ctx, tenantID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve connection of tenant '%s': %w", tenantID, err)
+		}
+		if record.DSN == "" {
+			return r.ConnPool, nil
+		}
+		key = record.DSN
+	}
+
+	if conn, ok := r.conns.Load(key); ok {
+		return r.poolOf(conn.(*gorm.DB)), nil
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if conn, ok := r.conns.Load(key); ok {
+		return r.poolOf(conn.(*gorm.DB)), nil
+	}
+
+	conn, err := r.open(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	r.conns.Store(key, conn)
+	return r.poolOf(conn), nil
+}
+
+// poolOf returns the pool of conn, the default one for nil.
+func (r *tenantRouter) poolOf(conn *gorm.DB) gorm.ConnPool {
+	if conn == nil {
+		return r.ConnPool
+	}
+	return conn.ConnPool
+}
+
+// open resolves tenantID and opens its dedicated pool, if any.
+func (r *tenantRouter) open(ctx context.Context, tenantID string) (*gorm.DB, error) {
+	databaseCtx, err := r.resolver.TenantConnection(ctx, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve connection of tenant '%s': %w", tenantID, err)
+	}
+	if databaseCtx == nil {
+		return nil, nil
+	}
+	if databaseCtx.driver != r.databaseCtx.driver {
+		return nil, fmt.Errorf("database of tenant '%s' runs on '%s', not '%s'",
+			tenantID, databaseCtx.GetDriverAlias(), r.databaseCtx.GetDriverAlias())
+	}
+
+	dialector, err := databaseCtx.GetDialector()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get dialector of tenant '%s': %w", tenantID, err)
+	}
+	// The connection only provides the pool: statements run with the callbacks and logger of
+	// the default connection.
+	conn, err := gorm.Open(dialector(), &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database connection of tenant '%s': %w", tenantID, err)
+	}
+	if r.profile != nil {
+		if err := r.profile.apply(conn); err != nil {
+			return nil, err
+		}
+	}
+	return conn, nil
+}
+
+// close closes the dedicated pools of the tenants.
+func (r *tenantRouter) close() error {
+	var errs []error
+	r.conns.Range(func(key, conn any) bool {
+		if conn := conn.(*gorm.DB); conn != nil {
+			if sqlDB, err := conn.DB(); err == nil {
+				errs = append(errs, sqlDB.Close())
+			}
+		}
+		r.conns.Delete(key)
+		return true
+	})
+	return errors.Join(errs...)
+}
+
+// PrepareContext prepares the query on the pool of the tenant of ctx.
+func (r *tenantRouter) PrepareContext(ctx context.Context, query string) (*sql.Stmt, error) {
+	pool, err := r.pool(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return pool.PrepareContext(ctx, query)
+}
+
+// ExecContext executes the query on the pool of the tenant of ctx.
+func (r *tenantRouter) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	pool, err := r.pool(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return pool.ExecContext(ctx, query, args...)
+}
+
+// QueryContext runs the query on the pool of the tenant of ctx.
+func (r *tenantRouter) QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	pool, err := r.pool(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return pool.QueryContext(ctx, query, args...)
+}
+
+// QueryRowContext runs the query on the pool of the tenant of ctx, returning at most one row.
+func (r *tenantRouter) QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row {
+	pool, err := r.pool(ctx)
+	if err != nil {
+		return failedRow(ctx, r.ConnPool, query, args, err)
+	}
+	return pool.QueryRowContext(ctx, query, args...)
+}
+
+// BeginTx starts a transaction on the pool of the tenant of ctx.
+func (r *tenantRouter) BeginTx(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, error) {
+	pool, err := r.pool(ctx)
+	if err != nil {
+		return nil, err
+	}
+	beginner, ok := pool.(gorm.TxBeginner)
+	if !ok {
+		return nil, fmt.Errorf("connection pool %T cannot begin transactions", pool)
+	}
+	return beginner.BeginTx(ctx, opts)
+}
+
+// GetDBConn returns the default pool, for (*gorm.DB).DB.
+func (r *tenantRouter) GetDBConn() (*sql.DB, error) {
+	return (&gorm.DB{Config: &gorm.Config{ConnPool: r.ConnPool}}).DB()
+}
+
+// failedRow returns a row failing with the cancellation of its context, since no row can
+// carry err.
+func failedRow(ctx context.Context, pool gorm.ConnPool, query string, args []any, err error) *sql.Row {
+	canceled, cancel := context.WithCancelCause(ctx)
+	cancel(err)
+	return pool.QueryRowContext(canceled, query, args...)
+}
@@ -0,0 +1,62 @@
+package gormext
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestTenantConnections verifies that tenants with a dedicated database run on its pool while
+// the others share the default one.
+func TestTenantConnections(t *testing.T) {
+	dir := t.TempDir()
+	dbCtx, err := NewDatabaseContext(filepath.Join(dir, "shared.db"), "sqlite", "silent")
+	assert.NoError(t, err)
+	dedicated, err := NewDatabaseContext(filepath.Join(dir, "big.db"), "sqlite", "silent")
+	assert.NoError(t, err)
+	postgres, err := NewDatabaseContext("postgres://app@localhost/app", "postgres", "silent")
+	assert.NoError(t, err)
+
+	var resolved []string
+	resolver := TenantConnectionResolverFunc(func(_ context.Context, tenantID string) (*DatabaseContext, error) {
+		resolved = append(resolved, tenantID)
+		switch tenantID {
+		case "big":
+			return dedicated, nil
+		case "moved":
+			return postgres, nil
+		case "broken":
+			return nil, errors.New("catalog unavailable")
+		}
+		return nil, nil
+	})
+
+	g, err := NewGorm(*dbCtx, nil, nil, nil, Config{TenantConnections: resolver})
+	assert.NoError(t, err)
+	assert.NoError(t, g.Migrate(&repoUser{}))
+	assert.NoError(t, g.ForTenant("big").Exec("CREATE TABLE repo_users (id INTEGER PRIMARY KEY, name TEXT, age INTEGER, active NUMERIC)"))
+
+	assert.NoError(t, g.ForTenant("small").Create(&repoUser{Name: "shared"}))
+	assert.NoError(t, g.ForTenant("big").WithTransaction(func(tx IRepository) error {
+		return tx.Create(&[]repoUser{{Name: "dedicated"}, {Name: "dedicated too"}})
+	}))
+
+	count := func(repo IRepository) int64 {
+		var n int64
+		assert.NoError(t, repo.Table("repo_users").Count(&n))
+		return n
+	}
+	assert.Equal(t, int64(1), count(g.GetDB()))
+	assert.Equal(t, int64(1), count(g.ForTenant("small")))
+	assert.Equal(t, int64(2), count(g.ForTenant("big")))
+	assert.Equal(t, int64(2), count(g.ForTenant("big")))
+	assert.Equal(t, []string{"big", "small"}, resolved, "tenants are resolved once")
+
+	assert.ErrorContains(t, g.ForTenant("moved").Find(&[]repoUser{}), "runs on 'postgres', not 'sqlite'")
+	assert.ErrorContains(t, g.ForTenant("broken").Find(&[]repoUser{}), "catalog unavailable")
+
+	assert.NoError(t, g.Close())
+}
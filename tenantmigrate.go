@@ -0,0 +1,112 @@
+package gormext
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// TenantMigrationError is returned by MigrateAllTenants when tenants failed to migrate, the
+// other tenants being migrated.
+type TenantMigrationError struct {
+	Failed map[string]error // Error of each failed tenant, by tenant ID.
+	Total  int              // Number of tenants migrated or failed.
+}
+
+// Error lists the failed tenants with their error.
+func (e *TenantMigrationError) Error() string {
+	tenants := make([]string, 0, len(e.Failed))
+	for tenantID := range e.Failed {
+		tenants = append(tenants, tenantID)
+	}
+	slices.Sort(tenants)
+
+	failures := make([]string, 0, len(tenants))
+	for _, tenantID := range tenants {
+		failures = append(failures, fmt.Sprintf("'%s': %v", tenantID, e.Failed[tenantID]))
+	}
+	return fmt.Sprintf("failed to migrate %d of %d tenants: %s", len(e.Failed), e.Total, strings.Join(failures, "; "))
+}
+
+// Unwrap returns the errors of the failed tenants.
+func (e *TenantMigrationError) Unwrap() []error {
+	errs := make([]error, 0, len(e.Failed))
+	for _, err := range e.Failed {
+		errs = append(errs, err)
+	}
+	return errs
+}
+
+// RegisterTenant registers tenants migrated by MigrateAllTenants.
+func (g *Gorm) RegisterTenant(tenantIDs ...string) {
+	for _, tenantID := range tenantIDs {
+		g.tenants.Store(tenantID, struct{}{})
+	}
+}
+
+// Tenants returns the registered tenant IDs, sorted.
+func (g *Gorm) Tenants() []string {
+	var tenants []string
+	g.tenants.Range(func(tenantID, _ any) bool {
+		tenants = append(tenants, tenantID.(string))
+		return true
+	})
+	slices.Sort(tenants)
+	return tenants
+}
+
+// tenantIDs returns the registered tenant IDs, and the ones of the catalog with
+// Config.TenantCatalog, sorted.
+func (g *Gorm) tenantIDs(ctx context.Context) ([]string, error) {
+	tenants := g.Tenants()
+	if !g.tenantCatalog {
+		return tenants, nil
+	}
+
+	records, err := g.catalog.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, record := range records {
+		if _, found := slices.BinarySearch(tenants, record.ID); !found {
+			tenants = append(tenants, record.ID)
+		}
+	}
+	slices.Sort(tenants)
+	return tenants, nil
+}
+
+// MigrateAllTenants runs auto-migration for models in the schema or database of every tenant,
+// like Migrate does for the default one, under a single migration lock. The tenants are the
+// registered ones and, with Config.TenantCatalog, the ones of the catalog. A tenant failing
+// does not stop the others: their failures are returned together as a *TenantMigrationError.
+// Each tenant run is reported to the OnRun hooks as a RunTenantMigration event, to follow the
+// progress.
+func (g *Gorm) MigrateAllTenants(models ...any) error {
+	ctx := WithMaintenanceBypass(context.Background())
+	return g.withMigrationLock(ctx, func() error {
+		tenants, err := g.tenantIDs(ctx)
+		if err != nil {
+			return err
+		}
+		failed := make(map[string]error)
+		for _, tenantID := range tenants {
+			if err := g.migrateTenant(ContextWithTenant(ctx, tenantID), tenantID, models); err != nil {
+				failed[tenantID] = err
+			}
+		}
+
+		if len(failed) > 0 {
+			return &TenantMigrationError{Failed: failed, Total: len(tenants)}
+		}
+		return nil
+	})
+}
+
+// migrateTenant runs auto-migration for models with the context of tenantID, reporting the run.
+func (g *Gorm) migrateTenant(ctx context.Context, tenantID string, models []any) (err error) {
+	defer g.reportRun(RunEvent{Kind: RunTenantMigration, Name: tenantID}, time.Now(), &err)
+	return g.migrate(ctx, models)
+}
@@ -0,0 +1,51 @@
+package gormext
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestMigrateAllTenants verifies that every registered tenant is migrated and reported, a
+// failing tenant leaving the others migrated.
+func TestMigrateAllTenants(t *testing.T) {
+	dir := t.TempDir()
+	dbCtx, err := NewDatabaseContext(filepath.Join(dir, "shared.db"), "sqlite", "silent")
+	assert.NoError(t, err)
+	dedicated, err := NewDatabaseContext(filepath.Join(dir, "big.db"), "sqlite", "silent")
+	assert.NoError(t, err)
+	unreachable, err := NewDatabaseContext(filepath.Join(dir, "missing", "gone.db"), "sqlite", "silent")
+	assert.NoError(t, err)
+
+	databases := map[string]*DatabaseContext{"big": dedicated, "gone": unreachable}
+	g, err := NewGorm(*dbCtx, nil, nil, nil, Config{
+		TenantConnections: TenantConnectionResolverFunc(func(_ context.Context, tenantID string) (*DatabaseContext, error) {
+			return databases[tenantID], nil
+		}),
+	})
+	assert.NoError(t, err)
+
+	var events []RunEvent
+	g.OnRun(func(event RunEvent) { events = append(events, event) })
+	g.RegisterTenant("small", "big", "gone")
+	assert.Equal(t, []string{"big", "gone", "small"}, g.Tenants())
+
+	err = g.MigrateAllTenants(&repoUser{})
+	var migrationErr *TenantMigrationError
+	assert.True(t, errors.As(err, &migrationErr))
+	assert.Equal(t, 3, migrationErr.Total)
+	assert.Len(t, migrationErr.Failed, 1)
+	assert.ErrorContains(t, err, "failed to migrate 1 of 3 tenants: 'gone': ")
+
+	assert.NoError(t, g.ForTenant("big").Create(&repoUser{Name: "dedicated"}))
+	assert.NoError(t, g.ForTenant("small").Create(&repoUser{Name: "shared"}))
+
+	assert.Len(t, events, 3)
+	for _, event := range events {
+		assert.Equal(t, RunTenantMigration, event.Kind)
+		assert.Equal(t, event.Name == "gone", event.Err != nil, event.Name)
+	}
+}
@@ -0,0 +1,69 @@
+package gormext
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// ProvisionTenant onboards tenantID in one call: it records the tenant in the tenant catalog
+// as pending unless already there, creates its schema or database with Config.SchemaPerTenant,
+// applies the versioned migrations and auto-migrates models in it, runs the seeds for the
+// tenant as WithSeedTenants does, then marks its record provisioned and registers it for
+// MigrateAllTenants. Pending tenants are left out of List and of the runs over every tenant.
+// Every step is idempotent, so provisioning again completes a tenant whose provisioning failed
+// midway. Tenants recorded beforehand with a DSN are migrated and seeded in their dedicated
+// database, which must exist.
+func (g *Gorm) ProvisionTenant(ctx context.Context, tenantID string, models ...any) error {
+	if tenantID == "" {
+		return errors.New("failed to provision tenant: empty tenant ID")
+	}
+
+	record, err := g.catalog.Get(ctx, tenantID)
+	if errors.Is(err, ErrTenantNotFound) {
+		record = TenantRecord{ID: tenantID, Pending: true}
+		if g.tenantSchema != nil {
+			record.Schema = g.tenantSchema(tenantID)
+		}
+		err = g.catalog.Put(ctx, record)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to provision tenant '%s': %w", tenantID, err)
+	}
+
+	if g.tenantSchema != nil && record.DSN == "" {
+		schema := record.Schema
+		if schema == "" {
+			schema = g.tenantSchema(tenantID)
+		}
+		if err := g.Admin().EnsureSchema(schema); err != nil {
+			return fmt.Errorf("failed to provision tenant '%s': %w", tenantID, err)
+		}
+	}
+
+	tenantCtx := ContextWithTenant(WithMaintenanceBypass(ctx), tenantID)
+	if err := g.MigrateUp(tenantCtx); err != nil {
+		return fmt.Errorf("failed to migrate tenant '%s': %w", tenantID, err)
+	}
+	if len(models) > 0 {
+		err := g.withMigrationLock(tenantCtx, func() error {
+			return g.migrate(tenantCtx, models)
+		})
+		if err != nil {
+			return fmt.Errorf("failed to migrate tenant '%s': %w", tenantID, err)
+		}
+	}
+
+	if err := g.seedContext(ctx, WithSeedTenants(tenantID)); err != nil {
+		return err
+	}
+
+	if record.Pending {
+		record.Pending = false
+		if err := g.catalog.Put(ctx, record); err != nil {
+			return fmt.Errorf("failed to provision tenant '%s': %w", tenantID, err)
+		}
+	}
+	g.RegisterTenant(tenantID)
+	return nil
+}
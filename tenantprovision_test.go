@@ -0,0 +1,61 @@
+package gormext
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestProvisionTenant verifies that provisioning migrates, seeds, records and registers the
+// tenant, and may run again to complete a failed provisioning.
+func TestProvisionTenant(t *testing.T) {
+	dbCtx, err := NewDatabaseContext(filepath.Join(t.TempDir(), "tenants.db"), "sqlite", "silent")
+	assert.NoError(t, err)
+	g, err := NewGorm(*dbCtx, nil, nil, nil, Config{TenantColumn: "tenant_id"})
+	assert.NoError(t, err)
+	dir := t.TempDir()
+	writeSQLFiles(t, dir, map[string]string{
+		"welcome.sql": "INSERT INTO tenant_notes (tenant_id, body) VALUES ({{ literal .TenantID }}, 'welcome');",
+	})
+	g.RegisterSeedFile(filepath.Join(dir, "welcome.sql"))
+
+	ctx := ContextWithTenant(context.Background(), "caller")
+	assert.NoError(t, g.ProvisionTenant(ctx, "acme", &tenantNote{}))
+	assert.NoError(t, g.ProvisionTenant(ctx, "acme", &tenantNote{}))
+	assert.ErrorContains(t, g.ProvisionTenant(ctx, ""), "empty tenant ID")
+
+	var notes []tenantNote
+	assert.NoError(t, g.ForTenant("acme").Find(&notes))
+	assert.Len(t, notes, 1)
+
+	var records []TenantRecord
+	assert.NoError(t, g.GetDB().Find(&records))
+	assert.Len(t, records, 1)
+	assert.Equal(t, "acme", records[0].ID)
+	assert.False(t, records[0].Pending)
+	assert.Equal(t, []string{"acme"}, g.Tenants())
+
+	// Tenants failing to provision stay pending, out of the enumerations, until provisioned.
+	writeSQLFiles(t, dir, map[string]string{"welcome.sql": "INSERT INTO missing_table VALUES (1);"})
+	assert.Error(t, g.ProvisionTenant(ctx, "globex", &tenantNote{}))
+	record, err := g.TenantCatalog().Get(ctx, "globex")
+	assert.NoError(t, err)
+	assert.True(t, record.Pending)
+	assert.True(t, record.ProvisionedAt.IsZero())
+	records, err = g.TenantCatalog().List(ctx)
+	assert.NoError(t, err)
+	assert.Len(t, records, 1)
+	assert.Equal(t, []string{"acme"}, g.Tenants())
+
+	writeSQLFiles(t, dir, map[string]string{
+		"welcome.sql": "INSERT INTO tenant_notes (tenant_id, body) VALUES ({{ literal .TenantID }}, 'welcome');",
+	})
+	assert.NoError(t, g.ProvisionTenant(ctx, "globex", &tenantNote{}))
+	record, err = g.TenantCatalog().Get(ctx, "globex")
+	assert.NoError(t, err)
+	assert.False(t, record.Pending)
+	assert.False(t, record.ProvisionedAt.IsZero())
+	assert.Equal(t, []string{"acme", "globex"}, g.Tenants())
+}
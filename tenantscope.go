@@ -0,0 +1,174 @@
+package gormext
+
+import (
+	"errors"
+	"fmt"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// withoutTenantScopeKey is the statement setting of WithoutTenantScope.
+const withoutTenantScopeKey = "gormext:without_tenant_scope"
+
+// ErrTenantRequired is returned by the statements of tenant-scoped models whose context
+// carries no tenant, see Config.TenantColumn.
+var ErrTenantRequired = errors.New("no tenant in the context of a tenant-scoped statement")
+
+// tenantScope is the plugin of Config.TenantColumn, scoping the statements of the models
+// having the tenant column to the tenant of their context.
+type tenantScope struct {
+	column string
+}
+
+// Name returns the plugin name.
+func (s *tenantScope) Name() string {
+	return "gormext:tenant_scope"
+}
+
+// Initialize registers the callbacks filtering reads, updates and deletes by tenant and
+// stamping the tenant on inserts and updates.
+func (s *tenantScope) Initialize(db *gorm.DB) error {
+	const name = "gormext:tenant_scope"
+	callbacks := db.Callback()
+	return errors.Join(
+		callbacks.Create().Before("gorm:create").Register(name, s.stamp),
+		callbacks.Query().Before("gorm:query").Register(name, s.filter),
+		callbacks.Row().Before("gorm:row").Register(name, s.filter),
+		callbacks.Update().Before("gorm:update").Register(name, s.filterUpdate),
+		callbacks.Delete().Before("gorm:delete").Register(name, s.filterWrite),
+	)
+}
+
+// tenant returns the tenant of a statement of a tenant-scoped model, false when the statement
+// is not scoped.
+func (s *tenantScope) tenant(db *gorm.DB) (string, bool) {
+	stmt := db.Statement
+	if db.Error != nil || stmt.Schema == nil || stmt.Schema.LookUpField(s.column) == nil {
+		return "", false
+	}
+	if without, _ := db.Get(withoutTenantScopeKey); without == true {
+		return "", false
+	}
+
+	tenantID, ok := TenantFromContext(stmt.Context)
+	if !ok {
+		db.AddError(fmt.Errorf("%w: '%s'", ErrTenantRequired, stmt.Table))
+		return "", false
+	}
+	return tenantID, true
+}
+
+// filter adds the tenant condition to the statement.
+func (s *tenantScope) filter(db *gorm.DB) {
+	if tenantID, ok := s.tenant(db); ok {
+		s.where(db.Statement, tenantID)
+	}
+}
+
+// filterWrite adds the tenant condition to an update or delete, which must have conditions of
+// its own unless global updates are allowed: the tenant condition alone would otherwise write
+// every row of the tenant.
+func (s *tenantScope) filterWrite(db *gorm.DB) {
+	if tenantID, ok := s.tenant(db); ok {
+		s.whereWrite(db, tenantID)
+	}
+}
+
+// filterUpdate scopes an update like filterWrite, and stamps the tenant column with the tenant
+// of the context, so that updates cannot move rows to another tenant.
+func (s *tenantScope) filterUpdate(db *gorm.DB) {
+	tenantID, ok := s.tenant(db)
+	if !ok || !s.whereWrite(db, tenantID) {
+		return
+	}
+	db.Statement.SetColumn(db.Statement.Schema.LookUpField(s.column).DBName, tenantID, true)
+}
+
+// whereWrite adds the tenant condition to a write having conditions of its own, false when it
+// has none.
+func (s *tenantScope) whereWrite(db *gorm.DB, tenantID string) bool {
+	stmt := db.Statement
+	if _, ok := stmt.Clauses["WHERE"]; !ok && !db.AllowGlobalUpdate && !hasPrimaryKeys(stmt) {
+		db.AddError(gorm.ErrMissingWhereClause)
+		return false
+	}
+	s.where(stmt, tenantID)
+	return true
+}
+
+// where adds the condition on the tenant column to stmt.
+func (s *tenantScope) where(stmt *gorm.Statement, tenantID string) {
+	field := stmt.Schema.LookUpField(s.column)
+	stmt.AddClause(clause.Where{Exprs: []clause.Expression{
+		clause.Eq{Column: clause.Column{Table: clause.CurrentTable, Name: field.DBName}, Value: tenantID},
+	}})
+}
+
+// stamp sets the tenant column of the created entities to the tenant of the context.
+func (s *tenantScope) stamp(db *gorm.DB) {
+	tenantID, ok := s.tenant(db)
+	if !ok {
+		return
+	}
+
+	stmt := db.Statement
+	field := stmt.Schema.LookUpField(s.column)
+	if !s.scopeConflict(db, field.DBName, tenantID) {
+		return
+	}
+	switch dest := stmt.Dest.(type) {
+	case map[string]any:
+		dest[field.DBName] = tenantID
+	case *map[string]any:
+		(*dest)[field.DBName] = tenantID
+	case []map[string]any:
+		for _, row := range dest {
+			row[field.DBName] = tenantID
+		}
+	default:
+		for _, entity := range auditEntities(stmt) {
+			if err := field.Set(stmt.Context, entity, tenantID); err != nil {
+				db.AddError(fmt.Errorf("failed to set tenant of '%s': %w", stmt.Table, err))
+				return
+			}
+		}
+	}
+}
+
+// scopeConflict limits the updates of an upsert, such as the one of Save on a missing row, to
+// the rows of the tenant, so that conflicts on the rows of other tenants update nothing. MySQL
+// cannot condition ON DUPLICATE KEY UPDATE, so its upserts of scoped models fail.
+func (s *tenantScope) scopeConflict(db *gorm.DB, column, tenantID string) bool {
+	stmt := db.Statement
+	onConflict, ok := stmt.Clauses["ON CONFLICT"].Expression.(clause.OnConflict)
+	if !ok || onConflict.DoNothing {
+		return true
+	}
+	if name := db.Dialector.Name(); name == "mysql" {
+		db.AddError(fmt.Errorf("%w: tenant-scoped upserts of '%s' on '%s'", ErrUnsupportedDriver, stmt.Table, name))
+		return false
+	}
+
+	onConflict.Where.Exprs = append(onConflict.Where.Exprs,
+		clause.Eq{Column: clause.Column{Table: stmt.Table, Name: column}, Value: tenantID})
+	stmt.AddClause(onConflict)
+	return true
+}
+
+// hasPrimaryKeys reports whether the statement writes entities with primary keys, which gorm
+// turns into conditions.
+func hasPrimaryKeys(stmt *gorm.Statement) bool {
+	for _, entity := range auditEntities(stmt) {
+		if _, ok := primaryConditions(stmt, entity); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// WithoutTenantScope makes the calls of the returned repository read and write the rows of
+// every tenant, for administrative jobs, see Config.TenantColumn.
+func (r *gormRepository) WithoutTenantScope() IRepository {
+	return r.with(r.db.Set(withoutTenantScopeKey, true))
+}
@@ -0,0 +1,79 @@
+package gormext
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+// tenantNote is a model of tables shared by tenants.
+type tenantNote struct {
+	ID       uint
+	TenantID string
+	Body     string
+}
+
+// newTenantScopedGorm returns a Gorm scoping tenantNote by tenant_id.
+func newTenantScopedGorm(t *testing.T) *Gorm {
+	dbCtx, err := NewDatabaseContext(filepath.Join(t.TempDir(), "tenants.db"), "sqlite", "silent")
+	assert.NoError(t, err)
+	g, err := NewGorm(*dbCtx, nil, nil, nil, Config{TenantColumn: "tenant_id"})
+	assert.NoError(t, err)
+	assert.NoError(t, g.Migrate(&tenantNote{}))
+	return g
+}
+
+// TestTenantScope verifies that statements of tenant-scoped models see and write the rows of
+// the context tenant only.
+func TestTenantScope(t *testing.T) {
+	g := newTenantScopedGorm(t)
+	acme, globex := g.ForTenant("acme"), g.ForTenant("globex")
+
+	note := tenantNote{TenantID: "globex", Body: "acme note"}
+	assert.NoError(t, acme.Create(&note))
+	assert.Equal(t, "acme", note.TenantID)
+	assert.NoError(t, globex.Create(&[]tenantNote{{Body: "globex note"}, {Body: "other globex note"}}))
+
+	var notes []tenantNote
+	assert.NoError(t, acme.Find(&notes))
+	assert.Len(t, notes, 1)
+	assert.NoError(t, globex.Where("body LIKE ?", "%note").Find(&notes))
+	assert.Len(t, notes, 2)
+
+	// Statements by table name only have no model to scope.
+	var count int64
+	assert.NoError(t, globex.Table("tenant_notes").Count(&count))
+	assert.Equal(t, int64(3), count)
+	assert.ErrorIs(t, globex.FirstByID(note.ID, &tenantNote{}), gorm.ErrRecordNotFound)
+
+	// Writes of the entities of another tenant match no row.
+	foreign := tenantNote{ID: note.ID, Body: "taken over"}
+	assert.NoError(t, globex.Delete(&foreign))
+	assert.NoError(t, acme.FirstByID(note.ID, &tenantNote{}))
+
+	// Saving the entity of another tenant neither updates it nor takes it over through the
+	// upsert Save falls back to.
+	assert.NoError(t, globex.Update(&foreign))
+	var stored tenantNote
+	assert.NoError(t, acme.FirstByID(note.ID, &stored))
+	assert.Equal(t, tenantNote{ID: note.ID, TenantID: "acme", Body: "acme note"}, stored)
+
+	// Updates keep the rows in the tenant of the context.
+	stored.TenantID, stored.Body = "globex", "moved"
+	assert.NoError(t, acme.Update(&stored))
+	assert.NoError(t, acme.FirstByID(note.ID, &stored))
+	assert.Equal(t, tenantNote{ID: note.ID, TenantID: "acme", Body: "moved"}, stored)
+
+	// The tenant condition does not stand in for the conditions of updates and deletes.
+	assert.ErrorIs(t, globex.Delete(&tenantNote{}), gorm.ErrMissingWhereClause)
+
+	assert.ErrorIs(t, g.GetDB().Find(&notes), ErrTenantRequired)
+	assert.NoError(t, g.GetDB().WithoutTenantScope().Find(&notes))
+	assert.Len(t, notes, 3)
+
+	assert.NoError(t, g.GetDB().WithContext(ContextWithTenant(context.Background(), "acme")).Find(&notes))
+	assert.Len(t, notes, 1)
+}
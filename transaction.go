@@ -0,0 +1,447 @@
+package gormext
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/gorm"
+)
+
+const (
+	// defaultTxRetries is the number of retries of RetryTransaction when TxRetry.MaxRetries is zero.
+	defaultTxRetries = 3
+
+	// defaultTxRetryDelay is the first retry delay of RetryTransaction when TxRetry.Delay is zero.
+	defaultTxRetryDelay = 10 * time.Millisecond
+
+	// txHooksKey is the statement setting holding the txHooks of the transaction of a
+	// repository, for callbacks deferring work until it commits.
+	txHooksKey = "gormext:tx_hooks"
+)
+
+var (
+	// ErrIsolationUnsupported is returned when a transaction isolation level is requested on a
+	// driver that lacks it.
+	ErrIsolationUnsupported = errors.New("transaction isolation level is not supported by the database driver")
+
+	// ErrNestedTxOptions is returned when options are given to a transaction nested in another,
+	// which runs in a savepoint of the outer transaction and shares its options.
+	ErrNestedTxOptions = errors.New("transaction options cannot be set on a nested transaction")
+
+	// savepointSeq numbers the savepoints of nested transactions.
+	savepointSeq atomic.Uint64
+)
+
+type (
+	// txKey is the context key of the ambient transaction.
+	txKey struct{}
+
+	// gormTransaction is the ITransaction of the default repository, a transaction or, when
+	// begun inside one, a savepoint.
+	gormTransaction struct {
+		*gormRepository
+		savepoint string
+		parent    *txHooks
+		done      bool
+	}
+
+	// txHooks holds the functions to run once a transaction ended, registered by AfterCommit
+	// and AfterRollback.
+	txHooks struct {
+		mu       sync.Mutex
+		commit   []func()
+		rollback []func()
+	}
+)
+
+// TxRetry configures the retries of RetryTransaction.
+type TxRetry struct {
+	MaxRetries int           // Retries after the first attempt, 3 when zero.
+	Delay      time.Duration // Delay before the first retry, doubled after each, 10ms when zero.
+	MaxDelay   time.Duration // Upper bound of the delay, unbounded when zero.
+}
+
+// TxOptions configures a transaction started by WithTransactionOpts.
+type TxOptions struct {
+	// Isolation is the isolation level, the database default when zero. PostgreSQL and MySQL
+	// support read uncommitted, read committed, repeatable read and serializable. SQLite
+	// transactions are always serializable, so these levels map to its default.
+	Isolation sql.IsolationLevel
+
+	// ReadOnly rejects writes in the transaction. SQLite enforces it with PRAGMA query_only.
+	ReadOnly bool
+
+	// Timeout aborts the transaction once elapsed, releasing its locks, unlimited when zero.
+	// It sets a deadline on the transaction context and, on PostgreSQL, the statement and idle
+	// in transaction timeouts; on MySQL, the SELECT execution and lock wait timeouts.
+	Timeout time.Duration
+}
+
+// WithTransactionOpts executes fn within a transaction started with opts, committing if it
+// returns nil. Inside a transaction, fn runs in a savepoint like with WithTransaction, and opts
+// must be zero.
+func (r *gormRepository) WithTransactionOpts(opts TxOptions, fn func(tx IRepository) error) error {
+	if inTransaction(r.db) {
+		if opts != (TxOptions{}) {
+			return ErrNestedTxOptions
+		}
+		return r.WithTransaction(fn)
+	}
+
+	driver := r.db.Dialector.Name()
+	sqlOpts, err := opts.driverOptions(driver)
+	if err != nil {
+		return err
+	}
+
+	db := r.db
+	if opts.Timeout > 0 {
+		ctx, cancel := context.WithTimeout(db.Statement.Context, opts.Timeout)
+		defer cancel()
+		db = db.WithContext(ctx)
+	}
+
+	set, reset := opts.sessionStatements(driver, mariaDB(r.db))
+	return r.transaction(db, sqlOpts, func(tx *gormRepository) (err error) {
+		for _, statement := range set {
+			if err := internalStatement(tx.db).Exec(statement).Error; err != nil {
+				return fmt.Errorf("failed to configure transaction: %w", err)
+			}
+		}
+		// Reset the connection before it returns to the pool.
+		defer func() {
+			for _, statement := range reset {
+				if resetErr := internalStatement(tx.db).Exec(statement).Error; err == nil && resetErr != nil {
+					err = fmt.Errorf("failed to reset transaction configuration: %w", resetErr)
+				}
+			}
+		}()
+		return fn(tx)
+	})
+}
+
+// sessionStatements returns the statements applying the options the driver does not take at
+// the start of a transaction, and the ones resetting the connection before it ends. MariaDB
+// has no max_execution_time, and bounds statements with max_statement_time in seconds.
+func (o TxOptions) sessionStatements(driver string, mariaDB bool) (set, reset []string) {
+	if o.ReadOnly && driver == "sqlite" {
+		set = append(set, "PRAGMA query_only = ON")
+		reset = append(reset, "PRAGMA query_only = OFF")
+	}
+	if o.Timeout <= 0 {
+		return set, reset
+	}
+
+	milliseconds := max(o.Timeout.Milliseconds(), 1)
+	switch driver {
+	case "postgres":
+		set = append(set,
+			fmt.Sprintf("SET LOCAL statement_timeout = %d", milliseconds),
+			fmt.Sprintf("SET LOCAL idle_in_transaction_session_timeout = %d", milliseconds),
+		)
+	case "mysql":
+		seconds := max(int64(math.Ceil(o.Timeout.Seconds())), 1)
+		if mariaDB {
+			set = append(set, fmt.Sprintf("SET SESSION max_statement_time = %g", float64(milliseconds)/1000))
+			reset = append(reset, "SET SESSION max_statement_time = DEFAULT")
+		} else {
+			set = append(set, fmt.Sprintf("SET SESSION max_execution_time = %d", milliseconds))
+			reset = append(reset, "SET SESSION max_execution_time = DEFAULT")
+		}
+		set = append(set, fmt.Sprintf("SET SESSION innodb_lock_wait_timeout = %d", seconds))
+		reset = append(reset, "SET SESSION innodb_lock_wait_timeout = DEFAULT")
+	}
+	return set, reset
+}
+
+// Begin starts a transaction, for workflows that cannot run in a WithTransaction closure, such
+// as long imports committing checkpoints. Inside a transaction it creates a savepoint instead,
+// released by Commit. Deferring Rollback is safe: it does nothing once the transaction ended.
+func (r *gormRepository) Begin() (ITransaction, error) {
+	if inTransaction(r.db) {
+		name := fmt.Sprintf("gormext_sp%d", savepointSeq.Add(1))
+		if err := internalStatement(r.db).SavePoint(name).Error; err != nil {
+			return nil, fmt.Errorf("failed to create savepoint '%s': %w", name, err)
+		}
+		return &gormTransaction{gormRepository: r.withTx(r.db, &txHooks{}), savepoint: name, parent: r.hooks}, nil
+	}
+
+	tx := r.db.Begin()
+	if tx.Error != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", tx.Error)
+	}
+	if err := r.beginTenant(tx); err != nil {
+		return nil, errors.Join(err, tx.Rollback().Error)
+	}
+	hooks := &txHooks{}
+	return &gormTransaction{gormRepository: &gormRepository{db: hooks.bind(tx), joinTx: r.joinTx, hooks: hooks}}, nil
+}
+
+// Commit commits the transaction, or releases its savepoint.
+func (t *gormTransaction) Commit() error {
+	if t.done {
+		return sql.ErrTxDone
+	}
+	t.done = true
+
+	if t.savepoint != "" {
+		if err := internalStatement(t.db).Exec("RELEASE SAVEPOINT " + t.savepoint).Error; err != nil {
+			t.hooks.run(false)
+			return fmt.Errorf("failed to release savepoint '%s': %w", t.savepoint, err)
+		}
+		t.parent.merge(t.hooks)
+		return nil
+	}
+	if err := t.db.Commit().Error; err != nil {
+		t.hooks.run(false)
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	t.hooks.run(true)
+	return nil
+}
+
+// Rollback rolls back the transaction, or to its savepoint. It does nothing once the
+// transaction ended.
+func (t *gormTransaction) Rollback() error {
+	if t.done {
+		return nil
+	}
+	t.done = true
+
+	defer t.hooks.run(false)
+	if t.savepoint != "" {
+		if err := internalStatement(t.db).RollbackTo(t.savepoint).Error; err != nil {
+			return fmt.Errorf("failed to roll back to savepoint '%s': %w", t.savepoint, err)
+		}
+		return nil
+	}
+	if err := t.db.Rollback().Error; err != nil {
+		return fmt.Errorf("failed to roll back transaction: %w", err)
+	}
+	return nil
+}
+
+// AfterCommit registers fn to run once the transaction started by the repository commits, such
+// as to invalidate caches or emit events. Functions registered in a nested transaction run when
+// the outermost one commits. Outside a transaction, fn runs right away.
+func (r *gormRepository) AfterCommit(fn func()) {
+	if r.hooks == nil {
+		fn()
+		return
+	}
+
+	r.hooks.mu.Lock()
+	defer r.hooks.mu.Unlock()
+	r.hooks.commit = append(r.hooks.commit, fn)
+}
+
+// AfterRollback registers fn to run once the transaction started by the repository rolls back,
+// or a nested transaction rolls back to its savepoint. Outside a transaction, fn never runs.
+func (r *gormRepository) AfterRollback(fn func()) {
+	if r.hooks == nil {
+		return
+	}
+
+	r.hooks.mu.Lock()
+	defer r.hooks.mu.Unlock()
+	r.hooks.rollback = append(r.hooks.rollback, fn)
+}
+
+// run runs the commit or rollback functions, once.
+func (h *txHooks) run(committed bool) {
+	h.mu.Lock()
+	fns := h.rollback
+	if committed {
+		fns = h.commit
+	}
+	h.commit, h.rollback = nil, nil
+	h.mu.Unlock()
+
+	for _, fn := range fns {
+		fn()
+	}
+}
+
+// merge hands the functions of a committed nested transaction to its parent, which runs them
+// when it ends. Without a parent tracked by the repository, they run right away.
+func (h *txHooks) merge(nested *txHooks) {
+	if h == nil {
+		nested.run(true)
+		return
+	}
+
+	nested.mu.Lock()
+	commit, rollback := nested.commit, nested.rollback
+	nested.commit, nested.rollback = nil, nil
+	nested.mu.Unlock()
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.commit = append(h.commit, commit...)
+	h.rollback = append(h.rollback, rollback...)
+}
+
+// ContextWithTx returns a context carrying tx, the repository of a running transaction, joined
+// by the WithContext of repositories created by NewTxRepository.
+func ContextWithTx(ctx context.Context, tx IRepository) context.Context {
+	return context.WithValue(ctx, txKey{}, tx)
+}
+
+// TxFromContext returns the transaction carried by ctx, if any.
+func TxFromContext(ctx context.Context) (IRepository, bool) {
+	if ctx == nil {
+		return nil, false
+	}
+	tx, ok := ctx.Value(txKey{}).(IRepository)
+	return tx, ok
+}
+
+// RetryTransaction runs fn in a transaction of repo, running it again in a new transaction when
+// it fails with a deadlock or serialization failure (see IsRetryableTxError), with exponential
+// backoff and jitter. It stops when ctx is done, returning the last error. Inside a transaction
+// fn runs once, since these failures abort the outer transaction.
+func RetryTransaction(ctx context.Context, repo IRepository, retry TxRetry, fn func(tx IRepository) error) error {
+	if r, ok := repo.(*gormRepository); ok && inTransaction(r.db) {
+		return repo.WithTransaction(fn)
+	}
+
+	if retry.MaxRetries == 0 {
+		retry.MaxRetries = defaultTxRetries
+	}
+	if retry.Delay == 0 {
+		retry.Delay = defaultTxRetryDelay
+	}
+
+	delay := retry.Delay
+	for attempt := 0; ; attempt++ {
+		err := repo.WithTransaction(fn)
+		if err == nil || attempt >= retry.MaxRetries || !IsRetryableTxError(err) {
+			return err
+		}
+
+		// Wait between half and all of the delay, so that conflicting transactions spread out.
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(delay/2 + rand.N(delay/2+1)):
+		}
+		delay *= 2
+		if retry.MaxDelay > 0 && delay > retry.MaxDelay {
+			delay = retry.MaxDelay
+		}
+	}
+}
+
+// IsRetryableTxError reports whether err is a deadlock or serialization failure, after which
+// the transaction may succeed when run again: MySQL error 1213, or PostgreSQL SQLSTATE 40001
+// or 40P01.
+func IsRetryableTxError(err error) bool {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return pgErr.Code == "40001" || pgErr.Code == "40P01"
+	}
+
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) {
+		return mysqlErr.Number == 1213
+	}
+	return false
+}
+
+// transaction runs fn in a transaction of db started with opts or, inside a transaction, in a
+// savepoint rolled back when fn fails, leaving the writes of the outer transaction in place.
+// A panic in fn is left to roll back the outer transaction. The functions fn registers with
+// AfterCommit and AfterRollback run once the transaction ended.
+func (r *gormRepository) transaction(db *gorm.DB, opts *sql.TxOptions, fn func(tx *gormRepository) error) error {
+	hooks := &txHooks{}
+	if !inTransaction(db) {
+		err := db.Transaction(func(tx *gorm.DB) error {
+			if err := r.beginTenant(tx); err != nil {
+				return err
+			}
+			return fn(r.withTx(tx, hooks))
+		}, opts)
+		hooks.run(err == nil)
+		return err
+	}
+
+	name := fmt.Sprintf("gormext_sp%d", savepointSeq.Add(1))
+	if err := internalStatement(db).SavePoint(name).Error; err != nil {
+		return fmt.Errorf("failed to create savepoint '%s': %w", name, err)
+	}
+
+	if err := fn(r.withTx(db, hooks)); err != nil {
+		defer hooks.run(false)
+		if rollbackErr := internalStatement(db).RollbackTo(name).Error; rollbackErr != nil {
+			return errors.Join(err, fmt.Errorf("failed to roll back to savepoint '%s': %w", name, rollbackErr))
+		}
+		return err
+	}
+
+	if err := internalStatement(db).Exec("RELEASE SAVEPOINT " + name).Error; err != nil {
+		hooks.run(false)
+		return fmt.Errorf("failed to release savepoint '%s': %w", name, err)
+	}
+	r.hooks.merge(hooks)
+	return nil
+}
+
+// withTx returns a copy of the repository running in the transaction db, whose hooks are
+// tracked by hooks.
+func (r *gormRepository) withTx(db *gorm.DB, hooks *txHooks) *gormRepository {
+	clone := *r
+	clone.db = hooks.bind(db)
+	clone.hooks = hooks
+	return &clone
+}
+
+// bind returns a session of db whose callbacks find the hooks with afterCommit.
+func (h *txHooks) bind(db *gorm.DB) *gorm.DB {
+	return db.Set(txHooksKey, h).Session(&gorm.Session{})
+}
+
+// afterCommit runs fn once the transaction of db commits, right away outside transactions
+// started by repositories.
+func afterCommit(db *gorm.DB, fn func()) {
+	hooks, ok := db.Get(txHooksKey)
+	if !ok || !inTransaction(db) {
+		fn()
+		return
+	}
+
+	h := hooks.(*txHooks)
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.commit = append(h.commit, fn)
+}
+
+// inTransaction reports whether db runs in a transaction.
+func inTransaction(db *gorm.DB) bool {
+	committer, ok := db.Statement.ConnPool.(gorm.TxCommitter)
+	return ok && committer != nil
+}
+
+// driverOptions maps the options to the capabilities of driver.
+func (o TxOptions) driverOptions(driver string) (*sql.TxOptions, error) {
+	switch o.Isolation {
+	case sql.LevelDefault, sql.LevelReadUncommitted, sql.LevelReadCommitted, sql.LevelRepeatableRead, sql.LevelSerializable:
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrIsolationUnsupported, o.Isolation)
+	}
+
+	if driver == "sqlite" {
+		// The SQLite driver ignores transaction options: transactions are serializable, and
+		// read-only transactions are enforced by WithTransactionOpts.
+		return &sql.TxOptions{}, nil
+	}
+	return &sql.TxOptions{Isolation: o.Isolation, ReadOnly: o.ReadOnly}, nil
+}
@@ -0,0 +1,296 @@
+package gormext
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestWithTransactionOpts verifies that isolation levels and read-only transactions are mapped
+// to the driver.
+func TestWithTransactionOpts(t *testing.T) {
+	g, repo := newTestRepository(t)
+
+	err := repo.WithTransactionOpts(TxOptions{ReadOnly: true}, func(tx IRepository) error {
+		var count int64
+		assert.NoError(t, tx.Table("repo_users").Count(&count))
+		return tx.Create(&repoUser{Name: "ana"})
+	})
+	assert.ErrorContains(t, err, "attempt to write a readonly database")
+
+	err = repo.WithTransactionOpts(TxOptions{Isolation: sql.LevelSerializable}, func(tx IRepository) error {
+		return tx.Create(&repoUser{Name: "ana"})
+	})
+	assert.NoError(t, err, "Read-only mode should end with its transaction")
+
+	var count int64
+	assert.NoError(t, g.connection.Model(&repoUser{}).Count(&count).Error)
+	assert.Equal(t, int64(1), count)
+
+	err = repo.WithTransactionOpts(TxOptions{Isolation: sql.LevelSnapshot}, func(IRepository) error { return nil })
+	assert.ErrorIs(t, err, ErrIsolationUnsupported)
+
+	opts, err := TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true}.driverOptions("postgres")
+	assert.NoError(t, err)
+	assert.Equal(t, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true}, opts)
+}
+
+// TestNestedTransaction verifies that nested transactions run in savepoints, rolling back only
+// their own writes when they fail.
+func TestNestedTransaction(t *testing.T) {
+	g, repo := newTestRepository(t)
+	errInner := errors.New("inner failed")
+
+	err := repo.WithTransaction(func(tx IRepository) error {
+		assert.NoError(t, tx.Create(&repoUser{Name: "outer"}))
+		assert.ErrorIs(t, tx.WithTransaction(func(inner IRepository) error {
+			assert.NoError(t, inner.Create(&repoUser{Name: "rolled back"}))
+			return errInner
+		}), errInner)
+		assert.NoError(t, tx.WithTransaction(func(inner IRepository) error {
+			return inner.WithTransaction(func(innermost IRepository) error {
+				return innermost.Create(&repoUser{Name: "nested"})
+			})
+		}))
+		assert.ErrorIs(t, tx.WithTransactionOpts(TxOptions{ReadOnly: true}, func(IRepository) error { return nil }), ErrNestedTxOptions)
+		return nil
+	})
+	assert.NoError(t, err)
+
+	var names []string
+	assert.NoError(t, g.connection.Model(&repoUser{}).Order("id").Pluck("name", &names).Error)
+	assert.Equal(t, []string{"outer", "nested"}, names)
+
+	err = repo.WithTransaction(func(tx IRepository) error {
+		assert.NoError(t, tx.Create(&repoUser{Name: "discarded"}))
+		return tx.WithTransaction(func(IRepository) error { return errInner })
+	})
+	assert.ErrorIs(t, err, errInner)
+	var count int64
+	assert.NoError(t, g.connection.Model(&repoUser{}).Count(&count).Error)
+	assert.Equal(t, int64(2), count, "An inner error returned by the outer transaction should roll it back")
+}
+
+// TestRetryTransaction verifies that deadlocks and serialization failures are retried until
+// the transaction succeeds or the retries are exhausted.
+func TestRetryTransaction(t *testing.T) {
+	g, repo := newTestRepository(t)
+	ctx := context.Background()
+	retry := TxRetry{MaxRetries: 2, Delay: time.Millisecond}
+
+	attempts := 0
+	err := RetryTransaction(ctx, repo, retry, func(tx IRepository) error {
+		attempts++
+		if err := tx.Create(&repoUser{Name: fmt.Sprintf("attempt %d", attempts)}); err != nil {
+			return err
+		}
+		if attempts < 3 {
+			return &pgconn.PgError{Code: "40001"}
+		}
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 3, attempts)
+	var names []string
+	assert.NoError(t, g.connection.Model(&repoUser{}).Pluck("name", &names).Error)
+	assert.Equal(t, []string{"attempt 3"}, names, "Failed attempts should be rolled back")
+
+	attempts = 0
+	err = RetryTransaction(ctx, repo, retry, func(IRepository) error {
+		attempts++
+		return &mysql.MySQLError{Number: 1213}
+	})
+	assert.True(t, IsRetryableTxError(err))
+	assert.Equal(t, 3, attempts, "Retries should stop at MaxRetries")
+
+	attempts = 0
+	errOther := errors.New("other")
+	assert.ErrorIs(t, RetryTransaction(ctx, repo, retry, func(IRepository) error {
+		attempts++
+		return errOther
+	}), errOther)
+	assert.Equal(t, 1, attempts, "Other errors should not be retried")
+	assert.False(t, IsRetryableTxError(&pgconn.PgError{Code: "23505"}))
+}
+
+// TestContextTransaction verifies that tx-aware repositories join the transaction carried by
+// the context, and that other repositories ignore it.
+func TestContextTransaction(t *testing.T) {
+	g, err := NewGorm(newTestDatabaseContext(), NewTxRepository, nil, nil)
+	assert.NoError(t, err)
+	assert.NoError(t, g.Migrate(&repoUser{}))
+	repo := g.GetDB()
+
+	createUser := func(ctx context.Context, name string) error {
+		return repo.WithContext(ctx).Create(&repoUser{Name: name})
+	}
+	errAbort := errors.New("abort")
+
+	err = repo.WithTransaction(func(tx IRepository) error {
+		ctx := ContextWithTx(context.Background(), tx)
+		found, ok := TxFromContext(ctx)
+		assert.True(t, ok)
+		assert.Equal(t, tx, found)
+
+		assert.NoError(t, createUser(ctx, "ana"))
+		assert.NoError(t, createUser(ctx, "bia"))
+		return errAbort
+	})
+	assert.ErrorIs(t, err, errAbort)
+
+	var count int64
+	assert.NoError(t, g.connection.Model(&repoUser{}).Count(&count).Error)
+	assert.Zero(t, count, "Repository calls should have joined the rolled back transaction")
+
+	_, ok := TxFromContext(context.Background())
+	assert.False(t, ok)
+
+	assert.NoError(t, repo.WithTransaction(func(tx IRepository) error {
+		plain := NewRepository(g.connection).WithContext(ContextWithTx(context.Background(), tx))
+		assert.False(t, inTransaction(plain.(*gormRepository).db), "The default repository should not join context transactions")
+		return nil
+	}))
+}
+
+// TestBegin verifies that manual transactions commit and roll back, nest as savepoints and
+// tolerate a deferred Rollback.
+func TestBegin(t *testing.T) {
+	g, repo := newTestRepository(t)
+	count := func() int64 {
+		var n int64
+		assert.NoError(t, g.connection.Model(&repoUser{}).Count(&n).Error)
+		return n
+	}
+
+	tx, err := repo.Begin()
+	assert.NoError(t, err)
+	assert.NoError(t, tx.Create(&repoUser{Name: "checkpoint 1"}))
+
+	inner, err := tx.Begin()
+	assert.NoError(t, err)
+	assert.NoError(t, inner.Create(&repoUser{Name: "discarded"}))
+	assert.NoError(t, inner.Rollback())
+
+	inner, err = tx.Begin()
+	assert.NoError(t, err)
+	assert.NoError(t, inner.Create(&repoUser{Name: "checkpoint 2"}))
+	assert.NoError(t, inner.Commit())
+
+	assert.NoError(t, tx.Commit())
+	assert.NoError(t, tx.Rollback(), "Rollback should do nothing once committed")
+	assert.ErrorIs(t, tx.Commit(), sql.ErrTxDone)
+
+	var names []string
+	assert.NoError(t, g.connection.Model(&repoUser{}).Order("id").Pluck("name", &names).Error)
+	assert.Equal(t, []string{"checkpoint 1", "checkpoint 2"}, names)
+
+	tx, err = repo.Begin()
+	assert.NoError(t, err)
+	assert.NoError(t, tx.Create(&repoUser{Name: "rolled back"}))
+	assert.NoError(t, tx.Rollback())
+	assert.Equal(t, int64(2), count())
+}
+
+// TestTransactionTimeout verifies that transactions are aborted once their timeout elapsed, and
+// that driver timeouts are set for the transaction only.
+func TestTransactionTimeout(t *testing.T) {
+	// A file database, since the connection of an aborted transaction may be discarded.
+	dbCtx, err := NewDatabaseContext(filepath.Join(t.TempDir(), "timeout.db"), "sqlite", "silent")
+	assert.NoError(t, err)
+	g, err := NewGorm(*dbCtx, nil, nil, nil)
+	assert.NoError(t, err)
+	assert.NoError(t, g.Migrate(&repoUser{}))
+	repo := g.GetDB()
+
+	err = repo.WithTransactionOpts(TxOptions{Timeout: 20 * time.Millisecond}, func(tx IRepository) error {
+		if err := tx.Create(&repoUser{Name: "runaway"}); err != nil {
+			return err
+		}
+		time.Sleep(50 * time.Millisecond)
+		return tx.Create(&repoUser{Name: "late"})
+	})
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	var count int64
+	assert.NoError(t, g.connection.Model(&repoUser{}).Count(&count).Error)
+	assert.Zero(t, count, "A timed out transaction should be rolled back")
+
+	set, reset := TxOptions{Timeout: 1500 * time.Millisecond}.sessionStatements("postgres", false)
+	assert.Equal(t, []string{
+		"SET LOCAL statement_timeout = 1500",
+		"SET LOCAL idle_in_transaction_session_timeout = 1500",
+	}, set)
+	assert.Empty(t, reset, "SET LOCAL ends with the transaction")
+
+	set, reset = TxOptions{Timeout: 1500 * time.Millisecond}.sessionStatements("mysql", false)
+	assert.Equal(t, []string{
+		"SET SESSION max_execution_time = 1500",
+		"SET SESSION innodb_lock_wait_timeout = 2",
+	}, set)
+	assert.Equal(t, []string{
+		"SET SESSION max_execution_time = DEFAULT",
+		"SET SESSION innodb_lock_wait_timeout = DEFAULT",
+	}, reset)
+
+	set, reset = TxOptions{Timeout: 1500 * time.Millisecond}.sessionStatements("mysql", true)
+	assert.Equal(t, []string{
+		"SET SESSION max_statement_time = 1.5",
+		"SET SESSION innodb_lock_wait_timeout = 2",
+	}, set)
+	assert.Equal(t, []string{
+		"SET SESSION max_statement_time = DEFAULT",
+		"SET SESSION innodb_lock_wait_timeout = DEFAULT",
+	}, reset)
+}
+
+// TestTransactionHooks verifies that after-commit and after-rollback functions run once the
+// outermost transaction ended, and only for the outcome they were registered for.
+func TestTransactionHooks(t *testing.T) {
+	_, repo := newTestRepository(t)
+	var events []string
+	record := func(event string) func() {
+		return func() { events = append(events, event) }
+	}
+
+	assert.NoError(t, repo.WithTransaction(func(tx IRepository) error {
+		tx.AfterCommit(record("outer commit"))
+		tx.AfterRollback(record("outer rollback"))
+		assert.NoError(t, tx.WithTransaction(func(inner IRepository) error {
+			inner.AfterCommit(record("inner commit"))
+			return nil
+		}))
+		assert.Error(t, tx.WithTransaction(func(inner IRepository) error {
+			inner.AfterCommit(record("discarded commit"))
+			inner.AfterRollback(record("savepoint rollback"))
+			return errors.New("inner failed")
+		}))
+		assert.Equal(t, []string{"savepoint rollback"}, events, "Commit hooks should wait for the outer commit")
+		return nil
+	}))
+	assert.Equal(t, []string{"savepoint rollback", "outer commit", "inner commit"}, events)
+
+	events = nil
+	assert.Error(t, repo.WithTransaction(func(tx IRepository) error {
+		tx.Where("name = ?", "ana").AfterCommit(record("commit"))
+		tx.AfterRollback(record("rollback"))
+		return errors.New("failed")
+	}))
+	assert.Equal(t, []string{"rollback"}, events)
+
+	events = nil
+	tx, err := repo.Begin()
+	assert.NoError(t, err)
+	tx.AfterCommit(record("manual commit"))
+	assert.Empty(t, events)
+	assert.NoError(t, tx.Commit())
+	repo.AfterCommit(record("no transaction"))
+	repo.AfterRollback(record("never"))
+	assert.Equal(t, []string{"manual commit", "no transaction"}, events)
+}
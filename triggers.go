@@ -0,0 +1,180 @@
+package gormext
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// TriggerSpec describes a row-level trigger managed by EnsureTrigger.
+//
+// Body is the trigger program in the dialect of the database: the statements between
+// BEGIN and END on SQLite, the trigger statement (usually a BEGIN ... END block) on MySQL,
+// and the PL/pgSQL function body (including BEGIN ... END) on Postgres.
+type TriggerSpec struct {
+	Name   string // Trigger name, defaults to "trg_<table>_<timing>_<event>".
+	Table  string // Table the trigger is attached to.
+	Timing string // BEFORE, AFTER or INSTEAD OF.
+	Event  string // INSERT, UPDATE or DELETE.
+	Body   string // Trigger program.
+}
+
+var (
+	// triggerTimings lists the supported trigger timings.
+	triggerTimings = map[string]bool{"BEFORE": true, "AFTER": true, "INSTEAD OF": true}
+
+	// triggerEvents lists the supported trigger events.
+	triggerEvents = map[string]bool{"INSERT": true, "UPDATE": true, "DELETE": true}
+)
+
+// EnsureTrigger creates the trigger described by spec, or replaces it when the existing
+// definition differs. It does nothing when the trigger is already up to date.
+func (g *Gorm) EnsureTrigger(spec TriggerSpec) error {
+	spec, err := normalizeTriggerSpec(spec)
+	if err != nil {
+		return err
+	}
+
+	ensure := func(tx *gorm.DB) error {
+		current, err := g.currentTrigger(tx, spec)
+		if err != nil {
+			return fmt.Errorf("failed to inspect trigger '%s': %w", spec.Name, err)
+		}
+		if current == g.triggerFingerprint(spec) {
+			return nil
+		}
+
+		for _, stmt := range g.triggerDDL(tx, spec) {
+			if err := tx.Exec(stmt).Error; err != nil {
+				return fmt.Errorf("failed to create trigger '%s': %w", spec.Name, err)
+			}
+		}
+		return nil
+	}
+
+	// MySQL DDL commits implicitly, so only wrap drivers with transactional DDL.
+	if g.databaseCtx.driver == MySQL {
+		return ensure(g.connection)
+	}
+	return g.connection.Transaction(ensure)
+}
+
+// normalizeTriggerSpec validates spec and fills in defaults.
+func normalizeTriggerSpec(spec TriggerSpec) (TriggerSpec, error) {
+	spec.Timing = strings.ToUpper(strings.Join(strings.Fields(spec.Timing), " "))
+	spec.Event = strings.ToUpper(strings.TrimSpace(spec.Event))
+	spec.Body = strings.TrimSpace(spec.Body)
+
+	if !routineNamePattern.MatchString(spec.Table) {
+		return spec, fmt.Errorf("invalid trigger table '%s'", spec.Table)
+	}
+	if !triggerTimings[spec.Timing] {
+		return spec, fmt.Errorf("invalid trigger timing '%s'", spec.Timing)
+	}
+	if !triggerEvents[spec.Event] {
+		return spec, fmt.Errorf("invalid trigger event '%s'", spec.Event)
+	}
+	if spec.Body == "" {
+		return spec, errors.New("trigger body is required")
+	}
+
+	if spec.Name == "" {
+		table := spec.Table[strings.LastIndex(spec.Table, ".")+1:]
+		spec.Name = strings.ToLower(fmt.Sprintf("trg_%s_%s_%s", table, strings.ReplaceAll(spec.Timing, " ", "_"), spec.Event))
+	}
+	if !routineNamePattern.MatchString(spec.Name) || strings.Contains(spec.Name, ".") {
+		return spec, fmt.Errorf("invalid trigger name '%s'", spec.Name)
+	}
+	return spec, nil
+}
+
+// triggerFingerprint returns the comparable form of spec as reported by currentTrigger.
+func (g *Gorm) triggerFingerprint(spec TriggerSpec) string {
+	if g.databaseCtx.driver == SQLite {
+		return g.triggerDDL(g.connection, spec)[1]
+	}
+	return strings.Join([]string{spec.Timing, spec.Event, spec.Table, spec.Body}, "|")
+}
+
+// currentTrigger returns the fingerprint of the existing trigger, or "" when it does not exist.
+func (g *Gorm) currentTrigger(tx *gorm.DB, spec TriggerSpec) (string, error) {
+	var rows []struct {
+		Timing string
+		Event  string
+		Table  string
+		Body   string
+	}
+
+	switch g.databaseCtx.driver {
+	case SQLite:
+		var definitions []string
+		err := tx.Raw("SELECT sql FROM sqlite_master WHERE type = 'trigger' AND name = ?", spec.Name).Scan(&definitions).Error
+		if err != nil || len(definitions) == 0 {
+			return "", err
+		}
+		return definitions[0], nil
+	case MySQL:
+		err := tx.Raw(`SELECT ACTION_TIMING AS timing, EVENT_MANIPULATION AS event, EVENT_OBJECT_TABLE AS `+"`table`"+`, ACTION_STATEMENT AS body
+			FROM information_schema.TRIGGERS WHERE TRIGGER_SCHEMA = DATABASE() AND TRIGGER_NAME = ?`, spec.Name).Scan(&rows).Error
+		if err != nil {
+			return "", err
+		}
+	case PostgreSQL:
+		// Trigger names are unique per table only: match the table and its schema as well.
+		schema, table, qualified := strings.Cut(spec.Table, ".")
+		if !qualified {
+			schema, table = "", spec.Table
+		}
+		err := tx.Raw(`SELECT t.action_timing AS timing, t.event_manipulation AS event, t.event_object_table AS "table", p.prosrc AS body
+			FROM information_schema.triggers t JOIN pg_proc p ON p.proname = ?
+			WHERE t.trigger_name = ? AND t.event_object_schema = COALESCE(NULLIF(?, ''), current_schema())
+			AND t.event_object_table = ?`, triggerFunction(spec), spec.Name, schema, table).Scan(&rows).Error
+		if err != nil {
+			return "", err
+		}
+	default:
+		return "", fmt.Errorf("%w: triggers on '%s'", ErrUnsupportedDriver, g.databaseCtx.GetDriverAlias())
+	}
+
+	if len(rows) == 0 {
+		return "", nil
+	}
+	row := rows[0]
+	table := spec.Table[strings.LastIndex(spec.Table, ".")+1:]
+	if row.Table == table {
+		row.Table = spec.Table
+	}
+	return strings.Join([]string{row.Timing, row.Event, row.Table, strings.TrimSpace(row.Body)}, "|"), nil
+}
+
+// triggerDDL returns the statements dropping and recreating the trigger.
+func (g *Gorm) triggerDDL(tx *gorm.DB, spec TriggerSpec) []string {
+	name, table := tx.Statement.Quote(spec.Name), tx.Statement.Quote(spec.Table)
+	drop := fmt.Sprintf("DROP TRIGGER IF EXISTS %s", name)
+	head := fmt.Sprintf("CREATE TRIGGER %s %s %s ON %s FOR EACH ROW", name, spec.Timing, spec.Event, table)
+
+	switch g.databaseCtx.driver {
+	case PostgreSQL:
+		function := tx.Statement.Quote(triggerFunction(spec))
+		return []string{
+			fmt.Sprintf("CREATE OR REPLACE FUNCTION %s() RETURNS trigger LANGUAGE plpgsql AS $gormext$%s$gormext$", function, spec.Body),
+			fmt.Sprintf("%s ON %s", drop, table),
+			fmt.Sprintf("%s EXECUTE FUNCTION %s()", head, function),
+		}
+	case SQLite:
+		body := spec.Body
+		if !strings.HasSuffix(body, ";") {
+			body += ";"
+		}
+		return []string{drop, fmt.Sprintf("%s BEGIN %s END", head, body)}
+	default:
+		return []string{drop, fmt.Sprintf("%s %s", head, spec.Body)}
+	}
+}
+
+// triggerFunction returns the name of the Postgres function backing the trigger.
+func triggerFunction(spec TriggerSpec) string {
+	return spec.Name + "_fn"
+}
@@ -0,0 +1,42 @@
+package gormext
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestEnsureTriggerIdempotent verifies triggers are created, kept when unchanged and replaced when changed.
+func TestEnsureTriggerIdempotent(t *testing.T) {
+	g, repo := newTestRepository(t)
+
+	spec := TriggerSpec{
+		Table:  "repo_users",
+		Timing: "before",
+		Event:  "insert",
+		Body:   "SELECT RAISE(ABORT, 'too young') WHERE NEW.age < 18",
+	}
+	assert.NoError(t, g.EnsureTrigger(spec), "Failed to create trigger")
+	assert.NoError(t, g.EnsureTrigger(spec), "Ensuring an unchanged trigger should succeed")
+
+	var count int64
+	assert.NoError(t, g.connection.Raw("SELECT count(*) FROM sqlite_master WHERE type = 'trigger' AND name = 'trg_repo_users_before_insert'").Scan(&count).Error)
+	assert.Equal(t, int64(1), count)
+
+	err := repo.Create(&repoUser{Name: "kid", Age: 10})
+	assert.ErrorContains(t, err, "too young")
+
+	spec.Body = "SELECT RAISE(ABORT, 'too old') WHERE NEW.age > 99"
+	assert.NoError(t, g.EnsureTrigger(spec), "Failed to replace trigger")
+	assert.NoError(t, repo.Create(&repoUser{Name: "kid", Age: 10}), "Replaced trigger should no longer reject")
+	assert.ErrorContains(t, repo.Create(&repoUser{Name: "elder", Age: 120}), "too old")
+}
+
+// TestEnsureTriggerValidation verifies invalid specs are rejected before touching the database.
+func TestEnsureTriggerValidation(t *testing.T) {
+	g, _ := newTestRepository(t)
+
+	assert.ErrorContains(t, g.EnsureTrigger(TriggerSpec{Table: "users", Timing: "DURING", Event: "INSERT", Body: "x"}), "invalid trigger timing")
+	assert.ErrorContains(t, g.EnsureTrigger(TriggerSpec{Table: "users", Timing: "AFTER", Event: "TRUNCATE", Body: "x"}), "invalid trigger event")
+	assert.ErrorContains(t, g.EnsureTrigger(TriggerSpec{Table: "users; drop", Timing: "AFTER", Event: "INSERT", Body: "x"}), "invalid trigger table")
+}
@@ -0,0 +1,84 @@
+package gormext
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// ErrRepositoryNotRegistered is returned when a unit of work is asked for a repository type
+// that was not registered.
+var ErrRepositoryNotRegistered = errors.New("repository not registered in the unit of work")
+
+type (
+	// UnitOfWork runs transactions across several application repositories, such as users,
+	// orders and payments: each Do opens one transaction and hands out instances of the
+	// registered repositories bound to it, committed or rolled back together.
+	UnitOfWork struct {
+		repo      IRepository
+		factories map[reflect.Type]func(tx IRepository) any
+	}
+
+	// Work is a running unit of work, handing out repositories bound to its transaction.
+	Work struct {
+		ctx       context.Context
+		tx        IRepository
+		factories map[reflect.Type]func(tx IRepository) any
+		instances map[reflect.Type]any
+	}
+)
+
+// NewUnitOfWork returns a unit of work running its transactions on repo.
+func NewUnitOfWork(repo IRepository) *UnitOfWork {
+	return &UnitOfWork{repo: repo, factories: make(map[reflect.Type]func(tx IRepository) any)}
+}
+
+// RegisterRepository registers factory, building a repository of type T, such as *UserRepo,
+// on top of the transaction of a unit of work. Register repositories before running units.
+func RegisterRepository[T any](u *UnitOfWork, factory func(tx IRepository) T) {
+	u.factories[reflect.TypeFor[T]()] = func(tx IRepository) any { return factory(tx) }
+}
+
+// Do runs fn in a transaction, committed if fn returns nil and rolled back otherwise, with all
+// the repositories fn obtains from w.
+func (u *UnitOfWork) Do(ctx context.Context, fn func(w *Work) error) error {
+	return u.repo.WithContext(ctx).WithTransaction(func(tx IRepository) error {
+		return fn(&Work{
+			ctx:       ContextWithTx(ctx, tx),
+			tx:        tx,
+			factories: u.factories,
+			instances: make(map[reflect.Type]any),
+		})
+	})
+}
+
+// Context returns the context of the unit, carrying its transaction for repositories created
+// by NewTxRepository.
+func (w *Work) Context() context.Context {
+	return w.ctx
+}
+
+// Tx returns the transaction of the unit.
+func (w *Work) Tx() IRepository {
+	return w.tx
+}
+
+// RepositoryOf returns the repository of type T bound to the transaction of w, built once per
+// unit by its registered factory.
+func RepositoryOf[T any](w *Work) (T, error) {
+	repoType := reflect.TypeFor[T]()
+	if instance, ok := w.instances[repoType]; ok {
+		return instance.(T), nil
+	}
+
+	factory, ok := w.factories[repoType]
+	if !ok {
+		var zero T
+		return zero, fmt.Errorf("%w: %s", ErrRepositoryNotRegistered, repoType)
+	}
+
+	instance := factory(w.tx)
+	w.instances[repoType] = instance
+	return instance.(T), nil
+}
@@ -0,0 +1,68 @@
+package gormext
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type (
+	uowUsers  struct{ db IRepository }
+	uowOrders struct{ db IRepository }
+)
+
+// TestUnitOfWork verifies that the repositories of a unit share its transaction, committed or
+// rolled back together.
+func TestUnitOfWork(t *testing.T) {
+	g, repo := newTestRepository(t)
+	uow := NewUnitOfWork(repo)
+	RegisterRepository(uow, func(tx IRepository) *uowUsers { return &uowUsers{db: tx} })
+	RegisterRepository(uow, func(tx IRepository) *uowOrders { return &uowOrders{db: tx} })
+	ctx := context.Background()
+
+	write := func(w *Work) error {
+		users, err := RepositoryOf[*uowUsers](w)
+		if err != nil {
+			return err
+		}
+		orders, err := RepositoryOf[*uowOrders](w)
+		if err != nil {
+			return err
+		}
+		again, _ := RepositoryOf[*uowUsers](w)
+		assert.Same(t, users, again, "Repositories should be built once per unit")
+		assert.Equal(t, w.Tx(), orders.db)
+
+		if err := users.db.Create(&repoUser{Name: "user"}); err != nil {
+			return err
+		}
+		return orders.db.Create(&repoUser{Name: "order"})
+	}
+
+	errPayment := errors.New("payment declined")
+	err := uow.Do(ctx, func(w *Work) error {
+		if err := write(w); err != nil {
+			return err
+		}
+		return errPayment
+	})
+	assert.ErrorIs(t, err, errPayment)
+
+	var count int64
+	assert.NoError(t, g.connection.Model(&repoUser{}).Count(&count).Error)
+	assert.Zero(t, count, "A failed unit should roll back every repository")
+
+	assert.NoError(t, uow.Do(ctx, write))
+	assert.NoError(t, g.connection.Model(&repoUser{}).Count(&count).Error)
+	assert.Equal(t, int64(2), count)
+
+	err = uow.Do(ctx, func(w *Work) error {
+		_, ok := TxFromContext(w.Context())
+		assert.True(t, ok)
+		_, err := RepositoryOf[*struct{}](w)
+		return err
+	})
+	assert.ErrorIs(t, err, ErrRepositoryNotRegistered)
+}
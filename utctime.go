@@ -0,0 +1,82 @@
+package gormext
+
+import (
+	"database/sql"
+	"reflect"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// DefaultTimePrecision is the precision of the times of Config.UTCTime without
+// Config.TimePrecision, the finest one stored by every supported driver.
+const DefaultTimePrecision = time.Microsecond
+
+var (
+	// timePtrType and nullTimeType are, along with timeType and deletedAtType, the types of
+	// the fields normalized by Config.UTCTime.
+	timePtrType  = reflect.TypeOf(&time.Time{})
+	nullTimeType = reflect.TypeOf(sql.NullTime{})
+)
+
+// utcTime is the plugin of Config.UTCTime, converting the times of read records to UTC.
+type utcTime struct{}
+
+// Name returns the plugin name.
+func (u *utcTime) Name() string {
+	return "gormext:utc_time"
+}
+
+// Initialize registers the callback normalizing the times of queried records.
+func (u *utcTime) Initialize(db *gorm.DB) error {
+	return db.Callback().Query().After("gorm:query").Register("gormext:utc_time", u.normalize)
+}
+
+// utcNow returns the NowFunc of Config.UTCTime, calling now (time.Now when nil) in UTC and
+// truncated to precision.
+func utcNow(now func() time.Time, precision time.Duration) func() time.Time {
+	if now == nil {
+		now = time.Now
+	}
+	if precision <= 0 {
+		precision = DefaultTimePrecision
+	}
+	return func() time.Time {
+		return now().UTC().Truncate(precision)
+	}
+}
+
+// normalize converts the time fields of the queried records to UTC.
+func (u *utcTime) normalize(db *gorm.DB) {
+	stmt := db.Statement
+	if db.Error != nil || stmt.Schema == nil {
+		return
+	}
+
+	for _, entity := range auditEntities(stmt) {
+		if entity.Type() != stmt.Schema.ModelType {
+			return
+		}
+		for _, field := range stmt.Schema.Fields {
+			if field.FieldType != timeType && field.FieldType != timePtrType &&
+				field.FieldType != deletedAtType && field.FieldType != nullTimeType {
+				continue
+			}
+
+			value := field.ReflectValueOf(stmt.Context, entity)
+			switch t := value.Addr().Interface().(type) {
+			case *time.Time:
+				*t = t.UTC()
+			case **time.Time:
+				if *t != nil {
+					utc := (*t).UTC()
+					*t = &utc
+				}
+			case *gorm.DeletedAt:
+				t.Time = t.Time.UTC()
+			case *sql.NullTime:
+				t.Time = t.Time.UTC()
+			}
+		}
+	}
+}
@@ -0,0 +1,39 @@
+package gormext
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// utcEvent is a model with the time fields normalized by Config.UTCTime.
+type utcEvent struct {
+	ID        uint
+	At        time.Time
+	EndsAt    *time.Time
+	CreatedAt time.Time
+}
+
+// TestUTCTime verifies that timestamps are filled in UTC at the configured precision and that
+// times read in another zone are converted to UTC.
+func TestUTCTime(t *testing.T) {
+	g, err := NewGorm(newTestDatabaseContext(), nil, nil, nil, Config{UTCTime: true, TimePrecision: time.Millisecond})
+	assert.NoError(t, err)
+	repo := g.GetDB()
+	assert.NoError(t, g.Migrate(&utcEvent{}))
+
+	zone := time.FixedZone("UTC+3", 3*60*60)
+	at := time.Date(2024, 5, 1, 12, 0, 0, 0, zone)
+	event := utcEvent{At: at, EndsAt: &at}
+	assert.NoError(t, repo.Create(&event))
+	assert.Equal(t, time.UTC, event.CreatedAt.Location())
+	assert.Zero(t, event.CreatedAt.Nanosecond()%int(time.Millisecond))
+
+	var stored utcEvent
+	assert.NoError(t, repo.FirstByID(event.ID, &stored))
+	assert.Equal(t, time.UTC, stored.At.Location())
+	assert.Equal(t, time.UTC, stored.EndsAt.Location())
+	assert.True(t, at.Equal(stored.At))
+	assert.Equal(t, 9, stored.At.Hour())
+}
@@ -0,0 +1,90 @@
+package gormext
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/gorm"
+)
+
+// ValidateQueries prepares every cached query against the live connection, reporting each
+// invalid query with its file and, where the database reports it, the error position.
+// Variants of other drivers and templated queries (containing "{{") are skipped; queries
+// must hold a single statement.
+func (g *Gorm) ValidateQueries(ctx context.Context) error {
+	sqlDB, err := g.connection.DB()
+	if err != nil {
+		return fmt.Errorf("failed to validate sql queries: %w", err)
+	}
+
+	var errs []error
+	for _, name := range g.ListQueries() {
+		query, _ := g.sqlQueries.Load(name)
+		if g.isForeignVariant(name) || strings.Contains(query.(string), "{{") {
+			continue
+		}
+
+		prepared := g.placeholderSQL(query.(string))
+		stmt, err := sqlDB.PrepareContext(ctx, prepared)
+		if err != nil {
+			errs = append(errs, g.invalidQueryError(name, prepared, err))
+			continue
+		}
+		stmt.Close()
+	}
+	return errors.Join(errs...)
+}
+
+// isForeignVariant reports whether name is a query variant of another driver, like report.mysql
+// on a Postgres connection.
+func (g *Gorm) isForeignVariant(name string) bool {
+	i := strings.LastIndexByte(name, '.')
+	if i < 0 {
+		return false
+	}
+
+	driver, ok := sqlDriverAliases[name[i+1:]]
+	return ok && driver != g.databaseCtx.driver
+}
+
+// placeholderSQL converts the ? and :name placeholders of query to the placeholders of the
+// connection's dialect, so it can be prepared as-is.
+func (g *Gorm) placeholderSQL(query string) string {
+	bound, _, _ := bindNamedFunc(query, g.databaseCtx.driver, func(string) (any, bool) { return nil, true })
+	args := make([]any, strings.Count(bound, "?"))
+	return g.connection.Session(&gorm.Session{DryRun: true}).Raw(bound, args...).Statement.SQL.String()
+}
+
+// invalidQueryError describes a query that failed to prepare.
+func (g *Gorm) invalidQueryError(name, query string, err error) error {
+	location := runtimeQuerySource
+	if source, ok := g.querySources.Load(name); ok {
+		location = source.(string)
+	}
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Position > 0 {
+		line, column := lineColumn(query, int(pgErr.Position))
+		location = fmt.Sprintf("%s:%d:%d", location, line, column)
+	}
+	return fmt.Errorf("invalid sql query '%s' (%s): %w", name, location, err)
+}
+
+// lineColumn converts a 1-based character position in query to a line and column.
+func lineColumn(query string, position int) (int, int) {
+	line, column := 1, 1
+	for i, r := range []rune(query) {
+		if i+1 >= position {
+			break
+		}
+		if r == '\n' {
+			line, column = line+1, 1
+			continue
+		}
+		column++
+	}
+	return line, column
+}
@@ -0,0 +1,37 @@
+package gormext
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestValidateQueries verifies invalid cached queries fail startup with their file name.
+func TestValidateQueries(t *testing.T) {
+	dir := t.TempDir()
+	writeSQLFiles(t, dir, map[string]string{
+		"valid.sql":           "SELECT name FROM sqlite_master WHERE type = ? AND name = :name",
+		"templated.sql":       "SELECT {{ident .Column}} FROM t",
+		"report.postgres.sql": "SELECT ILIKE FROM",
+	})
+
+	g, err := NewGorm(newTestDatabaseContext(), nil, []string{}, map[string]string{}, Config{QueryDirs: []string{dir}, ValidateQueries: true})
+	assert.NoError(t, err, "Valid, templated and foreign variant queries should pass validation")
+
+	g.sqlQueries.Store("runtime", "SELEC 1")
+	err = g.ValidateQueries(context.Background())
+	assert.ErrorContains(t, err, "invalid sql query 'runtime' (registered at runtime)")
+
+	writeSQLFiles(t, dir, map[string]string{"users/broken.sql": "SELECT * FORM users"})
+	_, err = NewGorm(newTestDatabaseContext(), nil, []string{}, map[string]string{}, Config{QueryDirs: []string{dir}, ValidateQueries: true})
+	assert.ErrorContains(t, err, "invalid sql query 'users.broken' ("+filepath.ToSlash(filepath.Join(dir, "users/broken.sql")))
+}
+
+// TestLineColumn verifies error positions are converted to line and column.
+func TestLineColumn(t *testing.T) {
+	line, column := lineColumn("SELECT *\nFORM users", 11)
+	assert.Equal(t, 2, line)
+	assert.Equal(t, 2, column)
+}
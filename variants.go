@@ -0,0 +1,94 @@
+package gormext
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"reflect"
+)
+
+type (
+	// QueryVariant rolls out a rewritten cached query gradually. Executions are routed to New
+	// for Percent percent of calls, or whenever Selector returns true when it is set. A
+	// CompareRate fraction of executions also runs the other variant and reports divergent
+	// results to OnDivergence (or the connection logger when it is nil).
+	QueryVariant struct {
+		Old          string                                  // Name of the cached query in use today.
+		New          string                                  // Name of the cached query being rolled out.
+		Percent      int                                     // Share of executions (0-100) routed to New.
+		Selector     func(ctx context.Context) bool          // Optional per-call routing, overrides Percent.
+		CompareRate  float64                                 // Fraction of executions (0-1) comparing both variants.
+		OnDivergence func(ctx context.Context, d Divergence) // Optional divergence handler.
+	}
+
+	// Divergence describes a sampled execution where both variants of a query disagreed.
+	Divergence struct {
+		Name   string // Registered variant name.
+		Served string // Query whose result was returned.
+		Shadow string // Query run for comparison.
+		Err    error  // Error of the comparison run, if it failed.
+	}
+)
+
+// RegisterQueryVariant registers a rollout between two cached queries under name.
+func (g *Gorm) RegisterQueryVariant(name string, variant QueryVariant) error {
+	for _, query := range []string{variant.Old, variant.New} {
+		if _, err := g.GetQuery(query); err != nil {
+			return fmt.Errorf("invalid variant '%s': %w", name, err)
+		}
+	}
+	if variant.Percent < 0 || variant.Percent > 100 {
+		return fmt.Errorf("invalid variant '%s': percent %d out of range", name, variant.Percent)
+	}
+
+	g.variants.Store(name, &variant)
+	return nil
+}
+
+// SelectVariant runs the variant registered under name with args, scanning the result into dest.
+// Names without a registered variant run the cached query of the same name.
+func (g *Gorm) SelectVariant(ctx context.Context, name string, dest any, args ...any) error {
+	value, ok := g.variants.Load(name)
+	if !ok {
+		return g.SelectQuery(ctx, name, dest, args...)
+	}
+	variant := value.(*QueryVariant)
+
+	served, shadow := variant.Old, variant.New
+	if variant.useNew(ctx) {
+		served, shadow = shadow, served
+	}
+
+	if err := g.SelectQuery(ctx, served, dest, args...); err != nil {
+		return err
+	}
+
+	if variant.CompareRate > 0 && rand.Float64() < variant.CompareRate {
+		g.compareVariant(ctx, name, variant, served, shadow, dest, args)
+	}
+	return nil
+}
+
+// useNew decides whether an execution is routed to the new query.
+func (v *QueryVariant) useNew(ctx context.Context) bool {
+	if v.Selector != nil {
+		return v.Selector(ctx)
+	}
+	return rand.IntN(100) < v.Percent
+}
+
+// compareVariant runs the shadow query into a fresh value and reports a divergence from served.
+func (g *Gorm) compareVariant(ctx context.Context, name string, variant *QueryVariant, served, shadow string, dest any, args []any) {
+	other := reflect.New(reflect.TypeOf(dest).Elem())
+	err := g.SelectQuery(ctx, shadow, other.Interface(), args...)
+	if err == nil && reflect.DeepEqual(reflect.ValueOf(dest).Elem().Interface(), other.Elem().Interface()) {
+		return
+	}
+
+	divergence := Divergence{Name: name, Served: served, Shadow: shadow, Err: err}
+	if variant.OnDivergence != nil {
+		variant.OnDivergence(ctx, divergence)
+		return
+	}
+	g.connection.Logger.Warn(ctx, "query variant '%s' diverged: served '%s', shadow '%s' (error: %v)", name, served, shadow, err)
+}
@@ -0,0 +1,72 @@
+package gormext
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// newVariantTestGorm creates a Gorm instance caching two versions of a user lookup query.
+func newVariantTestGorm(t *testing.T) *Gorm {
+	g, repo := newTestRepository(t)
+	for _, u := range []repoUser{{Name: "ann", Age: 20}, {Name: "bob", Age: 40}} {
+		assert.NoError(t, repo.Create(&u))
+	}
+
+	assert.NoError(t, g.storeQuery("users.adults.v1", "SELECT name FROM repo_users WHERE age >= ? ORDER BY id", "v1"))
+	assert.NoError(t, g.storeQuery("users.adults.v2", "SELECT name FROM repo_users WHERE age > ? ORDER BY id", "v2"))
+	return g
+}
+
+// TestSelectVariantRouting verifies executions follow the selector and fall back to plain cached queries.
+func TestSelectVariantRouting(t *testing.T) {
+	g := newVariantTestGorm(t)
+	ctx := context.Background()
+
+	useNew := false
+	assert.NoError(t, g.RegisterQueryVariant("users.adults", QueryVariant{
+		Old:      "users.adults.v1",
+		New:      "users.adults.v2",
+		Selector: func(context.Context) bool { return useNew },
+	}))
+
+	var names []string
+	assert.NoError(t, g.SelectVariant(ctx, "users.adults", &names, 20))
+	assert.Equal(t, []string{"ann", "bob"}, names)
+
+	useNew = true
+	names = nil
+	assert.NoError(t, g.SelectVariant(ctx, "users.adults", &names, 20))
+	assert.Equal(t, []string{"bob"}, names)
+
+	names = nil
+	assert.NoError(t, g.SelectVariant(ctx, "users.adults.v1", &names, 30), "Unregistered names should run the cached query")
+	assert.Equal(t, []string{"bob"}, names)
+}
+
+// TestSelectVariantDivergence verifies sampled comparisons report diverging results.
+func TestSelectVariantDivergence(t *testing.T) {
+	g := newVariantTestGorm(t)
+	ctx := context.Background()
+
+	var divergences []Divergence
+	assert.NoError(t, g.RegisterQueryVariant("users.adults", QueryVariant{
+		Old:          "users.adults.v1",
+		New:          "users.adults.v2",
+		CompareRate:  1,
+		OnDivergence: func(_ context.Context, d Divergence) { divergences = append(divergences, d) },
+	}))
+
+	var names []string
+	assert.NoError(t, g.SelectVariant(ctx, "users.adults", &names, 30))
+	assert.Empty(t, divergences, "Equal results should not be reported")
+
+	assert.NoError(t, g.SelectVariant(ctx, "users.adults", &names, 20))
+	if assert.Len(t, divergences, 1) {
+		assert.Equal(t, Divergence{Name: "users.adults", Served: "users.adults.v1", Shadow: "users.adults.v2"}, divergences[0])
+	}
+
+	err := g.RegisterQueryVariant("broken", QueryVariant{Old: "users.adults.v1", New: "missing"})
+	assert.ErrorContains(t, err, "sql query 'missing' not found")
+}